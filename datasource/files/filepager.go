@@ -2,6 +2,7 @@ package files
 
 import (
 	"path/filepath"
+	"sync"
 	"time"
 
 	u "github.com/araddon/gou"
@@ -42,6 +43,7 @@ type FilePager struct {
 	tbl             *schema.Table
 	p               *plan.Source
 	usePartitioning bool
+	fetchOnce       sync.Once
 
 	schema.ConnScanner
 }
@@ -108,6 +110,10 @@ func (m *FilePager) NextScanner() (schema.ConnScanner, error) {
 // NextFile gets next file
 func (m *FilePager) NextFile() (*FileReader, error) {
 
+	// started on first read, not at open, so the partition of the
+	// plan source (WalkExecSource) is known before any file is fetched
+	m.RunFetcher()
+
 	select {
 	case <-m.exit:
 		// See if exit was called
@@ -120,13 +126,14 @@ func (m *FilePager) NextFile() (*FileReader, error) {
 	}
 }
 
+// RunFetcher start the file pre-fetching, only the first call starts it.
 func (m *FilePager) RunFetcher() {
 	defer func() {
 		if r := recover(); r != nil {
 			u.Errorf("panic in fetcher %v", r)
 		}
 	}()
-	go m.fetcher()
+	m.fetchOnce.Do(func() { go m.fetcher() })
 }
 
 // fetcher process run in a go-routine to pre-fetch files
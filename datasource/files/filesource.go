@@ -366,6 +366,5 @@ func (m *FileSource) createPager(tableName string, partition, limit int) (*FileP
 
 	pg := NewFilePager(tableName, m)
 	pg.Limit = limit
-	pg.RunFetcher()
 	return pg, nil
 }
@@ -25,13 +25,14 @@ const (
 
 var (
 	// Different Features of this Static Data Source
-	_ schema.Source       = (*StaticDataSource)(nil)
-	_ schema.Conn         = (*StaticDataSource)(nil)
-	_ schema.ConnColumns  = (*StaticDataSource)(nil)
-	_ schema.ConnScanner  = (*StaticDataSource)(nil)
-	_ schema.ConnSeeker   = (*StaticDataSource)(nil)
-	_ schema.ConnUpsert   = (*StaticDataSource)(nil)
-	_ schema.ConnDeletion = (*StaticDataSource)(nil)
+	_ schema.Source          = (*StaticDataSource)(nil)
+	_ schema.Conn            = (*StaticDataSource)(nil)
+	_ schema.ConnColumns     = (*StaticDataSource)(nil)
+	_ schema.ConnScanner     = (*StaticDataSource)(nil)
+	_ schema.ConnSeeker      = (*StaticDataSource)(nil)
+	_ schema.ConnMultiSeeker = (*StaticDataSource)(nil)
+	_ schema.ConnUpsert      = (*StaticDataSource)(nil)
+	_ schema.ConnDeletion    = (*StaticDataSource)(nil)
 )
 
 // Key implements Key and Sort interfaces.
@@ -106,11 +107,16 @@ func makeId(dv driver.Value) uint64 {
 	return 0
 }
 
+// RowIdIndex is the indexedCol of a StaticDataSource without an indexed
+// column, each Put row is keyed by a new sequential row id instead.
+const RowIdIndex = -1
+
 // StaticDataSource implements qlbridge DataSource to allow in memory native go data
 // to have a Schema and implement and be operated on by Sql Operations
 //
 // Features
-// - only a single column may (and must) be identified as the "Indexed" column
+// - only a single column may be identified as the "Indexed" column, or
+//   RowIdIndex to key rows by a synthetic row id
 // - NOT threadsafe
 // - each StaticDataSource = a single Table
 type StaticDataSource struct {
@@ -121,6 +127,7 @@ type StaticDataSource struct {
 	cursor   btree.Item // cursor position for paging
 	bt       *btree.BTree
 	max      int
+	rowId    uint64 // last synthetic row id, if indexCol == RowIdIndex
 }
 
 func NewStaticDataSource(name string, indexedCol int, data [][]driver.Value, cols []string) *StaticDataSource {
@@ -131,7 +138,7 @@ func NewStaticDataSource(name string, indexedCol int, data [][]driver.Value, col
 	m := StaticDataSource{indexCol: indexedCol, name: name}
 	m.tbl = tbl
 	m.bt = btree.New(32)
-	m.tbl.SetColumns(cols)
+	m.SetColumns(cols)
 	for _, row := range data {
 		m.Put(nil, nil, row)
 	}
@@ -162,7 +169,16 @@ func (m *StaticDataSource) CreateIterator() schema.Iterator           { return m
 func (m *StaticDataSource) Tables() []string                          { return []string{m.name} }
 func (m *StaticDataSource) Columns() []string                         { return m.tbl.Columns() }
 func (m *StaticDataSource) Length() int                               { return m.bt.Len() }
-func (m *StaticDataSource) SetColumns(cols []string)                  { m.tbl.SetColumns(cols) }
+
+// SetColumns of this table, the indexed column is the primary key.
+func (m *StaticDataSource) SetColumns(cols []string) {
+	m.tbl.SetColumns(cols)
+	if m.indexCol >= 0 && m.indexCol < len(cols) {
+		m.tbl.Indexes = []*schema.Index{
+			{Name: "id", Fields: []string{cols[m.indexCol]}, PrimaryKey: true},
+		}
+	}
+}
 
 func (m *StaticDataSource) Next() schema.Message {
 	//u.Infof("Next()")
@@ -223,7 +239,7 @@ func (m *StaticDataSource) Put(ctx context.Context, key schema.Key, row interfac
 			u.Warnf("wrong column ct")
 			return nil, fmt.Errorf("Wrong number of columns, got %v expected %v", len(rowVals), len(m.Columns()))
 		}
-		id := makeId(rowVals[m.indexCol])
+		id := m.rowKey(rowVals)
 		sdm := datasource.NewSqlDriverMessageMap(id, rowVals, m.tbl.FieldPositions)
 		item := DriverItem{sdm}
 		itemResult := m.bt.ReplaceOrInsert(&item)
@@ -252,7 +268,7 @@ func (m *StaticDataSource) Put(ctx context.Context, key schema.Key, row interfac
 		}
 		id := uint64(0)
 		if key == nil {
-			if row[m.indexCol] == nil {
+			if m.indexCol >= 0 && row[m.indexCol] == nil {
 				// Since we do not have an indexed column to work off of,
 				// the ideal would be to get the job builder/planner to do
 				// a scan with whatever info we have and feed that in?   Instead
@@ -260,7 +276,7 @@ func (m *StaticDataSource) Put(ctx context.Context, key schema.Key, row interfac
 				u.Warnf("wtf, nil key? %v %v", m.indexCol, row)
 				return nil, fmt.Errorf("cannot update on non index column ")
 			}
-			id = makeId(row[m.indexCol])
+			id = m.rowKey(row)
 		} else {
 			id = makeId(key)
 			sdm, _ := m.Get(key)
@@ -287,8 +303,36 @@ func (m *StaticDataSource) Put(ctx context.Context, key schema.Key, row interfac
 	}
 }
 
+// rowKey the btree id of a row, its indexed column or the next row id.
+func (m *StaticDataSource) rowKey(row []driver.Value) uint64 {
+	if m.indexCol == RowIdIndex {
+		m.rowId++
+		return m.rowId
+	}
+	return makeId(row[m.indexCol])
+}
+
+// PutMulti put each of the [][]driver.Value rows of src, the rows are
+// checked before any is put.
 func (m *StaticDataSource) PutMulti(ctx context.Context, keys []schema.Key, src interface{}) ([]schema.Key, error) {
-	return nil, fmt.Errorf("not implemented")
+	rows, ok := src.([][]driver.Value)
+	if !ok {
+		return nil, fmt.Errorf("Expected [][]driver.Value but got %T", src)
+	}
+	for _, row := range rows {
+		if len(row) != len(m.Columns()) {
+			return nil, fmt.Errorf("Wrong number of columns, got %v expected %v", len(row), len(m.Columns()))
+		}
+	}
+	keys = make([]schema.Key, 0, len(rows))
+	for _, row := range rows {
+		key, err := m.Put(ctx, nil, row)
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
 }
 
 func (m *StaticDataSource) Get(key driver.Value) (schema.Message, error) {
@@ -299,14 +343,15 @@ func (m *StaticDataSource) Get(key driver.Value) (schema.Message, error) {
 	return nil, schema.ErrNotFound // Should not found be an error?
 }
 
+// MultiGet rows for these keys, keys not found are skipped.
 func (m *StaticDataSource) MultiGet(keys []driver.Value) ([]schema.Message, error) {
-	rows := make([]schema.Message, len(keys))
-	for i, key := range keys {
+	rows := make([]schema.Message, 0, len(keys))
+	for _, key := range keys {
 		item := m.bt.Get(NewKey(makeId(key)))
 		if item == nil {
-			return nil, schema.ErrNotFound
+			continue
 		}
-		rows[i] = item.(*DriverItem).SqlDriverMessageMap
+		rows = append(rows, item.(*DriverItem).SqlDriverMessageMap)
 	}
 	return rows, nil
 }
@@ -349,8 +394,7 @@ func (m *StaticDataSource) DeleteExpression(p interface{}, where expr.Node) (int
 				//this means do NOT delete
 			} else {
 				// Delete!
-				indexVal := msgCtx.Values()[m.indexCol]
-				deletedKeys = append(deletedKeys, NewKey(makeId(indexVal)))
+				deletedKeys = append(deletedKeys, NewKey(msgCtx.IdVal))
 			}
 		case nil:
 			// ??
@@ -131,9 +131,20 @@ func TestStaticDataSource(t *testing.T) {
 	assert.Equal(t, []string{"root", "admin"}, vals2[4], "Roles should match updated vals")
 	assert.Equal(t, created, vals2[3], "created date should match updated vals")
 
+	keys, err := static.PutMulti(nil, nil, [][]driver.Value{
+		{124, "bob", "bob@email.com", created.In(time.UTC), []string{}},
+		{123, "aaron", "aaron@email.com", created.In(time.UTC), []string{"admin"}},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(keys))
+	assert.Equal(t, 2, static.Length(), "has 2 rows after PutMulti()")
+	_, err = static.PutMulti(nil, nil, [][]driver.Value{{125, "short"}})
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 2, static.Length(), "no rows put")
+
 	curSize := static.Length()
 
-	err := schema.RegisterSourceAsSchema("btreetest", static)
+	err = schema.RegisterSourceAsSchema("btreetest", static)
 	assert.Equal(t, nil, err)
 	sch, ok = schema.DefaultRegistry().Schema("btreetest")
 	assert.Equal(t, true, ok)
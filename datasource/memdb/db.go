@@ -6,6 +6,7 @@ package memdb
 import (
 	"database/sql/driver"
 	"fmt"
+	"sync"
 
 	u "github.com/araddon/gou"
 	"github.com/hashicorp/go-memdb"
@@ -27,12 +28,14 @@ var (
 	_ schema.Source = (*MemDb)(nil)
 
 	// Ensure our dbConn implements variety of Connection interfaces.
-	_ schema.Conn         = (*dbConn)(nil)
-	_ schema.ConnColumns  = (*dbConn)(nil)
-	_ schema.ConnScanner  = (*dbConn)(nil)
-	_ schema.ConnUpsert   = (*dbConn)(nil)
-	_ schema.ConnDeletion = (*dbConn)(nil)
-	_ schema.ConnSeeker   = (*dbConn)(nil)
+	_ schema.Conn            = (*dbConn)(nil)
+	_ schema.ConnColumns     = (*dbConn)(nil)
+	_ schema.ConnScanner     = (*dbConn)(nil)
+	_ schema.ConnUpsert      = (*dbConn)(nil)
+	_ schema.ConnDeletion    = (*dbConn)(nil)
+	_ schema.ConnSeeker      = (*dbConn)(nil)
+	_ schema.ConnMultiSeeker = (*dbConn)(nil)
+	_ schema.ConnTransaction = (*dbConn)(nil)
 )
 
 // MemDb implements qlbridge `Source` to allow in-memory native go data
@@ -45,12 +48,26 @@ type MemDb struct {
 	primaryIndex   string
 	db             *memdb.MemDB
 	max            int
+	mu             sync.Mutex
+	txOpen         bool // a conn has begun a transaction, see dbConn.Begin
 }
 type dbConn struct {
 	md     *MemDb
 	db     *memdb.MemDB
+	tx     *memTxn // transaction begun or joined by this conn
 	txn    *memdb.Txn
 	result memdb.ResultIterator
+	rows   []interface{} // scan rows read in a transaction
+}
+
+// memTxn the write txn of a transaction, shared by the conns in it.  It is
+// taken on the first write so until then writes of other conns are not
+// blocked.  memdb txns are not safe for concurrent use, so each use holds
+// the lock.
+type memTxn struct {
+	mu   sync.Mutex
+	txn  *memdb.Txn
+	done bool
 }
 
 // NewMemDbData creates a MemDb with given indexes, columns, and values
@@ -141,6 +158,7 @@ func (m *MemDb) buildDefaultIndexes() {
 		m.indexes[0].PrimaryKey = true
 		m.primaryIndex = m.indexes[0].Name
 	}
+	m.tbl.Indexes = m.indexes
 }
 
 //func (m *MemDb) SetColumns(cols []string)                  { m.tbl.SetColumns(cols) }
@@ -151,9 +169,129 @@ func newDbConn(mdb *MemDb) *dbConn {
 }
 func (m *dbConn) Columns() []string { return m.md.tbl.Columns() }
 func (m *dbConn) Close() error      { return nil }
+
+// Begin a transaction on this conn, conns to this MemDb that Join it
+// read and write through it, others only see its writes once committed.
+// memdb allows a single writer, so there is one open transaction at a
+// time and once it has written, writes of other conns wait for it to end.
+func (m *dbConn) Begin() error {
+	if m.tx != nil {
+		return fmt.Errorf("memdb %q conn already in a transaction", m.md.tbl.Name)
+	}
+	m.md.mu.Lock()
+	if m.md.txOpen {
+		m.md.mu.Unlock()
+		return fmt.Errorf("memdb %q already has an open transaction", m.md.tbl.Name)
+	}
+	m.md.txOpen = true
+	m.md.mu.Unlock()
+	m.tx = &memTxn{}
+	return nil
+}
+
+// Join the transaction begun by tx, a conn to the same MemDb.
+func (m *dbConn) Join(tx schema.ConnTransaction) error {
+	other, ok := tx.(*dbConn)
+	if !ok || other.md != m.md || other.tx == nil {
+		return fmt.Errorf("memdb %q can not join transaction %T", m.md.tbl.Name, tx)
+	}
+	m.tx = other.tx
+	return nil
+}
+
+// Commit the transaction of this conn.
+func (m *dbConn) Commit() error {
+	return m.endTxn((*memdb.Txn).Commit)
+}
+
+// Rollback the transaction of this conn, discarding its writes.
+func (m *dbConn) Rollback() error {
+	return m.endTxn((*memdb.Txn).Abort)
+}
+
+func (m *dbConn) endTxn(end func(*memdb.Txn)) error {
+	if m.tx == nil {
+		return fmt.Errorf("memdb %q conn has no open transaction", m.md.tbl.Name)
+	}
+	tx := m.tx
+	m.tx = nil
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return schema.ErrTxDone
+	}
+	tx.done = true
+	if tx.txn != nil {
+		end(tx.txn)
+	}
+	m.md.mu.Lock()
+	m.md.txOpen = false
+	m.md.mu.Unlock()
+	return nil
+}
+
+// view run fn with a txn for reads, that of this conns transaction once it
+// has written so its writes are seen.
+func (m *dbConn) view(fn func(txn *memdb.Txn) error) error {
+	if m.tx != nil {
+		m.tx.mu.Lock()
+		defer m.tx.mu.Unlock()
+		if m.tx.done {
+			return schema.ErrTxDone
+		}
+		if m.tx.txn != nil {
+			return fn(m.tx.txn)
+		}
+	}
+	txn := m.db.Txn(false)
+	defer txn.Abort() // noop for reads
+	return fn(txn)
+}
+
+// update run fn with a txn for writes, that of this conns transaction, or
+// its own which is committed if fn succeeds.
+func (m *dbConn) update(fn func(txn *memdb.Txn) error) error {
+	if m.tx != nil {
+		m.tx.mu.Lock()
+		defer m.tx.mu.Unlock()
+		if m.tx.done {
+			return schema.ErrTxDone
+		}
+		if m.tx.txn == nil {
+			m.tx.txn = m.db.Txn(true)
+		}
+		return fn(m.tx.txn)
+	}
+	txn := m.db.Txn(true)
+	if err := fn(txn); err != nil {
+		txn.Abort()
+		return err
+	}
+	txn.Commit()
+	return nil
+}
+
 func (m *dbConn) Next() schema.Message {
 
-	if m.txn == nil {
+	if m.tx != nil && m.rows == nil {
+		// the shared txn may be written between calls, read its rows now
+		err := m.view(func(txn *memdb.Txn) error {
+			result, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex)
+			if err != nil {
+				return err
+			}
+			m.rows = make([]interface{}, 0)
+			for raw := result.Next(); raw != nil; raw = result.Next() {
+				m.rows = append(m.rows, raw)
+			}
+			return nil
+		})
+		if err != nil {
+			u.Errorf("error %v", err)
+			return nil
+		}
+	}
+	if m.tx == nil && m.txn == nil {
 		m.txn = m.db.Txn(false)
 	}
 	select {
@@ -161,15 +299,23 @@ func (m *dbConn) Next() schema.Message {
 		return nil
 	default:
 		for {
-			if m.result == nil {
-				result, err := m.txn.Get(m.md.tbl.Name, m.md.primaryIndex)
-				if err != nil {
-					u.Errorf("error %v", err)
+			var raw interface{}
+			if m.tx != nil {
+				if len(m.rows) == 0 {
 					return nil
 				}
-				m.result = result
+				raw, m.rows = m.rows[0], m.rows[1:]
+			} else {
+				if m.result == nil {
+					result, err := m.txn.Get(m.md.tbl.Name, m.md.primaryIndex)
+					if err != nil {
+						u.Errorf("error %v", err)
+						return nil
+					}
+					m.result = result
+				}
+				raw = m.result.Next()
 			}
-			raw := m.result.Next()
 			if raw == nil {
 				return nil
 			}
@@ -187,13 +333,14 @@ func (m *dbConn) Put(ctx context.Context, key schema.Key, row interface{}) (sche
 
 	switch rowVals := row.(type) {
 	case []driver.Value:
-		txn := m.db.Txn(true)
-		key, err := m.putValues(txn, rowVals)
+		var key schema.Key
+		err := m.update(func(txn *memdb.Txn) (err error) {
+			key, err = m.putValues(txn, rowVals)
+			return err
+		})
 		if err != nil {
-			txn.Abort()
 			return nil, err
 		}
-		txn.Commit()
 		return key, nil
 	default:
 		return nil, fmt.Errorf("Expected []driver.Value but got %T", row)
@@ -214,36 +361,40 @@ func (m *dbConn) putValues(txn *memdb.Txn, row []driver.Value) (schema.Key, erro
 }
 
 func (m *dbConn) PutMulti(ctx context.Context, keys []schema.Key, objs interface{}) ([]schema.Key, error) {
-	txn := m.db.Txn(true)
 
 	switch rows := objs.(type) {
 	case [][]driver.Value:
 		keys := make([]schema.Key, 0, len(rows))
-		for _, row := range rows {
-			key, err := m.putValues(txn, row)
-			if err != nil {
-				txn.Abort()
-				return nil, err
+		err := m.update(func(txn *memdb.Txn) error {
+			for _, row := range rows {
+				key, err := m.putValues(txn, row)
+				if err != nil {
+					return err
+				}
+				keys = append(keys, key)
 			}
-			keys = append(keys, key)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
-		txn.Commit()
 		return keys, nil
 	}
 	return nil, fmt.Errorf("unrecognized put object type: %T", objs)
 }
 
 func (m *dbConn) Get(key driver.Value) (schema.Message, error) {
-	txn := m.db.Txn(false)
-	iter, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex, fmt.Sprintf("%v", key))
+	var item interface{}
+	err := m.view(func(txn *memdb.Txn) (err error) {
+		item, err = txn.First(m.md.tbl.Name, m.md.primaryIndex, fmt.Sprintf("%v", key))
+		return err
+	})
 	if err != nil {
-		txn.Abort()
 		u.Errorf("error reading %v because %v", key, err)
 		return nil, err
 	}
-	txn.Commit() // noop
 
-	if item := iter.Next(); item != nil {
+	if item != nil {
 		if msg, ok := item.(schema.Message); ok {
 			return msg, nil
 		}
@@ -252,16 +403,38 @@ func (m *dbConn) Get(key driver.Value) (schema.Message, error) {
 	return nil, schema.ErrNotFound // Should not found be an error?
 }
 
+// MultiGet rows for these keys in a single read transaction, keys not
+// found are skipped.
+func (m *dbConn) MultiGet(keys []driver.Value) ([]schema.Message, error) {
+	rows := make([]schema.Message, 0, len(keys))
+	err := m.view(func(txn *memdb.Txn) error {
+		for _, key := range keys {
+			item, err := txn.First(m.md.tbl.Name, m.md.primaryIndex, fmt.Sprintf("%v", key))
+			if err != nil {
+				u.Errorf("error reading %v because %v", key, err)
+				return err
+			}
+			if msg, ok := item.(schema.Message); ok {
+				rows = append(rows, msg)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 // Interface for Deletion
 func (m *dbConn) Delete(key driver.Value) (int, error) {
-	txn := m.db.Txn(true)
-	err := txn.Delete(m.md.tbl.Name, key)
+	err := m.update(func(txn *memdb.Txn) error {
+		return txn.Delete(m.md.tbl.Name, key)
+	})
 	if err != nil {
-		txn.Abort()
 		u.Warnf("could not delete: %v  err=%v", key, err)
 		return 0, err
 	}
-	txn.Commit()
 	return 1, nil
 }
 
@@ -269,14 +442,24 @@ func (m *dbConn) Delete(key driver.Value) (int, error) {
 func (m *dbConn) DeleteExpression(p interface{}, where expr.Node) (int, error) {
 
 	var deletedKeys []schema.Key
-	txn := m.db.Txn(true)
+	err := m.update(func(txn *memdb.Txn) error {
+		return m.deleteWhere(txn, where, &deletedKeys)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(deletedKeys), nil
+}
+
+// deleteWhere delete the rows of txn matching where.
+func (m *dbConn) deleteWhere(txn *memdb.Txn, where expr.Node, deletedKeys *[]schema.Key) error {
+
+	var deletes []*datasource.SqlDriverMessage
 	iter, err := txn.Get(m.md.tbl.Name, m.md.primaryIndex)
 	if err != nil {
-		txn.Abort()
 		u.Errorf("could not get values %v", err)
-		return 0, err
+		return err
 	}
-deleteLoop:
 	for {
 		item := iter.Next()
 		if item == nil {
@@ -298,13 +481,9 @@ deleteLoop:
 			if whereVal.Val() == false {
 				//this means do NOT delete
 			} else {
-				// Delete!
-				if err = txn.Delete(m.md.tbl.Name, msg); err != nil {
-					u.Errorf("could not delete %v", err)
-					break deleteLoop
-				}
-				indexVal := msg.Vals[0]
-				deletedKeys = append(deletedKeys, schema.NewKeyUint(makeId(indexVal)))
+				// Delete!  after iterating, the txn may not be modified while
+				// iterating it
+				deletes = append(deletes, msg)
 			}
 		case nil:
 			// ??
@@ -317,10 +496,14 @@ deleteLoop:
 			}
 		}
 	}
-	if err != nil {
-		txn.Abort()
-		return 0, err
+	for i := 0; i < len(deletes) && err == nil; i++ {
+		msg := deletes[i]
+		if err = txn.Delete(m.md.tbl.Name, msg); err != nil {
+			u.Errorf("could not delete %v", err)
+			break
+		}
+		indexVal := msg.Vals[0]
+		*deletedKeys = append(*deletedKeys, schema.NewKeyUint(makeId(indexVal)))
 	}
-	txn.Commit()
-	return len(deletedKeys), nil
+	return err
 }
@@ -61,6 +61,12 @@ func TestMemDb(t *testing.T) {
 	assert.Equal(t, []string{"root", "admin"}, vals2[4], "Roles should match updated vals")
 	assert.Equal(t, created, vals2[3], "created date should match updated vals")
 
+	// MultiGet skips keys not found
+	rows, err := c.(schema.ConnMultiSeeker).MultiGet([]driver.Value{122, 999, 123})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, []string{"user_id"}, db.tbl.PrimaryKey())
+
 	ct := 0
 	for {
 		msg := dc.Next()
@@ -115,3 +121,85 @@ func TestMemDb(t *testing.T) {
 	}
 	assert.Equal(t, 0, ct)
 }
+
+func TestMemDbTransaction(t *testing.T) {
+
+	db, err := NewMemDbData("users", [][]driver.Value{{122, "bob"}}, []string{"user_id", "name"})
+	assert.Equal(t, nil, err)
+
+	c, _ := db.Open("users")
+	dc := c.(*dbConn)
+	assert.Equal(t, nil, dc.Begin())
+	c2, _ := db.Open("users")
+	assert.NotEqual(t, nil, c2.(*dbConn).Begin(), "only one open transaction")
+
+	_, err = dc.Put(nil, nil, []driver.Value{123, "aaron"})
+	assert.Equal(t, nil, err)
+	_, err = dc.Delete(122)
+	assert.Equal(t, nil, err)
+
+	// other conns do not see the writes of the transaction
+	_, err = c2.(schema.ConnSeeker).Get(123)
+	assert.Equal(t, schema.ErrNotFound, err)
+	_, err = c2.(schema.ConnSeeker).Get(122)
+	assert.Equal(t, nil, err)
+
+	// conns that join it do
+	c3, _ := db.Open("users")
+	assert.Equal(t, nil, c3.(*dbConn).Join(dc))
+	_, err = c3.(schema.ConnSeeker).Get(123)
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, dc.Rollback())
+	_, err = c2.(schema.ConnSeeker).Get(123)
+	assert.Equal(t, schema.ErrNotFound, err)
+	_, err = c2.(schema.ConnSeeker).Get(122)
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, nil, dc.Rollback(), "no open transaction")
+
+	dc = c2.(*dbConn)
+	assert.Equal(t, nil, dc.Begin())
+	_, err = dc.PutMulti(nil, nil, [][]driver.Value{{123, "aaron"}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, dc.Commit())
+	c4, _ := db.Open("users")
+	_, err = c4.(schema.ConnSeeker).Get(123)
+	assert.Equal(t, nil, err)
+}
+
+func TestMemDbTransactionLazyWrite(t *testing.T) {
+
+	db, err := NewMemDbData("users", [][]driver.Value{{122, "bob"}}, []string{"user_id", "name"})
+	assert.Equal(t, nil, err)
+
+	c, _ := db.Open("users")
+	dc := c.(*dbConn)
+	assert.Equal(t, nil, dc.Begin())
+	_, err = dc.Get(122)
+	assert.Equal(t, nil, err)
+
+	// a transaction that has not written does not block other writers
+	c2, _ := db.Open("users")
+	put := make(chan error, 1)
+	go func() {
+		_, err := c2.(schema.ConnUpsert).Put(nil, nil, []driver.Value{123, "aaron"})
+		put <- err
+	}()
+	select {
+	case err = <-put:
+		assert.Equal(t, nil, err)
+	case <-time.After(time.Second):
+		t.Fatal("write outside the transaction blocked")
+	}
+
+	// its first write sees the committed writes of others
+	_, err = dc.Put(nil, nil, []driver.Value{124, "carl"})
+	assert.Equal(t, nil, err)
+	_, err = dc.Get(123)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, dc.Commit())
+
+	c3, _ := db.Open("users")
+	_, err = c3.(schema.ConnSeeker).Get(124)
+	assert.Equal(t, nil, err)
+}
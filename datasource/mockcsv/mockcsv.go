@@ -6,6 +6,7 @@ package mockcsv
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -174,6 +175,7 @@ func (m *Source) loadTable(tableName string) error {
 	if err != nil {
 		return err
 	}
+	tbl.SetStats(&schema.TableStats{RowCt: int64(ds.Length()), Updated: time.Now()})
 	return datasource.IntrospectTable(tbl, iter)
 }
 
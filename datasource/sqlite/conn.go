@@ -7,6 +7,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"strings"
+	"time"
 
 	u "github.com/araddon/gou"
 	"github.com/dchest/siphash"
@@ -25,8 +26,10 @@ import (
 
 var (
 	// ensure our conn implements connection features
-	_ schema.ConnAll      = (*qryconn)(nil)
-	_ schema.ConnMutation = (*qryconn)(nil)
+	_ schema.ConnAll         = (*qryconn)(nil)
+	_ schema.ConnMutation    = (*qryconn)(nil)
+	_ schema.ConnTransaction = (*qryconn)(nil)
+	_ schema.ConnStats       = (*qryconn)(nil)
 
 	// SourcePlanner interface {
 	// 	// given our request statement, turn that into a plan.Task.
@@ -53,6 +56,7 @@ type (
 		err       error
 		sqlInsert string
 		sqlUpdate string
+		tx        *sql.Tx // transaction begun or joined by this conn
 	}
 )
 
@@ -67,13 +71,26 @@ func newQueryConn(tbl *schema.Table, source *Source) *qryconn {
 }
 
 func (m *qryconn) init() {
+	if len(m.cols) == 0 {
+		return
+	}
+	keyCol := m.cols[0]
+	if pk := m.tbl.PrimaryKey(); len(pk) == 1 {
+		if pos, ok := m.tbl.FieldPositions[pk[0]]; ok {
+			keyCol = pk[0]
+			m.indexCol = pos
+		}
+	}
 	cols := make([]string, len(m.cols))
 	vals := make([]string, len(m.cols))
+	sets := make([]string, len(m.cols))
 	for i, col := range m.cols {
 		cols[i] = expr.IdentityMaybeQuote('"', col)
 		vals[i] = "?"
+		sets[i] = cols[i] + " = ?"
 	}
 	m.sqlInsert = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", m.tbl.Name, strings.Join(cols, ", "), strings.Join(vals, ", "))
+	m.sqlUpdate = fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?;", m.tbl.Name, strings.Join(sets, ", "), expr.IdentityMaybeQuote('"', keyCol))
 }
 
 // Close the qryconn.  Since sqlite is a NON-threadsafe db, this is very important
@@ -89,6 +106,108 @@ func (m *qryconn) Close() error {
 	return nil
 }
 
+// Begin a transaction on this conn, conns to this source that Join it
+// query through it, others only see its writes once committed.
+func (m *qryconn) Begin() error {
+	if m.tx != nil {
+		return fmt.Errorf("sqlite %q conn already in a transaction", m.source.file)
+	}
+	tx, err := m.source.db.Begin()
+	if err != nil {
+		return err
+	}
+	m.tx = tx
+	return nil
+}
+
+// Join the transaction begun by tx, a conn to the same source.
+func (m *qryconn) Join(tx schema.ConnTransaction) error {
+	other, ok := tx.(*qryconn)
+	if !ok || other.source != m.source || other.tx == nil {
+		return fmt.Errorf("sqlite %q can not join transaction %T", m.source.file, tx)
+	}
+	m.tx = other.tx
+	return nil
+}
+
+// Commit the transaction of this conn.
+func (m *qryconn) Commit() error {
+	if m.tx == nil {
+		return fmt.Errorf("sqlite %q conn has no open transaction", m.source.file)
+	}
+	tx := m.tx
+	m.tx = nil
+	return tx.Commit()
+}
+
+// Rollback the transaction of this conn.
+func (m *qryconn) Rollback() error {
+	if m.tx == nil {
+		return fmt.Errorf("sqlite %q conn has no open transaction", m.source.file)
+	}
+	tx := m.tx
+	m.tx = nil
+	return tx.Rollback()
+}
+
+// dbx the transaction of this conn if any, else the db.
+func (m *qryconn) dbx() sqlDb {
+	if m.tx != nil {
+		return m.tx
+	}
+	return m.source.db
+}
+
+// TableStats of the table from a single aggregate query per table, sqlite
+// keeps no histograms so those are left empty.
+func (m *qryconn) TableStats() (*schema.TableStats, error) {
+
+	cols := m.tbl.Columns()
+	aggs := make([]string, 0, 1+4*len(cols))
+	aggs = append(aggs, "COUNT(*)")
+	for _, col := range cols {
+		qcol := expr.IdentityMaybeQuote('"', col)
+		aggs = append(aggs, fmt.Sprintf("COUNT(DISTINCT %[1]s), COUNT(%[1]s), MIN(%[1]s), MAX(%[1]s)", qcol))
+	}
+	row := m.dbx().QueryRow(fmt.Sprintf("SELECT %s FROM %v", strings.Join(aggs, ", "), m.tbl.Name))
+	vals := make([]interface{}, 1+4*len(cols))
+	for i := range vals {
+		vals[i] = new(interface{})
+	}
+	if err := row.Scan(vals...); err != nil {
+		return nil, err
+	}
+	val := func(i int) driver.Value {
+		if by, ok := (*(vals[i].(*interface{}))).([]byte); ok {
+			return string(by)
+		}
+		return *(vals[i].(*interface{}))
+	}
+	count := func(i int) int64 {
+		ct, _ := val(i).(int64)
+		return ct
+	}
+
+	ts := &schema.TableStats{
+		RowCt:   count(0),
+		Columns: make(map[string]*schema.ColumnStats, len(cols)),
+		Updated: time.Now(),
+	}
+	for i, col := range cols {
+		pos := 1 + 4*i
+		cs := &schema.ColumnStats{
+			NDV: count(pos),
+			Min: val(pos + 2),
+			Max: val(pos + 3),
+		}
+		if ts.RowCt > 0 {
+			cs.NullFrac = float64(ts.RowCt-count(pos+1)) / float64(ts.RowCt)
+		}
+		ts.Columns[col] = cs
+	}
+	return ts, nil
+}
+
 // CreateIterator creates an interator to page through each row in this query resultset.
 // This qryconn is wrapping a sql rows object, paging through until empty.
 func (m *qryconn) CreateIterator() schema.Iterator { return m }
@@ -161,7 +280,8 @@ func (m *qryconn) Next() schema.Message {
 	}
 }
 
-// Put interface for Upsert.Put() to do single row insert based on key.
+// Put interface for Upsert.Put() to do single row insert based on key,
+// replacing the row of the same key if there is one.
 func (m *qryconn) Put(ctx context.Context, key schema.Key, row interface{}) (schema.Key, error) {
 
 	//u.Infof("%p Put(),  row:%#v", m, row)
@@ -171,61 +291,113 @@ func (m *qryconn) Put(ctx context.Context, key schema.Key, row interface{}) (sch
 			u.Warnf("wrong column ct")
 			return nil, fmt.Errorf("Wrong number of columns, got %v expected %v", len(rowVals), len(m.Columns()))
 		}
-
-		id := MakeId(rowVals[m.indexCol])
-
-		row := m.source.db.QueryRow(fmt.Sprintf("SELECT * FROM %v WHERE %s = $1", m.tbl.Name, m.cols[0]), rowVals[m.indexCol])
-		vals := make([]driver.Value, len(m.cols))
-		if err := row.Scan(&vals); err != nil && err != sql.ErrNoRows {
-			u.Warnf("could not get current? %v", err)
+		if err := m.putValues(m.dbx(), rowVals); err != nil {
 			return nil, err
-		} else if err == sql.ErrNoRows {
-			//u.Debugf("empty, now do insert")
-			//sdm := datasource.NewSqlDriverMessageMap(id, rowVals, m.tbl.FieldPositions)
-			ivals := make([]interface{}, len(rowVals))
-			for i, v := range rowVals {
-				ivals[i] = v
-			}
-			_, err = m.source.db.Exec(m.sqlInsert, ivals...)
-			if err != nil {
-				u.Warnf("wtf %v", err)
-			}
-			//u.Debugf("%p  PUT: id:%v IdVal:%v  Id():%v vals:%#v", m, id, sdm.IdVal, sdm.Id(), rowVals)
-		} else {
-			u.Debugf("found current? %v", vals)
-			sdm := datasource.NewSqlDriverMessageMap(id, rowVals, m.tbl.FieldPositions)
-			_, err = m.source.db.Exec(m.stmt.String(), nil)
-			if err != nil {
-				u.Warnf("wtf %v", err)
-			}
-			u.Debugf("%p  PUT: id:%v IdVal:%v  Id():%v vals:%#v", m, id, sdm.IdVal, sdm.Id(), rowVals)
 		}
-
-		return NewKey(id), nil
+		return NewKey(MakeId(rowVals[m.indexCol])), nil
 	default:
 		u.Warnf("not implemented %T", row)
 		return nil, fmt.Errorf("Expected []driver.Value but got %T", row)
 	}
 }
 
+// PutMulti put each of the [][]driver.Value rows of src, in a single
+// transaction unless this conn is already in one.
 func (m *qryconn) PutMulti(ctx context.Context, keys []schema.Key, src interface{}) ([]schema.Key, error) {
-	return nil, fmt.Errorf("not implemented")
+
+	rows, ok := src.([][]driver.Value)
+	if !ok {
+		return nil, fmt.Errorf("Expected [][]driver.Value but got %T", src)
+	}
+	for _, row := range rows {
+		if len(row) != len(m.Columns()) {
+			return nil, fmt.Errorf("Wrong number of columns, got %v expected %v", len(row), len(m.Columns()))
+		}
+	}
+
+	db := m.dbx()
+	var tx *sql.Tx
+	if _, inTx := db.(*sql.Tx); !inTx {
+		var err error
+		if tx, err = m.source.db.Begin(); err != nil {
+			return nil, err
+		}
+		db = tx
+	}
+	keys = make([]schema.Key, 0, len(rows))
+	for _, row := range rows {
+		if err := m.putValues(db, row); err != nil {
+			if tx != nil {
+				tx.Rollback()
+			}
+			return nil, err
+		}
+		keys = append(keys, NewKey(MakeId(row[m.indexCol])))
+	}
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// putValues update the row of the same key, inserting it if there is none.
+func (m *qryconn) putValues(db sqlDb, row []driver.Value) error {
+	args := make([]interface{}, len(row), len(row)+1)
+	for i, v := range row {
+		args[i] = v
+	}
+	res, err := db.Exec(m.sqlUpdate, append(args, row[m.indexCol])...)
+	if err != nil {
+		return err
+	}
+	if ct, err := res.RowsAffected(); err != nil {
+		return err
+	} else if ct > 0 {
+		return nil
+	}
+	_, err = db.Exec(m.sqlInsert, args...)
+	return err
 }
 
 // Get a single row by key.
 func (m *qryconn) Get(key driver.Value) (schema.Message, error) {
 
-	row := m.source.db.QueryRow(fmt.Sprintf("SELECT * FROM %v WHERE %s = $1", m.tbl.Name, m.cols[0]), key)
-	vals := make([]driver.Value, len(m.cols))
-	if err := row.Scan(&vals); err != nil {
+	cols := m.tbl.Columns()
+	keyCol := cols[0]
+	if pk := m.tbl.PrimaryKey(); len(pk) == 1 {
+		keyCol = pk[0]
+	}
+	row := m.dbx().QueryRow(fmt.Sprintf("SELECT * FROM %v WHERE %s = $1", m.tbl.Name, expr.IdentityMaybeQuote('"', keyCol)), key)
+	dest := make([]interface{}, len(cols))
+	for i := range dest {
+		dest[i] = new(interface{})
+	}
+	if err := row.Scan(dest...); err == sql.ErrNoRows {
+		return nil, schema.ErrNotFound
+	} else if err != nil {
 		return nil, err
 	}
-	return datasource.NewSqlDriverMessageMap(0, vals, m.colidx), nil
+	vals := make([]driver.Value, len(cols))
+	for i, d := range dest {
+		vals[i] = *(d.(*interface{}))
+	}
+	return datasource.NewSqlDriverMessageMap(0, vals, m.tbl.FieldPositions), nil
 }
 
 // Delete deletes a single row by key
 func (m *qryconn) Delete(key driver.Value) (int, error) {
-	return -1, schema.ErrNotImplemented
+	keyCol := m.tbl.Columns()[0]
+	if pk := m.tbl.PrimaryKey(); len(pk) == 1 {
+		keyCol = pk[0]
+	}
+	res, err := m.dbx().Exec(fmt.Sprintf("DELETE FROM %v WHERE %s = $1", m.tbl.Name, expr.IdentityMaybeQuote('"', keyCol)), key)
+	if err != nil {
+		return 0, err
+	}
+	ct, err := res.RowsAffected()
+	return int(ct), err
 }
 
 // WalkSourceSelect An interface implemented by this connection allowing the planner
@@ -233,6 +405,7 @@ func (m *qryconn) Delete(key driver.Value) (int, error) {
 func (m *qryconn) WalkSourceSelect(planner plan.Planner, p *plan.Source) (plan.Task, error) {
 
 	sqlSelect := p.Stmt.Source
+	parent := sqlSelect
 	u.Infof("original %s", sqlSelect.String())
 	p.Stmt.Source = nil
 	p.Stmt.Rewrite(sqlSelect)
@@ -242,12 +415,17 @@ func (m *qryconn) WalkSourceSelect(planner plan.Planner, p *plan.Source) (plan.T
 
 	m.cols = sqlSelect.Columns.UnAliasedFieldNames()
 	m.colidx = sqlSelect.ColIndexes()
-	sqlString, _ := newRewriter(sqlSelect).rewrite()
+	rw := newRewriter(sqlSelect)
+	if p.Final {
+		rw.pushDistinct(parent)
+		p.LimitPushdown = rw.pushLimit(parent)
+	}
+	sqlString, _ := rw.rewrite()
 
 	u.Infof("after sqlite-rewrite %s", sqlSelect.String())
 	u.Infof("pushdown sql: %s", sqlString)
 
-	rows, err := m.source.db.Query(sqlString)
+	rows, err := m.dbx().Query(sqlString)
 	if err != nil {
 		u.Errorf("could not open master err=%v", err)
 		return nil, err
@@ -30,6 +30,8 @@ var (
 	_ schema.Source = (*Source)(nil)
 	// ensure our Source implements connection features
 	_ schema.Conn = (*Source)(nil)
+	// and can create tables
+	_ schema.Creator = (*Source)(nil)
 )
 
 // Source implements qlbridge DataSource to a sqlite file based source.
@@ -50,6 +52,13 @@ type Source struct {
 	tableList []string
 }
 
+// sqlDb the query methods common to *sql.DB and *sql.Tx
+type sqlDb interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 func newSourceEmtpy() schema.Source {
 	return &Source{
 		qryconns:  make(map[string]*qryconn),
@@ -146,6 +155,22 @@ func (m *Source) Table(table string) (*schema.Table, error) {
 // Tables gets list of tables
 func (m *Source) Tables() []string { return m.tableList }
 
+// CreateTable create the table in the sqlite db.
+func (m *Source) CreateTable(tbl *schema.Table) error {
+	sqls := TableToString(tbl)
+	if _, err := m.db.Exec(sqls); err != nil {
+		return err
+	}
+	name := strings.ToLower(tbl.Name)
+	m.tblmu.Lock()
+	defer m.tblmu.Unlock()
+	if _, exists := m.tables[name]; !exists {
+		m.tableList = append(m.tableList, name)
+	}
+	m.tables[name] = tableFromSQL(name, sqls)
+	return nil
+}
+
 // Close this source, closing the underlying sqlite db file
 func (m *Source) Close() error {
 	if m.db != nil {
@@ -171,6 +196,9 @@ func tableFromSQL(name, sqls string) *schema.Table {
 		colName := expr.IdentityTrim(parts[0])
 		// NewFieldBase(name string, valType value.ValueType, size int, desc string)
 		t.AddField(schema.NewFieldBase(colName, TypeFromString(parts[1]), 255, ""))
+		if strings.Contains(strings.ToUpper(cols), "PRIMARY KEY") {
+			t.Indexes = append(t.Indexes, &schema.Index{Name: "id", Fields: []string{colName}, PrimaryKey: true})
+		}
 		// u.Debugf("%d  %v", i, parts)
 		// u.Debugf("%q", expr.IdentityTrim(parts[0]))
 	}
@@ -3,10 +3,12 @@ package sqlite_test
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	u "github.com/araddon/gou"
 	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
@@ -20,6 +22,7 @@ import (
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/testutil"
+	"github.com/araddon/qlbridge/value"
 )
 
 /*
@@ -152,3 +155,120 @@ func TestSuite(t *testing.T) {
 	LoadTestDataOnce(t)
 	testutil.RunSimpleSuite(t)
 }
+
+func TestGet(t *testing.T) {
+	LoadTestDataOnce(t)
+	conn, err := sch.OpenConn("users")
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+
+	seeker, ok := conn.(schema.ConnSeeker)
+	assert.True(t, ok)
+	row, err := seeker.Get("9Ip1aKbeZe2njCDM")
+	assert.Equal(t, nil, err)
+	email, _ := row.(*datasource.SqlDriverMessageMap).Get("email")
+	assert.Equal(t, "aaron@email.com", email.ToString())
+
+	_, err = seeker.Get("not-a-user")
+	assert.Equal(t, schema.ErrNotFound, err)
+}
+
+func TestTransaction(t *testing.T) {
+	LoadTestDataOnce(t)
+
+	get := func(key string) error {
+		conn, err := sch.OpenConn("users")
+		assert.Equal(t, nil, err)
+		defer conn.Close()
+		_, err = conn.(schema.ConnSeeker).Get(key)
+		return err
+	}
+
+	conn, err := sch.OpenConn("users")
+	assert.Equal(t, nil, err)
+	tx, ok := conn.(schema.ConnTransaction)
+	assert.True(t, ok)
+	assert.Equal(t, nil, tx.Begin())
+	mut := conn.(schema.ConnAll)
+	_, err = mut.Put(nil, nil, []driver.Value{"txn-user", "txn@email.com", "", time.Now(), 0, "{}"})
+	assert.Equal(t, nil, err)
+	ct, err := mut.Delete("9Ip1aKbeZe2njCDM")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, ct)
+	conn.Close()
+
+	// other conns do not see the writes of the transaction
+	assert.Equal(t, schema.ErrNotFound, get("txn-user"))
+	assert.Equal(t, nil, get("9Ip1aKbeZe2njCDM"))
+
+	// conns that join it do
+	conn2, err := sch.OpenConn("users")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, conn2.(schema.ConnTransaction).Join(tx))
+	_, err = conn2.(schema.ConnSeeker).Get("txn-user")
+	assert.Equal(t, nil, err)
+	conn2.Close()
+
+	assert.Equal(t, nil, tx.Rollback())
+	assert.Equal(t, schema.ErrNotFound, get("txn-user"))
+	assert.Equal(t, nil, get("9Ip1aKbeZe2njCDM"))
+	assert.NotEqual(t, nil, tx.Commit(), "no open transaction")
+}
+
+func TestTableStats(t *testing.T) {
+	LoadTestDataOnce(t)
+	conn, err := sch.OpenConn("users")
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+
+	sc, ok := conn.(schema.ConnStats)
+	assert.True(t, ok)
+	stats, err := sc.TableStats()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(3), stats.RowCt)
+	assert.Equal(t, int64(3), stats.Columns["user_id"].NDV)
+	assert.Equal(t, 0.0, stats.Columns["user_id"].NullFrac)
+	assert.Equal(t, "aaron@email.com", stats.Columns["email"].Min)
+}
+
+func TestCreateTablePutMulti(t *testing.T) {
+	LoadTestDataOnce(t)
+
+	tbl := schema.NewTable("user_scores")
+	tbl.AddFieldType("user_id", value.StringType)
+	tbl.AddFieldType("score", value.IntType)
+	tbl.SetColumnsFromFields()
+	creator, ok := sch.DS.(schema.Creator)
+	assert.True(t, ok)
+	assert.Equal(t, nil, creator.CreateTable(tbl))
+	assert.Equal(t, nil, schema.DefaultRegistry().SchemaRefresh(sch.Name))
+
+	score := func(key string) int64 {
+		conn, err := sch.OpenConn("user_scores")
+		assert.Equal(t, nil, err)
+		defer conn.Close()
+		row, err := conn.(schema.ConnSeeker).Get(key)
+		assert.Equal(t, nil, err)
+		if err != nil {
+			return -1
+		}
+		v, _ := row.(*datasource.SqlDriverMessageMap).Get("score")
+		return v.Value().(int64)
+	}
+
+	conn, err := sch.OpenConn("user_scores")
+	assert.Equal(t, nil, err)
+	mut := conn.(schema.ConnUpsert)
+	keys, err := mut.PutMulti(nil, nil, [][]driver.Value{{"a", 1}, {"b", 2}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(keys))
+	_, err = mut.PutMulti(nil, nil, [][]driver.Value{{"c", 3}, {"d"}})
+	assert.NotEqual(t, nil, err, "short row")
+	// put of an existing key updates it
+	_, err = mut.Put(nil, nil, []driver.Value{"a", 10})
+	assert.Equal(t, nil, err)
+	conn.Close()
+
+	assert.Equal(t, int64(10), score("a"))
+	assert.Equal(t, int64(2), score("b"))
+}
@@ -33,7 +33,8 @@ func (m *rewrite) rewrite() (string, error) {
 
 	var err error
 
-	if m.sel.Where != nil {
+	// sub-queries are left for the where task to evaluate after the scan
+	if m.sel.Where != nil && len(rel.SubQueries(m.sel.Where.Expr)) == 0 {
 		m.result.Where = m.sel.Where
 		m.result.Where.Expr, err = m.walkNode(m.sel.Where.Expr)
 		if err != nil {
@@ -65,6 +66,51 @@ func (m *rewrite) rewrite() (string, error) {
 	return m.result.String(), nil
 }
 
+// pushLimit push the LIMIT and OFFSET of the (single source) select down
+// to sqlite, only if there is no aggregation or sorting left to do on the
+// rows after they are read.
+func (m *rewrite) pushLimit(parent *rel.SqlSelect) bool {
+	// sqlite requires a LIMIT for OFFSET
+	if parent.Limit == 0 {
+		return false
+	}
+	if len(parent.GroupBy) > 0 || parent.IsAggQuery() || parent.Having != nil ||
+		(parent.Distinct && !m.result.Distinct) || len(parent.OrderBy) > 0 {
+		return false
+	}
+	if parent.Where != nil && len(rel.SubQueries(parent.Where.Expr)) > 0 {
+		return false
+	}
+	m.result.Limit = parent.Limit
+	m.result.Offset = parent.Offset
+	return true
+}
+
+// pushDistinct push the DISTINCT of the (single source) select down to
+// sqlite, only if the select projects each of the columns read from
+// sqlite as is, so the rows sqlite de-duplicates are those we would.
+func (m *rewrite) pushDistinct(parent *rel.SqlSelect) bool {
+	if !parent.Distinct || len(parent.GroupBy) > 0 || parent.IsAggQuery() || parent.IsWindowQuery() {
+		return false
+	}
+	projected := make(map[string]bool, len(parent.Columns))
+	for _, col := range parent.Columns {
+		in, ok := col.Expr.(*expr.IdentityNode)
+		if !ok || col.Star {
+			return false
+		}
+		_, field, _ := in.LeftRight()
+		projected[field] = true
+	}
+	for _, field := range m.sel.Columns.UnAliasedFieldNames() {
+		if !projected[field] {
+			return false
+		}
+	}
+	m.result.Distinct = true
+	return true
+}
+
 // eval() returns ( value, isOk, isIdentity )
 func (m *rewrite) eval(arg expr.Node) (value.Value, bool, bool) {
 	switch arg := arg.(type) {
@@ -123,6 +169,13 @@ func (m *rewrite) walkSelectList() error {
 			// 	return nil, value.NewStringValue(curNode.Text), nil
 			case *expr.IdentityNode:
 				//u.Debugf("likely a projection, not agg T:%T  %v", curNode, curNode)
+			case *expr.CaseNode:
+				newNode, err := m.walkCase(curNode)
+				if err != nil {
+					u.Error(err)
+					return err
+				}
+				col.Expr = newNode
 			default:
 				u.Warnf("unrecognized not agg T:%T  %v", curNode, curNode)
 				//panic("Unrecognized node type")
@@ -145,7 +198,7 @@ func (m *rewrite) walkGroupBy() error {
 	for _, col := range m.sel.GroupBy {
 		if col.Expr != nil {
 			switch col.Expr.(type) {
-			case *expr.IdentityNode, *expr.FuncNode:
+			case *expr.IdentityNode, *expr.FuncNode, *expr.CaseNode:
 				newExpr, err := m.walkNode(col.Expr)
 				//fld := strings.Replace(expr.FindFirstIdentity(col.Expr), ".", "", -1)
 				if err == nil {
@@ -214,6 +267,8 @@ func (m *rewrite) walkNode(cur expr.Node) (expr.Node, error) {
 		return curNode, nil
 	case *expr.ArrayNode:
 		return m.walkArrayNode(curNode)
+	case *expr.CaseNode:
+		return m.walkCase(curNode)
 	default:
 		u.Debugf("unrecognized T:%T  %v", cur, cur)
 	}
@@ -272,9 +327,11 @@ func (m *rewrite) walkFilterBinary(node *expr.BinaryNode) (expr.Node, error) {
 		if node.Args[1].String() == "NULL" {
 			//u.Errorf("we found something wrong werener")
 			//return expr.NewIdentityNodeVal(fmt.Sprintf("%s IS NOT NULL", node.Args[0])), nil
-			node.Operator.V = "IS NOT NULL"
-			node.Args[1] = expr.NewIdentityNodeVal("")
-			//u.Warnf("rh %#v", node.Args[1])
+			// copy rather than mutate, the where node is shared with
+			// the planned statement evaluated in vm
+			op := node.Operator
+			op.V = "IS NOT NULL"
+			return expr.NewBinaryNode(op, node.Args[0], expr.NewIdentityNodeVal("")), nil
 		}
 		return node, nil
 		// case lex.TokenLogicOr:
@@ -326,6 +383,50 @@ func (m *rewrite) walkFilterBinary(node *expr.BinaryNode) (expr.Node, error) {
 	return node, nil
 }
 
+// Case expressions are native to sqlite, walk the operand, conditions
+// and results so their sub-expressions are rewritten.
+//
+//    CASE WHEN x != NULL THEN 1 ELSE 0 END  =>  CASE WHEN x IS NOT NULL THEN 1 ELSE 0 END
+//
+func (m *rewrite) walkCase(node *expr.CaseNode) (expr.Node, error) {
+	var err error
+	if node.Operand != nil {
+		if node.Operand, err = m.walkNode(node.Operand); err != nil {
+			return nil, err
+		}
+	}
+	for i := range node.Whens {
+		if node.Whens[i], err = m.walkNode(node.Whens[i]); err != nil {
+			return nil, err
+		}
+		if node.Thens[i], err = m.walkNode(node.Thens[i]); err != nil {
+			return nil, err
+		}
+	}
+	if node.Else != nil {
+		if node.Else, err = m.walkNode(node.Else); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// sqlite has coalesce, ifnull and nullif but no if(), so it is rewritten
+// as a case expression.
+//
+//    if(x > 1, "a", "b")  =>  CASE WHEN x > 1 THEN "a" ELSE "b" END
+//
+func ifToCase(node *expr.FuncNode) (*expr.CaseNode, bool) {
+	if len(node.Args) != 3 {
+		return nil, false
+	}
+	cn := expr.NewCaseNode(nil)
+	cn.Whens = []expr.Node{node.Args[0]}
+	cn.Thens = []expr.Node{node.Args[1]}
+	cn.Else = node.Args[2]
+	return cn, true
+}
+
 // Take an expression func, ensure we don't do runtime-checking (as the function)
 // doesn't really exist, then map that function to a mongo operation
 //
@@ -336,6 +437,10 @@ func (m *rewrite) walkFilterFunc(node *expr.FuncNode) (expr.Node, error) {
 	switch funcName := strings.ToLower(node.Name); funcName {
 	case "exists", "missing":
 
+	case "if":
+		if cn, ok := ifToCase(node); ok {
+			return m.walkCase(cn)
+		}
 	default:
 		u.Warnf("not implemented %T", funcName)
 	}
@@ -358,6 +463,10 @@ func (m *rewrite) walkProjectionFunc(node *expr.FuncNode) (expr.Node, error) {
 	switch funcName := strings.ToLower(node.Name); funcName {
 	case "count":
 		return node, nil
+	case "if":
+		if cn, ok := ifToCase(node); ok {
+			return m.walkCase(cn)
+		}
 	default:
 		u.Warnf("not implemented %v", funcName)
 	}
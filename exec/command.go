@@ -1,15 +1,18 @@
 package exec
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"strings"
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -20,7 +23,7 @@ var (
 	_ TaskRunner = (*Command)(nil)
 )
 
-// Command is executeable task for SET SQL commands
+// Command is executeable task for SET, COMMIT, ROLLBACK, ANALYZE SQL commands
 type Command struct {
 	*TaskBase
 	p *plan.Command
@@ -48,17 +51,27 @@ func (m *Command) Run() error {
 	//defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
 
-	if m.Ctx.Session == nil {
-		u.Warnf("no Context.Session?")
-		return fmt.Errorf("no Context.Session?")
-	}
-
 	switch kw := m.p.Stmt.Keyword(); kw {
 	case lex.TokenSet:
+		if m.Ctx.Session == nil {
+			u.Warnf("no Context.Session?")
+			return fmt.Errorf("no Context.Session?")
+		}
 		return m.runSet()
-	case lex.TokenRollback, lex.TokenCommit:
-		u.Debugf("ignorning transaction, not implemented.  %v", kw.String())
-		return nil
+	case lex.TokenCommit:
+		if m.Ctx.Txn == nil {
+			u.Debugf("no open transaction to commit")
+			return nil
+		}
+		return m.Ctx.Txn.Commit()
+	case lex.TokenRollback:
+		if m.Ctx.Txn == nil {
+			u.Debugf("no open transaction to rollback")
+			return nil
+		}
+		return m.Ctx.Txn.Rollback()
+	case lex.TokenAnalyze:
+		return m.runAnalyze()
 	default:
 		u.Warnf("unrecognized command: kw=%v   stmt:%s", kw, m.p.Stmt)
 	}
@@ -87,6 +100,74 @@ func (m *Command) runSet() error {
 	return nil
 }
 
+// runAnalyze collect the stats of a table, from the source if it can
+// supply them otherwise by scanning it, and store them on the table.
+func (m *Command) runAnalyze() error {
+
+	if m.Ctx.Schema == nil {
+		return fmt.Errorf("no schema to analyze table %q", m.p.Stmt.Identity)
+	}
+	tbl, err := m.Ctx.Schema.Table(m.p.Stmt.Identity)
+	if err != nil {
+		return err
+	}
+	conn, err := m.Ctx.OpenConn(tbl.Name)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if sc, ok := conn.(schema.ConnStats); ok {
+		stats, err := sc.TableStats()
+		if err != nil {
+			return err
+		}
+		tbl.SetStats(stats)
+		return nil
+	}
+
+	scanner, ok := conn.(schema.ConnScanner)
+	if !ok {
+		return fmt.Errorf("source for %q can not be scanned to analyze", tbl.Name)
+	}
+	cols := tbl.Columns()
+	collector := schema.NewStatsCollector(cols)
+	for {
+		select {
+		case <-m.SigChan():
+			return nil
+		default:
+		}
+		msg := scanner.Next()
+		if msg == nil {
+			break
+		}
+		collector.Add(statsRow(cols, msg))
+	}
+	tbl.SetStats(collector.Stats())
+	return nil
+}
+
+// statsRow the values of a scanned message in the order of the tables columns.
+func statsRow(cols []string, msg schema.Message) []driver.Value {
+	switch mt := msg.(type) {
+	case *datasource.SqlDriverMessage:
+		return mt.Vals
+	case expr.ContextReader:
+		row := make([]driver.Value, len(cols))
+		for i, col := range cols {
+			if v, ok := mt.Get(col); ok && v != nil && !v.Nil() {
+				row[i] = v.Value()
+			}
+		}
+		return row
+	}
+	if vals, ok := msg.Body().([]driver.Value); ok {
+		return vals
+	}
+	return nil
+}
+
 func evalSetExpression(col *rel.CommandColumn, ctx expr.ContextReadWriter, arg expr.Node) error {
 
 	switch bn := arg.(type) {
@@ -0,0 +1,223 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*CteSource)(nil)
+
+	// MaxCteIterations the most iterations of a recursive WITH sub-query
+	// before giving up on it reaching a fixed point.
+	MaxCteIterations = 1000
+)
+
+// CteSource reads the rows of a WITH sub-query (common table expression)
+// as a source.
+//
+// A sub-query read by a single source is run inline as its own job, its
+// rows streamed through.  One read by more than one source, or a recursive
+// one, is materialized by the first source to read it and its rows shared.
+// A recursive sub-query runs its anchor select then its step select against
+// the rows of the previous iteration until no new rows are found.
+type CteSource struct {
+	*TaskBase
+	p        *plan.Source
+	cte      *plan.Cte
+	colIndex map[string]int
+	ct       uint64
+}
+
+// NewCteSource create a task reading the rows of the WITH sub-query of p.
+func NewCteSource(ctx *plan.Context, p *plan.Source) *CteSource {
+	colIndex := make(map[string]int, len(p.Cte.Cols))
+	for i, col := range p.Cte.Cols {
+		colIndex[col] = i
+	}
+	return &CteSource{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		cte:      p.Cte,
+		colIndex: colIndex,
+	}
+}
+
+// Run read the sub-query rows.
+func (m *CteSource) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	if !m.cte.Materialize {
+		return m.stream()
+	}
+	rows, err := m.cte.Rows(func() ([][]driver.Value, error) {
+		if m.cte.Recursive {
+			return runRecursiveCte(m.Ctx, m.cte)
+		}
+		return runStatement(cteContext(m.Ctx, m.cte, m.cte.Stmt.Stmt))
+	})
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if !m.emit(row) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// stream run the sub-query as its own job forwarding its rows, the job is
+// closed if we are signaled to quit before it is done.
+func (m *CteSource) stream() error {
+	subCtx := cteContext(m.Ctx, m.cte, m.cte.Stmt.Stmt)
+	job, err := BuildSqlJob(subCtx)
+	if err != nil {
+		return fmt.Errorf("WITH sub-query %q: %v", m.cte.Stmt.Name, err)
+	}
+	var fwdErr error
+	quit := false
+	fwd := NewTaskBase(subCtx)
+	fwd.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if quit {
+			return false
+		}
+		var vals []driver.Value
+		switch mt := msg.(type) {
+		case nil:
+			// end of rows sentinel from a limit
+			return true
+		case *datasource.SqlDriverMessageMap:
+			vals = mt.Vals
+		case *datasource.SqlDriverMessage:
+			vals = mt.Vals
+		default:
+			fwdErr = fmt.Errorf("WITH sub-query unrecognized message %T", msg)
+			quit = true
+			return false
+		}
+		quit = !m.emit(vals)
+		return !quit
+	}
+	job.RootTask.Add(fwd)
+	if err = job.Setup(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- job.Run()
+	}()
+	select {
+	case err = <-done:
+		if err == nil {
+			err = fwdErr
+		}
+		return err
+	case <-m.SigChan():
+		job.Close()
+		<-done
+		return nil
+	}
+}
+
+func (m *CteSource) emit(vals []driver.Value) bool {
+	msg := datasource.NewSqlDriverMessageMap(m.ct, vals, m.colIndex)
+	m.ct++
+	select {
+	case <-m.SigChan():
+		return false
+	case m.msgOutCh <- msg:
+		m.track(msg)
+		return true
+	}
+}
+
+// runRecursiveCte the rows of a recursive sub-query, its anchor rows then
+// the rows of each iteration of its step select over the rows of the
+// iteration before, until an iteration finds no new rows.
+func runRecursiveCte(ctx *plan.Context, cte *plan.Cte) ([][]driver.Value, error) {
+	rows, err := runStatement(cteContext(ctx, cte, cte.Anchor()))
+	if err != nil {
+		return nil, err
+	}
+	var seen map[string]struct{}
+	if cte.Distinct() {
+		seen = make(map[string]struct{}, len(rows))
+		rows = distinctRows(seen, rows)
+	}
+	step := cte.Step()
+	working := rows
+	for i := 0; len(working) > 0; i++ {
+		if i >= MaxCteIterations {
+			return nil, fmt.Errorf("recursive WITH sub-query %q exceeded %d iterations", cte.Stmt.Name, MaxCteIterations)
+		}
+		if ctx.Context != nil {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		// the step reads the rows of the last iteration as the sub-query
+		stepCtx := cteContext(ctx, cte, step)
+		stepCtx.Ctes[strings.ToLower(cte.Stmt.Name)] = plan.NewCteRows(cte, working)
+		working, err = runStatement(stepCtx)
+		if err != nil {
+			return nil, err
+		}
+		if seen != nil {
+			working = distinctRows(seen, working)
+		}
+		rows = append(rows, working...)
+	}
+	return rows, nil
+}
+
+// distinctRows the rows not already seen, adding them to seen.
+func distinctRows(seen map[string]struct{}, rows [][]driver.Value) [][]driver.Value {
+	out := rows[:0]
+	for _, row := range rows {
+		key := setOpKey(row)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, row)
+	}
+	return out
+}
+
+// cteContext the context of a job running stmt of a WITH sub-query, a
+// non-recursive sub-query does not see itself so it may read a table of
+// the same name.
+func cteContext(ctx *plan.Context, cte *plan.Cte, stmt rel.SqlStatement) *plan.Context {
+	child := newChildContext(ctx, stmt.String())
+	name := strings.ToLower(cte.Stmt.Name)
+	child.Ctes = make(map[string]*plan.Cte, len(ctx.Ctes))
+	for n, c := range ctx.Ctes {
+		if n == name && !cte.Recursive {
+			continue
+		}
+		child.Ctes[n] = c
+	}
+	return child
+}
+
+// cteDetail the sub-query read by a source, for explain.
+func cteDetail(p *plan.Source) string {
+	parts := []string{"cte=" + p.Cte.Stmt.Name}
+	if p.Cte.Recursive {
+		parts = append(parts, "recursive")
+	}
+	if p.Cte.Materialize {
+		parts = append(parts, "materialized")
+	}
+	return strings.Join(parts, " ")
+}
@@ -1,14 +1,19 @@
 package exec
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/membtree"
 	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
@@ -88,14 +93,159 @@ func (m *Create) Run() error {
 		sourceConf.Name = schemaName
 
 		reg := schema.DefaultRegistry()
+		defer plan.DefaultStatementCache.Purge()
 
 		return reg.SchemaAddFromConfig(sourceConf)
+	case lex.TokenTable:
+		if cs.Select == nil {
+			break
+		}
+		createdCt, err := m.createTableAs(cs)
+		if err != nil {
+			return err
+		}
+		vals := []driver.Value{int64(0), createdCt}
+		m.msgOutCh <- &datasource.SqlDriverMessage{Vals: vals, IdVal: 1}
+		return nil
 	default:
 		u.Warnf("unrecognized create/alter: kw=%v   stmt:%s", cs.Tok, m.p.Stmt)
 	}
 	return ErrNotImplemented
 }
 
+// createTableAs create the table of a CREATE TABLE ... AS SELECT and put
+// the rows of its select in it, returning the number of rows put.  The
+// table is created by the source of the schema if it is a schema.Creator,
+// else it is an in-memory (membtree) table added to the schema.  Its columns
+// are those of the select, typed by the first batch of rows selected.
+// In-memory tables are keyed by a row id so every selected row is kept.
+func (m *Create) createTableAs(cs *rel.SqlCreate) (int64, error) {
+
+	s := m.Ctx.Schema
+	if s == nil {
+		return 0, fmt.Errorf("must have schema")
+	}
+	if tbl, _ := s.Table(cs.Identity); tbl != nil {
+		if cs.IfNotExists {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("table %q already exists", cs.Identity)
+	}
+
+	job, err := BuildSqlJob(newChildContext(m.Ctx, cs.Select.String()))
+	if err != nil {
+		return 0, err
+	}
+	cols := selectColumns(job)
+	var proj []*rel.ResultColumn
+	if job.Ctx.Projection != nil && job.Ctx.Projection.Proj != nil {
+		proj = job.Ctx.Projection.Proj.Columns
+	}
+
+	// the table is created once the first batch is selected, to type its
+	// columns from the rows
+	var w *rowWriter
+	var first [][]driver.Value
+	create := func() error {
+		tbl, db, err := m.createTable(cs.Identity, cols, proj, first)
+		if err != nil {
+			return err
+		}
+		if w, err = newRowWriter(m.Ctx, db, tbl, tbl.Columns()); err != nil {
+			return err
+		}
+		for _, row := range first {
+			if err := w.put(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = streamStatement(job, m.SigChan(), func(vals []driver.Value) error {
+		if w != nil {
+			return w.put(vals)
+		}
+		first = append(first, vals)
+		if len(first) < InsertBatchSize {
+			return nil
+		}
+		return create()
+	})
+	if err == nil && w == nil {
+		err = create()
+	}
+	if w == nil {
+		return 0, err
+	}
+	if closer, ok := w.db.(schema.Conn); ok {
+		defer closer.Close()
+	}
+	if err == nil {
+		err = w.flush()
+	}
+	return w.ct, err
+}
+
+// createTable create the named table of cols in the schema, each column
+// typed by the first of rows that has a value for it unless the
+// projection has a more specific type than string.  Returns the table
+// and a conn to put rows to it.
+func (m *Create) createTable(name string, cols []string, proj []*rel.ResultColumn, rows [][]driver.Value) (*schema.Table, schema.ConnUpsert, error) {
+
+	s := m.Ctx.Schema
+	tbl := schema.NewTable(name)
+	for i, col := range cols {
+		vt := value.StringType
+		if i < len(proj) && proj[i].Type != value.UnknownType && proj[i].Type != value.StringType {
+			vt = proj[i].Type
+		} else {
+			for _, row := range rows {
+				if i < len(row) && row[i] != nil {
+					vt = value.NewValue(row[i]).Type()
+					break
+				}
+			}
+		}
+		tbl.AddFieldType(col, vt)
+	}
+	tbl.SetColumnsFromFields()
+
+	reg := schema.DefaultRegistry()
+	defer plan.DefaultStatementCache.Purge()
+	if creator, ok := s.DS.(schema.Creator); ok {
+		if err := creator.CreateTable(tbl); err != nil {
+			return nil, nil, err
+		}
+		if err := reg.SchemaRefresh(s.Name); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		db := membtree.NewStaticDataSource(tbl.Name, membtree.RowIdIndex, nil, tbl.Columns())
+		dbTbl, _ := db.Table(tbl.Name)
+		for _, fld := range tbl.Fields {
+			dbTbl.AddField(fld)
+		}
+		if err := reg.SchemaAddChild(s.Name, schema.NewSchemaSource(tbl.Name, db)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	created, err := s.Table(tbl.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := m.Ctx.OpenConn(tbl.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, ok := conn.(schema.ConnUpsert)
+	if !ok {
+		conn.Close()
+		return nil, nil, fmt.Errorf("%T does not implement required schema.Upsert for CREATE TABLE ... AS SELECT", conn)
+	}
+	return created, db, nil
+}
+
 // NewDrop creates new drop exec task.
 func NewDrop(ctx *plan.Context, p *plan.Drop) *Drop {
 	m := &Drop{
@@ -124,6 +274,7 @@ func (m *Drop) Run() error {
 	case lex.TokenSource, lex.TokenSchema, lex.TokenTable:
 
 		reg := schema.DefaultRegistry()
+		defer plan.DefaultStatementCache.Purge()
 		return reg.SchemaDrop(s.Name, cs.Identity, cs.Tok.T)
 
 	default:
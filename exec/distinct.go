@@ -0,0 +1,67 @@
+package exec
+
+import (
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Distinct)(nil)
+)
+
+// Distinct drops the duplicate rows of a SELECT DISTINCT, de-duplicating
+// the projected rows on a hash of their values.  Rows are streamed in the
+// order they arrive, the first of each set of duplicates.
+type Distinct struct {
+	*TaskBase
+	p    *plan.Distinct
+	seen map[string]struct{}
+}
+
+// NewDistinct create a distinct task.
+func NewDistinct(ctx *plan.Context, p *plan.Distinct) *Distinct {
+	m := &Distinct{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		seen:     make(map[string]struct{}),
+	}
+	m.Handler = m.distinctFilter()
+	return m
+}
+
+func (m *Distinct) distinctFilter() MessageHandler {
+	out := m.MessageOut()
+	return func(ctx *plan.Context, msg schema.Message) bool {
+		var key string
+		switch mt := msg.(type) {
+		case nil:
+			// the upstream reached its limit, pass on the shutdown
+		case *datasource.SqlDriverMessageMap:
+			key = setOpKey(mt.Values())
+		case *datasource.SqlDriverMessage:
+			key = setOpKey(mt.Vals)
+		default:
+			u.Errorf("could not de-duplicate msg: %T", msg)
+			return false
+		}
+		if msg != nil {
+			if _, dup := m.seen[key]; dup {
+				return true
+			}
+			m.seen[key] = struct{}{}
+		}
+		select {
+		case out <- msg:
+			if msg != nil {
+				m.track(msg)
+			}
+			return true
+		case <-m.SigChan():
+			return false
+		}
+	}
+}
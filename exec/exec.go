@@ -115,6 +115,38 @@ type (
 		WalkAlter(p *plan.Alter) (Task, error)
 	}
 
+	// UnionExecutor Executors that can run set operations (UNION,
+	// INTERSECT, EXCEPT) of selects, optional so as to not break existing
+	// Executors.
+	UnionExecutor interface {
+		WalkUnion(p *plan.Union) (Task, error)
+		WalkSetOp(p *plan.SetOp) (Task, error)
+	}
+
+	// WindowExecutor Executors that can run window functions, optional so
+	// as to not break existing Executors.
+	WindowExecutor interface {
+		WalkWindow(p *plan.Window) (Task, error)
+	}
+
+	// DistinctExecutor Executors that can run SELECT DISTINCT, optional so
+	// as to not break existing Executors.
+	DistinctExecutor interface {
+		WalkDistinct(p *plan.Distinct) (Task, error)
+	}
+
+	// PartitionExecutor Executors that can run parallel partition scans,
+	// optional so as to not break existing Executors.
+	PartitionExecutor interface {
+		WalkPartitionScan(p *plan.PartitionScan) (Task, error)
+	}
+
+	// ExplainExecutor Executors that can run EXPLAIN of a statement,
+	// optional so as to not break existing Executors.
+	ExplainExecutor interface {
+		WalkExplain(p *plan.Explain) (Task, error)
+	}
+
 	// ExecutorSource Sources can often do their own execution-plan for sub-select statements
 	// ie mysql can do its own (select, projection) mongo, es can as well
 	// - provide interface to allow passing down select planning to source
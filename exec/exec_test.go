@@ -3,7 +3,11 @@ package exec_test
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,11 +15,14 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/memdb"
 	"github.com/araddon/qlbridge/datasource/mockcsv"
 	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
 	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/schema"
 	"github.com/araddon/qlbridge/testutil"
+	"github.com/araddon/qlbridge/value"
 )
 
 func TestMain(m *testing.M) {
@@ -32,6 +39,85 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+// runQueryMsgs run the statement of ctx to completion returning its
+// result rows.
+func runQueryMsgs(t *testing.T, ctx *plan.Context) []*datasource.SqlDriverMessageMap {
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Equal(t, nil, err, ctx.Raw)
+	if err != nil {
+		return nil
+	}
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	assert.Equal(t, nil, job.Setup(), ctx.Raw)
+	// Run returns once every task, the result buffer included, is done
+	assert.Equal(t, nil, job.Run(), ctx.Raw)
+	rows := make([]*datasource.SqlDriverMessageMap, 0, len(msgs))
+	for _, msg := range msgs {
+		rows = append(rows, msg.(*datasource.SqlDriverMessageMap))
+	}
+	return rows
+}
+
+// runContextRows run the statement of ctx returning each row as the ":"
+// joined values of cols, NULL for missing or nil values.
+func runContextRows(t *testing.T, ctx *plan.Context, cols ...string) []string {
+	msgs := runQueryMsgs(t, ctx)
+	rows := make([]string, 0, len(msgs))
+	for _, sdm := range msgs {
+		row := make([]string, 0, len(cols))
+		for _, col := range cols {
+			v, _ := sdm.Get(col)
+			if v == nil || v.Nil() {
+				row = append(row, "NULL")
+			} else {
+				row = append(row, v.ToString())
+			}
+		}
+		rows = append(rows, strings.Join(row, ":"))
+	}
+	return rows
+}
+
+// runQueryRows run sqlText against the mock csv schema, see runContextRows.
+func runQueryRows(t *testing.T, sqlText string, cols ...string) []string {
+	return runContextRows(t, td.TestContext(sqlText), cols...)
+}
+
+// sortedRows sort rows of a query with no order by.
+func sortedRows(rows []string) []string {
+	sort.Strings(rows)
+	return rows
+}
+
+// queryRows query db returning the string form of each value of each row.
+func queryRows(t *testing.T, db *sql.DB, sqlText string) [][]string {
+	rows, err := db.Query(sqlText)
+	assert.Equal(t, nil, err, sqlText)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	assert.Equal(t, nil, err)
+	var out [][]string
+	for rows.Next() {
+		vals := make([]interface{}, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range vals {
+			dest[i] = &vals[i]
+		}
+		assert.Equal(t, nil, rows.Scan(dest...))
+		row := make([]string, len(vals))
+		for i, v := range vals {
+			row[i] = fmt.Sprintf("%v", v)
+		}
+		out = append(out, row)
+	}
+	assert.Equal(t, nil, rows.Err(), sqlText)
+	return out
+}
+
 func TestStatements(t *testing.T) {
 	testutil.RunTestSuite(t)
 }
@@ -137,6 +223,17 @@ func TestExecSelectWhere(t *testing.T) {
 	assert.True(t, row[4] == true)
 }
 
+func TestExecWhereConstant(t *testing.T) {
+
+	// constant filters are folded at plan time
+	assert.Equal(t, []string{}, runQueryRows(t, `SELECT email FROM users WHERE 1 = 2`, "email"))
+	assert.Equal(t, []string{}, runQueryRows(t, `SELECT email FROM users WHERE 1 = 2 AND email = "aaron@email.com"`, "email"))
+	assert.Equal(t, []string{"aaron@email.com"}, runQueryRows(t, `
+		SELECT email FROM users WHERE 1 = 1 AND email = "aaron@email.com"`, "email"))
+	assert.Equal(t, []string{"aaron@email.com", "bob@email.com", "not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT email FROM users WHERE 1 = 2 OR 2 > 1`, "email")))
+}
+
 func TestExecGroupBy(t *testing.T) {
 
 	sqlText := `
@@ -201,6 +298,60 @@ func TestExecGroupBy(t *testing.T) {
 	assert.True(t, int(row[0].(float64)) == 14, "expected avg(len(email))=14 but got %v", int(row[0].(float64)))
 }
 
+func TestExecGroupByAggs(t *testing.T) {
+
+	sqlText := `
+		select 
+	        user_id, min(price), max(price), count_distinct(item_id),
+	        group_concat(order_id, "|"), stddev(price), variance(price)
+	    FROM orders
+	    GROUP BY user_id
+	`
+	ctx := td.TestContext(sqlText)
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Equal(t, nil, err)
+
+	msgs := make([]schema.Message, 0)
+	resultWriter := exec.NewResultBuffer(ctx, &msgs)
+	job.RootTask.Add(resultWriter)
+
+	err = job.Setup()
+	assert.Equal(t, nil, err)
+	err = job.Run()
+	time.Sleep(time.Millisecond * 10)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(msgs), "should have grouped orders into 2 users")
+	var row []driver.Value
+	for _, msg := range msgs {
+		r := msg.(*datasource.SqlDriverMessageMap).Values()
+		if r[0].(string) == "9Ip1aKbeZe2njCDM" {
+			row = r
+		}
+	}
+	assert.Equal(t, 7, len(row), "%#v", row)
+	// price is a string column in mock csv, so min/max compare as strings
+	assert.Equal(t, "22.50", row[1])
+	assert.Equal(t, "37.50", row[2])
+	assert.Equal(t, int64(2), row[3])
+	assert.Equal(t, "1|2", row[4])
+	assert.Equal(t, 7.5, row[5])
+	assert.Equal(t, 56.25, row[6])
+}
+
+func TestAggregatorWrappers(t *testing.T) {
+	sum, avg, ct := exec.NewSum(nil, false), exec.NewAvg(nil, false), exec.NewCount(nil)
+	for _, v := range []value.Value{value.NewIntValue(2), value.NewNumberValue(4), value.NewNilValue()} {
+		sum.Do(v)
+		avg.Do(v)
+		ct.Do(v)
+	}
+	assert.Equal(t, 6.0, sum.Result())
+	assert.Equal(t, int64(2), ct.Result())
+	_, isPartial := exec.NewSum(nil, true).Result().(*exec.AggPartial)
+	assert.True(t, isPartial)
+	assert.Equal(t, 3.0, avg.Result())
+}
+
 func TestExecHaving(t *testing.T) {
 	sqlText := `
 		select 
@@ -232,6 +383,48 @@ func TestExecHaving(t *testing.T) {
 	assert.True(t, int(row[1].(int64)) == 2, "expected 2 orders for %v", row)
 }
 
+func TestExecOrderSpill(t *testing.T) {
+
+	runSpill := func(sqlText string) []string {
+		ctx := td.TestContext(sqlText)
+		ctx.MemoryLimit = 1
+		return runContextRows(t, ctx, "email")
+	}
+	expected := []string{"not_an_email_2", "bob@email.com", "aaron@email.com"}
+
+	// in memory sort
+	assert.Equal(t, expected, runQueryRows(t, "SELECT email FROM users ORDER BY email DESC", "email"))
+
+	// tiny memory limit forces every row to be spilled to its own run
+	// which then must be merged
+	assert.Equal(t, expected, runSpill("SELECT email FROM users ORDER BY email DESC"))
+
+	// top-n heap
+	assert.Equal(t, expected[:2], runSpill("SELECT email FROM users ORDER BY email DESC LIMIT 2"))
+	assert.Equal(t, []string{"aaron@email.com"}, runQueryRows(t, "SELECT email FROM users ORDER BY email ASC LIMIT 1", "email"))
+}
+
+func TestExecOrderTyped(t *testing.T) {
+
+	mockcsv.LoadTable(mockcsv.SchemaName, "scores", "id,name,score\n1,bob,9\n2,Alice,100\n3,carl,\n4,Dan,10")
+
+	// csv values are strings, so score sorts lexically, "" first
+	assert.Equal(t, []string{"carl", "Dan", "Alice", "bob"}, runQueryRows(t, "SELECT name FROM scores ORDER BY score", "name"))
+
+	// typed numeric ordering, the empty score can't be converted so is null,
+	// nulls are smallest by default
+	assert.Equal(t, []string{"carl", "bob", "Dan", "Alice"}, runQueryRows(t, "SELECT name FROM scores ORDER BY toint(score)", "name"))
+	assert.Equal(t, []string{"Alice", "Dan", "bob", "carl"}, runQueryRows(t, "SELECT name FROM scores ORDER BY toint(score) DESC", "name"))
+	assert.Equal(t, []string{"bob", "Dan", "Alice", "carl"}, runQueryRows(t, "SELECT name FROM scores ORDER BY toint(score) ASC NULLS LAST", "name"))
+	assert.Equal(t, []string{"carl", "Alice", "Dan", "bob"}, runQueryRows(t, "SELECT name FROM scores ORDER BY toint(score) DESC NULLS FIRST", "name"))
+	assert.Equal(t, []string{"bob", "Dan"}, runQueryRows(t, "SELECT name FROM scores ORDER BY toint(score) NULLS LAST LIMIT 2", "name"))
+
+	// collation
+	assert.Equal(t, []string{"Alice", "Dan", "bob", "carl"}, runQueryRows(t, "SELECT name FROM scores ORDER BY name", "name"))
+	assert.Equal(t, []string{"Alice", "bob", "carl", "Dan"}, runQueryRows(t, "SELECT name FROM scores ORDER BY name COLLATE nocase", "name"))
+	assert.Equal(t, []string{"Dan", "carl", "bob", "Alice"}, runQueryRows(t, "SELECT name FROM scores ORDER BY name COLLATE nocase DESC", "name"))
+}
+
 type UserEvent struct {
 	Id     string
 	UserId string
@@ -440,6 +633,131 @@ func TestExecUpdateAndUpsert(t *testing.T) {
 	assert.True(t, ue1.Date.Year() == 2013, "Upsert should have changed date")
 }
 
+func TestExecUpdateScan(t *testing.T) {
+
+	mockcsv.LoadTable(mockcsv.SchemaName, "user_hits",
+		"id,user_id,hits,rank\n1,abcd,1,3\n2,abcd,5,1\n3,xyz,2,2")
+
+	sqlDb, err := sql.Open("qlbridge", "mockcsv")
+	assert.Equal(t, nil, err)
+	defer sqlDb.Close()
+
+	update := func(sqlText string) int64 {
+		result, err := sqlDb.Exec(sqlText)
+		assert.Equal(t, nil, err, sqlText)
+		ct, err := result.RowsAffected()
+		assert.Equal(t, nil, err)
+		return ct
+	}
+	hits := func() map[string]int64 {
+		rows, err := sqlDb.Query("SELECT id, hits FROM user_hits")
+		assert.Equal(t, nil, err)
+		defer rows.Close()
+		vals := make(map[string]int64)
+		for rows.Next() {
+			var id string
+			var ct int64
+			assert.Equal(t, nil, rows.Scan(&id, &ct))
+			vals[id] = ct
+		}
+		return vals
+	}
+
+	// values are evaluated against each row they update
+	assert.Equal(t, int64(2), update(`UPDATE user_hits SET hits = hits + 1 WHERE user_id = "abcd"`))
+	assert.Equal(t, map[string]int64{"1": 2, "2": 6, "3": 2}, hits())
+
+	// no where updates every row
+	assert.Equal(t, int64(3), update(`UPDATE user_hits SET hits = hits * 2`))
+	assert.Equal(t, map[string]int64{"1": 4, "2": 12, "3": 4}, hits())
+
+	// order by, limit the rows updated
+	assert.Equal(t, int64(1), update(`UPDATE user_hits SET hits = 0 ORDER BY rank ASC LIMIT 1`))
+	assert.Equal(t, map[string]int64{"1": 4, "2": 0, "3": 4}, hits())
+	assert.Equal(t, int64(0), update(`UPDATE user_hits SET hits = 0 WHERE user_id = "none"`))
+
+	_, err = sqlDb.Exec(`UPDATE user_hits SET id = 7 WHERE user_id = "xyz"`)
+	assert.NotEqual(t, nil, err, "the key column is not updatable")
+	_, err = sqlDb.Exec(`UPDATE user_hits SET not_a_column = 7`)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestExecInsertSelect(t *testing.T) {
+
+	mockcsv.LoadTable(mockcsv.SchemaName, "hits_src",
+		"id,user_id,hits,score\n1,abcd,1,2.5\n2,abcd,5,1.5\n3,xyz,2,4")
+	mockcsv.LoadTable(mockcsv.SchemaName, "hits_dest", "id,user_id,hits,score\n0,none,0,0")
+
+	sqlDb, err := sql.Open("qlbridge", "mockcsv")
+	assert.Equal(t, nil, err)
+	defer sqlDb.Close()
+
+	put := func(sqlText string) int64 {
+		result, err := sqlDb.Exec(sqlText)
+		assert.Equal(t, nil, err, sqlText)
+		if err != nil {
+			return -1
+		}
+		ct, err := result.RowsAffected()
+		assert.Equal(t, nil, err)
+		return ct
+	}
+	rows := func(sqlText string) map[string]string {
+		rows, err := sqlDb.Query(sqlText)
+		assert.Equal(t, nil, err, sqlText)
+		defer rows.Close()
+		vals := make(map[string]string)
+		for rows.Next() {
+			var id, v string
+			assert.Equal(t, nil, rows.Scan(&id, &v))
+			vals[id] = v
+		}
+		return vals
+	}
+
+	// column list, in other than the table's column order
+	assert.Equal(t, int64(2), put(`INSERT INTO hits_dest (user_id, id) SELECT user_id, id FROM hits_src WHERE user_id = "abcd"`))
+	assert.Equal(t, map[string]string{"0": "none", "1": "abcd", "2": "abcd"},
+		rows("SELECT id, user_id FROM hits_dest"))
+
+	// no column list is the table's columns, values are coerced to them
+	// with batches smaller than the rows selected
+	batchSize := exec.InsertBatchSize
+	exec.InsertBatchSize = 2
+	defer func() { exec.InsertBatchSize = batchSize }()
+	assert.Equal(t, int64(3), put(`INSERT INTO hits_dest SELECT id, user_id, hits * 2, score FROM hits_src`))
+	assert.Equal(t, map[string]string{"0": "0", "1": "2", "2": "10", "3": "4"},
+		rows("SELECT id, hits FROM hits_dest"))
+
+	_, err = sqlDb.Exec(`INSERT INTO hits_dest (id, user_id) SELECT id FROM hits_src`)
+	assert.NotEqual(t, nil, err, "column count mismatch")
+	_, err = sqlDb.Exec(`INSERT INTO hits_dest (id, not_a_column) SELECT id, user_id FROM hits_src`)
+	assert.NotEqual(t, nil, err)
+	_, err = sqlDb.Exec(`INSERT INTO hits_dest (id, hits) SELECT id, user_id FROM hits_src`)
+	assert.NotEqual(t, nil, err, "user_id is not an int")
+
+	// mockcsv can not create tables so the table is in-memory
+	assert.Equal(t, int64(2), put(`CREATE TABLE user_hits_ct AS SELECT user_id, count(*) AS ct, sum(hits) AS hits FROM hits_src GROUP BY user_id`))
+	assert.Equal(t, map[string]string{"abcd": "2", "xyz": "1"},
+		rows("SELECT user_id, ct FROM user_hits_ct"))
+	assert.Equal(t, map[string]string{"abcd": "6"},
+		rows("SELECT user_id, hits FROM user_hits_ct WHERE hits > 5"))
+
+	// rows with the same first column value are all kept
+	assert.Equal(t, int64(3), put(`CREATE TABLE user_hits_all AS SELECT user_id, hits FROM hits_src`))
+	assert.Equal(t, map[string]string{"abcd": "2", "xyz": "1"},
+		rows("SELECT user_id, count(*) FROM user_hits_all GROUP BY user_id"))
+	put(`DROP TABLE user_hits_all`)
+
+	assert.Equal(t, int64(0), put(`CREATE TABLE IF NOT EXISTS user_hits_ct AS SELECT user_id FROM hits_src`))
+	_, err = sqlDb.Exec(`CREATE TABLE user_hits_ct AS SELECT user_id FROM hits_src`)
+	assert.NotEqual(t, nil, err, "table exists")
+
+	put(`DROP TABLE user_hits_ct`)
+	_, err = sqlDb.Query("SELECT user_id FROM user_hits_ct")
+	assert.NotEqual(t, nil, err)
+}
+
 func TestExecDelete(t *testing.T) {
 
 	// By "Loading" table we force it to exist in this non DDL mock store
@@ -520,3 +838,624 @@ func testSubselect(t *testing.T) {
 	assert.True(t, err == nil, "no error %v", err)
 	assert.True(t, len(msgs) == 1, "should have filtered out 2 messages")
 }
+
+func TestExecJoinSpill(t *testing.T) {
+
+	runJoin := func(sqlText string, memLimit int64) []string {
+		ctx := td.TestContext(sqlText)
+		ctx.MemoryLimit = memLimit
+		return sortedRows(runContextRows(t, ctx, "user_id", "order_id"))
+	}
+	sqlText := `SELECT u.user_id, o.order_id FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id`
+	expected := []string{"9Ip1aKbeZe2njCDM:1", "9Ip1aKbeZe2njCDM:2"}
+	assert.Equal(t, expected, runJoin(sqlText, 0))
+	// tiny memory limit forces the build side to be partitioned to disk
+	assert.Equal(t, expected, runJoin(sqlText, 1))
+
+	sqlText = `SELECT u.user_id, o.order_id FROM users AS u
+		FULL OUTER JOIN orders AS o ON u.user_id = o.user_id`
+	expected = []string{"9Ip1aKbeZe2njCDM:1", "9Ip1aKbeZe2njCDM:2",
+		"NULL:3", "hT2impsOPUREcVPc:NULL", "hT2impsabc345c:NULL"}
+	assert.Equal(t, expected, runJoin(sqlText, 0))
+	assert.Equal(t, expected, runJoin(sqlText, 1))
+}
+
+func TestExecOuterJoinNulls(t *testing.T) {
+
+	// anti-join, the users with no orders
+	assert.Equal(t, []string{"bob@email.com:NULL", "not_an_email_2:NULL"}, sortedRows(runQueryRows(t, `
+		SELECT u.email, o.order_id FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.order_id IS NULL`, "email", "order_id")))
+	assert.Equal(t, []string{"aaron@email.com:1", "aaron@email.com:2"}, sortedRows(runQueryRows(t, `
+		SELECT u.email, o.order_id FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.order_id IS NOT NULL`, "email", "order_id")))
+
+	// seek join, and the missing left side of a full outer join
+	assert.Equal(t, []string{"NULL:3"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		LEFT JOIN users AS u ON o.user_id = u.user_id
+		WHERE u.email IS NULL`, "email", "order_id")))
+	assert.Equal(t, []string{"NULL:3"}, sortedRows(runQueryRows(t, `
+		SELECT u.email, o.order_id FROM users AS u
+		FULL OUTER JOIN orders AS o ON u.user_id = o.user_id
+		WHERE u.email IS NULL`, "email", "order_id")))
+}
+
+func TestExecJoinSeek(t *testing.T) {
+
+	// users are looked up by their user_id key for each order
+	assert.Equal(t, []string{"1:aaron@email.com", "2:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		INNER JOIN users AS u ON o.user_id = u.user_id`, "order_id", "email")))
+	assert.Equal(t, []string{"1:aaron@email.com", "2:aaron@email.com", "3:NULL"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		LEFT JOIN users AS u ON o.user_id = u.user_id`, "order_id", "email")))
+	assert.Equal(t, []string{}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		INNER JOIN users AS u ON o.user_id = u.user_id
+		WHERE u.email = "bob@email.com"`, "order_id", "email")))
+}
+
+func TestExecJoinResidual(t *testing.T) {
+
+	// hash join on user_id, price is a residual predicate
+	assert.Equal(t, []string{"2:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id AND o.price > 30`, "order_id", "email")))
+	assert.Equal(t, []string{"2:aaron@email.com", "NULL:bob@email.com", "NULL:not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id AND o.price > 30`, "order_id", "email")))
+
+	// seek join on the users key, with residual
+	assert.Equal(t, []string{"1:NULL", "2:aaron@email.com", "3:NULL"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		LEFT JOIN users AS u ON o.user_id = u.user_id AND o.price > 30`, "order_id", "email")))
+
+	// no equality to hash on, nested loop
+	assert.Equal(t, []string{"2:aaron@email.com", "2:bob@email.com", "2:not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON o.price > 30`, "order_id", "email")))
+	assert.Equal(t, []string{"1:bob@email.com", "2:bob@email.com", "3:bob@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON u.user_id != o.user_id AND u.email = "bob@email.com"`, "order_id", "email")))
+
+	// inequality between the sides, csv values compared as the column types
+	assert.Equal(t, []string{"1:bob@email.com", "1:not_an_email_2", "2:bob@email.com", "2:not_an_email_2",
+		"3:bob@email.com", "3:not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON u.referral_count < o.item_count`, "order_id", "email")))
+	assert.Equal(t, []string{"1:aaron@email.com", "2:aaron@email.com", "3:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON o.price < u.referral_count`, "order_id", "email")))
+	assert.Equal(t, []string{"1:bob@email.com", "1:not_an_email_2", "2:bob@email.com", "2:not_an_email_2",
+		"3:bob@email.com", "3:not_an_email_2", "NULL:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		LEFT JOIN orders AS o ON u.referral_count < o.item_count`, "order_id", "email")))
+}
+
+func TestExecJoinPushDown(t *testing.T) {
+
+	// predicates pushed to, and columns pruned from, both sources
+	assert.Equal(t, []string{"1:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.price < 30 AND u.referral_count > 10 * 2`, "order_id", "email")))
+	assert.Equal(t, []string{"2:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.price >= 30 AND u.email LIKE "aaron%"`, "order_id", "email")))
+
+	// the preserved side of an outer join is filtered before the join,
+	// the null-supplying side after
+	assert.Equal(t, []string{"1:aaron@email.com", "2:aaron@email.com", "NULL:bob@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id
+		WHERE u.referral_count > 1 AND u.email LIKE "%@email.com"`, "order_id", "email")))
+	assert.Equal(t, []string{"1:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.price < 30`, "order_id", "email")))
+
+	// seek join, pushed to the sought source
+	assert.Equal(t, []string{"1:NULL", "2:NULL", "3:NULL"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		LEFT JOIN users AS u ON o.user_id = u.user_id AND u.referral_count < 20`, "order_id", "email")))
+	assert.Equal(t, []string{"2:aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT o.order_id, u.email FROM orders AS o
+		INNER JOIN users AS u ON o.user_id = u.user_id
+		WHERE u.referral_count > 20 AND o.price > 30`, "order_id", "email")))
+
+	// the identity projection of optpairs is eliminated
+	mockcsv.LoadTable(mockcsv.SchemaName, "optpairs", "order_id,user_id\n7,hT2impsOPUREcVPc\n8,nobody")
+	assert.Equal(t, []string{"7:bob@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT s.order_id, s.user_id, u.email FROM optpairs AS s
+		INNER JOIN users AS u ON s.user_id = u.user_id`, "order_id", "email")))
+}
+
+func TestExecWhereSubQuery(t *testing.T) {
+
+	// un-correlated IN, materialized once
+	assert.Equal(t, []string{"aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT email FROM users WHERE user_id IN (SELECT user_id FROM orders)`, "email")))
+	assert.Equal(t, []string{"bob@email.com", "not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT email FROM users WHERE user_id NOT IN (SELECT user_id FROM orders)`, "email")))
+
+	// correlated EXISTS decorrelated to a keyed lookup (semi/anti join)
+	assert.Equal(t, []string{"aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT u.email FROM users AS u
+		WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id)`, "u.email")))
+	assert.Equal(t, []string{"bob@email.com", "not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT u.email FROM users AS u
+		WHERE NOT EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id AND o.price > 30)`, "u.email")))
+
+	// scalar, un-correlated and correlated (count of no rows is 0)
+	assert.Equal(t, []string{"aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT email FROM users WHERE referral_count > (SELECT count(*) FROM orders) * 5`, "email")))
+	assert.Equal(t, []string{"bob@email.com", "not_an_email_2"}, sortedRows(runQueryRows(t, `
+		SELECT u.email FROM users AS u
+		WHERE (SELECT count(*) FROM orders AS o WHERE o.user_id = u.user_id) = 0`, "u.email")))
+
+	// sub-query referencing the outer rows in a join
+	assert.Equal(t, []string{"aaron@email.com", "aaron@email.com"}, sortedRows(runQueryRows(t, `
+		SELECT u.email FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.order_id IN (SELECT order_id FROM orders WHERE user_id = "9Ip1aKbeZe2njCDM")`, "email")))
+}
+
+func TestExecLimitOffset(t *testing.T) {
+	// pages of a sorted result
+	assert.Equal(t, []string{"aaron@email.com", "bob@email.com"}, runQueryRows(t, "SELECT email FROM users ORDER BY email ASC LIMIT 2", "email"))
+	assert.Equal(t, []string{"not_an_email_2"}, runQueryRows(t, "SELECT email FROM users ORDER BY email ASC LIMIT 2 OFFSET 2", "email"))
+	assert.Equal(t, []string{"bob@email.com"}, runQueryRows(t, "SELECT email FROM users ORDER BY email ASC LIMIT 1, 1", "email"))
+	assert.Equal(t, []string{"bob@email.com", "not_an_email_2"}, runQueryRows(t, "SELECT email FROM users ORDER BY email ASC OFFSET 1", "email"))
+	assert.Equal(t, []string{}, runQueryRows(t, "SELECT email FROM users ORDER BY email ASC LIMIT 2 OFFSET 5", "email"))
+
+	// without ORDER BY and after GROUP BY
+	assert.Equal(t, 1, len(runQueryRows(t, "SELECT email FROM users LIMIT 1 OFFSET 2", "email")))
+	assert.Equal(t, 1, len(runQueryRows(t, "SELECT user_id, count(*) FROM orders GROUP BY user_id LIMIT 1", "user_id")))
+	assert.Equal(t, 1, len(runQueryRows(t, "SELECT user_id, count(*) FROM orders GROUP BY user_id OFFSET 1", "user_id")))
+	assert.Equal(t, []string{"abcabcabc"}, runQueryRows(t, "SELECT user_id, count(*) FROM orders GROUP BY user_id ORDER BY user_id DESC LIMIT 1", "user_id"))
+	assert.Equal(t, []string{"9Ip1aKbeZe2njCDM"}, runQueryRows(t, "SELECT user_id, count(*) FROM orders GROUP BY user_id ORDER BY user_id DESC LIMIT 1 OFFSET 1", "user_id"))
+}
+
+func TestExecSetOperations(t *testing.T) {
+	assert.Equal(t, []string{"hT2impsabc345c", "9Ip1aKbeZe2njCDM", "hT2impsOPUREcVPc", "9Ip1aKbeZe2njCDM", "abcabcabc", "9Ip1aKbeZe2njCDM"},
+		runQueryRows(t, "SELECT user_id FROM users UNION ALL SELECT user_id FROM orders", "user_id"))
+	assert.Equal(t, []string{"9Ip1aKbeZe2njCDM", "abcabcabc", "hT2impsOPUREcVPc", "hT2impsabc345c"},
+		runQueryRows(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id", "user_id"))
+	assert.Equal(t, []string{"9Ip1aKbeZe2njCDM"},
+		runQueryRows(t, "SELECT user_id FROM users INTERSECT SELECT user_id FROM orders", "user_id"))
+	assert.Equal(t, []string{"hT2impsabc345c", "hT2impsOPUREcVPc"},
+		runQueryRows(t, "SELECT user_id FROM users EXCEPT SELECT user_id FROM orders", "user_id"))
+	// multiset, both 9Ip1aKbeZe2njCDM orders are kept
+	assert.Equal(t, []string{"9Ip1aKbeZe2njCDM", "abcabcabc", "9Ip1aKbeZe2njCDM"},
+		runQueryRows(t, "SELECT user_id FROM orders INTERSECT ALL SELECT user_id FROM orders", "user_id"))
+	// evaluated left to right, the order by/limit apply to the combined result
+	assert.Equal(t, []string{"abcabcabc"},
+		runQueryRows(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders EXCEPT SELECT user_id FROM users ORDER BY user_id LIMIT 1", "user_id"))
+	assert.Equal(t, []string{"hT2impsOPUREcVPc", "abcabcabc"},
+		runQueryRows(t, "SELECT user_id FROM users UNION SELECT user_id FROM orders ORDER BY user_id DESC LIMIT 2 OFFSET 1", "user_id"))
+
+	// order by, limit only allowed on the last select
+	_, err := exec.BuildSqlJob(td.TestContext("SELECT user_id FROM users LIMIT 1 UNION SELECT user_id FROM orders"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestExecExplain(t *testing.T) {
+
+	// task name -> row
+	byTask := func(rows []*datasource.SqlDriverMessageMap) map[string]*datasource.SqlDriverMessageMap {
+		tasks := make(map[string]*datasource.SqlDriverMessageMap)
+		for _, row := range rows {
+			task, _ := row.Get("task")
+			tasks[task.ToString()] = row
+		}
+		return tasks
+	}
+
+	rows := runQueryMsgs(t, td.TestContext(`EXPLAIN SELECT u.user_id, o.item_id FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id WHERE o.price > 30`))
+	tasks := byTask(rows)
+	assert.Equal(t, int64(1), rows[0].Vals[0])
+	assert.Equal(t, int64(0), rows[0].Vals[1])
+	for _, task := range []string{"source", "joinseek", "joinkey", "where", "projection"} {
+		assert.NotEqual(t, nil, tasks[task], "missing %q in %v", task, tasks)
+	}
+	// the smaller orders are read first, seeking the users by primary key
+	detail, _ := tasks["joinseek"].Get("detail")
+	assert.True(t, strings.Contains(detail.ToString(), "strategy=seek table=users"), detail.ToString())
+	// the where was pushed below the join to the orders source
+	detail, _ = tasks["where"].Get("detail")
+	assert.Equal(t, "filter=price > 30", detail.ToString())
+	_, hasRows := rows[0].Get("rows")
+	assert.False(t, hasRows)
+
+	// analyze runs the statement, the final projection sent the result rows
+	rows = runQueryMsgs(t, td.TestContext("EXPLAIN ANALYZE SELECT user_id FROM orders WHERE price > 30"))
+	tasks = byTask(rows)
+	ct, _ := tasks["source"].Get("rows")
+	assert.Equal(t, int64(3), ct.Value())
+	ct, _ = tasks["projection"].Get("rows")
+	assert.Equal(t, int64(1), ct.Value())
+	ct, _ = rows[0].Get("rows")
+	assert.Equal(t, int64(1), ct.Value())
+	bytes, _ := tasks["source"].Get("bytes")
+	assert.True(t, bytes.Value().(int64) > 0)
+
+	rows = runQueryMsgs(t, td.TestContext("EXPLAIN FORMAT=JSON SELECT user_id FROM orders WHERE price > 30"))
+	assert.Equal(t, 1, len(rows))
+	var tree struct {
+		Task     string
+		Children []struct {
+			Task string
+		}
+	}
+	assert.Equal(t, nil, json.Unmarshal([]byte(rows[0].Vals[0].(string)), &tree))
+	assert.Equal(t, "sequential", tree.Task)
+	assert.Equal(t, 2, len(tree.Children))
+	assert.Equal(t, "projection", tree.Children[1].Task)
+}
+
+// baseExecutor an Executor implemented without any of the optional walks.
+type baseExecutor struct {
+	exec.Executor
+}
+
+func TestExecOptionalExecutor(t *testing.T) {
+	build := func(sqlText string) error {
+		ctx := td.TestContext(sqlText)
+		job := exec.NewExecutor(ctx, plan.NewPlanner(ctx))
+		job.Executor = &baseExecutor{job}
+		_, err := exec.BuildSqlJobPlanned(job.Planner, job.Executor, ctx)
+		return err
+	}
+	assert.Equal(t, nil, build(`SELECT user_id FROM users`))
+	assert.Equal(t, exec.ErrNotImplemented, build(`EXPLAIN SELECT user_id FROM users`))
+	assert.Equal(t, exec.ErrNotImplemented, build(`SELECT user_id FROM users UNION SELECT user_id FROM orders`))
+	assert.Equal(t, exec.ErrNotImplemented, build(`SELECT user_id, row_number() OVER (ORDER BY user_id) AS rn FROM users`))
+	assert.Equal(t, exec.ErrNotImplemented, build(`SELECT DISTINCT user_id FROM orders`))
+}
+
+func TestExecAnalyze(t *testing.T) {
+	mockcsv.LoadTable(mockcsv.SchemaName, "analyzed", "id,name,score\n1,a,10\n2,b,\n3,b,30\n4,c,40")
+
+	ctx := td.TestContext("ANALYZE TABLE analyzed")
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Equal(t, nil, err)
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	assert.Equal(t, nil, job.Setup())
+	assert.Equal(t, nil, job.Run())
+	assert.Equal(t, 0, len(msgs))
+
+	tbl, err := ctx.Schema.Table("analyzed")
+	assert.Equal(t, nil, err)
+	stats := tbl.Stats()
+	assert.NotEqual(t, nil, stats)
+	assert.Equal(t, int64(4), stats.RowCt)
+	assert.Equal(t, int64(4), stats.Columns["id"].NDV)
+	assert.Equal(t, int64(3), stats.Columns["name"].NDV)
+	assert.Equal(t, "a", stats.Columns["name"].Min)
+	assert.Equal(t, "c", stats.Columns["name"].Max)
+	assert.Equal(t, 0.25, stats.Columns["score"].NullFrac)
+
+	ctx = td.TestContext("ANALYZE TABLE not_a_table")
+	job, err = exec.BuildSqlJob(ctx)
+	assert.Equal(t, nil, err)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	assert.Equal(t, nil, job.Setup())
+	assert.NotEqual(t, nil, job.Run())
+}
+
+// partSource a memdb table split into partitions, each scanned by
+// its own memdb.
+type partSource struct {
+	*memdb.MemDb
+	parts []*memdb.MemDb
+}
+
+func newPartSource(name string, cols []string, parts ...[][]driver.Value) (*partSource, error) {
+	var all [][]driver.Value
+	m := &partSource{}
+	for _, rows := range parts {
+		part, err := memdb.NewMemDbData(name, rows, cols)
+		if err != nil {
+			return nil, err
+		}
+		m.parts = append(m.parts, part)
+		all = append(all, rows...)
+	}
+	mdb, err := memdb.NewMemDbData(name, all, cols)
+	if err != nil {
+		return nil, err
+	}
+	m.MemDb = mdb
+	return m, nil
+}
+
+func (m *partSource) Partitions() []*schema.Partition {
+	parts := make([]*schema.Partition, len(m.parts))
+	for i := range m.parts {
+		parts[i] = &schema.Partition{Id: fmt.Sprintf("p%d", i)}
+	}
+	return parts
+}
+func (m *partSource) PartitionSource(p *schema.Partition) (schema.Conn, error) {
+	var i int
+	if _, err := fmt.Sscanf(p.Id, "p%d", &i); err != nil {
+		return nil, err
+	}
+	return m.parts[i].Open(p.Id)
+}
+
+func TestExecPartitionScan(t *testing.T) {
+	src, err := newPartSource("sales", []string{"id", "region", "amount"},
+		[][]driver.Value{{int64(1), "east", int64(10)}, {int64(2), "west", int64(20)}},
+		[][]driver.Value{{int64(3), "east", int64(30)}, {int64(4), "east", int64(5)}},
+		[][]driver.Value{{int64(5), "west", int64(40)}},
+	)
+	assert.Equal(t, nil, err)
+	err = schema.RegisterSourceAsSchema("partsales", src)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("qlbridge", "partsales")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	type agg struct {
+		region string
+		ct     int64
+		sum    float64
+		avg    float64
+	}
+	rows, err := db.Query(`SELECT region, count(*), sum(amount), avg(amount) FROM sales
+		WHERE amount > 5 GROUP BY region`)
+	assert.Equal(t, nil, err)
+	var aggs []agg
+	for rows.Next() {
+		var a agg
+		assert.Equal(t, nil, rows.Scan(&a.region, &a.ct, &a.sum, &a.avg))
+		aggs = append(aggs, a)
+	}
+	assert.Equal(t, nil, rows.Err())
+	rows.Close()
+	sort.Slice(aggs, func(i, j int) bool { return aggs[i].region < aggs[j].region })
+	assert.Equal(t, []agg{{"east", 2, 40, 20}, {"west", 2, 60, 30}}, aggs)
+
+	// rows of each partition are merged
+	rows, err = db.Query(`SELECT id FROM sales WHERE region = "east" ORDER BY id`)
+	assert.Equal(t, nil, err)
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		assert.Equal(t, nil, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	rows.Close()
+	assert.Equal(t, []int64{1, 3, 4}, ids)
+
+	// each partition is a source, partially grouped before the merge
+	rows, err = db.Query(`EXPLAIN ANALYZE SELECT count(*) FROM sales`)
+	assert.Equal(t, nil, err)
+	tasks := make(map[string][]string)
+	var merged int64
+	for rows.Next() {
+		var id, parentID, depth, ct, bytes int64
+		var task string
+		var detail sql.NullString
+		var wall float64
+		assert.Equal(t, nil, rows.Scan(&id, &parentID, &depth, &task, &detail, &ct, &bytes, &wall))
+		tasks[task] = append(tasks[task], detail.String)
+		if task == "partitionmerge" {
+			merged = ct
+		}
+	}
+	rows.Close()
+	assert.Equal(t, []string{"partitions=3"}, tasks["partitionmerge"])
+	assert.Equal(t, 3, len(tasks["source"]), "%v", tasks)
+	assert.True(t, strings.Contains(tasks["source"][2], "partition=p2"), "%v", tasks["source"])
+	assert.Equal(t, []string{"group= partial", "group= partial", "group= partial"}, tasks["groupby"])
+	assert.Equal(t, 1, len(tasks["groupby-final"]))
+	// a partial row per partition
+	assert.Equal(t, int64(3), merged)
+
+	// partition scans are optional for executors
+	ctx := plan.NewContext(`SELECT count(*) FROM sales`)
+	ctx.Schema, _ = schema.DefaultRegistry().Schema("partsales")
+	job := exec.NewExecutor(ctx, plan.NewPlanner(ctx))
+	job.Executor = &baseExecutor{job}
+	_, err = exec.BuildSqlJobPlanned(job.Planner, job.Executor, ctx)
+	assert.Equal(t, exec.ErrNotImplemented, err)
+
+	// a partition count on a source that can't open a partition is scanned once
+	mockcsv.LoadTable(mockcsv.SchemaName, "partcsv", "id,region\n1,east\n2,west\n3,east")
+	tbl, err := td.TestContext("").Schema.Table("partcsv")
+	assert.Equal(t, nil, err)
+	tbl.PartitionCt = 3
+	assert.Equal(t, []string{"1", "2", "3"}, sortedRows(runQueryRows(t, `SELECT id FROM partcsv`, "id")))
+	assert.Equal(t, []string{"3"}, runQueryRows(t, `SELECT count(*) AS ct FROM partcsv`, "ct"))
+}
+
+func TestExecWindow(t *testing.T) {
+	src, err := newPartSource("sales", []string{"id", "region", "amount"},
+		[][]driver.Value{{int64(1), "east", int64(10)}, {int64(2), "west", int64(20)}, {int64(3), "east", int64(30)}},
+		[][]driver.Value{{int64(4), "east", int64(10)}, {int64(5), "west", int64(40)}, {int64(6), "east", int64(50)}},
+	)
+	assert.Equal(t, nil, err)
+	err = schema.RegisterSourceAsSchema("windowsales", src)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("qlbridge", "windowsales")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	// ranking, the source's 2 partitions are merged before the window
+	assert.Equal(t, [][]string{
+		{"1", "3", "1", "1"},
+		{"2", "2", "1", "1"},
+		{"3", "2", "3", "2"},
+		{"4", "4", "1", "1"},
+		{"5", "1", "2", "2"},
+		{"6", "1", "4", "3"},
+	}, queryRows(t, db, `SELECT id,
+		row_number() OVER (PARTITION BY region ORDER BY amount DESC) AS rn,
+		rank() OVER (PARTITION BY region ORDER BY amount) AS rk,
+		dense_rank() OVER (PARTITION BY region ORDER BY amount) AS drk
+		FROM sales ORDER BY id`))
+
+	// offsets
+	assert.Equal(t, [][]string{
+		{"1", "<nil>", "30", "10"},
+		{"2", "<nil>", "40", "20"},
+		{"3", "10", "10", "10"},
+		{"4", "30", "50", "10"},
+		{"5", "20", "0", "20"},
+		{"6", "10", "0", "10"},
+	}, queryRows(t, db, `SELECT id,
+		lag(amount) OVER (PARTITION BY region ORDER BY id),
+		lead(amount, 1, 0) OVER (PARTITION BY region ORDER BY id),
+		first_value(amount) OVER (PARTITION BY region ORDER BY id)
+		FROM sales ORDER BY id`))
+
+	// aggregates over the default frame (rows up to the current one), ROWS
+	// frame and the whole partition
+	assert.Equal(t, [][]string{
+		{"1", "10", "30", "25"},
+		{"2", "20", "60", "30"},
+		{"3", "40", "60", "25"},
+		{"4", "50", "80", "25"},
+		{"5", "60", "100", "30"},
+		{"6", "100", "90", "25"},
+	}, queryRows(t, db, `SELECT id,
+		sum(amount) OVER (PARTITION BY region ORDER BY id) AS running,
+		sum(amount) OVER (ORDER BY id ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING) AS moving,
+		avg(amount) OVER (PARTITION BY region) AS regionavg
+		FROM sales ORDER BY id`))
+
+	// RANGE frames include the peers, and rows within the offset
+	assert.Equal(t, [][]string{
+		{"1", "20", "40"},
+		{"2", "40", "70"},
+		{"3", "70", "90"},
+		{"4", "20", "40"},
+		{"5", "110", "120"},
+		{"6", "160", "90"},
+	}, queryRows(t, db, `SELECT id,
+		sum(amount) OVER (ORDER BY amount) AS peers,
+		sum(amount) OVER (ORDER BY amount RANGE BETWEEN 10 PRECEDING AND 10 FOLLOWING) AS nearby
+		FROM sales ORDER BY id`))
+
+	// ordered by the alias of the window column
+	assert.Equal(t, [][]string{{"6", "1"}, {"5", "2"}, {"3", "3"}},
+		queryRows(t, db, `SELECT id, row_number() OVER (ORDER BY amount DESC, id) AS rn FROM sales ORDER BY rn LIMIT 3`))
+
+	_, err = db.Query(`SELECT region, count(*) OVER () FROM sales GROUP BY region`)
+	assert.NotEqual(t, nil, err)
+
+	// not a window function, or an unsupported window
+	_, err = db.Query(`SELECT id, tolower(region) OVER () FROM sales`)
+	assert.NotEqual(t, nil, err)
+	_, err = db.Query(`SELECT id, sum(amount) OVER (ORDER BY region, id RANGE 1 PRECEDING) FROM sales`)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestExecCte(t *testing.T) {
+	src, err := newPartSource("org", []string{"id", "parent_id", "name"},
+		[][]driver.Value{
+			{int64(1), int64(0), "ceo"},
+			{int64(2), int64(1), "cto"},
+			{int64(3), int64(1), "cfo"},
+			{int64(4), int64(2), "dev"},
+			{int64(5), int64(4), "intern"},
+		},
+	)
+	assert.Equal(t, nil, err)
+	err = schema.RegisterSourceAsSchema("cteorg", src)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("qlbridge", "cteorg")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	// read once, run inline
+	assert.Equal(t, [][]string{{"cto"}, {"cfo"}},
+		queryRows(t, db, `WITH reports AS (SELECT id, name FROM org WHERE parent_id = 1)
+			SELECT name FROM reports ORDER BY id`))
+
+	// column names, and a sub-query reading an earlier one
+	assert.Equal(t, [][]string{{"4", "dev"}},
+		queryRows(t, db, `WITH reports (rid, rname) AS (SELECT id, name FROM org WHERE parent_id = 1),
+			second AS (SELECT o.id, o.name FROM org AS o INNER JOIN reports AS r ON o.parent_id = r.rid)
+			SELECT id, name FROM second`))
+
+	// read twice, materialized once
+	assert.Equal(t, [][]string{{"cto", "4"}, {"dev", "5"}},
+		queryRows(t, db, `WITH staff AS (SELECT id, parent_id, name FROM org WHERE id > 1)
+			SELECT b.name, e.id FROM staff AS b INNER JOIN staff AS e ON e.parent_id = b.id`))
+	assert.Equal(t, [][]string{{"2"}, {"3"}, {"4"}, {"5"}, {"4"}, {"5"}},
+		queryRows(t, db, `WITH staff AS (SELECT id FROM org WHERE id > 1)
+			SELECT id FROM staff UNION ALL SELECT id FROM staff WHERE id > 3`))
+	details := make([]string, 0)
+	for _, row := range queryRows(t, db, `EXPLAIN WITH staff AS (SELECT id FROM org WHERE id > 1)
+			SELECT id FROM staff UNION ALL SELECT id FROM staff WHERE id > 3`) {
+		if row[3] == "ctesource" {
+			details = append(details, row[4])
+		}
+	}
+	assert.Equal(t, []string{"cte=staff materialized", "cte=staff materialized"}, details)
+
+	// shadows a table of the same name, which its own select reads
+	assert.Equal(t, [][]string{{"5"}},
+		queryRows(t, db, `WITH org AS (SELECT id FROM org WHERE parent_id = 4) SELECT id FROM org`))
+
+	// recursive, all those reporting to the cto however indirectly
+	assert.Equal(t, [][]string{{"cto"}, {"dev"}, {"intern"}},
+		queryRows(t, db, `WITH RECURSIVE below (id, name) AS (
+				SELECT id, name FROM org WHERE name = "cto"
+				UNION ALL
+				SELECT o.id, o.name FROM org AS o INNER JOIN below AS b ON o.parent_id = b.id
+			)
+			SELECT name FROM below ORDER BY id`))
+	assert.Equal(t, [][]string{{"1"}, {"2"}, {"3"}, {"4"}},
+		queryRows(t, db, `WITH RECURSIVE seq (n) AS (
+				SELECT id FROM org WHERE id = 1
+				UNION ALL
+				SELECT n + 1 FROM seq WHERE n < 4
+			)
+			SELECT n FROM seq`))
+
+	// recursive UNION drops the rows already found, so it ends at a cycle
+	assert.Equal(t, [][]string{{"1"}, {"2"}, {"3"}},
+		queryRows(t, db, `WITH RECURSIVE cyc (n) AS (
+				SELECT id FROM org WHERE id = 1
+				UNION
+				SELECT n % 3 + 1 FROM cyc
+			)
+			SELECT n FROM cyc ORDER BY n`))
+
+	// while UNION ALL never ends, it fails at the iteration limit
+	ctx := plan.NewContext(`WITH RECURSIVE cyc (n) AS (
+			SELECT id FROM org WHERE id = 1
+			UNION ALL
+			SELECT n % 3 + 1 FROM cyc
+		)
+		SELECT n FROM cyc`)
+	ctx.Schema, _ = schema.DefaultRegistry().Schema("cteorg")
+	job, err := exec.BuildSqlJob(ctx)
+	assert.Equal(t, nil, err)
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs))
+	assert.Equal(t, nil, job.Setup())
+	err = job.Run()
+	assert.NotEqual(t, nil, err)
+	assert.True(t, strings.Contains(fmt.Sprintf("%v", err), "iterations"), err)
+
+	// the step must read the sub-query once, not aggregate it
+	_, err = db.Query(`WITH RECURSIVE t (n) AS (SELECT 1 UNION ALL SELECT count(*) FROM t) SELECT n FROM t`)
+	assert.NotEqual(t, nil, err)
+	_, err = db.Query(`WITH RECURSIVE t (n) AS (SELECT id FROM t UNION ALL SELECT id FROM org) SELECT n FROM t`)
+	assert.NotEqual(t, nil, err)
+}
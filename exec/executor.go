@@ -7,7 +7,6 @@ import (
 
 	"github.com/araddon/qlbridge/datasource/membtree"
 	"github.com/araddon/qlbridge/plan"
-	"github.com/araddon/qlbridge/rel"
 )
 
 var (
@@ -15,7 +14,12 @@ var (
 	_ JobRunner = (*JobExecutor)(nil)
 
 	// Ensure that we implement the plan.Planner interface for our job
-	_ Executor = (*JobExecutor)(nil)
+	_ Executor          = (*JobExecutor)(nil)
+	_ UnionExecutor     = (*JobExecutor)(nil)
+	_ ExplainExecutor   = (*JobExecutor)(nil)
+	_ PartitionExecutor = (*JobExecutor)(nil)
+	_ WindowExecutor    = (*JobExecutor)(nil)
+	_ DistinctExecutor  = (*JobExecutor)(nil)
 	//_ plan.SourcePlanner = (*SourceBuilder)(nil)
 )
 
@@ -28,7 +32,6 @@ type JobExecutor struct {
 	Executor Executor
 	RootTask TaskRunner
 	Ctx      *plan.Context
-	distinct bool
 	children []Task
 }
 
@@ -58,17 +61,27 @@ func BuildSqlJob(ctx *plan.Context) (*JobExecutor, error) {
 }
 
 // BuildSqlJobPlanned Create Job made up of sub-tasks in DAG that is the
-// plan for execution of this query/job.
+// plan for execution of this query/job.  A statement already on the
+// context, ie from a prepared statement, is planned without parsing.
 func BuildSqlJobPlanned(planner plan.Planner, executor Executor, ctx *plan.Context) (Task, error) {
 
 	//u.Debugf("build: %q", ctx.Raw)
 	if ctx.Raw == "" {
 		return nil, fmt.Errorf("no sql provided")
 	}
-	stmt, err := rel.ParseSql(ctx.Raw)
-	if err != nil {
-		u.Debugf("could not parse sql : %v", err)
-		return nil, err
+	if ctx.Context != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	stmt := ctx.Stmt
+	if stmt == nil {
+		var err error
+		stmt, err = plan.DefaultStatementCache.Statement(ctx)
+		if err != nil {
+			u.Debugf("could not parse sql : %v", err)
+			return nil, err
+		}
 	}
 	if stmt == nil {
 		return nil, fmt.Errorf("Not statement for parse? %v", ctx.Raw)
@@ -118,6 +131,11 @@ func (m *JobExecutor) WalkPlan(p plan.Task) (Task, error) {
 			p.Stmt.SetSystemQry()
 		}
 		return m.Executor.WalkSelect(p)
+	case *plan.Union:
+		if ue, ok := m.Executor.(UnionExecutor); ok {
+			return ue.WalkUnion(p)
+		}
+		return nil, ErrNotImplemented
 	case *plan.Upsert:
 		return m.Executor.WalkUpsert(p)
 	case *plan.Insert:
@@ -128,6 +146,11 @@ func (m *JobExecutor) WalkPlan(p plan.Task) (Task, error) {
 		return m.Executor.WalkDelete(p)
 	case *plan.Command:
 		return m.Executor.WalkCommand(p)
+	case *plan.Explain:
+		if ee, ok := m.Executor.(ExplainExecutor); ok {
+			return ee.WalkExplain(p)
+		}
+		return nil, ErrNotImplemented
 
 	// DDL
 	case *plan.Create:
@@ -152,6 +175,12 @@ func (m *JobExecutor) WalkSelect(p *plan.Select) (Task, error) {
 	root := m.NewTask(p)
 	return root, m.WalkChildren(p, root)
 }
+
+// WalkUnion create dag of plan Union.
+func (m *JobExecutor) WalkUnion(p *plan.Union) (Task, error) {
+	root := m.NewTask(p)
+	return root, m.WalkChildren(p, root)
+}
 func (m *JobExecutor) WalkUpsert(p *plan.Upsert) (Task, error) {
 	root := m.NewTask(p)
 	return root, root.Add(NewUpsert(m.Ctx, p))
@@ -169,6 +198,9 @@ func (m *JobExecutor) WalkDelete(p *plan.Delete) (Task, error) {
 	return root, root.Add(NewDelete(m.Ctx, p))
 }
 func (m *JobExecutor) WalkSource(p *plan.Source) (Task, error) {
+	if p.Cte != nil {
+		return NewCteSource(m.Ctx, p), nil
+	}
 	if len(p.Static) > 0 {
 		static := membtree.NewStaticData("static")
 		static.SetColumns(p.Cols)
@@ -183,7 +215,7 @@ func (m *JobExecutor) WalkSource(p *plan.Source) (Task, error) {
 			u.Warnf("no datasource")
 			return nil, fmt.Errorf("missing data source")
 		}
-		source, err := p.DataSource.Open(p.Stmt.SourceName())
+		source, err := m.Ctx.OpenSource(p.DataSource, p.Stmt.SourceName())
 		if err != nil {
 			return nil, err
 		}
@@ -204,7 +236,7 @@ func (m *JobExecutor) WalkSourceExec(p *plan.Source) (Task, error) {
 			u.Warnf("no datasource")
 			return nil, fmt.Errorf("missing data source")
 		}
-		source, err := p.DataSource.Open(p.Stmt.SourceName())
+		source, err := m.Ctx.OpenSource(p.DataSource, p.Stmt.SourceName())
 		if err != nil {
 			return nil, err
 		}
@@ -225,11 +257,20 @@ func (m *JobExecutor) WalkHaving(p *plan.Having) (Task, error) {
 	return NewHaving(m.Ctx, p), nil
 }
 func (m *JobExecutor) WalkGroupBy(p *plan.GroupBy) (Task, error) {
+	if p.Final {
+		return NewGroupByFinal(m.Ctx, p), nil
+	}
 	return NewGroupBy(m.Ctx, p), nil
 }
 func (m *JobExecutor) WalkOrder(p *plan.Order) (Task, error) {
 	return NewOrder(m.Ctx, p), nil
 }
+func (m *JobExecutor) WalkWindow(p *plan.Window) (Task, error) {
+	return NewWindow(m.Ctx, p)
+}
+func (m *JobExecutor) WalkDistinct(p *plan.Distinct) (Task, error) {
+	return NewDistinct(m.Ctx, p), nil
+}
 func (m *JobExecutor) WalkProjection(p *plan.Projection) (Task, error) {
 	return NewProjection(m.Ctx, p), nil
 }
@@ -246,6 +287,21 @@ func (m *JobExecutor) WalkJoin(p *plan.JoinMerge) (Task, error) {
 		u.Errorf("whoops %T  %v", l, err)
 		return nil, err
 	}
+	if p.Seek {
+		// Seek right rows by key instead of scanning, if the source
+		// can't seek fall back to the hash join
+		seeker, err := openSeeker(m.Ctx, p.Right.(*plan.Source))
+		if err != nil {
+			return nil, err
+		}
+		if seeker != nil {
+			err = execTask.Add(NewJoinSeek(m.Ctx, l.(TaskRunner), seeker, p))
+			if err != nil {
+				return nil, err
+			}
+			return execTask, nil
+		}
+	}
 	r, err := m.WalkPlanAll(p.Right)
 	if err != nil {
 		return nil, err
@@ -265,6 +321,63 @@ func (m *JobExecutor) WalkJoin(p *plan.JoinMerge) (Task, error) {
 func (m *JobExecutor) WalkJoinKey(p *plan.JoinKey) (Task, error) {
 	return NewJoinKey(m.Ctx, p), nil
 }
+func (m *JobExecutor) WalkSetOp(p *plan.SetOp) (Task, error) {
+	execTask := NewTaskParallel(m.Ctx)
+	var inputs [2]TaskRunner
+	for i, in := range []plan.Task{p.Left, p.Right} {
+		var t Task
+		var err error
+		switch it := in.(type) {
+		case *plan.Select:
+			t, err = m.Executor.WalkSelect(it)
+		case *plan.SetOp:
+			// sequential wrapper so the nested set op keeps its own output
+			t = NewTaskSequential(m.Ctx)
+			var op Task
+			if op, err = m.WalkPlanTask(it); err == nil {
+				err = t.Add(op)
+			}
+		default:
+			err = fmt.Errorf("unsupported set operation input %T", in)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = execTask.Add(t); err != nil {
+			return nil, err
+		}
+		inputs[i] = t.(TaskRunner)
+	}
+	err := execTask.Add(NewSetOp(m.Ctx, inputs[0], inputs[1], p))
+	if err != nil {
+		return nil, err
+	}
+	return execTask, nil
+}
+func (m *JobExecutor) WalkPartitionScan(p *plan.PartitionScan) (Task, error) {
+	execTask := NewTaskParallel(m.Ctx)
+	inputs := make([]TaskRunner, len(p.Parts))
+	for i, part := range p.Parts {
+		// sequential wrapper so each partition keeps its own output
+		seq := NewTaskSequential(m.Ctx)
+		t, err := m.WalkPlanAll(part)
+		if err != nil {
+			return nil, err
+		}
+		if err = seq.Add(t); err != nil {
+			return nil, err
+		}
+		if err = execTask.Add(seq); err != nil {
+			return nil, err
+		}
+		inputs[i] = seq
+	}
+	err := execTask.Add(NewPartitionMerge(m.Ctx, inputs))
+	if err != nil {
+		return nil, err
+	}
+	return execTask, nil
+}
 func (m *JobExecutor) WalkPlanAll(p plan.Task) (Task, error) {
 	root, err := m.WalkPlanTask(p)
 	if err != nil {
@@ -296,12 +409,32 @@ func (m *JobExecutor) WalkPlanTask(p plan.Task) (Task, error) {
 		return m.Executor.WalkGroupBy(p)
 	case *plan.Order:
 		return m.Executor.WalkOrder(p)
+	case *plan.Window:
+		if we, ok := m.Executor.(WindowExecutor); ok {
+			return we.WalkWindow(p)
+		}
+		return nil, ErrNotImplemented
+	case *plan.Distinct:
+		if de, ok := m.Executor.(DistinctExecutor); ok {
+			return de.WalkDistinct(p)
+		}
+		return nil, ErrNotImplemented
 	case *plan.Projection:
 		return m.Executor.WalkProjection(p)
 	case *plan.JoinMerge:
 		return m.Executor.WalkJoin(p)
 	case *plan.JoinKey:
 		return m.Executor.WalkJoinKey(p)
+	case *plan.SetOp:
+		if ue, ok := m.Executor.(UnionExecutor); ok {
+			return ue.WalkSetOp(p)
+		}
+		return nil, ErrNotImplemented
+	case *plan.PartitionScan:
+		if pe, ok := m.Executor.(PartitionExecutor); ok {
+			return pe.WalkPartitionScan(p)
+		}
+		return nil, ErrNotImplemented
 	}
 	panic(fmt.Sprintf("Task plan-exec Not implemented for %T", p))
 }
@@ -314,6 +447,12 @@ func (m *JobExecutor) WalkCommand(p *plan.Command) (Task, error) {
 	return root, root.Add(NewCommand(m.Ctx, p))
 }
 
+// WalkExplain walk EXPLAIN [ANALYZE] statements.
+func (m *JobExecutor) WalkExplain(p *plan.Explain) (Task, error) {
+	root := m.NewTask(p)
+	return root, root.Add(NewExplain(m.Ctx, p))
+}
+
 // DDL Operations
 
 // WalkCreate walks the Create plan.
@@ -341,6 +480,7 @@ func (m *JobExecutor) WalkChildren(p plan.Task, root Task) error {
 		et, err := m.WalkPlanTask(t)
 		if err != nil {
 			u.Errorf("could not create task %#v err=%v", t, err)
+			return err
 		}
 		if len(t.Children()) == 0 {
 			err = root.Add(et)
@@ -384,9 +524,31 @@ func (m *JobExecutor) Setup() error {
 	return m.RootTask.Setup(0)
 }
 
-// Run this task
+// Run this task.  If the plan context (go context) is cancelled or its
+// deadline exceeded the job is closed, which closes every task's SigChan
+// and the source conns, and the context error is returned.
 func (m *JobExecutor) Run() error {
-	return m.RootTask.Run()
+	ctx := m.Ctx.Context
+	if ctx == nil || ctx.Done() == nil {
+		return m.RootTask.Run()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.RootTask.Close()
+		case <-finished:
+		}
+	}()
+	err := m.RootTask.Run()
+	close(finished)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
 }
 
 // Close the normal close of root task
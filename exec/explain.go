@@ -0,0 +1,349 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	_ = u.EMPTY
+
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Explain)(nil)
+)
+
+// ExplainColumns the result columns of an EXPLAIN statement.  Each row is
+// a task of the exec dag in depth-first order, ANALYZE adds the rows, bytes
+// sent downstream and the wall time of each task.  FORMAT = JSON returns
+// a single row with the task tree as json.
+func ExplainColumns(stmt *rel.SqlDescribe) []string {
+	if stmt.Format == "json" {
+		return []string{"explain"}
+	}
+	cols := []string{"id", "parent_id", "depth", "task", "detail"}
+	if stmt.Analyze {
+		cols = append(cols, "rows", "bytes", "wall_ms")
+	}
+	return cols
+}
+
+// Explain is the executeable task for EXPLAIN [ANALYZE] statements, it
+// builds (and for ANALYZE runs) the job of the explained statement then
+// emits its dag of tasks as rows.
+type Explain struct {
+	*TaskBase
+	p *plan.Explain
+}
+
+// explainNode a task of the explained job.
+type explainNode struct {
+	ID       int            `json:"id"`
+	Task     string         `json:"task"`
+	Detail   string         `json:"detail,omitempty"`
+	Stats    *explainStats  `json:"stats,omitempty"`
+	Children []*explainNode `json:"children,omitempty"`
+	parentID int
+	depth    int
+}
+
+type explainStats struct {
+	Rows   int64   `json:"rows"`
+	Bytes  int64   `json:"bytes"`
+	WallMs float64 `json:"wall_ms"`
+}
+
+// NewExplain creates new explain exec task
+func NewExplain(ctx *plan.Context, p *plan.Explain) *Explain {
+	return &Explain{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+	}
+}
+
+// Run the explained statement job and emit its tasks.
+func (m *Explain) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	stmt := m.p.Stmt
+	ctx := newChildContext(m.Ctx, stmt.Stmt.String())
+	ctx.Txn = m.Ctx.Txn
+	job, err := BuildSqlJob(ctx)
+	if err != nil {
+		return err
+	}
+
+	var sink TaskRunner
+	if stmt.Analyze {
+		sink = newDiscardTask(ctx)
+		job.RootTask.Add(sink)
+		if err = job.Setup(); err != nil {
+			job.Close()
+			return err
+		}
+		started := time.Now()
+		err = job.Run()
+		if st, ok := job.RootTask.(taskStatser); ok {
+			st.addWall(time.Since(started))
+		}
+		job.Close()
+		if err != nil {
+			return err
+		}
+	} else {
+		// never run, only release the source conns opened by planning
+		closeSources(job.RootTask)
+	}
+
+	ct := 0
+	root := explainTree(job.RootTask, sink, stmt.Analyze, 0, 0, &ct)
+
+	cols := ExplainColumns(stmt)
+	colIndex := make(map[string]int, len(cols))
+	for i, col := range cols {
+		colIndex[col] = i
+	}
+
+	if stmt.Format == "json" {
+		by, err := json.Marshal(root)
+		if err != nil {
+			return err
+		}
+		m.emit(datasource.NewSqlDriverMessageMap(1, []driver.Value{string(by)}, colIndex))
+		return nil
+	}
+
+	var walk func(n *explainNode) bool
+	walk = func(n *explainNode) bool {
+		var detail driver.Value // NULL if the task has no detail
+		if n.Detail != "" {
+			detail = n.Detail
+		}
+		row := []driver.Value{int64(n.ID), int64(n.parentID), int64(n.depth), n.Task, detail}
+		if n.Stats != nil {
+			row = append(row, n.Stats.Rows, n.Stats.Bytes, n.Stats.WallMs)
+		}
+		if !m.emit(datasource.NewSqlDriverMessageMap(uint64(n.ID), row, colIndex)) {
+			return false
+		}
+		for _, c := range n.Children {
+			if !walk(c) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(root)
+	return nil
+}
+
+func (m *Explain) emit(msg schema.Message) bool {
+	select {
+	case <-m.SigChan():
+		return false
+	case m.msgOutCh <- msg:
+		m.track(msg)
+		return true
+	}
+}
+
+// explainTree describe the task t and its children, skipping the
+// result sink added to run the job.
+func explainTree(t Task, sink TaskRunner, analyze bool, parentID, depth int, ct *int) *explainNode {
+	*ct++
+	n := &explainNode{
+		ID:       *ct,
+		Task:     explainTaskName(t),
+		Detail:   explainDetail(t),
+		parentID: parentID,
+		depth:    depth,
+	}
+	if analyze {
+		st := explainTaskStats(t, sink)
+		n.Stats = &explainStats{
+			Rows:   st.Rows,
+			Bytes:  st.Bytes,
+			WallMs: float64(st.Wall) / float64(time.Millisecond),
+		}
+	}
+	for _, c := range t.Children() {
+		if sink != nil && c == Task(sink) {
+			continue
+		}
+		n.Children = append(n.Children, explainTree(c, sink, analyze, n.ID, depth+1, ct))
+	}
+	return n
+}
+
+// explainTaskStats the stats of a task, the output of a sequential or
+// parallel task is that of its last child.
+func explainTaskStats(t Task, sink TaskRunner) TaskStats {
+	var st TaskStats
+	if ts, ok := t.(taskStatser); ok {
+		st = ts.Stats()
+	}
+	switch t.(type) {
+	case *TaskSequential, *TaskParallel:
+		children := t.Children()
+		if len(children) > 0 && sink != nil && children[len(children)-1] == Task(sink) {
+			children = children[:len(children)-1]
+		}
+		if len(children) > 0 {
+			out := explainTaskStats(children[len(children)-1], sink)
+			st.Rows, st.Bytes = out.Rows, out.Bytes
+		}
+	}
+	return st
+}
+
+func explainTaskName(t Task) string {
+	switch t.(type) {
+	case *TaskSequential:
+		return "sequential"
+	case *TaskParallel:
+		return "parallel"
+	case *GroupByFinal:
+		return "groupby-final"
+	}
+	name := fmt.Sprintf("%T", t)
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(name)
+}
+
+// explainDetail the sources, predicates, join strategy etc of a task.
+func explainDetail(t Task) string {
+	switch tt := t.(type) {
+	case *Source:
+		if tt.p == nil || tt.p.Stmt == nil {
+			return ""
+		}
+		parts := []string{"table=" + tt.p.Stmt.SourceName()}
+		if tt.p.Stmt.Source != nil {
+			parts = append(parts, "select="+tt.p.Stmt.Source.String())
+		}
+		if len(tt.p.Static) > 0 {
+			parts = append(parts, "static")
+		}
+		if tt.p.Partition != nil {
+			parts = append(parts, "partition="+tt.p.Partition.Id)
+		}
+		return strings.Join(parts, " ")
+	case *CteSource:
+		return cteDetail(tt.p)
+	case *Where:
+		return "filter=" + tt.filter.String()
+	case *JoinKey:
+		return "key=" + nodesString(tt.p.Source.Stmt.JoinNodes())
+	case *JoinMerge:
+		parts := []string{"strategy=hash"}
+		if tt.nestedLoop {
+			parts[0] = "strategy=nested-loop"
+		}
+		if tt.buildLeft {
+			parts = append(parts, "build=left")
+		} else {
+			parts = append(parts, "build=right")
+		}
+		switch {
+		case tt.leftOuter && tt.rightOuter:
+			parts = append(parts, "outer=full")
+		case tt.leftOuter:
+			parts = append(parts, "outer=left")
+		case tt.rightOuter:
+			parts = append(parts, "outer=right")
+		}
+		if tt.rightStmt != nil && tt.rightStmt.JoinExpr != nil {
+			parts = append(parts, "on="+tt.rightStmt.JoinExpr.String())
+		}
+		return strings.Join(parts, " ")
+	case *JoinSeek:
+		parts := []string{"strategy=seek"}
+		if tt.tbl != nil {
+			parts = append(parts, "table="+tt.tbl.Name)
+		}
+		if tt.p.RightFrom.JoinExpr != nil {
+			parts = append(parts, "on="+tt.p.RightFrom.JoinExpr.String())
+		}
+		return strings.Join(parts, " ")
+	case *PartitionMerge:
+		return fmt.Sprintf("partitions=%d", len(tt.inputs))
+	case *SetOp:
+		op := strings.ToUpper(tt.p.Op.String())
+		if tt.p.All {
+			op += " ALL"
+		}
+		return "op=" + op
+	case *GroupBy:
+		if tt.p.Partial {
+			return "group=" + tt.p.Stmt.GroupBy.String() + " partial"
+		}
+		return "group=" + tt.p.Stmt.GroupBy.String()
+	case *GroupByFinal:
+		return "group=" + tt.p.Stmt.GroupBy.String()
+	case *Order:
+		return "order=" + tt.p.Stmt.OrderBy.String()
+	case *Window:
+		fns := make([]string, 0)
+		for _, col := range tt.p.Stmt.Columns {
+			if col.Over != nil {
+				fns = append(fns, windowKey(col))
+			}
+		}
+		return "functions=" + strings.Join(fns, ", ")
+	case *Distinct:
+		return "columns=" + tt.p.Stmt.Columns.String()
+	case *Projection:
+		if tt.p == nil || tt.p.Stmt == nil {
+			return ""
+		}
+		detail := "columns=" + tt.p.Stmt.Columns.String()
+		if tt.p.Final {
+			detail += " final"
+		}
+		if tt.p.Stmt.Limit > 0 {
+			detail += fmt.Sprintf(" limit=%d", tt.p.Stmt.Limit)
+		}
+		return detail
+	}
+	return ""
+}
+
+func nodesString(nodes []expr.Node) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// closeSources close the source conns of a job that is not run.
+func closeSources(t Task) {
+	if s, ok := t.(*Source); ok {
+		if err := s.closeSource(); err != nil {
+			u.Warnf("could not close source %v", err)
+		}
+	}
+	for _, c := range t.Children() {
+		closeSources(c)
+	}
+}
+
+// newDiscardTask a result sink that drops all rows.
+func newDiscardTask(ctx *plan.Context) TaskRunner {
+	t := NewTaskBase(ctx)
+	t.Handler = func(ctx *plan.Context, msg schema.Message) bool { return true }
+	return t
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/expr/builtins"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/value"
@@ -84,6 +85,7 @@ func (m *GroupBy) Run() error {
 		u.Warnf("Group By statement not supported? %v", err)
 		return err
 	}
+	inputs := aggInputs(columns)
 
 	// are are going to hold entire row in memory while we are calculating
 	//  so obviously not scalable.
@@ -142,7 +144,7 @@ msgReadLoop:
 				if col.Expr == nil {
 					u.Warnf("wat?   nil col expr? %#v", col)
 				} else {
-					v, ok := vm.Eval(mm, col.Expr)
+					v, ok := vm.Eval(mm, inputs[i])
 					//u.Infof("mt: %T  mm %#v", mm, mm)
 					if !ok || v == nil {
 						//u.Debugf("evaled nil? key=%v  val=%v expr:%s", col.Key(), v, col.Expr.String())
@@ -169,7 +171,9 @@ msgReadLoop:
 			//u.Debugf("GroupBy output row? key:%s %#v", key, row)
 		}
 		//u.Debugf("row: %v  cols:%v", row, colIndex)
-		outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex)
+		msg := datasource.NewSqlDriverMessageMap(i, row, colIndex)
+		outCh <- msg
+		m.track(msg)
 		i++
 	}
 
@@ -242,6 +246,9 @@ msgReadLoop:
 				}
 				if col.Expr == nil {
 					u.Warnf("wat?   nil col expr? %#v", col)
+				} else if gbf, isValue := aggs[i].(*groupByFunc); isValue {
+					// the group by value, same in each partial
+					gbf.last = dv[i]
 				} else {
 					v := dv[i]
 					switch vt := v.(type) {
@@ -252,8 +259,7 @@ msgReadLoop:
 						aggs[i].Merge(&vt)
 					case int64:
 						aggs[i].Merge(&AggPartial{Ct: vt})
-					case string:
-						aggs[i] = &groupByFunc{vt}
+					case nil:
 					default:
 						u.Warnf("unhandled type: %#v", v)
 					}
@@ -268,7 +274,9 @@ msgReadLoop:
 			//u.Debugf("agg result: %#v  %v", row[i], row[i])
 		}
 		//u.Debugf("GroupBy output row? %v", row)
-		outCh <- datasource.NewSqlDriverMessageMap(i, row, colIndex)
+		msg := datasource.NewSqlDriverMessageMap(i, row, colIndex)
+		outCh <- msg
+		m.track(msg)
 		i++
 	}
 
@@ -318,23 +326,12 @@ func (m *GroupByFinal) Close() error {
 // that will be reduced on finalizer.  IE, for consistent-hash based
 // group-bys calculated across multiple nodes this holds info that
 // needs to be further calculated it only represents this hash.
-type AggPartial struct {
-	Ct int64
-	N  float64
-}
+type AggPartial = expr.AggPartial
+
+// Aggregator is the stateful per-group accumulator for an aggregate
+// function, see expr.Aggregator.
+type Aggregator = expr.Aggregator
 
-type AggFunc func(v value.Value)
-type resultFunc func() interface{}
-type Aggregator interface {
-	Do(v value.Value)
-	Result() interface{}
-	Reset()
-	Merge(*AggPartial)
-}
-type agg struct {
-	do     AggFunc
-	result resultFunc
-}
 type groupByFunc struct {
 	last interface{}
 }
@@ -347,91 +344,31 @@ func NewGroupByValue(col *rel.Column) Aggregator {
 	return &groupByFunc{}
 }
 
-type sum struct {
-	partial bool
-	ct      int64
-	n       float64
-}
-
-func (m *sum) Do(v value.Value) {
-	m.ct++
-	switch vt := v.(type) {
-	case value.IntValue:
-		m.n += vt.Float()
-	case value.NumberValue:
-		m.n += vt.Val()
-	}
-}
-func (m *sum) Result() interface{} {
-	if !m.partial {
-		return m.n
-	}
-	return &AggPartial{
-		m.ct,
-		m.n,
-	}
-}
-func (m *sum) Reset() { m.n = 0 }
-func (m *sum) Merge(a *AggPartial) {
-	m.ct += a.Ct
-	m.n += a.N
-}
+// NewSum create the sum Aggregator.
+//
+// Deprecated: aggregators are supplied by the function registry, see
+// expr.FuncNode.NewAggregator and builtins.Sum.
 func NewSum(col *rel.Column, partial bool) Aggregator {
-	return &sum{partial: partial}
+	agg, _ := (&builtins.Sum{}).NewAggregator(nil, partial)
+	return agg
 }
 
-type avg struct {
-	partial bool
-	ct      int64
-	n       float64
-}
-
-func (m *avg) Do(v value.Value) {
-	m.ct++
-	switch vt := v.(type) {
-	case value.IntValue:
-		m.n += vt.Float()
-	case value.NumberValue:
-		m.n += vt.Val()
-	}
-}
-func (m *avg) Result() interface{} {
-	if !m.partial {
-		return m.n / float64(m.ct)
-	}
-	return &AggPartial{
-		m.ct,
-		m.n,
-	}
-}
-func (m *avg) Reset() { m.n = 0; m.ct = 0 }
-func (m *avg) Merge(a *AggPartial) {
-	m.ct += a.Ct
-	m.n += a.N
-}
+// NewAvg create the avg Aggregator.
+//
+// Deprecated: aggregators are supplied by the function registry, see
+// expr.FuncNode.NewAggregator and builtins.Avg.
 func NewAvg(col *rel.Column, partial bool) Aggregator {
-	return &avg{partial: partial}
+	agg, _ := (&builtins.Avg{}).NewAggregator(nil, partial)
+	return agg
 }
 
-type count struct {
-	n int64
-}
-
-func (m *count) Do(v value.Value) {
-	if v == nil || v.Nil() {
-		return
-	}
-	m.n++
-}
-func (m *count) Result() interface{} {
-	return m.n
-}
-func (m *count) Reset() { m.n = 0 }
-func (m *count) Merge(a *AggPartial) {
-	m.n += a.Ct
-}
+// NewCount create the count Aggregator.
+//
+// Deprecated: aggregators are supplied by the function registry, see
+// expr.FuncNode.NewAggregator and builtins.Count.
 func NewCount(col *rel.Column) Aggregator {
-	return &count{}
+	agg, _ := (&builtins.Count{}).NewAggregator(nil, false)
+	return agg
 }
 
 func buildAggs(p *plan.GroupBy) ([]Aggregator, error) {
@@ -452,27 +389,24 @@ colLoop:
 			}
 		}
 
-		// Since we made it here, it is an aggregate func
-		//  move to a registry of some kind to allow extension
+		// Since we made it here, it is an aggregate func, the function
+		// registry supplies its Aggregator.
 		switch n := col.Expr.(type) {
 		case *expr.FuncNode:
-
-			// TODO:  extract to a UDF Registry Similar to builtins
-			switch strings.ToLower(n.Name) {
-			case "avg":
-				aggs[colIdx] = NewAvg(col, p.Partial)
-			case "count":
-				aggs[colIdx] = NewCount(col)
-			case "sum":
-				aggs[colIdx] = NewSum(col, p.Partial)
-			default:
+			if n.F.AggMaker == nil {
 				return nil, fmt.Errorf("Not implemented groupby for function: %s", col.Expr)
 			}
+			agg, err := n.NewAggregator(p.Partial)
+			if err != nil {
+				return nil, err
+			}
+			aggs[colIdx] = agg
 		case *expr.BinaryNode:
 			// expression logic?
 			return nil, fmt.Errorf("Not implemented groupby for expression column: %s", col.Expr)
 		case *expr.IdentityNode:
-			// We can have a naked group by which basically means distinct? should have been caught above
+			// neither grouped nor aggregated, SELECT DISTINCT is planned
+			// as its own task rather than as a group by
 			return nil, fmt.Errorf("Not implemented groupby for identity column %s", col.Expr)
 		default:
 			return nil, fmt.Errorf("Not implemented groupby for %T column: %s", col.Expr, col.Expr)
@@ -480,3 +414,16 @@ colLoop:
 	}
 	return aggs, nil
 }
+
+// aggInputs the node evaluated per row for the aggregator of each column,
+// the argument of a DISTINCT aggregate.
+func aggInputs(cols rel.Columns) []expr.Node {
+	inputs := make([]expr.Node, len(cols))
+	for i, col := range cols {
+		inputs[i] = col.Expr
+		if fn, ok := col.Expr.(*expr.FuncNode); ok {
+			inputs[i] = fn.AggInput()
+		}
+	}
+	return inputs
+}
@@ -3,15 +3,20 @@ package exec
 import (
 	"database/sql/driver"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"strings"
-	"sync"
+	"time"
 
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -20,6 +25,9 @@ var (
 
 	// Ensure that we implement the Task Runner interface
 	_ TaskRunner = (*JoinMerge)(nil)
+	_ TaskRunner = (*JoinSeek)(nil)
+
+	_ expr.ContextReader = (*joinRow)(nil)
 )
 
 type KeyEvaluator func(msg schema.Message) driver.Value
@@ -72,6 +80,10 @@ func (m *JoinKey) Run() error {
 			}
 
 			//u.Infof("In joinkey msg %#v", msg)
+			if sm, isSdm := msg.(*datasource.SqlDriverMessage); isSdm && m.p.Source.Tbl != nil {
+				// full table rows, the source projection was optimized away
+				msg = sm.ToMsgMap(m.p.Source.Tbl.FieldPositions)
+			}
 		msgTypeSwitch:
 			switch mt := msg.(type) {
 			case *datasource.SqlDriverMessageMap:
@@ -79,8 +91,11 @@ func (m *JoinKey) Run() error {
 				for i, node := range joinNodes {
 					joinVal, ok := vm.Eval(mt, node)
 					//u.Debugf("evaluating: ok?%v T:%T result=%v node '%v'", ok, joinVal, joinVal.ToString(), node.String())
-					if !ok {
-						u.Errorf("could not evaluate: %T %#v   %v", joinVal, joinVal, msg)
+					if !ok || value.IsNilish(joinVal) {
+						// NULL keys never match, pass along un-keyed so
+						// outer joins may still emit the row
+						outCh <- mt
+						m.track(mt)
 						break msgTypeSwitch
 					}
 					vals[i] = joinVal.ToString()
@@ -89,6 +104,7 @@ func (m *JoinKey) Run() error {
 				key := strings.Join(vals, string(byte(0)))
 				mt.SetKeyHashed(key)
 				outCh <- mt
+				m.track(mt)
 			default:
 				return fmt.Errorf("To use JoinKey must use SqlDriverMessageMap but got %T", msg)
 			}
@@ -97,6 +113,10 @@ func (m *JoinKey) Run() error {
 	}
 }
 
+// joinSpillPartitions is number of partitions each side of a join is
+// split into when the build side exceeds the plan.Context MemoryLimit.
+const joinSpillPartitions = 16
+
 // Scans 2 source tasks for rows, evaluate keys, use for join
 //
 type JoinMerge struct {
@@ -106,10 +126,19 @@ type JoinMerge struct {
 	ltask     TaskRunner
 	rtask     TaskRunner
 	colIndex  map[string]int
+	// keep un-matched rows from left, right for outer joins
+	leftOuter  bool
+	rightOuter bool
+	buildLeft  bool
+	nestedLoop bool
+	filter     *joinFilter
+	ct         uint64 // id of next output row
 }
 
-// A very stupid naive parallel join merge, uses Key() as value to merge
-//   two different input channels
+// A hash join of two input channels on their Key() which was
+// evaluated by JoinKey.  All rows of the build side (the smaller side
+// per table stats, else the right) are read into a hash table, then the
+// probe side is streamed through it, so output is in probe side order.
 //
 //   source1   ->
 //                \
@@ -117,6 +146,18 @@ type JoinMerge struct {
 //                /
 //   source2   ->
 //
+// If the build side exceeds the plan.Context MemoryLimit both sides are
+// partitioned by key hash into temp files, and each partition joined
+// in turn (grace hash join).
+//
+// Rows whose keys match are only joined if the full ON expression
+// (including any non-equality predicates) evaluates true.  If the ON has
+// no equality to hash on, all rows share one key so each probe row is
+// compared to every build row (nested loop).
+//
+// For LEFT, RIGHT, FULL outer joins the rows from the outer side(s) that
+// have no match are emitted with NULL values for the other sides columns.
+//
 // Distributed:
 //
 //   source1a  ->                |-> --  join  -->
@@ -138,137 +179,299 @@ func NewJoinNaiveMerge(ctx *plan.Context, l, r TaskRunner, p *plan.JoinMerge) *J
 	m.rtask = r
 	m.leftStmt = p.LeftFrom
 	m.rightStmt = p.RightFrom
+	m.leftOuter = p.LeftOuter
+	m.rightOuter = p.RightOuter
+	m.buildLeft = p.BuildLeft
+	m.nestedLoop = p.NestedLoop
+	m.filter = newJoinFilter(p, false)
 
 	return m
 }
 
+// joinHash the in-memory hash table of build side rows.
+type joinHash struct {
+	rows    []*datasource.SqlDriverMessageMap
+	matched []bool
+	keys    map[driver.Value][]int
+	size    int64
+}
+
+func newJoinHash() *joinHash {
+	return &joinHash{keys: make(map[driver.Value][]int)}
+}
+
+func (m *joinHash) add(msg *datasource.SqlDriverMessageMap) {
+	m.keys[msg.Key()] = append(m.keys[msg.Key()], len(m.rows))
+	m.rows = append(m.rows, msg)
+	m.matched = append(m.matched, false)
+	m.size += rowSize(msg.Vals)
+}
+
+// joinPartitions are the spill files of one side of a grace hash join.
+type joinPartitions []*spillFile
+
+func newJoinPartitions(ctx *plan.Context) (joinPartitions, error) {
+	parts := make(joinPartitions, joinSpillPartitions)
+	for i := range parts {
+		sf, err := newSpillFile(ctx)
+		if err != nil {
+			parts.Close()
+			return nil, err
+		}
+		parts[i] = sf
+	}
+	return parts, nil
+}
+
+func (m joinPartitions) Write(msg *datasource.SqlDriverMessageMap) error {
+	key, _ := msg.Key().(string)
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return m[h.Sum32()%uint32(len(m))].Write(msg)
+}
+
+func (m joinPartitions) Close() {
+	for _, sf := range m {
+		if sf != nil {
+			sf.Close()
+		}
+	}
+}
+
 func (m *JoinMerge) Run() error {
 	defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
 
-	outCh := m.MessageOut()
-
-	leftIn := m.ltask.MessageOut()
-	rightIn := m.rtask.MessageOut()
+	buildIn, probeIn := m.rtask.MessageOut(), m.ltask.MessageOut()
+	buildOuter, probeOuter := m.rightOuter, m.leftOuter
+	if m.buildLeft {
+		buildIn, probeIn = probeIn, buildIn
+		buildOuter, probeOuter = probeOuter, buildOuter
+	}
 
-	lh := make(map[driver.Value][]*datasource.SqlDriverMessageMap)
-	rh := make(map[driver.Value][]*datasource.SqlDriverMessageMap)
+	ht := newJoinHash()
+	var buildParts, probeParts joinPartitions
+	defer func() {
+		buildParts.Close()
+		probeParts.Close()
+	}()
 
-	wg := new(sync.WaitGroup)
-	wg.Add(1)
-	var fatalErr error
-	go func() {
-		for {
-			//u.Infof("In source Scanner msg %#v", msg)
-			select {
-			case <-m.SigChan():
-				u.Debugf("got signal quit")
-				wg.Done()
-				wg.Done()
-				return
-			case msg, ok := <-leftIn:
-				if !ok {
-					//u.Debugf("NICE, got left shutdown")
-					wg.Done()
-					return
-				} else {
-					switch mt := msg.(type) {
-					case *datasource.SqlDriverMessageMap:
-						key := mt.Key()
-						if key == "" {
-							fatalErr = fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
-							u.Errorf("no key? %#v  %v", mt, fatalErr)
-							close(m.TaskBase.sigCh)
-							return
-						}
-						lh[key] = append(lh[key], mt)
-					default:
-						fatalErr = fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
-						u.Errorf("unrecognized msg %T", msg)
-						close(m.TaskBase.sigCh)
-						return
+	// Build
+	for {
+		msg, ok, err := m.nextJoinMsg(buildIn)
+		if err != nil || m.closed() {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch {
+		case isNullKey(msg):
+			// NULL keys never match
+			if buildOuter && !m.emitJoined(msg, nil) {
+				return nil
+			}
+		case buildParts != nil:
+			if err := buildParts.Write(msg); err != nil {
+				return err
+			}
+		default:
+			ht.add(msg)
+			if m.Ctx.MemoryLimit > 0 && ht.size > m.Ctx.MemoryLimit {
+				// Switch to grace hash join, move what we have so far to disk
+				if buildParts, err = newJoinPartitions(m.Ctx); err != nil {
+					return err
+				}
+				for _, row := range ht.rows {
+					if err := buildParts.Write(row); err != nil {
+						return err
 					}
 				}
+				ht = nil
 			}
+		}
+	}
 
+	if buildParts != nil {
+		var err error
+		if probeParts, err = newJoinPartitions(m.Ctx); err != nil {
+			return err
 		}
-	}()
-	wg.Add(1)
-	go func() {
-		for {
+	}
 
-			//u.Infof("In source Scanner iter %#v", item)
-			select {
-			case <-m.SigChan():
-				u.Debugf("got quit signal join source 1")
-				wg.Done()
-				wg.Done()
-				return
-			case msg, ok := <-rightIn:
-				if !ok {
-					//u.Debugf("NICE, got right shutdown")
-					wg.Done()
-					return
-				} else {
-					switch mt := msg.(type) {
-					case *datasource.SqlDriverMessageMap:
-						key := mt.Key()
-						if key == "" {
-							fatalErr = fmt.Errorf(`To use Join msgs must have keys but got "" for %+v`, mt)
-							u.Errorf("no key? %#v  %v", mt, fatalErr)
-							close(m.TaskBase.sigCh)
-							return
-						}
-						rh[key] = append(rh[key], mt)
-					default:
-						fatalErr = fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
-						u.Errorf("unrecognized msg %T", msg)
-						close(m.TaskBase.sigCh)
-						return
-					}
-				}
+	// Probe, when partitioned the probe side is only written to disk here
+	for {
+		msg, ok, err := m.nextJoinMsg(probeIn)
+		if err != nil || m.closed() {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch {
+		case isNullKey(msg):
+			if probeOuter && !m.emitJoined(nil, msg) {
+				return nil
+			}
+		case probeParts != nil:
+			if err := probeParts.Write(msg); err != nil {
+				return err
 			}
+		default:
+			if !m.probe(ht, msg, probeOuter) {
+				return nil
+			}
+		}
+	}
+
+	if buildParts == nil {
+		m.emitUnmatched(ht, buildOuter)
+		return nil
+	}
 
+	// Join each partition pair in turn
+	for i := range buildParts {
+		ht = newJoinHash()
+		rdr, err := buildParts[i].Reader()
+		if err != nil {
+			return err
 		}
-	}()
-	wg.Wait()
-	//u.Info("leaving source scanner")
-	i := uint64(0)
-	for keyLeft, valLeft := range lh {
-		//u.Debugf("compare:  key:%v  left:%#v  right:%#v  rh: %#v", keyLeft, valLeft, rh[keyLeft], rh)
-		if valRight, ok := rh[keyLeft]; ok {
-			//u.Debugf("found match?\n\t%d left=%#v\n\t%d right=%#v", len(valLeft), valLeft, len(valRight), valRight)
-			msgs := m.mergeValueMessages(valLeft, valRight)
-			//u.Debugf("msgsct: %v   msgs:%#v", len(msgs), msgs)
-			for _, msg := range msgs {
-				//outCh <- datasource.NewUrlValuesMsg(i, msg)
-				//u.Debugf("i:%d   msg:%#v", i, msg)
-				msg.IdVal = i
-				i++
-				outCh <- msg
+		for {
+			msg, err := rdr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
 			}
+			ht.add(msg)
+		}
+		if m.Ctx.MemoryLimit > 0 && ht.size > m.Ctx.MemoryLimit {
+			u.Warnf("join partition %d of %d bytes exceeds memory limit %d", i, ht.size, m.Ctx.MemoryLimit)
+		}
+
+		rdr, err = probeParts[i].Reader()
+		if err != nil {
+			return err
+		}
+		for {
+			msg, err := rdr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			if !m.probe(ht, msg, probeOuter) {
+				return nil
+			}
+		}
+		if !m.emitUnmatched(ht, buildOuter) {
+			return nil
 		}
 	}
 	return nil
 }
 
-func (m *JoinMerge) mergeValueMessages(lmsgs, rmsgs []*datasource.SqlDriverMessageMap) []*datasource.SqlDriverMessageMap {
-	// m.leftStmt.Columns, m.rightStmt.Columns, nil
-	//func mergeValuesMsgs(lmsgs, rmsgs []datasource.Message, lcols, rcols []*rel.Column, cols map[string]*rel.Column) []*datasource.SqlDriverMessageMap {
-	out := make([]*datasource.SqlDriverMessageMap, 0)
-	//u.Infof("merge values: %v:%v", len(lcols), len(rcols))
-	for _, lm := range lmsgs {
-		//u.Warnf("nice SqlDriverMessageMap: %#v", lmt)
-		for _, rm := range rmsgs {
-			vals := make([]driver.Value, len(m.colIndex))
-			vals = m.valIndexing(vals, lm.Values(), m.leftStmt.Source.Columns)
-			vals = m.valIndexing(vals, rm.Values(), m.rightStmt.Source.Columns)
-			newMsg := datasource.NewSqlDriverMessageMap(0, vals, m.colIndex)
-			//u.Infof("out: %+v", newMsg)
-			out = append(out, newMsg)
+// nextJoinMsg read next message from a join input, ok is false
+// when input is closed or we have been signaled to quit.
+func (m *JoinMerge) nextJoinMsg(in <-chan schema.Message) (*datasource.SqlDriverMessageMap, bool, error) {
+	select {
+	case <-m.SigChan():
+		return nil, false, nil
+	case msg, ok := <-in:
+		if !ok {
+			return nil, false, nil
+		}
+		mt, isSdm := msg.(*datasource.SqlDriverMessageMap)
+		if !isSdm {
+			u.Errorf("unrecognized msg %T", msg)
+			return nil, false, fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
+		}
+		if m.nestedLoop {
+			mt.SetKey(nestedLoopKey)
+		}
+		return mt, true, nil
+	}
+}
+
+// closed has this task been signaled to quit.
+func (m *JoinMerge) closed() bool {
+	select {
+	case <-m.SigChan():
+		return true
+	default:
+		return false
+	}
+}
+
+// nestedLoopKey the key all rows share in a nested loop join.
+const nestedLoopKey = "\x00"
+
+func isNullKey(msg *datasource.SqlDriverMessageMap) bool {
+	key := msg.Key()
+	return key == nil || key == ""
+}
+
+// probe the hash table with this probe side row emitting the joined rows,
+// false if we were signaled to quit.
+func (m *JoinMerge) probe(ht *joinHash, msg *datasource.SqlDriverMessageMap, outer bool) bool {
+	matched := false
+	for _, idx := range ht.keys[msg.Key()] {
+		build := ht.rows[idx]
+		left, right := msg, build
+		if m.buildLeft {
+			left, right = build, msg
+		}
+		if !m.filter.match(left, right) {
+			continue
+		}
+		matched = true
+		ht.matched[idx] = true
+		if !m.emitJoined(build, msg) {
+			return false
+		}
+	}
+	if !matched && outer {
+		return m.emitJoined(nil, msg)
+	}
+	return true
+}
+
+// emitUnmatched for outer joins the build side rows that had no match.
+func (m *JoinMerge) emitUnmatched(ht *joinHash, outer bool) bool {
+	if !outer {
+		return true
+	}
+	for i, row := range ht.rows {
+		if !ht.matched[i] && !m.emitJoined(row, nil) {
+			return false
 		}
 	}
-	return out
+	return true
+}
+
+// emitJoined merge a build and probe row (either may be nil for outer joins)
+// and send it, false if we were signaled to quit.
+func (m *JoinMerge) emitJoined(build, probe *datasource.SqlDriverMessageMap) bool {
+	lm, rm := probe, build
+	if m.buildLeft {
+		lm, rm = build, probe
+	}
+	vals := make([]driver.Value, len(m.colIndex))
+	if lm != nil {
+		vals = m.valIndexing(vals, lm.Values(), m.leftStmt.Source.Columns)
+	}
+	if rm != nil {
+		vals = m.valIndexing(vals, rm.Values(), m.rightStmt.Source.Columns)
+	}
+	msg := datasource.NewSqlDriverMessageMap(m.ct, vals, m.colIndex)
+	m.ct++
+	select {
+	case <-m.SigChan():
+		return false
+	case m.msgOutCh <- msg:
+		m.track(msg)
+		return true
+	}
 }
 
 func (m *JoinMerge) valIndexing(valOut, valSource []driver.Value, cols []*rel.Column) []driver.Value {
@@ -294,3 +497,394 @@ func (m *JoinMerge) valIndexing(valOut, valSource []driver.Value, cols []*rel.Co
 	}
 	return valOut
 }
+
+// joinSeekBatchSize max number of left rows whose keys are looked up
+// in a single MultiGet.
+const joinSeekBatchSize = 100
+
+// JoinSeek is an index lookup (nested-loop) join, it streams the left
+// side and looks up matching right rows by key on a ConnSeeker instead
+// of scanning the right source.
+//
+//   source1   ->  batch keys  ->  Get/MultiGet(keys) on source2  -->
+//
+type JoinSeek struct {
+	*TaskBase
+	p         *plan.JoinMerge
+	ltask     TaskRunner
+	seeker    schema.ConnSeeker
+	tbl       *schema.Table
+	leftNode  expr.Node
+	rightNode expr.Node
+	where     expr.Node // right sources pushed down where
+	filter    *joinFilter
+	ct        uint64
+}
+
+// openSeeker open a conn to the join right source, nil if
+// the source does not support seeking.
+func openSeeker(ctx *plan.Context, p *plan.Source) (schema.ConnSeeker, error) {
+	conn := p.Conn
+	if conn == nil {
+		if p.DataSource == nil {
+			return nil, fmt.Errorf("missing data source")
+		}
+		c, err := ctx.OpenSource(p.DataSource, p.Stmt.SourceName())
+		if err != nil {
+			return nil, err
+		}
+		conn = c
+	}
+	seeker, ok := conn.(schema.ConnSeeker)
+	if !ok {
+		conn.Close()
+		return nil, nil
+	}
+	return seeker, nil
+}
+
+// NewJoinSeek create a seek join reading left rows from l and seeking
+// the right source.
+func NewJoinSeek(ctx *plan.Context, l TaskRunner, seeker schema.ConnSeeker, p *plan.JoinMerge) *JoinSeek {
+	m := &JoinSeek{
+		TaskBase:  NewTaskBase(ctx),
+		p:         p,
+		ltask:     l,
+		seeker:    seeker,
+		leftNode:  p.LeftFrom.JoinNodes()[0],
+		rightNode: p.RightFrom.JoinNodes()[0],
+		filter:    newJoinFilter(p, true),
+	}
+	if src, ok := p.Right.(*plan.Source); ok {
+		m.tbl = src.Tbl
+	}
+	if p.RightFrom.Source != nil && p.RightFrom.Source.Where != nil {
+		m.where = p.RightFrom.Source.Where.Expr
+	}
+	return m
+}
+
+func (m *JoinSeek) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+	if conn, ok := m.seeker.(schema.Conn); ok {
+		defer conn.Close()
+	}
+
+	inCh := m.ltask.MessageOut()
+	batch := make([]*datasource.SqlDriverMessageMap, 0, joinSeekBatchSize)
+
+	for {
+		msg, ok, err := m.next(inCh, len(batch) == 0)
+		if err != nil {
+			return err
+		}
+		if msg != nil {
+			batch = append(batch, msg)
+			if len(batch) < joinSeekBatchSize {
+				continue
+			}
+		}
+		// full batch, closed input, or nothing ready to read
+		if len(batch) > 0 {
+			if err := m.joinBatch(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// next left message, if wait is false returns nil msg when none
+// are immediately available.  ok is false on closed input or quit.
+func (m *JoinSeek) next(in <-chan schema.Message, wait bool) (*datasource.SqlDriverMessageMap, bool, error) {
+	var msg schema.Message
+	var ok bool
+	if wait {
+		select {
+		case <-m.SigChan():
+			return nil, false, nil
+		case msg, ok = <-in:
+		}
+	} else {
+		select {
+		case <-m.SigChan():
+			return nil, false, nil
+		case msg, ok = <-in:
+		default:
+			return nil, true, nil
+		}
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	mt, isSdm := msg.(*datasource.SqlDriverMessageMap)
+	if !isSdm {
+		return nil, false, fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", msg)
+	}
+	return mt, true, nil
+}
+
+// joinBatch look up the right rows for this batch of left rows and emit.
+func (m *JoinSeek) joinBatch(batch []*datasource.SqlDriverMessageMap) error {
+
+	lkeys := make([]string, len(batch))
+	keys := make([]driver.Value, 0, len(batch))
+	seen := make(map[string]bool, len(batch))
+	for i, lm := range batch {
+		kv, ok := vm.Eval(lm, m.leftNode)
+		if !ok || value.IsNilish(kv) {
+			// NULL keys never match
+			continue
+		}
+		lkeys[i] = kv.ToString()
+		if !seen[lkeys[i]] {
+			seen[lkeys[i]] = true
+			keys = append(keys, kv.Value())
+		}
+	}
+
+	var rows []schema.Message
+	if ms, ok := m.seeker.(schema.ConnMultiSeeker); ok && len(keys) > 0 {
+		found, err := ms.MultiGet(keys)
+		if err != nil {
+			return err
+		}
+		rows = found
+	} else {
+		for _, key := range keys {
+			row, err := m.seeker.Get(key)
+			if err == schema.ErrNotFound || row == nil {
+				continue
+			} else if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	right := make(map[string][]*datasource.SqlDriverMessageMap, len(rows))
+	for _, row := range rows {
+		rm := m.toMsgMap(row)
+		if rm == nil {
+			return fmt.Errorf("To use Join must use SqlDriverMessageMap but got %T", row)
+		}
+		if m.where != nil {
+			if wv, ok := vm.Eval(rm, m.where); !ok || !isTrue(wv) {
+				continue
+			}
+		}
+		kv, ok := vm.Eval(rm, m.rightNode)
+		if !ok || value.IsNilish(kv) {
+			continue
+		}
+		key := kv.ToString()
+		right[key] = append(right[key], rm)
+	}
+
+	for i, lm := range batch {
+		matched := false
+		if lkeys[i] != "" {
+			for _, rm := range right[lkeys[i]] {
+				if !m.filter.match(lm, rm) {
+					continue
+				}
+				matched = true
+				if !m.emit(lm, rm) {
+					return nil
+				}
+			}
+		}
+		if !matched && m.p.LeftOuter && !m.emit(lm, nil) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *JoinSeek) toMsgMap(row schema.Message) *datasource.SqlDriverMessageMap {
+	switch mt := row.(type) {
+	case *datasource.SqlDriverMessageMap:
+		return mt
+	case *datasource.SqlDriverMessage:
+		if m.tbl != nil {
+			return mt.ToMsgMap(m.tbl.FieldPositions)
+		}
+	}
+	return nil
+}
+
+// emit the merged left and right (may be nil) row, right rows are full
+// table rows so are read by column name, false if signaled to quit.
+func (m *JoinSeek) emit(lm, rm *datasource.SqlDriverMessageMap) bool {
+	vals := make([]driver.Value, len(m.p.ColIndex))
+	for _, col := range m.p.LeftFrom.Source.Columns {
+		if col.ParentIndex < 0 || col.ParentIndex >= len(vals) || col.Index >= len(lm.Vals) {
+			continue
+		}
+		vals[col.ParentIndex] = lm.Vals[col.Index]
+	}
+	if rm != nil {
+		for _, col := range m.p.RightFrom.Source.Columns {
+			if col.ParentIndex < 0 || col.ParentIndex >= len(vals) {
+				continue
+			}
+			if idx, ok := rm.ColIndex[col.SourceField]; ok && idx < len(rm.Vals) {
+				vals[col.ParentIndex] = rm.Vals[idx]
+			}
+		}
+	}
+	msg := datasource.NewSqlDriverMessageMap(m.ct, vals, m.p.ColIndex)
+	m.ct++
+	select {
+	case <-m.SigChan():
+		return false
+	case m.msgOutCh <- msg:
+		m.track(msg)
+		return true
+	}
+}
+
+func isTrue(v value.Value) bool {
+	bv, ok := v.(value.BoolValue)
+	return ok && bv.Val()
+}
+
+// joinFilter evaluates the full join ON expression for a pair of left,
+// right rows, as the join keys only cover its equality predicates.
+type joinFilter struct {
+	node  expr.Node
+	left  joinSide
+	right joinSide
+}
+
+// joinSide the alias and column positions of one side of a join.
+type joinSide struct {
+	alias string
+	cols  map[string]int // nil to read full table rows by column name
+	tbl   *schema.Table  // declared column types, nil if unknown
+}
+
+// joinRow an expr.ContextReader over the left and right rows of a join,
+// resolving alias qualified identities to that sides row.
+type joinRow struct {
+	f     *joinFilter
+	left  *datasource.SqlDriverMessageMap
+	right *datasource.SqlDriverMessageMap
+}
+
+// newJoinFilter for this join, nil if the ON expression is only the
+// equality join keys so needs no evaluation.  seekRight is true if right
+// rows are full table rows (seek join) instead of the right source rows.
+func newJoinFilter(p *plan.JoinMerge, seekRight bool) *joinFilter {
+	node := p.RightFrom.JoinExpr
+	if node == nil || (!p.NestedLoop && conjunctCount(node) == len(p.RightFrom.JoinNodes())) {
+		return nil
+	}
+	m := &joinFilter{
+		node:  node,
+		left:  joinSide{alias: sourceAlias(p.LeftFrom), cols: sourceColumns(p.LeftFrom), tbl: taskTable(p.Left)},
+		right: joinSide{alias: sourceAlias(p.RightFrom), tbl: taskTable(p.Right)},
+	}
+	if !seekRight {
+		m.right.cols = sourceColumns(p.RightFrom)
+	}
+	return m
+}
+
+// match does the ON expression evaluate true for these rows, a nil
+// filter matches everything.
+func (m *joinFilter) match(left, right *datasource.SqlDriverMessageMap) bool {
+	if m == nil {
+		return true
+	}
+	v, ok := vm.Eval(&joinRow{m, left, right}, m.node)
+	return ok && isTrue(v)
+}
+
+func (m *joinRow) Get(key string) (value.Value, bool) {
+	left, right, hasLeft := expr.LeftRight(key)
+	if !hasLeft {
+		if v, ok := m.f.left.get(m.left, right); ok {
+			return v, true
+		}
+		return m.f.right.get(m.right, right)
+	}
+	switch strings.ToLower(left) {
+	case m.f.left.alias:
+		return m.f.left.get(m.left, right)
+	case m.f.right.alias:
+		return m.f.right.get(m.right, right)
+	}
+	return nil, false
+}
+func (m *joinRow) Row() map[string]value.Value { return nil }
+func (m *joinRow) Ts() time.Time               { return time.Time{} }
+
+// get the value of column name of this sides row, as the declared type
+// of the column so sources of untyped values (csv) compare as typed.
+func (m *joinSide) get(row *datasource.SqlDriverMessageMap, name string) (value.Value, bool) {
+	var v driver.Value
+	if m.cols == nil {
+		val, ok := row.Get(name)
+		if !ok {
+			return nil, false
+		}
+		v = val.Value()
+	} else if idx, ok := m.cols[strings.ToLower(name)]; ok && idx < len(row.Vals) {
+		v = row.Vals[idx]
+	} else {
+		return nil, false
+	}
+	if m.tbl != nil {
+		if vt, ok := m.tbl.Column(name); ok {
+			if cv, err := coerceValue(vt, v); err == nil {
+				v = cv
+			}
+		}
+	}
+	return value.NewValue(v), true
+}
+
+// taskTable the table read by a source task, nil for other tasks.
+func taskTable(t plan.Task) *schema.Table {
+	if src, ok := t.(*plan.Source); ok {
+		return src.Tbl
+	}
+	return nil
+}
+
+// sourceColumns position in the source rows of each source field.
+func sourceColumns(from *rel.SqlSource) map[string]int {
+	cols := make(map[string]int)
+	if from.Source == nil {
+		return cols
+	}
+	for _, col := range from.Source.Columns {
+		if _, ok := cols[strings.ToLower(col.SourceField)]; !ok {
+			cols[strings.ToLower(col.SourceField)] = col.Index
+		}
+	}
+	return cols
+}
+
+func sourceAlias(from *rel.SqlSource) string {
+	if from.Alias != "" {
+		return strings.ToLower(from.Alias)
+	}
+	return strings.ToLower(from.Name)
+}
+
+// conjunctCount the number of AND'd predicates in node.
+func conjunctCount(node expr.Node) int {
+	if bn, ok := node.(*expr.BinaryNode); ok {
+		switch bn.Operator.T {
+		case lex.TokenAnd, lex.TokenLogicAnd:
+			return conjunctCount(bn.Args[0]) + conjunctCount(bn.Args[1])
+		}
+	}
+	return 1
+}
@@ -7,9 +7,11 @@ import (
 	u "github.com/araddon/gou"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -19,6 +21,10 @@ var (
 	_ TaskRunner = (*Upsert)(nil)
 	_ TaskRunner = (*DeletionTask)(nil)
 	_ TaskRunner = (*DeletionScanner)(nil)
+
+	// InsertBatchSize the number of rows of an INSERT ... SELECT, or
+	// CREATE TABLE ... AS SELECT, put to the source per PutMulti.
+	InsertBatchSize = 500
 )
 
 type (
@@ -45,6 +51,17 @@ type (
 	DeletionScanner struct {
 		*DeletionTask
 	}
+	// rowWriter puts the rows of a select into columns of a table in
+	// batches, each value coerced to the type of its column.
+	rowWriter struct {
+		ctx   *plan.Context
+		db    schema.ConnUpsert
+		tbl   *schema.Table
+		pos   []int             // table position of each select column
+		types []value.ValueType // table column type of each select column
+		batch [][]driver.Value
+		ct    int64
+	}
 )
 
 // An insert to write to data source
@@ -89,7 +106,7 @@ func (m *Upsert) Close() error {
 		return nil
 	}
 	m.closed = true
-	if closer, ok := m.db.(schema.Source); ok {
+	if closer, ok := m.db.(schema.Conn); ok {
 		if err := closer.Close(); err != nil {
 			return err
 		}
@@ -104,6 +121,8 @@ func (m *Upsert) Run() error {
 	var err error
 	var affectedCt int64
 	switch {
+	case m.insert != nil && m.insert.Select != nil:
+		affectedCt, err = m.insertSelect()
 	case m.insert != nil:
 		affectedCt, err = m.insertRows(m.insert.Rows)
 	case m.upsert != nil && len(m.upsert.Rows) > 0:
@@ -138,47 +157,122 @@ func (m *Upsert) updateValues() (int64, error) {
 		// fall through
 	}
 
+	// if our backend source supports Where-Patches, ie update multiple, and
+	// the values don't depend on the rows they update
+	dbpatch, ok := m.db.(schema.ConnPatchWhere)
+	if ok && !m.updateScans() {
+		valmap, err := m.updateValueMap(nil)
+		if err != nil {
+			return 0, err
+		}
+		var where expr.Node
+		if m.update.Where != nil {
+			where = m.update.Where.Expr
+		}
+		updated, err := dbpatch.PatchWhere(m.Ctx, where, valmap)
+		u.Infof("patch: %v %v", updated, err)
+		if err != nil {
+			return updated, err
+		}
+		return updated, nil
+	}
+
+	// - for sources/queries that can't do partial updates we need to do a read first
+	return m.updateScan()
+}
+
+// updateScans true if the update must read the rows it changes, its values
+// reference them or it is ordered/limited.
+func (m *Upsert) updateScans() bool {
+	if len(m.update.OrderBy) > 0 || m.update.Limit > 0 {
+		return true
+	}
+	for _, valcol := range m.update.Values {
+		if valcol.Expr != nil && len(expr.FindAllIdentityField(valcol.Expr)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// updateScan poly fill of an update for sources that can't patch the rows
+// of a where, select the rows of the update and put each back with its
+// values evaluated against the row.
+func (m *Upsert) updateScan() (int64, error) {
+
+	tbl, err := m.Ctx.Schema.Table(m.update.Table)
+	if err != nil {
+		return 0, err
+	}
+	cols := tbl.Columns()
+	keyCol := cols[0]
+	if pk := tbl.PrimaryKey(); len(pk) == 1 {
+		keyCol = pk[0]
+	}
+	for name := range m.update.Values {
+		if _, ok := tbl.FieldPositions[name]; !ok {
+			return 0, fmt.Errorf("no column %q in table %q", name, tbl.Name)
+		}
+		if name == keyCol {
+			// the row would be put as a new row, not replace the old
+			return 0, fmt.Errorf("cannot update key column %q of table %q", name, tbl.Name)
+		}
+	}
+
+	rows, err := runStatement(newChildContext(m.Ctx, m.update.SqlSelect().String()))
+	if err != nil {
+		return 0, err
+	}
+
+	var updatedCt int64
+	for _, row := range rows {
+		select {
+		case <-m.SigChan():
+			return updatedCt, nil
+		default:
+		}
+		if len(row) != len(cols) {
+			return updatedCt, fmt.Errorf("Wrong number of columns, expected %v got %v", len(cols), len(row))
+		}
+		valmap, err := m.updateValueMap(datasource.NewSqlDriverMessageMap(0, row, tbl.FieldPositions))
+		if err != nil {
+			return updatedCt, err
+		}
+		vals := make([]driver.Value, len(row))
+		copy(vals, row)
+		for name, val := range valmap {
+			vals[tbl.FieldPositions[name]] = val
+		}
+		if _, err := m.db.Put(m.Ctx, nil, vals); err != nil {
+			u.Errorf("Could not put values: %v", err)
+			return updatedCt, err
+		}
+		updatedCt++
+	}
+	return updatedCt, nil
+}
+
+// updateValueMap the values of the update, its expressions evaluated
+// against row, the row being updated (nil if none).
+func (m *Upsert) updateValueMap(row expr.ContextReader) (map[string]driver.Value, error) {
+
 	valmap := make(map[string]driver.Value, len(m.update.Values))
 	for key, valcol := range m.update.Values {
 
 		// TODO: qlbridge#13  Need a way of expressing which layer (here, db) this expr should run in?
 		//  - ie, run in backend datasource?   or here?  translate the expr to native language
 		if valcol.Expr != nil {
-			exprVal, ok := vm.Eval(nil, valcol.Expr)
+			exprVal, ok := vm.Eval(row, valcol.Expr)
 			if !ok {
 				u.Errorf("Could not evaluate: %s", valcol.Expr)
-				return 0, fmt.Errorf("Could not evaluate expression: %v", valcol.Expr)
+				return nil, fmt.Errorf("Could not evaluate expression: %v", valcol.Expr)
 			}
 			valmap[key] = exprVal.Value()
 		} else {
-			u.Debugf("%T  %v", valcol.Value.Value(), valcol.Value.Value())
 			valmap[key] = valcol.Value.Value()
 		}
-		//u.Debugf("key:%v col: %v   vals:%v", key, valcol, valmap[key])
 	}
-
-	// if our backend source supports Where-Patches, ie update multiple
-	dbpatch, ok := m.db.(schema.ConnPatchWhere)
-	if ok {
-		updated, err := dbpatch.PatchWhere(m.Ctx, m.update.Where.Expr, valmap)
-		u.Infof("patch: %v %v", updated, err)
-		if err != nil {
-			return updated, err
-		}
-		return updated, nil
-	}
-
-	// TODO:   If it does not implement Where Patch then we need to do a poly fill
-	//      Do we have to recognize if the Where is on a primary key?
-	// - for sources/queries that can't do partial updates we need to do a read first
-
-	// Create a key from Where
-	key := datasource.KeyFromWhere(m.update.Where)
-	if _, err := m.db.Put(m.Ctx, key, valmap); err != nil {
-		u.Errorf("Could not put values: %v", err)
-		return 0, err
-	}
-	return 1, nil
+	return valmap, nil
 }
 
 func (m *Upsert) insertRows(rows [][]*rel.ValueColumn) (int64, error) {
@@ -213,6 +307,128 @@ func (m *Upsert) insertRows(rows [][]*rel.ValueColumn) (int64, error) {
 	return int64(len(rows)), nil
 }
 
+// insertSelect put the rows of an INSERT ... SELECT, the select is run as
+// its own job streaming its rows to the source in batches.  Without a
+// column list the select's columns are the table's, in order.  Sources
+// that serialize their conns (sqlite) can not select from and put to
+// themselves in one statement.
+func (m *Upsert) insertSelect() (int64, error) {
+
+	tbl, err := m.Ctx.Schema.Table(m.insert.Table)
+	if err != nil {
+		return 0, err
+	}
+	cols := m.insert.ColumnNames()
+	if len(cols) == 0 {
+		cols = tbl.Columns()
+	}
+	w, err := newRowWriter(m.Ctx, m.db, tbl, cols)
+	if err != nil {
+		return 0, err
+	}
+
+	job, err := BuildSqlJob(newChildContext(m.Ctx, m.insert.Select.String()))
+	if err != nil {
+		return 0, err
+	}
+	if selCols := selectColumns(job); len(selCols) != len(cols) {
+		job.Close()
+		return 0, fmt.Errorf("INSERT into %d columns of %q but SELECT has %d columns", len(cols), tbl.Name, len(selCols))
+	}
+	if err = streamStatement(job, m.SigChan(), w.put); err != nil {
+		return w.ct, err
+	}
+	err = w.flush()
+	return w.ct, err
+}
+
+// selectColumns the names of the columns of the select a job was built
+// for, its star expanded.
+func selectColumns(job *JobExecutor) []string {
+	if sel, ok := job.Ctx.Stmt.(*rel.SqlSelect); ok {
+		return sel.Columns.AliasedFieldNames()
+	}
+	return nil
+}
+
+// newRowWriter a writer of rows of the named columns of tbl to db.
+func newRowWriter(ctx *plan.Context, db schema.ConnUpsert, tbl *schema.Table, cols []string) (*rowWriter, error) {
+	m := &rowWriter{
+		ctx:   ctx,
+		db:    db,
+		tbl:   tbl,
+		pos:   make([]int, len(cols)),
+		types: make([]value.ValueType, len(cols)),
+	}
+	for i, col := range cols {
+		pos, ok := tbl.FieldPositions[col]
+		if !ok {
+			return nil, fmt.Errorf("no column %q in table %q", col, tbl.Name)
+		}
+		m.pos[i] = pos
+		m.types[i], _ = tbl.Column(col)
+	}
+	return m, nil
+}
+
+// put a row of the select, its columns not selected are nil, putting the
+// batch once it is full.
+func (m *rowWriter) put(vals []driver.Value) error {
+	if len(vals) != len(m.pos) {
+		return fmt.Errorf("Wrong number of columns, expected %v got %v", len(m.pos), len(vals))
+	}
+	row := make([]driver.Value, len(m.tbl.Columns()))
+	for i, v := range vals {
+		cv, err := coerceValue(m.types[i], v)
+		if err != nil {
+			return fmt.Errorf("could not convert %v to %s for column %q: %v", v, m.types[i], m.tbl.Columns()[m.pos[i]], err)
+		}
+		row[m.pos[i]] = cv
+	}
+	m.batch = append(m.batch, row)
+	if len(m.batch) >= InsertBatchSize {
+		return m.flush()
+	}
+	return nil
+}
+
+// flush put the rows of the batch.
+func (m *rowWriter) flush() error {
+	if len(m.batch) == 0 {
+		return nil
+	}
+	if _, err := m.db.PutMulti(m.ctx.Context, nil, m.batch); err != nil {
+		u.Errorf("Could not put values: fordb T:%T  %v", m.db, err)
+		return err
+	}
+	m.ct += int64(len(m.batch))
+	m.batch = nil
+	return nil
+}
+
+// coerceValue v as a value of vt, the type of the column it is put in.
+// Values of columns of other types than string, int, number, bool and
+// time are put as they are.
+func coerceValue(vt value.ValueType, v driver.Value) (driver.Value, error) {
+	switch vt {
+	case value.StringType, value.IntType, value.NumberType, value.BoolType, value.TimeType:
+	default:
+		return v, nil
+	}
+	if v == nil {
+		return nil, nil
+	}
+	val := value.NewValue(v)
+	if val.Nil() || val.Type() == vt {
+		return v, nil
+	}
+	cv, err := value.Cast(vt, val)
+	if err != nil {
+		return nil, err
+	}
+	return cv.Value(), nil
+}
+
 func (m *DeletionTask) Close() error {
 	m.Lock()
 	if m.closed {
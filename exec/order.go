@@ -1,7 +1,9 @@
 package exec
 
 import (
+	"container/heap"
 	"fmt"
+	"io"
 	"sort"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/value"
 	"github.com/araddon/qlbridge/vm"
 )
 
@@ -56,19 +59,42 @@ func (m *Order) Close() error {
 	return m.TaskBase.Close()
 }
 
+// Run the order by task.  Rows are buffered and sorted in memory, unless
+// there is a LIMIT in which case only the top-n rows are held in a heap.
+// When the plan.Context MemoryLimit is exceeded sorted runs are spilled
+// to temp files and k-way merged on output.
 func (m *Order) Run() error {
 	defer m.Ctx.Recover()
 	defer close(m.msgOutCh)
+	defer func() {
+		m.isComplete = true
+		close(m.complete)
+	}()
 
 	outCh := m.MessageOut()
 	inCh := m.MessageIn()
 
 	colIndex := m.p.Stmt.ColIndexes()
-	orderCt := len(m.p.Stmt.OrderBy)
 
-	// are are going to hold entire row in memory while we are calculating
-	//  so obviously not scalable.
-	sl := NewOrderMessages(m.p)
+	sl, err := NewOrderMessages(m.p)
+	if err != nil {
+		return err
+	}
+
+	var top *orderHeap
+	if m.p.Stmt.Limit > 0 && !m.p.Stmt.Distinct {
+		// the OFFSET rows are skipped after us by the projection, the
+		// duplicates of a DISTINCT may be more than the LIMIT
+		top = &orderHeap{sl, m.p.Stmt.Limit + m.p.Stmt.Offset}
+	}
+
+	var runs []*spillFile
+	defer func() {
+		for _, run := range runs {
+			run.Close()
+		}
+	}()
+	memSize := int64(0)
 
 msgReadLoop:
 	for {
@@ -100,84 +126,194 @@ msgReadLoop:
 					sdm = datasource.NewSqlDriverMessageMapCtx(msg.Id(), msgReader, colIndex)
 				}
 
-				// We are going to use VM Engine to create a value for each statement in group by
-				//  then join each value together to create a unique key.
-				keys := make([]string, orderCt)
-				for i, col := range m.p.Stmt.OrderBy {
-					if col.Expr != nil {
-						if key, ok := vm.Eval(sdm, col.Expr); ok {
-							//u.Debugf("msgtype:%T  key:%q for-expr:%s", sdm, key, col.Expr)
-							keys[i] = key.ToString()
-						} else {
-							// Is this an error?
-							//u.Warnf("no key?  %s for %+v", col.Expr, sdm)
-						}
-					} else {
-						//u.Warnf("no col.expr? %#v", col)
-					}
+				mk := sl.newMsgKey(sdm)
+				if top != nil {
+					top.add(mk)
+					continue
 				}
 
-				//u.Infof("found key:%s for %+v", key, sdm)
-				sl.l = append(sl.l, &msgkey{keys, sdm})
+				sl.l = append(sl.l, mk)
+				memSize += rowSize(sdm.Vals)
+				if m.Ctx.MemoryLimit > 0 && memSize > m.Ctx.MemoryLimit {
+					run, err := sl.spill(m.Ctx)
+					if err != nil {
+						return err
+					}
+					runs = append(runs, run)
+					memSize = 0
+				}
 			}
 		}
 	}
 
 	sort.Sort(sl)
 
-	for _, m := range sl.l {
-		//u.Debugf("got %s:%v msgs", key, vals)
-		outCh <- m.msg
+	if len(runs) == 0 {
+		for _, mk := range sl.l {
+			select {
+			case <-m.SigChan():
+				return nil
+			case outCh <- mk.msg:
+				m.track(mk.msg)
+			}
+		}
+		return nil
+	}
+
+	if len(sl.l) > 0 {
+		run, err := sl.spill(m.Ctx)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
 	}
+	return m.mergeRuns(sl, runs)
+}
 
-	m.isComplete = true
-	close(m.complete)
+// mergeRuns k-way merge of the sorted spill files.
+func (m *Order) mergeRuns(sl *OrderMessages, runs []*spillFile) error {
+
+	outCh := m.MessageOut()
+	mh := &mergeHeap{om: sl}
+	for _, run := range runs {
+		rdr, err := run.Reader()
+		if err != nil {
+			return err
+		}
+		msg, err := rdr.Next()
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		mh.l = append(mh.l, &mergeCursor{sl.newMsgKey(msg), rdr})
+	}
+	heap.Init(mh)
 
+	for mh.Len() > 0 {
+		cur := mh.l[0]
+		select {
+		case <-m.SigChan():
+			return nil
+		case outCh <- cur.mk.msg:
+			m.track(cur.mk.msg)
+		}
+		msg, err := cur.rdr.Next()
+		if err == io.EOF {
+			heap.Pop(mh)
+			continue
+		} else if err != nil {
+			return err
+		}
+		cur.mk = sl.newMsgKey(msg)
+		heap.Fix(mh, 0)
+	}
 	return nil
 }
 
 type msgkey struct {
-	keys []string
+	keys []value.Value
 	msg  *datasource.SqlDriverMessageMap
 }
+
+// OrderMessages sortable messages, compared on their typed order by keys.
 type OrderMessages struct {
-	l      []*msgkey
-	invert []bool
+	l  []*msgkey
+	ob *vm.OrderBy
 }
 
-func NewOrderMessages(p *plan.Order) *OrderMessages {
-	invert := make([]bool, len(p.Stmt.OrderBy))
-	for i, col := range p.Stmt.OrderBy {
-		//u.Debugf("invert?  %s ORDER %v", col.Expr, col.Order)
-		if col.Expr != nil {
-			if !col.Asc() {
-				invert[i] = true
-			}
-		}
+// NewOrderMessages create sortable message list for the order by columns
+// of this plan, error if they use an unknown collation.
+func NewOrderMessages(p *plan.Order) (*OrderMessages, error) {
+	ob, err := vm.NewOrderBy(p.Stmt.OrderBy)
+	if err != nil {
+		return nil, err
 	}
 	return &OrderMessages{
-		l:      make([]*msgkey, 0),
-		invert: invert,
+		l:  make([]*msgkey, 0),
+		ob: ob,
+	}, nil
+}
+
+// newMsgKey evaluate the order by expressions for this message
+// to create its sort keys.
+func (m *OrderMessages) newMsgKey(sdm *datasource.SqlDriverMessageMap) *msgkey {
+	return &msgkey{m.ob.Keys(sdm), sdm}
+}
+
+// spill sort the buffered messages and write them as a run to a temp file.
+func (m *OrderMessages) spill(ctx *plan.Context) (*spillFile, error) {
+	sort.Sort(m)
+	run, err := newSpillFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, mk := range m.l {
+		if err = run.Write(mk.msg); err != nil {
+			run.Close()
+			return nil, err
+		}
 	}
+	m.l = m.l[:0]
+	return run, nil
+}
+
+func (m *OrderMessages) less(a, b *msgkey) bool {
+	return m.ob.Compare(a.keys, b.keys) < 0
 }
 func (m *OrderMessages) Len() int {
 	return len(m.l)
 }
 func (m *OrderMessages) Less(i, j int) bool {
-	for ki, key := range m.l[i].keys {
-		if key < m.l[j].keys[ki] {
-			if m.invert[ki] {
-				return false
-			}
-			return true
-		} else {
-			if m.invert[ki] {
-				return true
-			}
-		}
-	}
-	return false
+	return m.less(m.l[i], m.l[j])
 }
 func (m *OrderMessages) Swap(i, j int) {
 	m.l[i], m.l[j] = m.l[j], m.l[i]
 }
+
+// orderHeap keeps the top n messages for an ORDER BY ... LIMIT n, as a
+// heap with the last (worst) message at the root.
+type orderHeap struct {
+	*OrderMessages
+	n int
+}
+
+func (m *orderHeap) Less(i, j int) bool { return m.less(m.l[j], m.l[i]) }
+func (m *orderHeap) Push(x interface{}) { m.l = append(m.l, x.(*msgkey)) }
+func (m *orderHeap) Pop() interface{} {
+	last := m.l[len(m.l)-1]
+	m.l = m.l[:len(m.l)-1]
+	return last
+}
+func (m *orderHeap) add(mk *msgkey) {
+	if len(m.l) < m.n {
+		heap.Push(m, mk)
+		return
+	}
+	if m.less(mk, m.l[0]) {
+		m.l[0] = mk
+		heap.Fix(m, 0)
+	}
+}
+
+// mergeCursor current message of a sorted run being merged.
+type mergeCursor struct {
+	mk  *msgkey
+	rdr *spillReader
+}
+
+// mergeHeap min-heap of the current message of each sorted run.
+type mergeHeap struct {
+	om *OrderMessages
+	l  []*mergeCursor
+}
+
+func (m *mergeHeap) Len() int           { return len(m.l) }
+func (m *mergeHeap) Less(i, j int) bool { return m.om.less(m.l[i].mk, m.l[j].mk) }
+func (m *mergeHeap) Swap(i, j int)      { m.l[i], m.l[j] = m.l[j], m.l[i] }
+func (m *mergeHeap) Push(x interface{}) { m.l = append(m.l, x.(*mergeCursor)) }
+func (m *mergeHeap) Pop() interface{} {
+	last := m.l[len(m.l)-1]
+	m.l = m.l[:len(m.l)-1]
+	return last
+}
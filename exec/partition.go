@@ -0,0 +1,74 @@
+package exec
+
+import (
+	"sync"
+
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*PartitionMerge)(nil)
+)
+
+// PartitionMerge merges the rows of the parallel scans of each partition
+// of a source into a single output, in no particular order.
+//
+//   partition 0 ->
+//                  \
+//   partition 1 ->  --  merge  -->
+//                  /
+//   partition n ->
+//
+type PartitionMerge struct {
+	*TaskBase
+	inputs []TaskRunner
+}
+
+// NewPartitionMerge create a merge of the partition input tasks.
+func NewPartitionMerge(ctx *plan.Context, inputs []TaskRunner) *PartitionMerge {
+	return &PartitionMerge{
+		TaskBase: NewTaskBase(ctx),
+		inputs:   inputs,
+	}
+}
+
+// Run the merge until each input is closed.
+func (m *PartitionMerge) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	var wg sync.WaitGroup
+	for _, in := range m.inputs {
+		wg.Add(1)
+		go func(inCh MessageChan) {
+			defer wg.Done()
+			for {
+				select {
+				case <-m.SigChan():
+					return
+				case msg, ok := <-inCh:
+					if !ok {
+						return
+					}
+					if !m.send(msg) {
+						return
+					}
+				}
+			}
+		}(in.MessageOut())
+	}
+	wg.Wait()
+	return nil
+}
+
+func (m *PartitionMerge) send(msg schema.Message) bool {
+	select {
+	case <-m.SigChan():
+		return false
+	case m.msgOutCh <- msg:
+		m.track(msg)
+		return true
+	}
+}
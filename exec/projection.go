@@ -25,6 +25,9 @@ type Projection struct {
 // and additional columns such as those used in Where, GroupBy etc are used
 // even if they will not be used in Final projection
 func NewProjection(ctx *plan.Context, p *plan.Projection) *Projection {
+	if p.LimitOnly {
+		return NewProjectionLimit(ctx, p)
+	}
 	if p.Final {
 		return NewProjectionFinal(ctx, p)
 	}
@@ -109,6 +112,15 @@ func (m *Projection) CloseFinal() error {
 	return m.TaskBase.Close()
 }
 
+// offset the number of rows to skip, only the final projection applies
+// the select OFFSET.
+func (m *Projection) offset() int {
+	if !m.p.Final || m.p.P == nil {
+		return 0
+	}
+	return m.p.P.Offset()
+}
+
 // Create handler function for evaluation (ie, field selection from tuples)
 func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 
@@ -116,6 +128,11 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 	columns := m.p.Stmt.Columns
 	colIndex := m.p.Stmt.ColIndexes()
 	limit := m.p.Stmt.Limit
+	offset := m.offset()
+	if m.p.Stmt.Distinct {
+		// limited after the rows are de-duplicated
+		limit, offset = 0, 0
+	}
 	if limit == 0 {
 		limit = math.MaxInt32
 	}
@@ -124,6 +141,13 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 	if m.p.Proj != nil {
 		colCt = len(m.p.Proj.Columns)
 	}
+	// window columns were evaluated by the Window task, read by key
+	winKeys := make([]string, len(columns))
+	for i, col := range columns {
+		if col.Over != nil {
+			winKeys[i] = windowKey(col)
+		}
+	}
 
 	rowCt := 0
 	return func(ctx *plan.Context, msg schema.Message) bool {
@@ -135,6 +159,11 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 		default:
 		}
 
+		if offset > 0 {
+			offset--
+			return true // skip it, before the limit
+		}
+
 		//u.Infof("got projection message: %T %#v", msg, msg.Body())
 		var outMsg schema.Message
 		switch mt := msg.(type) {
@@ -147,7 +176,7 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 			}, mt.Ts())
 			//u.Debugf("about to project: %#v", mt)
 			colIdx := -1
-			for _, col := range columns {
+			for ci, col := range columns {
 				colIdx += 1
 				//u.Debugf("%d  colidx:%v sidx: %v pidx:%v key:%q Expr:%v", colIdx, col.Index, col.SourceIndex, col.ParentIndex, col.Key(), col.Expr)
 
@@ -202,6 +231,10 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 
 				} else if col.Expr == nil {
 					u.Warnf("wat?   nil col expr? %#v", col)
+				} else if winKeys[ci] != "" {
+					if idx, ok := mt.ColIndex[winKeys[ci]]; ok && idx < len(mt.Vals) {
+						row[colIdx] = mt.Vals[idx]
+					}
 				} else {
 					v, ok := vm.Eval(rdr, col.Expr)
 					if !ok {
@@ -304,6 +337,7 @@ func (m *Projection) projectionEvaluator(isFinal bool) MessageHandler {
 		//u.Debugf("row:%d  completed projection for: %p %#v", rowCt, out, outMsg)
 		select {
 		case out <- outMsg:
+			m.track(outMsg)
 			return true
 		case <-m.SigChan():
 			return false
@@ -319,6 +353,7 @@ func (m *Projection) limitEvaluator() MessageHandler {
 	if limit == 0 {
 		limit = math.MaxInt32
 	}
+	offset := m.offset()
 
 	rowCt := 0
 	return func(ctx *plan.Context, msg schema.Message) bool {
@@ -330,6 +365,11 @@ func (m *Projection) limitEvaluator() MessageHandler {
 		default:
 		}
 
+		if offset > 0 {
+			offset--
+			return true // swallow it
+		}
+
 		if rowCt >= limit {
 			if rowCt == limit {
 				//u.Debugf("%p Projection reaching Limit!!! rowct:%v  limit:%v", m, rowCt, limit)
@@ -345,6 +385,7 @@ func (m *Projection) limitEvaluator() MessageHandler {
 
 		select {
 		case out <- msg:
+			m.track(msg)
 			return true
 		case <-m.SigChan():
 			return false
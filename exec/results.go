@@ -59,9 +59,14 @@ func NewResultExecWriter(ctx *plan.Context) *ResultExecWriter {
 	m.Handler = func(ctx *plan.Context, msg schema.Message) bool {
 		switch mt := msg.(type) {
 		case *datasource.SqlDriverMessage:
+			// a mutation that errored sends its error rather than counts
 			if len(mt.Vals) > 1 {
-				m.lastInsertID = mt.Vals[0].(int64)
-				m.rowsAffected = mt.Vals[1].(int64)
+				if id, ok := mt.Vals[0].(int64); ok {
+					m.lastInsertID = id
+				}
+				if ct, ok := mt.Vals[1].(int64); ok {
+					m.rowsAffected = ct
+				}
 			}
 		case nil:
 			u.Warnf("got nil")
@@ -102,6 +107,10 @@ func NewResultBuffer(ctx *plan.Context, writeTo *[]schema.Message) *ResultBuffer
 		TaskBase: NewTaskBase(ctx),
 	}
 	m.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if msg == nil {
+			// nil is the end of rows signal, ie after a limit
+			return true
+		}
 		*writeTo = append(*writeTo, msg)
 		//u.Infof("write to msgs: %v", len(*writeTo))
 		return true
@@ -166,7 +175,13 @@ func (m *ResultBuffer) Close() error {
 func (m *ResultWriter) Next(dest []driver.Value) error {
 	select {
 	case <-m.SigChan():
+		if err := m.Ctx.Err(); err != nil {
+			// cancelled or timed out
+			return err
+		}
 		return ErrShuttingDown
+	case <-m.Ctx.Done():
+		return m.Ctx.Err()
 	case err := <-m.ErrChan():
 		return err
 	case msg, ok := <-m.MessageIn():
@@ -213,6 +228,7 @@ func resultWrite(m *ResultWriter) MessageHandler {
 
 		select {
 		case out <- msg:
+			m.track(msg)
 			return true
 		case <-m.SigChan():
 			return false
@@ -252,6 +268,8 @@ func msgToRow(msg schema.Message, cols []string, dest []driver.Value) error {
 	*/
 	case *datasource.SqlDriverMessageMap:
 		for i, key := range cols {
+			// dest is re-used across rows, NULL must not carry the prior rows value
+			dest[i] = nil
 			val, ok := mt.Get(key)
 			//u.Debugf("key=%v %T %v", key, val, val)
 			if ok && val != nil && !val.Nil() {
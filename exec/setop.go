@@ -0,0 +1,201 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*SetOp)(nil)
+)
+
+// SetOp combines the rows of 2 input tasks (UNION, INTERSECT, EXCEPT).
+//
+//   left select  ->
+//                  \
+//                    --  set op  -->
+//                  /
+//   right select ->
+//
+// UNION ALL streams the left then the right rows.  UNION de-duplicates on
+// a hash of the row values, INTERSECT and EXCEPT read the right rows into
+// a hash table then stream the left rows against it.
+type SetOp struct {
+	*TaskBase
+	p        *plan.SetOp
+	ltask    TaskRunner
+	rtask    TaskRunner
+	colIndex map[string]int
+	ct       uint64
+}
+
+// NewSetOp create a set operation task of left, right input tasks.
+func NewSetOp(ctx *plan.Context, l, r TaskRunner, p *plan.SetOp) *SetOp {
+	return &SetOp{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		ltask:    l,
+		rtask:    r,
+		colIndex: p.ColIndex,
+	}
+}
+
+// Run the set operation.
+func (m *SetOp) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	left, right := m.ltask.MessageOut(), m.rtask.MessageOut()
+
+	switch m.p.Op {
+	case lex.TokenUnion:
+		var seen map[string]struct{}
+		if !m.p.All {
+			seen = make(map[string]struct{})
+		}
+		for _, in := range []<-chan schema.Message{left, right} {
+			for {
+				vals, ok, err := m.next(in)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					break
+				}
+				if seen != nil {
+					key := setOpKey(vals)
+					if _, dup := seen[key]; dup {
+						continue
+					}
+					seen[key] = struct{}{}
+				}
+				if !m.emit(vals) {
+					return nil
+				}
+			}
+		}
+		return nil
+
+	case lex.TokenIntersect, lex.TokenExcept:
+		counts := make(map[string]int)
+		for {
+			vals, ok, err := m.next(right)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			counts[setOpKey(vals)]++
+		}
+		if m.closed() {
+			return nil
+		}
+		intersect := m.p.Op == lex.TokenIntersect
+		emitted := make(map[string]struct{})
+		for {
+			vals, ok, err := m.next(left)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			key := setOpKey(vals)
+			ct, inRight := counts[key]
+			if m.p.All {
+				// multiset, each right row cancels/matches one left row
+				if ct > 0 {
+					counts[key] = ct - 1
+				}
+				if intersect != (ct > 0) {
+					continue
+				}
+			} else {
+				if intersect != inRight {
+					continue
+				}
+				if _, dup := emitted[key]; dup {
+					continue
+				}
+				emitted[key] = struct{}{}
+			}
+			if !m.emit(vals) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unsupported set operation %s", m.p.Op)
+}
+
+// next row values from an input, ok is false when the input is
+// closed or we have been signaled to quit.
+func (m *SetOp) next(in <-chan schema.Message) ([]driver.Value, bool, error) {
+	for {
+		select {
+		case <-m.SigChan():
+			return nil, false, nil
+		case msg, ok := <-in:
+			if !ok {
+				return nil, false, nil
+			}
+			switch mt := msg.(type) {
+			case nil:
+				// end of rows sentinel from a limit
+				continue
+			case *datasource.SqlDriverMessageMap:
+				return mt.Values(), true, nil
+			case *datasource.SqlDriverMessage:
+				return mt.Vals, true, nil
+			default:
+				u.Errorf("unrecognized msg %T", msg)
+				return nil, false, fmt.Errorf("set operation requires SqlDriverMessageMap but got %T", msg)
+			}
+		}
+	}
+}
+
+func (m *SetOp) emit(vals []driver.Value) bool {
+	msg := datasource.NewSqlDriverMessageMap(m.ct, vals, m.colIndex)
+	m.ct++
+	select {
+	case <-m.SigChan():
+		return false
+	case m.msgOutCh <- msg:
+		m.track(msg)
+		return true
+	}
+}
+
+// closed has this task been signaled to quit.
+func (m *SetOp) closed() bool {
+	select {
+	case <-m.SigChan():
+		return true
+	default:
+		return false
+	}
+}
+
+// setOpKey the hash key of a row, NULLs compare equal to each other.
+func setOpKey(vals []driver.Value) string {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		if v == nil {
+			parts[i] = "\x01"
+			continue
+		}
+		parts[i] = value.NewValue(v).ToString()
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -137,7 +137,7 @@ func (m *Source) Run() error {
 		case <-sigChan:
 			return nil
 		case m.msgOutCh <- item:
-			// continue
+			m.track(item)
 		}
 
 	}
@@ -0,0 +1,120 @@
+package exec
+
+import (
+	"bufio"
+	"database/sql/driver"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/plan"
+)
+
+func init() {
+	// Row value types that may be found in a message and have to be
+	// gob encoded into spill files.
+	gob.Register(time.Time{})
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+	gob.Register(map[string]string{})
+	gob.Register(map[string]int64{})
+	gob.Register(map[string]float64{})
+	gob.Register(map[string]bool{})
+	gob.Register(map[string]time.Time{})
+}
+
+// spillRow is the on-disk representation of a message in a spill file.
+type spillRow struct {
+	Id   uint64
+	Key  string
+	Vals []driver.Value
+}
+
+// spillFile is a temp file of gob encoded rows, written by a task which
+// has exceeded the plan.Context MemoryLimit and read back sequentially.
+type spillFile struct {
+	f        *os.File
+	w        *bufio.Writer
+	enc      *gob.Encoder
+	ct       int
+	colIndex map[string]int // col index of the messages in this file
+}
+
+func newSpillFile(ctx *plan.Context) (*spillFile, error) {
+	f, err := ioutil.TempFile(ctx.TempDir, "qlbridge-spill-")
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &spillFile{f: f, w: w, enc: gob.NewEncoder(w)}, nil
+}
+
+// Write append a message to end of file.
+func (m *spillFile) Write(msg *datasource.SqlDriverMessageMap) error {
+	if m.colIndex == nil {
+		m.colIndex = msg.ColIndex
+	}
+	m.ct++
+	key, _ := msg.Key().(string)
+	return m.enc.Encode(&spillRow{Id: msg.IdVal, Key: key, Vals: msg.Vals})
+}
+
+// Reader flush writes and return a reader positioned at start of file.
+func (m *spillFile) Reader() (*spillReader, error) {
+	if err := m.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := m.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return &spillReader{dec: gob.NewDecoder(bufio.NewReader(m.f)), colIndex: m.colIndex}, nil
+}
+
+// Close and remove the underlying temp file.
+func (m *spillFile) Close() error {
+	m.f.Close()
+	return os.Remove(m.f.Name())
+}
+
+// spillReader reads back messages written to a spillFile.
+type spillReader struct {
+	dec      *gob.Decoder
+	colIndex map[string]int
+}
+
+// Next message in file, returns io.EOF at end.
+func (m *spillReader) Next() (*datasource.SqlDriverMessageMap, error) {
+	row := spillRow{}
+	if err := m.dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	msg := datasource.NewSqlDriverMessageMap(row.Id, row.Vals, m.colIndex)
+	if row.Key != "" {
+		msg.SetKey(row.Key)
+	}
+	return msg, nil
+}
+
+// rowSize approximate in-memory size in bytes of a row of values, used
+// for accounting against the plan.Context MemoryLimit.
+func rowSize(vals []driver.Value) int64 {
+	sz := int64(64)
+	for _, v := range vals {
+		switch vt := v.(type) {
+		case string:
+			sz += int64(len(vt)) + 16
+		case []byte:
+			sz += int64(len(vt)) + 24
+		case time.Time:
+			sz += 24
+		case nil:
+			sz += 16
+		default:
+			sz += 32
+		}
+	}
+	return sz
+}
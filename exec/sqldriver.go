@@ -1,16 +1,11 @@
 package exec
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
-	"errors"
 	"fmt"
-	"io"
-	"strconv"
-	"strings"
 	"sync"
-	"time"
 
 	u "github.com/araddon/gou"
 
@@ -18,18 +13,26 @@ import (
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
 )
 
 var (
 	// Ensure our driver implements appropriate database/sql interfaces
-	_ driver.Conn    = (*qlbConn)(nil)
-	_ driver.Driver  = (*qlbdriver)(nil)
-	_ driver.Execer  = (*qlbConn)(nil)
-	_ driver.Queryer = (*qlbConn)(nil)
-	_ driver.Result  = (*qlbResult)(nil)
-	_ driver.Rows    = (*qlbRows)(nil)
-	_ driver.Stmt    = (*qlbStmt)(nil)
-	//_ driver.Tx      = (*driverConn)(nil)
+	_ driver.Conn              = (*qlbConn)(nil)
+	_ driver.Driver            = (*qlbdriver)(nil)
+	_ driver.Execer            = (*qlbConn)(nil)
+	_ driver.ExecerContext     = (*qlbConn)(nil)
+	_ driver.Queryer           = (*qlbConn)(nil)
+	_ driver.QueryerContext    = (*qlbConn)(nil)
+	_ driver.NamedValueChecker = (*qlbConn)(nil)
+	_ driver.Result            = (*qlbResult)(nil)
+	_ driver.Rows              = (*qlbRows)(nil)
+	_ driver.Stmt              = (*qlbStmt)(nil)
+	_ driver.StmtExecContext   = (*qlbStmt)(nil)
+	_ driver.StmtQueryContext  = (*qlbStmt)(nil)
+	_ driver.ColumnConverter   = (*qlbStmt)(nil)
+	_ driver.NamedValueChecker = (*qlbStmt)(nil)
+	_ driver.Tx                = (*qlbTx)(nil)
 
 	// Create an instance of our driver
 	qlbd          = &qlbdriver{}
@@ -106,6 +109,8 @@ type qlbConn struct {
 	parallel bool   // Do we Run In Background Mode?  Default = true
 	connInfo string //
 	schema   *schema.Schema
+	txn      *schema.Transaction // open transaction, statements run in it
+	prepared map[string]*plan.PreparedStatement
 }
 
 // Exec may return ErrSkip.
@@ -125,9 +130,41 @@ func (m *qlbConn) Query(query string, args []driver.Value) (driver.Rows, error)
 	return stmt.Query(args)
 }
 
-// Prepare returns a prepared statement, bound to this connection.
+// ExecContext Execer implementation, the statement is cancelled when ctx is done.
+func (m *qlbConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	stmt := &qlbStmt{conn: m, query: query}
+	return stmt.ExecContext(ctx, args)
+}
+
+// QueryContext Queryer implementation, the query is cancelled when ctx is done.
+func (m *qlbConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	stmt := &qlbStmt{conn: m, query: query}
+	return stmt.QueryContext(ctx, args)
+}
+
+// CheckNamedValue NamedValueChecker implementation, args are converted to
+// driver values, or passed through as is if they are already a value.Value.
+func (m *qlbConn) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(nv)
+}
+
+// Prepare returns a prepared statement, bound to this connection.  The
+// statement is parsed once per connection and re-used, values are bound
+// to its placeholders each time it is run.
 func (m *qlbConn) Prepare(query string) (driver.Stmt, error) {
-	return nil, expr.ErrNotImplemented
+	ps, ok := m.prepared[query]
+	if !ok {
+		var err error
+		ps, err = plan.NewPreparedStatement(query)
+		if err != nil {
+			return nil, err
+		}
+		if m.prepared == nil {
+			m.prepared = make(map[string]*plan.PreparedStatement)
+		}
+		m.prepared[query] = ps
+	}
+	return &qlbStmt{conn: m, query: query, ps: ps}, nil
 }
 
 // Close invalidates and potentially stops any current
@@ -139,22 +176,53 @@ func (m *qlbConn) Prepare(query string) (driver.Stmt, error) {
 // idle connections, it shouldn't be necessary for drivers to
 // do their own connection caching.
 func (m *qlbConn) Close() error {
-	//u.Debugf("sqlbConn.Close() do we need to do anything here?")
+	if txn := m.openTxn(); txn != nil {
+		// an open transaction is abandoned
+		m.txn = nil
+		return txn.Rollback()
+	}
 	return nil
 }
 
-// Begin starts and returns a new transaction.
+// openTxn the open transaction of this connection, nil if there is none
+// or it was ended by a COMMIT or ROLLBACK statement.
+func (m *qlbConn) openTxn() *schema.Transaction {
+	if m.txn != nil && m.txn.Done() {
+		m.txn = nil
+	}
+	return m.txn
+}
+
+// Begin starts and returns a new transaction.  The statements of this
+// connection run in it, on the sources supporting schema.ConnTransaction,
+// until it is committed or rolled back.
 func (m *qlbConn) Begin() (driver.Tx, error) {
-	return nil, expr.ErrNotImplemented
+	if m.openTxn() != nil {
+		return nil, fmt.Errorf("transaction already open")
+	}
+	m.txn = schema.NewTransaction()
+	return &qlbTx{conn: m, txn: m.txn}, nil
 }
 
 // sql.Tx Transaction Interface implementation.
-type qlbTx struct{}
+type qlbTx struct {
+	conn *qlbConn
+	txn  *schema.Transaction
+}
 
-func (conn *qlbTx) Commit() error {
-	return expr.ErrNotImplemented
+func (m *qlbTx) Commit() error {
+	m.done()
+	return m.txn.Commit()
+}
+func (m *qlbTx) Rollback() error {
+	m.done()
+	return m.txn.Rollback()
+}
+func (m *qlbTx) done() {
+	if m.conn.txn == m.txn {
+		m.conn.txn = nil
+	}
 }
-func (conn *qlbTx) Rollback() error { return expr.ErrNotImplemented }
 
 // driver.Stmt Interface implementation.
 //
@@ -165,6 +233,7 @@ type qlbStmt struct {
 	job   *JobExecutor
 	query string
 	conn  *qlbConn
+	ps    *plan.PreparedStatement // optional, the statement is prepared
 }
 
 // Close closes the statement.
@@ -175,6 +244,9 @@ func (m *qlbStmt) Close() error {
 	if m.job != nil {
 		m.job.Close()
 	}
+	if m.ps != nil && m.conn.prepared[m.query] == m.ps {
+		delete(m.conn.prepared, m.query)
+	}
 	return nil
 }
 
@@ -187,22 +259,61 @@ func (m *qlbStmt) Close() error {
 // NumInput may also return -1, if the driver doesn't know
 // its number of placeholders. In that case, the sql package
 // will not sanity check Exec or Query argument counts.
-func (m *qlbStmt) NumInput() int { return 0 }
+func (m *qlbStmt) NumInput() int {
+	if m.ps == nil {
+		return -1
+	}
+	return m.ps.NumInput()
+}
+
+// CheckNamedValue NamedValueChecker implementation.
+func (m *qlbStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	return checkNamedValue(nv)
+}
 
 // Exec executes a query that doesn't return rows, such
 // as an INSERT, UPDATE, DELETE
 func (m *qlbStmt) Exec(args []driver.Value) (driver.Result, error) {
-	var err error
-	if len(args) > 0 {
-		m.query, err = queryArgsConvert(m.query, args)
-		if err != nil {
-			return nil, err
-		}
+	return m.runExec(m.context(nil, bindValues(args)))
+}
+
+// ExecContext StmtExecContext implementation, the statement is cancelled
+// when ctx is done.
+func (m *qlbStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return m.runExec(m.context(ctx, bindNamedValues(args)))
+}
+
+// Query executes a query that may return rows, such as a SELECT
+func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return m.runQuery(m.context(nil, bindValues(args)))
+}
+
+// QueryContext StmtQueryContext implementation, the query is cancelled
+// when ctx is done.
+func (m *qlbStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return m.runQuery(m.context(ctx, bindNamedValues(args)))
+}
+
+// context the plan context to run the statement with params bound to its
+// placeholders.
+func (m *qlbStmt) context(cctx context.Context, params *expr.Params) *plan.Context {
+	var ctx *plan.Context
+	if m.ps != nil {
+		ctx = m.ps.Context(params)
+	} else {
+		ctx = plan.NewContext(m.query)
+		ctx.Params = params
 	}
+	ctx.Schema = m.conn.schema
+	ctx.Txn = m.conn.openTxn()
+	if cctx != nil {
+		ctx.Context = cctx
+	}
+	return ctx
+}
 
+func (m *qlbStmt) runExec(ctx *plan.Context) (driver.Result, error) {
 	// Create a Job, which is Dag of Tasks that Run()
-	ctx := plan.NewContext(m.query)
-	ctx.Schema = m.conn.schema
 	job, err := BuildSqlJob(ctx)
 	if err != nil {
 		return nil, err
@@ -212,32 +323,27 @@ func (m *qlbStmt) Exec(args []driver.Value) (driver.Result, error) {
 	resultWriter := NewResultExecWriter(ctx)
 	job.RootTask.Add(resultWriter)
 
-	job.Setup()
+	if err = job.Setup(); err != nil {
+		return nil, err
+	}
 	//u.Infof("in qlbdriver.Exec about to run")
 	err = job.Run()
 	//u.Debugf("After qlb driver.Run() in Exec()")
 	if err != nil {
 		u.Errorf("error on Query.Run(): %v", err)
-		//resultWriter.ErrChan() <- err
-		//job.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// cancelled or timed out
+			return nil, ctxErr
+		}
+		return nil, err
 	}
 	return resultWriter.Result(), nil
 }
 
-// Query executes a query that may return rows, such as a SELECT
-func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
-	var err error
-	if len(args) > 0 {
-		m.query, err = queryArgsConvert(m.query, args)
-		if err != nil {
-			return nil, err
-		}
-	}
+func (m *qlbStmt) runQuery(ctx *plan.Context) (driver.Rows, error) {
 	u.Debugf("query: %v", m.query)
 
 	// Create a Job, which is Dag of Tasks that Run()
-	ctx := plan.NewContext(m.query)
-	ctx.Schema = m.conn.schema
 	job, err := BuildSqlJob(ctx)
 	if err != nil {
 		u.Warnf("return error? %v", err)
@@ -247,19 +353,32 @@ func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
 
 	// The only type of stmt that makes sense for Query is SELECT
 	//  and we need list of columns that requires casing
-	sqlSelect, ok := job.Ctx.Stmt.(*rel.SqlSelect)
-	if !ok {
+	var cols []string
+	switch st := job.Ctx.Stmt.(type) {
+	case *rel.SqlSelect:
+		cols = st.Columns.AliasedFieldNames()
+	case *rel.SqlUnion:
+		// the first select names the columns of a set operation
+		cols = st.First().Columns.AliasedFieldNames()
+	case *rel.SqlDescribe:
+		if st.Stmt == nil {
+			return nil, fmt.Errorf("We could not recognize that as a select query: %T", job.Ctx.Stmt)
+		}
+		cols = ExplainColumns(st)
+	default:
 		u.Warnf("ctx? %v", job.Ctx)
 		return nil, fmt.Errorf("We could not recognize that as a select query: %T", job.Ctx.Stmt)
 	}
 
 	// Prepare a result writer, we manually append this task to end
 	// of job?
-	resultWriter := NewResultRows(ctx, sqlSelect.Columns.AliasedFieldNames())
+	resultWriter := NewResultRows(ctx, cols)
 
 	job.RootTask.Add(resultWriter)
 
-	job.Setup()
+	if err = job.Setup(); err != nil {
+		return nil, err
+	}
 
 	// TODO:   this can't run in parallel-buffered mode?
 	// how to open in go-routine and still be able to send error to rows?
@@ -289,7 +408,49 @@ func (m *qlbStmt) Query(args []driver.Value) (driver.Rows, error) {
 // column index.  If the type of a specific column isn't known
 // or shouldn't be handled specially, DefaultValueConverter
 // can be returned.
-func (conn *qlbStmt) ColumnConverter(idx int) driver.ValueConverter { return nil }
+func (conn *qlbStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return driver.DefaultParameterConverter
+}
+
+// checkNamedValue convert the arg of nv to a driver value, a value.Value
+// is bound as is.
+func checkNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.(value.Value); ok {
+		return nil
+	}
+	v, err := driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = v
+	return nil
+}
+
+// bindValues the params binding args to the placeholders by position.
+func bindValues(args []driver.Value) *expr.Params {
+	vals := make([]value.Value, len(args))
+	for i, arg := range args {
+		vals[i] = value.NewValue(arg)
+	}
+	return expr.NewParams(vals, nil)
+}
+
+// bindNamedValues the params binding args to the placeholders, named args
+// by name and the others by position.
+func bindNamedValues(args []driver.NamedValue) *expr.Params {
+	params := expr.NewParams(nil, nil)
+	for _, arg := range args {
+		if arg.Name != "" {
+			if params.Named == nil {
+				params.Named = make(map[string]value.Value)
+			}
+			params.Named[arg.Name] = value.NewValue(arg.Value)
+			continue
+		}
+		params.Args = append(params.Args, value.NewValue(arg.Value))
+	}
+	return params
+}
 
 // driver.Rows Interface implementation.
 //
@@ -335,110 +496,3 @@ func (r *qlbResult) LastInsertId() (int64, error) { return r.lastId, r.err }
 // RowsAffected returns the number of rows affected by the
 // query.
 func (r *qlbResult) RowsAffected() (int64, error) { return r.affected, r.err }
-
-func join(a []string) string {
-	n := 0
-	for _, s := range a {
-		n += len(s)
-	}
-	b := make([]byte, n)
-	n = 0
-	for _, s := range a {
-		n += copy(b[n:], s)
-	}
-	return string(b)
-}
-
-func queryArgsConvert(query string, args []driver.Value) (string, error) {
-	if len(args) == 0 {
-		return query, nil
-	}
-	// a tiny, tiny, tiny bit of string sanitization
-	if strings.ContainsAny(query, `'"`) {
-		return "", nil
-	}
-	q := make([]string, 2*len(args)+1)
-	n := 0
-	for _, a := range args {
-		i := strings.IndexRune(query, '?')
-		if i == -1 {
-			return "", errors.New("number of parameters doesn't match number of placeholders")
-		}
-		var s string
-		switch v := a.(type) {
-		case nil:
-			s = "NULL"
-		case string:
-			s = "'" + escapeString(v) + "'"
-		case []byte:
-			s = "'" + escapeString(string(v)) + "'"
-		case int64:
-			s = strconv.FormatInt(v, 10)
-		case time.Time:
-			s = "'" + v.Format(MysqlTimeFormat) + "'"
-		case bool:
-			if v {
-				s = "1"
-			} else {
-				s = "0"
-			}
-		case float64:
-			s = strconv.FormatFloat(v, 'e', 12, 64)
-		default:
-			panic(fmt.Sprintf("%v (%T) can't be handled by godrv", v, v))
-		}
-		q[n] = query[:i]
-		q[n+1] = s
-		query = query[i+1:]
-		n += 2
-	}
-	q[n] = query
-	return join(q), nil
-}
-
-func escapeString(txt string) string {
-	var (
-		esc string
-		buf bytes.Buffer
-	)
-	last := 0
-	for ii, bb := range txt {
-		switch bb {
-		case 0:
-			esc = `\0`
-		case '\n':
-			esc = `\n`
-		case '\r':
-			esc = `\r`
-		case '\\':
-			esc = `\\`
-		case '\'':
-			esc = `\'`
-		case '"':
-			esc = `\"`
-		case '\032':
-			esc = `\Z`
-		default:
-			continue
-		}
-		io.WriteString(&buf, txt[last:ii])
-		io.WriteString(&buf, esc)
-		last = ii + 1
-	}
-	io.WriteString(&buf, txt[last:])
-	return buf.String()
-}
-
-func escapeQuotes(txt string) string {
-	var buf bytes.Buffer
-	last := 0
-	for ii, bb := range txt {
-		if bb == '\'' {
-			io.WriteString(&buf, txt[last:ii])
-			io.WriteString(&buf, `''`)
-			last = ii + 1
-		}
-	}
-	io.WriteString(&buf, txt[last:])
-	return buf.String()
-}
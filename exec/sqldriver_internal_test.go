@@ -0,0 +1,30 @@
+package exec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSqlDriverPreparedClose(t *testing.T) {
+	conn := &qlbConn{}
+	query := "SELECT email FROM users WHERE user_id = ?"
+
+	// statements of the same query share the prepared statement
+	s1, err := conn.Prepare(query)
+	assert.Equal(t, nil, err)
+	s2, err := conn.Prepare(query)
+	assert.Equal(t, nil, err)
+	assert.True(t, s1.(*qlbStmt).ps == s2.(*qlbStmt).ps)
+	assert.Equal(t, 1, len(conn.prepared))
+
+	// closing a statement forgets it
+	assert.Equal(t, nil, s1.Close())
+	assert.Equal(t, 0, len(conn.prepared))
+	assert.Equal(t, nil, s2.Close())
+	s3, err := conn.Prepare(query)
+	assert.Equal(t, nil, err)
+	assert.True(t, s3.(*qlbStmt).ps != s2.(*qlbStmt).ps)
+	assert.Equal(t, nil, s3.Close())
+	assert.Equal(t, 0, len(conn.prepared))
+}
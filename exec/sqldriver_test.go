@@ -1,7 +1,10 @@
 package exec_test
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,6 +12,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/memdb"
+	"github.com/araddon/qlbridge/schema"
 )
 
 type user struct {
@@ -366,3 +371,311 @@ func TestSqlDbConnFailure(t *testing.T) {
 	assert.True(t, uo1.Price == 22.5, "? %#v", uo1)
 	rows2.Close()
 }
+
+func TestSqlCsvDriverOuterJoin(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	type userOrderId struct {
+		UserId  sql.NullString
+		OrderId sql.NullString
+	}
+	query := func(sqlText string) []userOrderId {
+		rows, err := db.Query(sqlText)
+		assert.Equal(t, nil, err)
+		defer rows.Close()
+		uos := make([]userOrderId, 0)
+		for rows.Next() {
+			var uo userOrderId
+			assert.Equal(t, nil, rows.Scan(&uo.UserId, &uo.OrderId))
+			uos = append(uos, uo)
+		}
+		assert.Equal(t, nil, rows.Err())
+		return uos
+	}
+	// count rows w null user, null order
+	nulls := func(uos []userOrderId) (int, int) {
+		nu, no := 0, 0
+		for _, uo := range uos {
+			if !uo.UserId.Valid {
+				nu++
+			}
+			if !uo.OrderId.Valid {
+				no++
+			}
+		}
+		return nu, no
+	}
+
+	// 2 matched orders, 2 users without orders
+	uos := query(`SELECT u.user_id, o.order_id FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id`)
+	assert.Equal(t, 4, len(uos), "%+v", uos)
+	nu, no := nulls(uos)
+	assert.Equal(t, 0, nu)
+	assert.Equal(t, 2, no)
+
+	// 2 matched orders, 1 order without user
+	uos = query(`SELECT u.user_id, o.order_id FROM users AS u
+		RIGHT OUTER JOIN orders AS o ON u.user_id = o.user_id`)
+	assert.Equal(t, 3, len(uos), "%+v", uos)
+	nu, no = nulls(uos)
+	assert.Equal(t, 1, nu)
+	assert.Equal(t, 0, no)
+
+	uos = query(`SELECT u.user_id, o.order_id FROM users AS u
+		FULL OUTER JOIN orders AS o ON u.user_id = o.user_id`)
+	assert.Equal(t, 5, len(uos), "%+v", uos)
+	nu, no = nulls(uos)
+	assert.Equal(t, 1, nu)
+	assert.Equal(t, 2, no)
+
+	// where on the nullable side is applied after the join
+	uos = query(`SELECT u.user_id, o.order_id FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.price > 30`)
+	assert.Equal(t, 1, len(uos), "%+v", uos)
+}
+
+func TestSqlDriverTransaction(t *testing.T) {
+
+	mdb, err := memdb.NewMemDbData("txn_users", [][]driver.Value{{"u1", "bob@email.com"}}, []string{"user_id", "email"})
+	assert.Equal(t, nil, err)
+	err = schema.RegisterSourceAsSchema("memdb_txn", mdb)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("qlbridge", "memdb_txn")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	emails := func(q interface {
+		Query(string, ...interface{}) (*sql.Rows, error)
+	}) []string {
+		rows, err := q.Query("SELECT email FROM txn_users")
+		assert.Equal(t, nil, err)
+		defer rows.Close()
+		var vals []string
+		for rows.Next() {
+			var email string
+			assert.Equal(t, nil, rows.Scan(&email))
+			vals = append(vals, email)
+		}
+		return vals
+	}
+
+	// rolled back writes are undone
+	tx, err := db.Begin()
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`INSERT INTO txn_users (user_id, email) VALUES ("u2", "aaron@email.com")`)
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`DELETE FROM txn_users WHERE user_id = "u1"`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"aaron@email.com"}, emails(tx))
+	// another connection does not see the open transaction's writes
+	assert.Equal(t, []string{"bob@email.com"}, emails(db))
+	assert.Equal(t, nil, tx.Rollback())
+	assert.Equal(t, []string{"bob@email.com"}, emails(db))
+
+	// committed writes are kept
+	tx, err = db.Begin()
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`INSERT INTO txn_users (user_id, email) VALUES ("u2", "aaron@email.com")`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"bob@email.com"}, emails(db))
+	assert.Equal(t, nil, tx.Commit())
+	assert.Equal(t, []string{"bob@email.com", "aaron@email.com"}, emails(db))
+	assert.Equal(t, sql.ErrTxDone, tx.Rollback())
+
+	// updates read, and write, the rows of the transaction
+	tx, err = db.Begin()
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`INSERT INTO txn_users (user_id, email) VALUES ("u3", "carl@email.com")`)
+	assert.Equal(t, nil, err)
+	res, err := tx.Exec(`UPDATE txn_users SET email = replace(email, "@email.com", "@example.com") WHERE user_id != "u1"`)
+	assert.Equal(t, nil, err)
+	ct, err := res.RowsAffected()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(2), ct)
+	assert.Equal(t, nil, tx.Commit())
+	assert.Equal(t, []string{"bob@email.com", "aaron@example.com", "carl@example.com"}, emails(db))
+
+	// statements opening conns of their own run in the transaction
+	tx, err = db.Begin()
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`INSERT INTO txn_users (user_id, email) VALUES ("u4", "dan@email.com")`)
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`ANALYZE TABLE txn_users`)
+	assert.Equal(t, nil, err)
+	res, err = tx.Exec(`CREATE TABLE txn_users_copy AS SELECT user_id, email FROM txn_users`)
+	assert.Equal(t, nil, err)
+	ct, err = res.RowsAffected()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(4), ct)
+	assert.Equal(t, nil, tx.Rollback())
+	assert.Equal(t, []string{"bob@email.com", "aaron@example.com", "carl@example.com"}, emails(db))
+
+	// a COMMIT statement ends the transaction, later statements are not in it
+	tx, err = db.Begin()
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`DELETE FROM txn_users WHERE user_id = "u3"`)
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`COMMIT`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"bob@email.com", "aaron@example.com"}, emails(tx))
+	assert.Equal(t, schema.ErrTxDone, tx.Commit())
+	assert.Equal(t, []string{"bob@email.com", "aaron@example.com"}, emails(db))
+
+	// as does a ROLLBACK statement, and the connection can begin another
+	conn, err := db.Conn(context.Background())
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+	tx, err = conn.BeginTx(context.Background(), nil)
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`DELETE FROM txn_users WHERE user_id = "u1"`)
+	assert.Equal(t, nil, err)
+	_, err = tx.Exec(`ROLLBACK`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, schema.ErrTxDone, tx.Rollback())
+	tx, err = conn.BeginTx(context.Background(), nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"bob@email.com", "aaron@example.com"}, emails(tx))
+	assert.Equal(t, nil, tx.Commit())
+}
+
+func TestSqlDriverParams(t *testing.T) {
+
+	mdb, err := memdb.NewMemDbData("param_users", [][]driver.Value{{"u1", "bob@email.com", int64(30)}}, []string{"user_id", "email", "age"})
+	assert.Equal(t, nil, err)
+	err = schema.RegisterSourceAsSchema("memdb_params", mdb)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("qlbridge", "memdb_params")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	email := func(query string, args ...interface{}) string {
+		var email string
+		err := db.QueryRow(query, args...).Scan(&email)
+		assert.Equal(t, nil, err, query)
+		return email
+	}
+
+	// a quote in a value is only ever a value
+	_, err = db.Exec("INSERT INTO param_users (user_id, email, age) VALUES (?, ?, ?)", "u2", `o'brien "x"@email.com`, 40)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `o'brien "x"@email.com`, email("SELECT email FROM param_users WHERE user_id = ?", "u2"))
+	assert.Equal(t, "bob@email.com", email("SELECT email FROM param_users WHERE age < $1 AND user_id = $2", 35, "u1"))
+	assert.Equal(t, "bob@email.com", email("SELECT email FROM param_users WHERE user_id = :id", sql.Named("id", "u1")))
+
+	// a prepared statement is re-used with different values
+	stmt, err := db.Prepare("SELECT email FROM param_users WHERE user_id = ?")
+	assert.Equal(t, nil, err)
+	defer stmt.Close()
+	for id, want := range map[string]string{"u1": "bob@email.com", "u2": `o'brien "x"@email.com`} {
+		var got string
+		assert.Equal(t, nil, stmt.QueryRow(id).Scan(&got))
+		assert.Equal(t, want, got)
+	}
+	// the number of args is checked against the placeholders
+	_, err = stmt.Query("u1", "u2")
+	assert.NotEqual(t, nil, err)
+
+	res, err := db.Exec("DELETE FROM param_users WHERE user_id = ?", "u2")
+	assert.Equal(t, nil, err)
+	affected, _ := res.RowsAffected()
+	assert.Equal(t, int64(1), affected)
+	var ct int64
+	assert.Equal(t, nil, db.QueryRow("SELECT count(*) FROM param_users WHERE age > ?", 0).Scan(&ct))
+	assert.Equal(t, int64(1), ct)
+}
+
+// endlessSource a memdb table whose conns scan rows forever.
+type endlessSource struct {
+	*memdb.MemDb
+	closed int32
+}
+type endlessConn struct {
+	s  *endlessSource
+	ct int
+}
+
+func (m *endlessSource) Open(table string) (schema.Conn, error) { return &endlessConn{s: m}, nil }
+func (m *endlessConn) Columns() []string                        { return []string{"id"} }
+func (m *endlessConn) Close() error {
+	atomic.StoreInt32(&m.s.closed, 1)
+	return nil
+}
+func (m *endlessConn) Next() schema.Message {
+	if atomic.LoadInt32(&m.s.closed) == 1 {
+		return nil
+	}
+	time.Sleep(time.Millisecond)
+	m.ct++
+	return datasource.NewSqlDriverMessageMap(uint64(m.ct), []driver.Value{int64(m.ct)}, map[string]int{"id": 0})
+}
+
+func TestSqlDriverContext(t *testing.T) {
+
+	mdb, err := memdb.NewMemDbData("endless", [][]driver.Value{{int64(1)}}, []string{"id"})
+	assert.Equal(t, nil, err)
+	src := &endlessSource{MemDb: mdb}
+	err = schema.RegisterSourceAsSchema("endless_ctx", src)
+	assert.Equal(t, nil, err)
+
+	db, err := sql.Open("qlbridge", "endless_ctx")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	// a timeout surfaces as DeadlineExceeded, and closes the source
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, "SELECT id FROM endless")
+	assert.Equal(t, nil, err)
+	ct := 0
+	for rows.Next() {
+		ct++
+	}
+	assert.Equal(t, context.DeadlineExceeded, rows.Err())
+	assert.True(t, ct > 0)
+	rows.Close()
+	for i := 0; i < 100 && atomic.LoadInt32(&src.closed) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&src.closed))
+
+	// an already cancelled context does not run
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = db.QueryContext(ctx, "SELECT id FROM endless")
+	assert.Equal(t, context.Canceled, err)
+	_, err = db.ExecContext(ctx, "DELETE FROM endless WHERE id = 1")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSqlDriverExplain(t *testing.T) {
+
+	db, err := sql.Open("qlbridge", "mockcsv")
+	assert.Equal(t, nil, err)
+	defer db.Close()
+
+	rows, err := db.Query("EXPLAIN ANALYZE SELECT user_id FROM orders WHERE price > 30")
+	assert.Equal(t, nil, err)
+	defer rows.Close()
+	cols, err := rows.Columns()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"id", "parent_id", "depth", "task", "detail", "rows", "bytes", "wall_ms"}, cols)
+	tasks := make([]string, 0)
+	for rows.Next() {
+		var id, parentID, depth, ct, bytes int64
+		var task string
+		var detail sql.NullString
+		var wall float64
+		assert.Equal(t, nil, rows.Scan(&id, &parentID, &depth, &task, &detail, &ct, &bytes, &wall))
+		tasks = append(tasks, task)
+	}
+	assert.Equal(t, nil, rows.Err())
+	assert.Equal(t, "sequential", tasks[0])
+	assert.Equal(t, "projection", tasks[len(tasks)-1])
+}
@@ -0,0 +1,143 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+// resolveSubQueries materialize the sub-queries of filter, each is run
+// once as its own job and its rows held by the SubQueryNode for the vm
+// to evaluate outer rows against.
+func resolveSubQueries(ctx *plan.Context, filter expr.Node) error {
+	for _, sq := range rel.SubQueries(filter) {
+		err := sq.Resolve(func(sel *rel.SqlSelect) ([][]driver.Value, error) {
+			return runSubQuery(ctx, sel)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runSubQuery run a select to completion returning its rows.
+func runSubQuery(ctx *plan.Context, sel *rel.SqlSelect) ([][]driver.Value, error) {
+	rows, err := runStatement(newChildContext(ctx, sel.String()))
+	if err != nil {
+		return nil, fmt.Errorf("sub-query %q: %v", sel, err)
+	}
+	return rows, nil
+}
+
+// runStatement run the statement of a child context to completion
+// returning its rows.
+func runStatement(subCtx *plan.Context) ([][]driver.Value, error) {
+
+	job, err := BuildSqlJob(subCtx)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]schema.Message, 0)
+	job.RootTask.Add(NewResultBuffer(subCtx, &msgs))
+	if err = job.Setup(); err != nil {
+		return nil, err
+	}
+	if err = job.Run(); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]driver.Value, 0, len(msgs))
+	for _, msg := range msgs {
+		switch mt := msg.(type) {
+		case *datasource.SqlDriverMessageMap:
+			rows = append(rows, mt.Vals)
+		case *datasource.SqlDriverMessage:
+			rows = append(rows, mt.Vals)
+		default:
+			return nil, fmt.Errorf("sub-query unrecognized message %T", msg)
+		}
+	}
+	return rows, nil
+}
+
+// streamStatement run a job built by BuildSqlJob handing each of its rows
+// to fn as they are produced, rather than buffering them as runStatement
+// does.  The job is closed if fn errors or quit is signaled before it is
+// done.
+func streamStatement(job *JobExecutor, quit <-chan bool, fn func(vals []driver.Value) error) error {
+	var fnErr error
+	failed := make(chan struct{})
+	sink := NewTaskBase(job.Ctx)
+	sink.Handler = func(ctx *plan.Context, msg schema.Message) bool {
+		if fnErr != nil {
+			return false
+		}
+		var vals []driver.Value
+		switch mt := msg.(type) {
+		case nil:
+			// end of rows sentinel from a limit
+			return true
+		case *datasource.SqlDriverMessageMap:
+			vals = mt.Vals
+		case *datasource.SqlDriverMessage:
+			vals = mt.Vals
+		default:
+			fnErr = fmt.Errorf("unrecognized message %T", msg)
+		}
+		if fnErr == nil {
+			fnErr = fn(vals)
+		}
+		if fnErr != nil {
+			close(failed)
+			return false
+		}
+		return true
+	}
+	job.RootTask.Add(sink)
+	if err := job.Setup(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- job.Run()
+	}()
+	select {
+	case err := <-done:
+		if fnErr != nil {
+			return fnErr
+		}
+		return err
+	case <-failed:
+		job.Close()
+		<-done
+		return fnErr
+	case <-quit:
+		job.Close()
+		<-done
+		return nil
+	}
+}
+
+// newChildContext create the plan context of a statement run as its own
+// job on behalf of the statement of ctx, sharing its schema, session,
+// transaction and limits.
+func newChildContext(ctx *plan.Context, raw string) *plan.Context {
+	child := plan.NewContext(raw)
+	child.Context = ctx.Context
+	child.Schema = ctx.Schema
+	child.Session = ctx.Session
+	child.Txn = ctx.Txn
+	child.Funcs = ctx.Funcs
+	child.DisableRecover = ctx.DisableRecover
+	child.MemoryLimit = ctx.MemoryLimit
+	child.TempDir = ctx.TempDir
+	child.Ctes = ctx.Ctes
+	return child
+}
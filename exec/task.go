@@ -2,11 +2,15 @@ package exec
 
 import (
 	"context"
+	"database/sql/driver"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/datasource"
 	"github.com/araddon/qlbridge/plan"
 	"github.com/araddon/qlbridge/schema"
 )
@@ -32,6 +36,23 @@ type TaskBase struct {
 	errCh    ErrChan
 	sigCh    SigChan // notify of quit/stop
 	errors   []error
+	stats    TaskStats
+}
+
+// TaskStats runtime statistics of a task, the rows (and approximate
+// bytes) it sent downstream and the wall time of its Run().  Used by
+// EXPLAIN ANALYZE.
+type TaskStats struct {
+	Rows  int64
+	Bytes int64
+	Wall  time.Duration
+}
+
+// taskStatser tasks that collect TaskStats, all TaskBase embedders.
+type taskStatser interface {
+	Stats() TaskStats
+	track(msg schema.Message)
+	addWall(d time.Duration)
 }
 
 func NewTaskBase(ctx *plan.Context) *TaskBase {
@@ -97,11 +118,42 @@ func (m *TaskBase) Close() error {
 }
 func (m *TaskBase) CloseFinal() error { return nil }
 
+// Stats the runtime statistics of this task so far.
+func (m *TaskBase) Stats() TaskStats {
+	return TaskStats{
+		Rows:  atomic.LoadInt64(&m.stats.Rows),
+		Bytes: atomic.LoadInt64(&m.stats.Bytes),
+		Wall:  time.Duration(atomic.LoadInt64((*int64)(&m.stats.Wall))),
+	}
+}
+
+// track a message sent downstream, the nil end-of-rows sentinel is not a row.
+func (m *TaskBase) track(msg schema.Message) {
+	var vals []driver.Value
+	switch mt := msg.(type) {
+	case nil:
+		return
+	case *datasource.SqlDriverMessageMap:
+		vals = mt.Vals
+	case *datasource.SqlDriverMessage:
+		vals = mt.Vals
+	}
+	atomic.AddInt64(&m.stats.Rows, 1)
+	atomic.AddInt64(&m.stats.Bytes, rowSize(vals))
+}
+
+func (m *TaskBase) addWall(d time.Duration) {
+	atomic.AddInt64((*int64)(&m.stats.Wall), int64(d))
+}
+
 func MakeHandler(task TaskRunner) MessageHandler {
 	out := task.MessageOut()
 	return func(ctx *plan.Context, msg schema.Message) bool {
 		select {
 		case out <- msg:
+			if st, ok := task.(taskStatser); ok {
+				st.track(msg)
+			}
 			return true
 		case <-task.SigChan():
 			return false
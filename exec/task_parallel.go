@@ -3,6 +3,7 @@ package exec
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -127,6 +128,8 @@ func (m *TaskParallel) Run() error {
 	}
 
 	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var runErr error
 
 	// start tasks in reverse order, so that by time
 	// source starts up all downstreams have started
@@ -135,9 +138,20 @@ func (m *TaskParallel) Run() error {
 		go func(taskId int) {
 			task := m.runners[taskId]
 			//u.Infof("starting task %d-%d %T in:%p  out:%p", m.depth, taskId, task, task.MessageIn(), task.MessageOut())
-			if err := task.Run(); err != nil {
+			started := time.Now()
+			err := task.Run()
+			if st, ok := task.(taskStatser); ok {
+				st.addWall(time.Since(started))
+			}
+			if err != nil {
 				u.Errorf("%T.Run() errored %v", task, err)
-				// TODO:  what do we do with this error?   send to error channel?
+				// the first error of the parallel tasks is the error of the
+				// job, ie a missing partition of a scan
+				errMu.Lock()
+				if runErr == nil {
+					runErr = err
+				}
+				errMu.Unlock()
 			}
 			//u.Debugf("exiting taskId: %v %T", taskId, task)
 			wg.Done()
@@ -146,5 +160,5 @@ func (m *TaskParallel) Run() error {
 
 	wg.Wait()
 
-	return nil
+	return runErr
 }
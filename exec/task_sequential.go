@@ -3,6 +3,7 @@ package exec
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	u "github.com/araddon/gou"
 
@@ -148,7 +149,12 @@ func (m *TaskSequential) Run() (err error) {
 		go func(taskId int) {
 			task := m.runners[taskId]
 			//u.Infof("starting task %d-%d %T in:%p  out:%p", m.depth, taskId, task, task.MessageIn(), task.MessageOut())
-			if taskErr := task.Run(); taskErr != nil {
+			started := time.Now()
+			taskErr := task.Run()
+			if st, ok := task.(taskStatser); ok {
+				st.addWall(time.Since(started))
+			}
+			if taskErr != nil {
 				u.Errorf("%T.Run() errored %v", task, taskErr)
 				// TODO:  what do we do with this error?   send to error channel?
 				err = taskErr
@@ -84,6 +84,14 @@ func NewHaving(ctx *plan.Context, p *plan.Having) *Where {
 	return s
 }
 
+// Setup materializes any sub-queries of the filter before rows flow.
+func (m *Where) Setup(depth int) error {
+	if err := resolveSubQueries(m.Ctx, m.filter); err != nil {
+		return err
+	}
+	return m.TaskBase.Setup(depth)
+}
+
 func whereFilter(filter expr.Node, task TaskRunner, cols map[string]int) MessageHandler {
 	out := task.MessageOut()
 
@@ -139,6 +147,9 @@ func whereFilter(filter expr.Node, task TaskRunner, cols map[string]int) Message
 		//u.Debugf("about to send from where to forward: %#v", msg)
 		select {
 		case out <- msg:
+			if st, ok := task.(taskStatser); ok {
+				st.track(msg)
+			}
 			return true
 		case <-task.SigChan():
 			return false
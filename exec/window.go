@@ -0,0 +1,467 @@
+package exec
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ TaskRunner = (*Window)(nil)
+)
+
+// Window evaluates the window function columns (fn OVER (...)) of a
+// select.  As each window may look at any row of its partition all
+// rows are held in memory, then emitted in their input order with the
+// value of each window column appended, under its windowKey, for the
+// projection to read.
+type Window struct {
+	*TaskBase
+	p    *plan.Window
+	wins []*windowColumn
+}
+
+// NewWindow create the window function task, error if a column is not
+// a window function or its window is not supported.
+func NewWindow(ctx *plan.Context, p *plan.Window) (*Window, error) {
+	wins := make([]*windowColumn, 0)
+	for _, col := range p.Stmt.Columns {
+		if col.Over == nil {
+			continue
+		}
+		wc, err := newWindowColumn(col)
+		if err != nil {
+			return nil, err
+		}
+		wins = append(wins, wc)
+	}
+	return &Window{
+		TaskBase: NewTaskBase(ctx),
+		p:        p,
+		wins:     wins,
+	}, nil
+}
+
+// windowKey the column index key of the value of a window column.  Its
+// alias may be the name of a source column (sum(amount) OVER () is
+// aliased amount) so the key is the full function and window.
+func windowKey(col *rel.Column) string {
+	return col.Expr.String() + " " + col.Over.String()
+}
+
+// Run the window task, standard task interface.
+func (m *Window) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.msgOutCh)
+
+	outCh := m.MessageOut()
+	inCh := m.MessageIn()
+
+	colIndex := m.p.Stmt.ColIndexes()
+	wins := m.wins
+
+	rows := make([]*datasource.SqlDriverMessageMap, 0)
+
+msgReadLoop:
+	for {
+
+		select {
+		case <-m.SigChan():
+			return nil
+		case msg, ok := <-inCh:
+			if !ok {
+				break msgReadLoop
+			}
+			switch mt := msg.(type) {
+			case nil:
+				// end of rows signal
+				continue
+			case *datasource.SqlDriverMessageMap:
+				rows = append(rows, mt)
+			default:
+				msgReader, isContextReader := msg.(expr.ContextReader)
+				if !isContextReader {
+					u.Errorf("unrecognized msg %T", msg)
+					return fmt.Errorf("To use Window must use SqlDriverMessageMap but got %T", msg)
+				}
+				rows = append(rows, datasource.NewSqlDriverMessageMapCtx(msg.Id(), msgReader, colIndex))
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// window values of each row, in input order
+	vals := make([][]driver.Value, len(rows))
+	for i := range vals {
+		vals[i] = make([]driver.Value, len(wins))
+	}
+	for wi, wc := range wins {
+		for _, part := range wc.partitions(rows) {
+			if err := wc.eval(rows, part, vals, wi); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the input index plus the key (and alias) of each window column
+	rowLen := len(rows[0].Vals)
+	outIndex := make(map[string]int, len(rows[0].ColIndex)+2*len(wins))
+	for k, idx := range rows[0].ColIndex {
+		outIndex[k] = idx
+	}
+	for wi, wc := range wins {
+		outIndex[windowKey(wc.col)] = rowLen + wi
+		if _, exists := outIndex[wc.col.As]; !exists && wc.col.As != "" {
+			// so ORDER BY may sort on the alias
+			outIndex[wc.col.As] = rowLen + wi
+		}
+	}
+
+	for i, row := range rows {
+		out := make([]driver.Value, rowLen+len(wins))
+		copy(out, row.Vals)
+		copy(out[rowLen:], vals[i])
+		msg := datasource.NewSqlDriverMessageMap(row.Id(), out, outIndex)
+		select {
+		case <-m.SigChan():
+			return nil
+		case outCh <- msg:
+			m.track(msg)
+		}
+	}
+	return nil
+}
+
+// windowFunc the kind of window function of a window column.
+type windowFunc int
+
+const (
+	windowAgg windowFunc = iota
+	windowRowNumber
+	windowRank
+	windowDenseRank
+	windowLag
+	windowLead
+	windowFirstValue
+)
+
+// windowColumn a window function column, its function and window.
+type windowColumn struct {
+	col  *rel.Column
+	fn   *expr.FuncNode
+	kind windowFunc
+	ob   *vm.OrderBy
+}
+
+func newWindowColumn(col *rel.Column) (*windowColumn, error) {
+	fn, ok := col.Expr.(*expr.FuncNode)
+	if !ok {
+		return nil, fmt.Errorf("Not a window function: %s", col.Expr)
+	}
+	wc := &windowColumn{col: col, fn: fn}
+	switch strings.ToLower(fn.Name) {
+	case "row_number":
+		wc.kind = windowRowNumber
+	case "rank":
+		wc.kind = windowRank
+	case "dense_rank":
+		wc.kind = windowDenseRank
+	case "lag":
+		wc.kind = windowLag
+	case "lead":
+		wc.kind = windowLead
+	case "first_value":
+		wc.kind = windowFirstValue
+	default:
+		if fn.F.AggMaker == nil {
+			return nil, fmt.Errorf("Not a window function: %s", col.Expr)
+		}
+		wc.kind = windowAgg
+	}
+	if fr := col.Over.Frame; fr != nil && fr.Range && len(col.Over.OrderBy) != 1 {
+		if (!fr.Start.Unbounded && fr.Start.Offset != 0) || (!fr.End.Unbounded && fr.End.Offset != 0) {
+			return nil, fmt.Errorf("RANGE with offset requires exactly one ORDER BY column: %s", col.Over)
+		}
+	}
+	ob, err := vm.NewOrderBy(col.Over.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+	wc.ob = ob
+	return wc, nil
+}
+
+// windowPartition the rows (indexes into all rows) of a partition in
+// window order with their order by keys.
+type windowPartition struct {
+	idx  []int
+	keys [][]value.Value
+}
+
+// partitions split the rows by the PARTITION BY values, each sorted
+// (stable so peers keep input order) by the window's ORDER BY.
+func (m *windowColumn) partitions(rows []*datasource.SqlDriverMessageMap) []*windowPartition {
+	byKey := make(map[string]*windowPartition)
+	parts := make([]*windowPartition, 0)
+	for i, row := range rows {
+		keys := make([]string, len(m.col.Over.PartitionBy))
+		for ki, col := range m.col.Over.PartitionBy {
+			if v, ok := vm.Eval(row, col.Expr); ok && v != nil {
+				keys[ki] = v.ToString()
+			}
+		}
+		key := strings.Join(keys, ",")
+		part, ok := byKey[key]
+		if !ok {
+			part = &windowPartition{}
+			byKey[key] = part
+			parts = append(parts, part)
+		}
+		part.idx = append(part.idx, i)
+		part.keys = append(part.keys, m.ob.Keys(row))
+	}
+	for _, part := range parts {
+		sort.Stable(&windowSort{part, m.ob})
+	}
+	return parts
+}
+
+type windowSort struct {
+	*windowPartition
+	ob *vm.OrderBy
+}
+
+func (m *windowSort) Len() int           { return len(m.idx) }
+func (m *windowSort) Less(i, j int) bool { return m.ob.Compare(m.keys[i], m.keys[j]) < 0 }
+func (m *windowSort) Swap(i, j int) {
+	m.idx[i], m.idx[j] = m.idx[j], m.idx[i]
+	m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+}
+
+// eval the window function over the rows of a partition, setting
+// column wi of vals for each of them.
+func (m *windowColumn) eval(rows []*datasource.SqlDriverMessageMap, part *windowPartition, vals [][]driver.Value, wi int) error {
+
+	n := len(part.idx)
+
+	// the first and last row of each row's peers, rows with equal
+	// ORDER BY values (all rows if there is no ORDER BY)
+	peerStart := make([]int, n)
+	peerEnd := make([]int, n)
+	for i := 0; i < n; i++ {
+		if i > 0 && m.ob.Compare(part.keys[i-1], part.keys[i]) == 0 {
+			peerStart[i] = peerStart[i-1]
+		} else {
+			peerStart[i] = i
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		if i < n-1 && peerStart[i+1] == peerStart[i] {
+			peerEnd[i] = peerEnd[i+1]
+		} else {
+			peerEnd[i] = i
+		}
+	}
+
+	switch m.kind {
+	case windowRowNumber:
+		for i, ri := range part.idx {
+			vals[ri][wi] = int64(i + 1)
+		}
+	case windowRank:
+		for i, ri := range part.idx {
+			vals[ri][wi] = int64(peerStart[i] + 1)
+		}
+	case windowDenseRank:
+		rank := int64(0)
+		for i, ri := range part.idx {
+			if peerStart[i] == i {
+				rank++
+			}
+			vals[ri][wi] = rank
+		}
+	case windowLag, windowLead:
+		for i, ri := range part.idx {
+			offset := int64(1)
+			if len(m.fn.Args) > 1 {
+				v, ok := vm.Eval(rows[ri], m.fn.Args[1])
+				if !ok {
+					return fmt.Errorf("Could not evaluate offset of %s", m.fn)
+				}
+				iv, ok := value.ValueToInt64(v)
+				if !ok || iv < 0 {
+					return fmt.Errorf("Expected offset >= 0 for %s but got %v", m.fn, v)
+				}
+				offset = iv
+			}
+			target := int64(i) - offset
+			if m.kind == windowLead {
+				target = int64(i) + offset
+			}
+			var arg expr.Node
+			var ctx expr.EvalContext
+			switch {
+			case target >= 0 && target < int64(n):
+				arg, ctx = m.fn.Args[0], rows[part.idx[target]]
+			case len(m.fn.Args) > 2:
+				arg, ctx = m.fn.Args[2], rows[ri]
+			default:
+				continue
+			}
+			if v, ok := vm.Eval(ctx, arg); ok && v != nil && !v.Nil() {
+				vals[ri][wi] = v.Value()
+			}
+		}
+	case windowFirstValue:
+		for i, ri := range part.idx {
+			lo, hi, err := m.frame(part, peerStart, peerEnd, i)
+			if err != nil {
+				return err
+			}
+			if lo > hi {
+				continue
+			}
+			if v, ok := vm.Eval(rows[part.idx[lo]], m.fn.Args[0]); ok && v != nil && !v.Nil() {
+				vals[ri][wi] = v.Value()
+			}
+		}
+	case windowAgg:
+		agg, err := m.fn.NewAggregator(false)
+		if err != nil {
+			return err
+		}
+		// per row value of the function, fed to the aggregator as in GroupBy
+		fvals := make([]value.Value, n)
+		for i, ri := range part.idx {
+			if v, ok := vm.Eval(rows[ri], m.fn.AggInput()); ok && v != nil {
+				fvals[i] = v
+			} else {
+				fvals[i] = value.NewNilValue()
+			}
+		}
+		// the aggregator holds the rows [aggLo, aggHi], extended while
+		// the frame start is unchanged, otherwise re-aggregated
+		aggLo, aggHi := 0, -1
+		for i, ri := range part.idx {
+			lo, hi, err := m.frame(part, peerStart, peerEnd, i)
+			if err != nil {
+				return err
+			}
+			if lo != aggLo || hi < aggHi {
+				agg.Reset()
+				aggLo, aggHi = lo, lo-1
+			}
+			for ; aggHi < hi; aggHi++ {
+				agg.Do(fvals[aggHi+1])
+			}
+			vals[ri][wi] = driver.Value(agg.Result())
+		}
+	}
+	return nil
+}
+
+// frame the first and last (inclusive) position in the partition of
+// the window frame of row i, empty if lo > hi.
+func (m *windowColumn) frame(part *windowPartition, peerStart, peerEnd []int, i int) (int, int, error) {
+	n := len(part.idx)
+	fr := m.col.Over.Frame
+	if fr == nil {
+		if len(m.col.Over.OrderBy) == 0 {
+			return 0, n - 1, nil
+		}
+		// RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+		return 0, peerEnd[i], nil
+	}
+	lo, err := m.bound(part, peerStart, peerEnd, i, fr.Range, fr.Start, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	hi, err := m.bound(part, peerStart, peerEnd, i, fr.Range, fr.End, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > n-1 {
+		hi = n - 1
+	}
+	return lo, hi, nil
+}
+
+// bound the position of a frame start (or end) for row i.
+func (m *windowColumn) bound(part *windowPartition, peerStart, peerEnd []int, i int, isRange bool, b rel.WindowBound, start bool) (int, error) {
+	n := len(part.idx)
+	switch {
+	case b.Unbounded && b.Offset < 0:
+		return 0, nil
+	case b.Unbounded:
+		return n - 1, nil
+	case !isRange:
+		return i + int(b.Offset), nil
+	case b.Offset == 0:
+		if start {
+			return peerStart[i], nil
+		}
+		return peerEnd[i], nil
+	}
+
+	// RANGE n PRECEDING/FOLLOWING, rows whose order by value is within
+	// n of this row's.  A NULL row's frame is its peers.
+	cur := part.keys[i][0]
+	if value.IsNilish(cur) {
+		if start {
+			return peerStart[i], nil
+		}
+		return peerEnd[i], nil
+	}
+	curf, ok := value.ValueToFloat64(cur)
+	if !ok {
+		return 0, fmt.Errorf("RANGE with offset requires a numeric ORDER BY value but got %v", cur)
+	}
+	// sign so that positions are ascending in dir*value
+	dir := float64(1)
+	if !m.col.Over.OrderBy[0].Asc() {
+		dir = -1
+	}
+	target := dir*curf + float64(b.Offset)
+
+	// the non NULL rows, which are sorted together
+	nnLo, nnHi := 0, n
+	for nnLo < n && value.IsNilish(part.keys[nnLo][0]) {
+		nnLo++
+	}
+	for nnHi > nnLo && value.IsNilish(part.keys[nnHi-1][0]) {
+		nnHi--
+	}
+	var err error
+	pos := func(j int) float64 {
+		f, ok := value.ValueToFloat64(part.keys[j][0])
+		if !ok && err == nil {
+			err = fmt.Errorf("RANGE with offset requires a numeric ORDER BY value but got %v", part.keys[j][0])
+		}
+		return dir * f
+	}
+	if start {
+		// first row at or after target
+		j := nnLo + sort.Search(nnHi-nnLo, func(k int) bool { return pos(nnLo+k) >= target })
+		return j, err
+	}
+	// last row at or before target
+	j := nnLo + sort.Search(nnHi-nnLo, func(k int) bool { return pos(nnLo+k) > target }) - 1
+	return j, err
+}
@@ -3,6 +3,8 @@ package builtins
 import (
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/value"
@@ -26,6 +28,11 @@ func (m *Avg) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 }
 func (m *Avg) IsAgg() bool { return true }
 
+// NewAggregator creates the group-by aggregator for avg.
+func (m *Avg) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &avgAgg{partial: partial}, nil
+}
+
 func avgEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
 	avg := float64(0)
 	ct := 0
@@ -79,6 +86,11 @@ func (m *Sum) Type() value.ValueType { return value.NumberType }
 // IsAgg yes sum is an agg.
 func (m *Sum) IsAgg() bool { return true }
 
+// NewAggregator creates the group-by aggregator for sum.
+func (m *Sum) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &sumAgg{partial: partial}, nil
+}
+
 func (m *Sum) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 	if len(n.Args) < 1 {
 		return nil, fmt.Errorf("Expected 1 or more args for Sum(arg, arg, ...) but got %s", n)
@@ -142,6 +154,11 @@ type Count struct{}
 func (m *Count) Type() value.ValueType { return value.IntType }
 func (m *Count) IsAgg() bool           { return true }
 
+// NewAggregator creates the group-by aggregator for count.
+func (m *Count) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &countAgg{}, nil
+}
+
 func (m *Count) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 	if len(n.Args) != 1 {
 		return nil, fmt.Errorf("Expected max 1 arg for count(arg) but got %s", n)
@@ -155,3 +172,460 @@ func incrementEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool)
 	}
 	return value.NewIntValue(1), true
 }
+
+// Min smallest of values.  Numbers compare numerically, times chronologically
+// and everything else by string value.  As an aggregate it is the smallest
+// value across rows.
+//
+//    min(1,2,3) => 1, true
+//    min("b","a") => "a", true
+//
+type Min struct{}
+
+// Type is unknown, same as args
+func (m *Min) Type() value.ValueType { return value.UnknownType }
+func (m *Min) IsAgg() bool           { return true }
+func (m *Min) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for min(arg, arg, ...) but got %s", n)
+	}
+	return minEval, nil
+}
+
+// NewAggregator creates the group-by aggregator for min.
+func (m *Min) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &minMaxAgg{partial: partial}, nil
+}
+
+func minEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	return pickEval(vals, false)
+}
+
+// Max largest of values.  Numbers compare numerically, times chronologically
+// and everything else by string value.  As an aggregate it is the largest
+// value across rows.
+//
+//    max(1,2,3) => 3, true
+//    max("b","a") => "b", true
+//
+type Max struct{}
+
+// Type is unknown, same as args
+func (m *Max) Type() value.ValueType { return value.UnknownType }
+func (m *Max) IsAgg() bool           { return true }
+func (m *Max) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for max(arg, arg, ...) but got %s", n)
+	}
+	return maxEval, nil
+}
+
+// NewAggregator creates the group-by aggregator for max.
+func (m *Max) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &minMaxAgg{partial: partial, max: true}, nil
+}
+
+func maxEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	return pickEval(vals, true)
+}
+
+func pickEval(vals []value.Value, max bool) (value.Value, bool) {
+	var pick value.Value
+	for _, val := range vals {
+		if val == nil || val.Nil() || val.Err() {
+			continue
+		}
+		if pick == nil || aggLess(val, pick) != max {
+			pick = val
+		}
+	}
+	if pick == nil {
+		return value.NilValueVal, false
+	}
+	return pick, true
+}
+
+// Variance population variance of values.  As an aggregate it is
+// the variance across rows.
+//
+//    variance(1,2,3,4) => 1.25, true
+//
+type Variance struct{}
+
+// Type is number
+func (m *Variance) Type() value.ValueType { return value.NumberType }
+func (m *Variance) IsAgg() bool           { return true }
+func (m *Variance) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for variance(arg, arg, ...) but got %s", n)
+	}
+	return varianceEval, nil
+}
+
+// NewAggregator creates the group-by aggregator for variance.
+func (m *Variance) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &varianceAgg{partial: partial}, nil
+}
+
+func varianceEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if len(vals) == 1 {
+		// single value per row, the aggregator does the work
+		if fv, ok := value.ValueToFloat64(vals[0]); ok && !math.IsNaN(fv) {
+			return value.NewNumberValue(fv), true
+		}
+		return value.NumberNaNValue, false
+	}
+	agg := &varianceAgg{}
+	for _, val := range vals {
+		agg.Do(val)
+	}
+	if agg.ct == 0 {
+		return value.NumberNaNValue, false
+	}
+	return value.NewNumberValue(agg.variance()), true
+}
+
+// StdDev population standard deviation of values.  As an aggregate it
+// is the standard deviation across rows.
+//
+//    stddev(2,4,4,4,5,5,7,9) => 2.0, true
+//
+type StdDev struct{}
+
+// Type is number
+func (m *StdDev) Type() value.ValueType { return value.NumberType }
+func (m *StdDev) IsAgg() bool           { return true }
+func (m *StdDev) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for stddev(arg, arg, ...) but got %s", n)
+	}
+	return stdDevEval, nil
+}
+
+// NewAggregator creates the group-by aggregator for stddev.
+func (m *StdDev) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return &varianceAgg{partial: partial, stddev: true}, nil
+}
+
+func stdDevEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if len(vals) == 1 {
+		return varianceEval(ctx, vals)
+	}
+	v, ok := varianceEval(ctx, vals)
+	if !ok {
+		return v, false
+	}
+	return value.NewNumberValue(math.Sqrt(v.(value.NumberValue).Val())), true
+}
+
+// CountDistinct count of distinct non-null values.  Per row it
+// returns its argument, as an aggregate the number of distinct values
+// seen across rows.
+//
+//    count_distinct(email) => 3
+//
+type CountDistinct struct{}
+
+// Type is Integer
+func (m *CountDistinct) Type() value.ValueType { return value.IntType }
+func (m *CountDistinct) IsAgg() bool           { return true }
+func (m *CountDistinct) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for count_distinct(arg) but got %s", n)
+	}
+	return firstArgEval, nil
+}
+
+// NewAggregator creates the group-by aggregator for count_distinct.
+func (m *CountDistinct) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	return newDistinctAgg(partial), nil
+}
+
+func firstArgEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if vals[0] == nil || vals[0].Nil() || vals[0].Err() {
+		return value.NilValueVal, false
+	}
+	return vals[0], true
+}
+
+// GroupConcat concatenate non-null values across rows with optional
+// separator (default ",").  Per row it returns its argument as string.
+//
+//    group_concat(name)        => "bob,aaron"
+//    group_concat(name, "|")   => "bob|aaron"
+//
+type GroupConcat struct{}
+
+// Type is string
+func (m *GroupConcat) Type() value.ValueType { return value.StringType }
+func (m *GroupConcat) IsAgg() bool           { return true }
+func (m *GroupConcat) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 || len(n.Args) > 2 {
+		return nil, fmt.Errorf("Expected 1 or 2 args for group_concat(arg, [separator]) but got %s", n)
+	}
+	if len(n.Args) == 2 {
+		if _, ok := n.Args[1].(*expr.StringNode); !ok {
+			return nil, fmt.Errorf("Expected string literal separator for group_concat(arg, separator) but got %s", n)
+		}
+	}
+	return groupConcatEval, nil
+}
+
+// NewAggregator creates the group-by aggregator for group_concat.
+func (m *GroupConcat) NewAggregator(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+	sep := ","
+	if len(n.Args) == 2 {
+		if sn, ok := n.Args[1].(*expr.StringNode); ok {
+			sep = sn.Text
+		}
+	}
+	return &concatAgg{partial: partial, sep: sep}, nil
+}
+
+func groupConcatEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if vals[0] == nil || vals[0].Nil() || vals[0].Err() {
+		return value.NilValueVal, false
+	}
+	return value.NewStringValue(vals[0].ToString()), true
+}
+
+// aggLess compares two values for min/max using typed value.Compare,
+// values that can't be compared fall back to their string value.
+func aggLess(l, r value.Value) bool {
+	cmp, err := value.Compare(l, r)
+	if err != nil {
+		return l.ToString() < r.ToString()
+	}
+	return cmp < 0
+}
+
+type sumAgg struct {
+	partial bool
+	ct      int64
+	n       float64
+}
+
+func (m *sumAgg) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	m.ct++
+	switch vt := v.(type) {
+	case value.IntValue:
+		m.n += vt.Float()
+	case value.NumberValue:
+		m.n += vt.Val()
+	}
+}
+func (m *sumAgg) Result() interface{} {
+	if !m.partial {
+		return m.n
+	}
+	return &expr.AggPartial{Ct: m.ct, N: m.n}
+}
+func (m *sumAgg) Reset() { m.n = 0; m.ct = 0 }
+func (m *sumAgg) Merge(a *expr.AggPartial) {
+	m.ct += a.Ct
+	m.n += a.N
+}
+
+type avgAgg struct {
+	partial bool
+	ct      int64
+	n       float64
+}
+
+func (m *avgAgg) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	m.ct++
+	switch vt := v.(type) {
+	case value.IntValue:
+		m.n += vt.Float()
+	case value.NumberValue:
+		m.n += vt.Val()
+	}
+}
+func (m *avgAgg) Result() interface{} {
+	if !m.partial {
+		return m.n / float64(m.ct)
+	}
+	return &expr.AggPartial{Ct: m.ct, N: m.n}
+}
+func (m *avgAgg) Reset() { m.n = 0; m.ct = 0 }
+func (m *avgAgg) Merge(a *expr.AggPartial) {
+	m.ct += a.Ct
+	m.n += a.N
+}
+
+type countAgg struct {
+	n int64
+}
+
+func (m *countAgg) Do(v value.Value) {
+	if v == nil || v.Nil() {
+		return
+	}
+	m.n++
+}
+func (m *countAgg) Result() interface{}      { return m.n }
+func (m *countAgg) Reset()                   { m.n = 0 }
+func (m *countAgg) Merge(a *expr.AggPartial) { m.n += a.Ct }
+
+type minMaxAgg struct {
+	partial bool
+	max     bool
+	ct      int64
+	val     value.Value
+}
+
+func (m *minMaxAgg) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	m.ct++
+	if m.val == nil || aggLess(v, m.val) != m.max {
+		m.val = v
+	}
+}
+func (m *minMaxAgg) Result() interface{} {
+	var val interface{}
+	if m.val != nil {
+		val = m.val.Value()
+	}
+	if !m.partial {
+		return val
+	}
+	return &expr.AggPartial{Ct: m.ct, Val: val}
+}
+func (m *minMaxAgg) Reset() { m.val = nil; m.ct = 0 }
+func (m *minMaxAgg) Merge(a *expr.AggPartial) {
+	if a.Val == nil {
+		return
+	}
+	m.Do(value.NewValue(a.Val))
+	m.ct += a.Ct - 1
+}
+
+type varianceAgg struct {
+	partial bool
+	stddev  bool
+	ct      int64
+	n       float64
+	sq      float64
+}
+
+func (m *varianceAgg) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	fv, ok := value.ValueToFloat64(v)
+	if !ok || math.IsNaN(fv) {
+		return
+	}
+	m.ct++
+	m.n += fv
+	m.sq += fv * fv
+}
+func (m *varianceAgg) variance() float64 {
+	mean := m.n / float64(m.ct)
+	return m.sq/float64(m.ct) - mean*mean
+}
+func (m *varianceAgg) Result() interface{} {
+	if m.partial {
+		return &expr.AggPartial{Ct: m.ct, N: m.n, Sq: m.sq}
+	}
+	if m.ct == 0 {
+		return nil
+	}
+	if m.stddev {
+		return math.Sqrt(m.variance())
+	}
+	return m.variance()
+}
+func (m *varianceAgg) Reset() { m.ct = 0; m.n = 0; m.sq = 0 }
+func (m *varianceAgg) Merge(a *expr.AggPartial) {
+	m.ct += a.Ct
+	m.n += a.N
+	m.sq += a.Sq
+}
+
+// distinctKey the key of a value in a distinct set, its type and value
+// so 1 and "1" are distinct.
+func distinctKey(v value.Value) string {
+	return v.Type().String() + ":" + v.ToString()
+}
+
+type distinctAgg struct {
+	partial bool
+	seen    map[string]struct{}
+}
+
+func newDistinctAgg(partial bool) *distinctAgg {
+	return &distinctAgg{partial: partial, seen: make(map[string]struct{})}
+}
+func (m *distinctAgg) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	m.seen[distinctKey(v)] = struct{}{}
+}
+
+func (m *distinctAgg) Result() interface{} {
+	if !m.partial {
+		return int64(len(m.seen))
+	}
+	vals := make([]interface{}, 0, len(m.seen))
+	for k := range m.seen {
+		vals = append(vals, k)
+	}
+	return &expr.AggPartial{Ct: int64(len(m.seen)), Vals: vals}
+}
+func (m *distinctAgg) Reset() { m.seen = make(map[string]struct{}) }
+func (m *distinctAgg) Merge(a *expr.AggPartial) {
+	for _, v := range a.Vals {
+		if s, ok := v.(string); ok {
+			m.seen[s] = struct{}{}
+		}
+	}
+}
+
+type concatAgg struct {
+	partial bool
+	sep     string
+	vals    []string
+}
+
+func (m *concatAgg) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	switch vt := v.(type) {
+	case value.TimeValue:
+		m.vals = append(m.vals, vt.Val().Format(time.RFC3339))
+	default:
+		m.vals = append(m.vals, v.ToString())
+	}
+}
+func (m *concatAgg) Result() interface{} {
+	if !m.partial {
+		if len(m.vals) == 0 {
+			return nil
+		}
+		return strings.Join(m.vals, m.sep)
+	}
+	vals := make([]interface{}, len(m.vals))
+	for i, v := range m.vals {
+		vals[i] = v
+	}
+	return &expr.AggPartial{Ct: int64(len(m.vals)), Vals: vals}
+}
+func (m *concatAgg) Reset() { m.vals = nil }
+func (m *concatAgg) Merge(a *expr.AggPartial) {
+	for _, v := range a.Vals {
+		if s, ok := v.(string); ok {
+			m.vals = append(m.vals, s)
+		}
+	}
+}
@@ -29,6 +29,20 @@ func LoadAllBuiltins() {
 		expr.FuncAdd("count", &Count{})
 		expr.FuncAdd("avg", &Avg{})
 		expr.FuncAdd("sum", &Sum{})
+		expr.FuncAdd("min", &Min{})
+		expr.FuncAdd("max", &Max{})
+		expr.FuncAdd("variance", &Variance{})
+		expr.FuncAdd("stddev", &StdDev{})
+		expr.FuncAdd("count_distinct", &CountDistinct{})
+		expr.FuncAdd("group_concat", &GroupConcat{})
+
+		// window
+		expr.FuncAdd("row_number", &RowNumber{})
+		expr.FuncAdd("rank", &Rank{})
+		expr.FuncAdd("dense_rank", &DenseRank{})
+		expr.FuncAdd("lag", &Lag{})
+		expr.FuncAdd("lead", &Lead{})
+		expr.FuncAdd("first_value", &FirstValue{})
 
 		// logical
 		expr.FuncAdd("gt", &Gt{})
@@ -41,6 +55,10 @@ func LoadAllBuiltins() {
 		expr.FuncAdd("exists", &Exists{})
 		expr.FuncAdd("any", &Any{})
 		expr.FuncAdd("all", &All{})
+		expr.FuncAdd("coalesce", &Coalesce{})
+		expr.FuncAdd("ifnull", &IfNull{})
+		expr.FuncAdd("nullif", &NullIf{})
+		expr.FuncAdd("if", &If{})
 
 		// Map
 		expr.FuncAdd("map", &MapFunc{})
@@ -25,6 +25,7 @@ func TestMain(m *testing.M) {
 
 	builtins.LoadAllBuiltins()
 	expr.FuncAdd("emptyslice", &emptySlice{})
+	expr.FuncAdd("counted", &counted{})
 	// Now run the actual Tests
 	os.Exit(m.Run())
 }
@@ -39,6 +40,19 @@ func (m *emptySlice) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 	}, nil
 }
 
+// counted counts its evaluations, to test short-circuit evaluation
+type counted struct{}
+
+var countedCt int
+
+func (m *counted) Type() value.ValueType { return value.IntType }
+func (m *counted) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return func(ctx expr.EvalContext, args []value.Value) (value.Value, bool) {
+		countedCt++
+		return value.NewIntValue(1), true
+	}, nil
+}
+
 type testBuiltins struct {
 	expr string
 	val  value.Value
@@ -223,6 +237,18 @@ var builtinTests = []testBuiltins{
 	{`all(ZeroTime)`, value.BoolValueFalse},
 	{`all(-1)`, value.BoolValueFalse},
 
+	{`coalesce(not_a_field, event, "x")`, value.NewStringValue("hello")},
+	{`coalesce(not_a_field, 5)`, value.NewIntValue(5)},
+	{`coalesce(not_a_field, notreal)`, value.NewNilValue()},
+	{`ifnull(event, "x")`, value.NewStringValue("hello")},
+	{`ifnull(not_a_field, "x")`, value.NewStringValue("x")},
+	{`nullif(event, "hello")`, value.NewNilValue()},
+	{`nullif(event, "world")`, value.NewStringValue("hello")},
+	{`nullif(not_a_field, "world")`, value.NewNilValue()},
+	{`if(eq(event,"hello"), "yes", "no")`, value.NewStringValue("yes")},
+	{`if(eq(event,"world"), "yes", "no")`, value.NewStringValue("no")},
+	{`if(not_a_field, "yes")`, value.NewNilValue()},
+
 	/*
 		Map, List, Array functions
 	*/
@@ -776,6 +802,17 @@ var builtinTests = []testBuiltins{
 	{`count(not_a_field)`, value.ErrValue},
 	{`count(not_a_field)`, nil},
 
+	{`min(3,1,2)`, value.NewIntValue(1)},
+	{`min("b","a")`, value.NewStringValue("a")},
+	{`min(not_a_field)`, nil},
+	{`max(3,1,2)`, value.NewIntValue(3)},
+	{`max("b","a")`, value.NewStringValue("b")},
+	{`variance(1,2,3,4)`, value.NewNumberValue(1.25)},
+	{`variance("hello")`, value.ErrValue},
+	{`stddev(2,4,4,4,5,5,7,9)`, value.NewNumberValue(2)},
+	{`count_distinct("a")`, value.NewStringValue("a")},
+	{`group_concat(5, "|")`, value.NewStringValue("5")},
+
 	// JsonPath
 	{`json.jmespath(json_field, "[?name == 'n1'].name | [0]")`, value.NewStringValue("n1")},
 	{`json.jmespath(json_field, "[?b].ct | [0]")`, value.NewNumberValue(8)},
@@ -904,6 +941,8 @@ var testValidation = []string{
 	`json.jmespath(json_field)`,    // Must have 2 args
 	`json.jmespath(json_field, 1)`, // Must have 2 args, 2nd must be string
 	`json.jmespath(json_bad, "")`,
+
+	`group_concat(a, b)`, // separator must be string literal
 }
 var testValidationx = []string{
 	`tolower()`, `lower(a,b)`, // must be one arg
@@ -963,6 +1002,28 @@ func TestValidation(t *testing.T) {
 	}
 }
 
+func TestShortCircuit(t *testing.T) {
+	tests := []struct {
+		expr string
+		ct   int
+	}{
+		{`coalesce(event, counted())`, 0},
+		{`coalesce(not_a_field, counted(), counted())`, 1},
+		{`ifnull(event, counted())`, 0},
+		{`nullif(not_a_field, counted())`, 0},
+		{`if(eq(event,"hello"), "yes", counted())`, 0},
+		{`if(eq(event,"world"), counted(), "no")`, 0},
+		{`CASE WHEN event == "hello" THEN 1 WHEN counted() THEN 2 ELSE counted() END`, 0},
+		{`CASE event WHEN "world" THEN counted() WHEN "hello" THEN 2 ELSE counted() END`, 0},
+	}
+	for _, tt := range tests {
+		countedCt = 0
+		_, ok := vm.Eval(readContext, expr.MustParse(tt.expr))
+		assert.True(t, ok, tt.expr)
+		assert.Equal(t, tt.ct, countedCt, tt.expr)
+	}
+}
+
 func TestBuiltins(t *testing.T) {
 
 	t1 := dateparse.MustParse("12/18/2015")
@@ -314,3 +314,140 @@ func (m *All) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
 
 // Type is BoolType for All function
 func (m *All) Type() value.ValueType { return value.BoolType }
+
+// Coalesce returns the first of its arguments which is not null, the
+// arguments after it are not evaluated.
+//
+//    coalesce(nickname, name, "anonymous")  => "bob", true
+//    coalesce(not_field, 5)                 => 5, true
+//
+type Coalesce struct{}
+
+// Type is unknown, the type of its arguments
+func (m *Coalesce) Type() value.ValueType { return value.UnknownType }
+func (m *Coalesce) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 {
+		return nil, fmt.Errorf("Expected 1 or more args for COALESCE(arg, arg, ...) but got %s", n)
+	}
+	return coalesceEval, nil
+}
+func (m *Coalesce) LazyEval(n *expr.FuncNode) expr.LazyEvaluatorFunc {
+	return coalesceLazyEval
+}
+func coalesceEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	for _, v := range vals {
+		if !value.IsNilish(v) {
+			return v, true
+		}
+	}
+	return value.NewNilValue(), true
+}
+func coalesceLazyEval(ctx expr.EvalContext, args []expr.Node, eval expr.ArgEvaluator) (value.Value, bool) {
+	for _, arg := range args {
+		if v, ok := eval(arg); ok && !value.IsNilish(v) {
+			return v, true
+		}
+	}
+	return value.NewNilValue(), true
+}
+
+// IfNull returns its first argument if it is not null, else its second
+// which is only then evaluated.
+//
+//    ifnull(nickname, name)  => "bob", true
+//
+type IfNull struct{}
+
+// Type is unknown, the type of its arguments
+func (m *IfNull) Type() value.ValueType { return value.UnknownType }
+func (m *IfNull) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 2 {
+		return nil, fmt.Errorf("Expected exactly 2 args for IFNULL(arg, default) but got %s", n)
+	}
+	return coalesceEval, nil
+}
+func (m *IfNull) LazyEval(n *expr.FuncNode) expr.LazyEvaluatorFunc {
+	return coalesceLazyEval
+}
+
+// NullIf returns null if its two arguments are equal, else the first.  The
+// second is not evaluated if the first is null.
+//
+//    nullif(status, "unknown")  => nil, true    // status = "unknown"
+//    nullif(status, "unknown")  => "open", true // status = "open"
+//
+type NullIf struct{}
+
+// Type is unknown, the type of its first argument
+func (m *NullIf) Type() value.ValueType { return value.UnknownType }
+func (m *NullIf) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 2 {
+		return nil, fmt.Errorf("Expected exactly 2 args for NULLIF(arg, arg) but got %s", n)
+	}
+	return nullIfEval, nil
+}
+func (m *NullIf) LazyEval(n *expr.FuncNode) expr.LazyEvaluatorFunc {
+	return nullIfLazyEval
+}
+func nullIfEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if value.IsNilish(vals[0]) {
+		return value.NewNilValue(), true
+	}
+	if value.IsNilish(vals[1]) {
+		return vals[0], true
+	}
+	if eq, err := value.Equal(vals[0], vals[1]); err == nil && eq {
+		return value.NewNilValue(), true
+	}
+	return vals[0], true
+}
+func nullIfLazyEval(ctx expr.EvalContext, args []expr.Node, eval expr.ArgEvaluator) (value.Value, bool) {
+	lv, ok := eval(args[0])
+	if !ok || value.IsNilish(lv) {
+		return value.NewNilValue(), true
+	}
+	rv, ok := eval(args[1])
+	if !ok {
+		rv = nil
+	}
+	return nullIfEval(ctx, []value.Value{lv, rv})
+}
+
+// If returns its second argument if its first is true, else its third
+// (or null if it has none).  Only the argument returned is evaluated.
+//
+//    if(age > 20, "adult", "minor")  => "adult", true
+//
+type If struct{}
+
+// Type is unknown, the type of its arguments
+func (m *If) Type() value.ValueType { return value.UnknownType }
+func (m *If) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 2 || len(n.Args) > 3 {
+		return nil, fmt.Errorf("Expected 2 or 3 args for IF(cond, then, else) but got %s", n)
+	}
+	return ifEval, nil
+}
+func (m *If) LazyEval(n *expr.FuncNode) expr.LazyEvaluatorFunc {
+	return ifLazyEval
+}
+func ifEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	if bv, ok := value.ValueToBool(vals[0]); ok && bv {
+		return vals[1], true
+	}
+	if len(vals) > 2 {
+		return vals[2], true
+	}
+	return value.NewNilValue(), true
+}
+func ifLazyEval(ctx expr.EvalContext, args []expr.Node, eval expr.ArgEvaluator) (value.Value, bool) {
+	if cv, ok := eval(args[0]); ok {
+		if bv, ok := value.ValueToBool(cv); ok && bv {
+			return eval(args[1])
+		}
+	}
+	if len(args) > 2 {
+		return eval(args[2])
+	}
+	return value.NewNilValue(), true
+}
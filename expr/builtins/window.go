@@ -0,0 +1,106 @@
+package builtins
+
+import (
+	"fmt"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/value"
+)
+
+// RowNumber the 1 based position of the row in its window partition,
+// only meaningful with an OVER clause, which is evaluated by the executor.
+//
+//    row_number() OVER (PARTITION BY region ORDER BY amount DESC)
+//
+type RowNumber struct{}
+
+// Type is IntType
+func (m *RowNumber) Type() value.ValueType { return value.IntType }
+func (m *RowNumber) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return noArgWindowValidate("row_number", n)
+}
+
+// Rank the rank of the row in its window partition, peers (equal ORDER BY
+// values) share a rank leaving gaps after them.
+//
+//    rank() OVER (ORDER BY score DESC)   => 1, 1, 3
+//
+type Rank struct{}
+
+// Type is IntType
+func (m *Rank) Type() value.ValueType { return value.IntType }
+func (m *Rank) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return noArgWindowValidate("rank", n)
+}
+
+// DenseRank the rank of the row in its window partition without gaps.
+//
+//    dense_rank() OVER (ORDER BY score DESC)   => 1, 1, 2
+//
+type DenseRank struct{}
+
+// Type is IntType
+func (m *DenseRank) Type() value.ValueType { return value.IntType }
+func (m *DenseRank) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return noArgWindowValidate("dense_rank", n)
+}
+
+func noArgWindowValidate(name string, n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 0 {
+		return nil, fmt.Errorf("Expected 0 args for %s() but got %s", name, n)
+	}
+	return nilEval, nil
+}
+
+func nilEval(ctx expr.EvalContext, vals []value.Value) (value.Value, bool) {
+	return value.NilValueVal, false
+}
+
+// Lag the value of expr at the row offset (default 1) rows before this
+// one in its window partition, or default (nil) if there is no such row.
+//
+//    lag(amount) OVER (ORDER BY id)
+//    lag(amount, 2, 0) OVER (PARTITION BY region ORDER BY id)
+//
+type Lag struct{}
+
+// Type is unknown, the type of expr
+func (m *Lag) Type() value.ValueType { return value.UnknownType }
+func (m *Lag) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return offsetWindowValidate("lag", n)
+}
+
+// Lead the value of expr at the row offset (default 1) rows after this
+// one in its window partition, or default (nil) if there is no such row.
+//
+//    lead(amount) OVER (ORDER BY id)
+//
+type Lead struct{}
+
+// Type is unknown, the type of expr
+func (m *Lead) Type() value.ValueType { return value.UnknownType }
+func (m *Lead) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return offsetWindowValidate("lead", n)
+}
+
+func offsetWindowValidate(name string, n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) < 1 || len(n.Args) > 3 {
+		return nil, fmt.Errorf("Expected 1 to 3 args for %s(expr [, offset [, default]]) but got %s", name, n)
+	}
+	return firstArgEval, nil
+}
+
+// FirstValue the value of expr at the first row of the window frame.
+//
+//    first_value(amount) OVER (PARTITION BY region ORDER BY id)
+//
+type FirstValue struct{}
+
+// Type is unknown, the type of expr
+func (m *FirstValue) Type() value.ValueType { return value.UnknownType }
+func (m *FirstValue) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	if len(n.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for first_value(expr) but got %s", n)
+	}
+	return firstArgEval, nil
+}
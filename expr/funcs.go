@@ -1,6 +1,7 @@
 package expr
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 
@@ -24,10 +25,51 @@ type (
 		// function.
 		Validate(n *FuncNode) (EvaluatorFunc, error)
 	}
+	// ArgEvaluator evaluates a single argument of a LazyFunc.
+	ArgEvaluator func(arg Node) (value.Value, bool)
+	// LazyEvaluatorFunc evaluates a function from its un-evaluated args,
+	// evaluating only those it needs through eval (short-circuit).
+	LazyEvaluatorFunc func(ctx EvalContext, args []Node, eval ArgEvaluator) (value.Value, bool)
+	// LazyFunc allows a CustomFunc to evaluate its own args lazily rather
+	// than be handed all of them evaluated, such as coalesce() which stops
+	// at the first non-null arg.
+	LazyFunc interface {
+		LazyEval(n *FuncNode) LazyEvaluatorFunc
+	}
 	// AggFunc allows custom functions to specify if they provide aggregation
 	AggFunc interface {
 		IsAgg() bool
 	}
+	// AggPartial is a struct to represent the partial aggregation
+	// that will be reduced on finalizer.  IE, for consistent-hash based
+	// group-bys calculated across multiple nodes this holds info that
+	// needs to be further calculated it only represents this hash.
+	AggPartial struct {
+		Ct   int64         // count of values seen
+		N    float64       // running numeric total
+		Sq   float64       // running sum of squares (variance, stddev)
+		Val  interface{}   // single carried value (min, max)
+		Vals []interface{} // list of carried values (distinct, concat)
+	}
+	// Aggregator is the stateful accumulator for an aggregate function
+	// in a group-by.  Do is called once per row with the evaluated
+	// value of the function node's AggInput, Result once per group.
+	// When partial, Result must return an *AggPartial which a finalizer
+	// Merge's.
+	Aggregator interface {
+		Do(v value.Value)
+		Result() interface{}
+		Reset()
+		Merge(*AggPartial)
+	}
+	// AggregatorMaker creates a new Aggregator for the given function node.
+	AggregatorMaker func(n *FuncNode, partial bool) (Aggregator, error)
+	// AggregatorFunc allows a CustomFunc to declare its own group-by
+	// Aggregator alongside its row evaluation.
+	AggregatorFunc interface {
+		AggFunc
+		NewAggregator(n *FuncNode, partial bool) (Aggregator, error)
+	}
 	// FuncResolver is a function resolution interface that allows
 	// local/namespaced function resolution.
 	FuncResolver interface {
@@ -58,16 +100,33 @@ func NewFuncRegistry() *FuncRegistry {
 
 // Add a name/function to registry
 func (m *FuncRegistry) Add(name string, fn CustomFunc) {
+	var mk AggregatorMaker
+	if aggfn, ok := fn.(AggregatorFunc); ok {
+		mk = aggfn.NewAggregator
+	}
+	m.add(name, fn, mk)
+}
+
+// AddAgg add a name/function to registry along with the Aggregator
+// maker used to evaluate it across rows in a group-by.
+func (m *FuncRegistry) AddAgg(name string, fn CustomFunc, mk AggregatorMaker) {
+	m.add(name, fn, mk)
+}
+
+func (m *FuncRegistry) add(name string, fn CustomFunc, mk AggregatorMaker) {
 	name = strings.ToLower(name)
-	newFunc := Func{Name: name, CustomFunc: fn}
+	newFunc := Func{Name: name, CustomFunc: fn, AggMaker: mk}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	aggfn, hasAggFlag := fn.(AggFunc)
 	if hasAggFlag {
 		newFunc.Aggregate = aggfn.IsAgg()
-		if newFunc.Aggregate {
-			m.aggs[name] = struct{}{}
-		}
+	}
+	if mk != nil {
+		newFunc.Aggregate = true
+	}
+	if newFunc.Aggregate {
+		m.aggs[name] = struct{}{}
 	}
 	m.funcs[name] = newFunc
 }
@@ -80,7 +139,108 @@ func (m *FuncRegistry) FuncGet(name string) (Func, bool) {
 	return fn, ok
 }
 
+// IsAgg is this named function an aggregate function.
+func (m *FuncRegistry) IsAgg(name string) bool {
+	m.mu.RLock()
+	_, ok := m.aggs[strings.ToLower(name)]
+	m.mu.RUnlock()
+	return ok
+}
+
 // FuncAdd Global add Functions to the VM func registry occurs here.
 func FuncAdd(name string, fn CustomFunc) {
 	funcReg.Add(name, fn)
 }
+
+// AggAdd Global add of an aggregate function and its group-by Aggregator.
+func AggAdd(name string, fn CustomFunc, mk AggregatorMaker) {
+	funcReg.AddAgg(name, fn, mk)
+}
+
+// NewAggregator creates the group-by Aggregator for this function node
+// if its function is a registered aggregate.  The Aggregator of a
+// DISTINCT aggregate, count(DISTINCT x), is passed the value of its
+// argument rather than of the function, see AggInput.
+func (m *FuncNode) NewAggregator(partial bool) (Aggregator, error) {
+	if m.F.AggMaker == nil {
+		return nil, fmt.Errorf("No aggregator registered for function: %s", m.Name)
+	}
+	if !m.Distinct {
+		return m.F.AggMaker(m, partial)
+	}
+	if len(m.Args) != 1 {
+		return nil, fmt.Errorf("Expected 1 arg for DISTINCT aggregate but got %s", m)
+	}
+	// the partials carry the distinct values, only the final aggregates
+	agg, err := m.F.AggMaker(m, false)
+	if err != nil {
+		return nil, err
+	}
+	return &distinctAggregator{
+		fn:      m,
+		agg:     agg,
+		partial: partial,
+		seen:    make(map[string]struct{}),
+	}, nil
+}
+
+// AggInput the node evaluated per row and passed to the Do of this
+// function's Aggregator, the function itself or the argument of a
+// DISTINCT aggregate.
+func (m *FuncNode) AggInput() Node {
+	if m.Distinct && len(m.Args) == 1 {
+		return m.Args[0]
+	}
+	return m
+}
+
+// distinctAggregator feeds the Aggregator of a DISTINCT aggregate the
+// function's value for each distinct non-null value of its argument.
+type distinctAggregator struct {
+	fn      *FuncNode
+	agg     Aggregator
+	partial bool
+	seen    map[string]struct{}
+	vals    []interface{}
+}
+
+func (m *distinctAggregator) Do(v value.Value) {
+	if v == nil || v.Nil() || v.Err() {
+		return
+	}
+	// keyed by type as well as value so 1 and "1" are distinct
+	key := v.Type().String() + ":" + v.ToString()
+	if _, dup := m.seen[key]; dup {
+		return
+	}
+	m.seen[key] = struct{}{}
+	if m.partial {
+		m.vals = append(m.vals, v.Value())
+		return
+	}
+	if m.fn.Eval == nil {
+		m.agg.Do(v)
+		return
+	}
+	if fv, ok := m.fn.Eval(nil, []value.Value{v}); ok && fv != nil {
+		m.agg.Do(fv)
+	} else {
+		m.agg.Do(value.NewNilValue())
+	}
+}
+func (m *distinctAggregator) Result() interface{} {
+	if m.partial {
+		return &AggPartial{Ct: int64(len(m.vals)), Vals: m.vals}
+	}
+	return m.agg.Result()
+}
+func (m *distinctAggregator) Reset() {
+	m.seen = make(map[string]struct{})
+	m.vals = nil
+	m.agg.Reset()
+}
+func (m *distinctAggregator) Merge(a *AggPartial) {
+	for _, v := range a.Vals {
+		m.Do(value.NewValue(v))
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/expr/builtins"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/value"
 )
 
 func TestFuncsRegistry(t *testing.T) {
@@ -17,3 +19,101 @@ func TestFuncsRegistry(t *testing.T) {
 	assert.Equal(t, false, ok)
 
 }
+
+type testAggFunc struct{}
+
+func (m *testAggFunc) Type() value.ValueType { return value.IntType }
+func (m *testAggFunc) Validate(n *expr.FuncNode) (expr.EvaluatorFunc, error) {
+	return expr.EmptyEvalFunc, nil
+}
+
+type testAgg struct{ n int64 }
+
+func (m *testAgg) Do(v value.Value)         { m.n++ }
+func (m *testAgg) Result() interface{}      { return m.n }
+func (m *testAgg) Reset()                   { m.n = 0 }
+func (m *testAgg) Merge(a *expr.AggPartial) { m.n += a.Ct }
+
+func TestFuncsRegistryAgg(t *testing.T) {
+	t.Parallel()
+
+	reg := expr.NewFuncRegistry()
+	reg.AddAgg("RowCt", &testAggFunc{}, func(n *expr.FuncNode, partial bool) (expr.Aggregator, error) {
+		return &testAgg{}, nil
+	})
+	assert.True(t, reg.IsAgg("rowct"))
+
+	fn, ok := reg.FuncGet("rowct")
+	assert.True(t, ok)
+	assert.True(t, fn.Aggregate)
+
+	node, err := expr.ParseExprWithFuncs(expr.NewLexTokenPager(lex.NewExpressionLexer("rowct(a)")), reg)
+	assert.Equal(t, nil, err)
+	agg, err := node.(*expr.FuncNode).NewAggregator(false)
+	assert.Equal(t, nil, err)
+	agg.Do(value.NewIntValue(1))
+	agg.Merge(&expr.AggPartial{Ct: 2})
+	assert.Equal(t, int64(3), agg.Result())
+
+	// builtins that declare their own aggregator
+	builtins.LoadAllBuiltins()
+	node, err = expr.ParseExpression("max(a)")
+	assert.Equal(t, nil, err)
+	_, err = node.(*expr.FuncNode).NewAggregator(true)
+	assert.Equal(t, nil, err)
+
+	node, err = expr.ParseExpression("tolower(a)")
+	assert.Equal(t, nil, err)
+	_, err = node.(*expr.FuncNode).NewAggregator(false)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestFuncsDistinctAgg(t *testing.T) {
+	t.Parallel()
+
+	builtins.LoadAllBuiltins()
+	node, err := expr.ParseExpression("count(DISTINCT a)")
+	assert.Equal(t, nil, err)
+	fn := node.(*expr.FuncNode)
+	assert.True(t, fn.Distinct)
+	assert.True(t, fn.AggInput().Equal(fn.Args[0]))
+
+	// partials carry their distinct values, so values seen by more
+	// than one partition are counted once
+	p1, err := fn.NewAggregator(true)
+	assert.Equal(t, nil, err)
+	p2, err := fn.NewAggregator(true)
+	assert.Equal(t, nil, err)
+	for _, v := range []int64{1, 2, 2} {
+		p1.Do(value.NewIntValue(v))
+	}
+	p2.Do(value.NewIntValue(2))
+	p2.Do(value.NewIntValue(3))
+	p2.Do(value.NewNilValue())
+
+	agg, err := fn.NewAggregator(false)
+	assert.Equal(t, nil, err)
+	agg.Merge(p1.Result().(*expr.AggPartial))
+	agg.Merge(p2.Result().(*expr.AggPartial))
+	assert.Equal(t, int64(3), agg.Result())
+
+	agg.Reset()
+	agg.Do(value.NewStringValue("a"))
+	agg.Do(value.NewStringValue("a"))
+	assert.Equal(t, int64(1), agg.Result())
+
+	// values of different types are distinct
+	agg.Reset()
+	agg.Do(value.NewIntValue(1))
+	agg.Do(value.NewStringValue("1"))
+	assert.Equal(t, int64(2), agg.Result())
+
+	node, err = expr.ParseExpression("count_distinct(a)")
+	assert.Equal(t, nil, err)
+	agg, err = node.(*expr.FuncNode).NewAggregator(false)
+	assert.Equal(t, nil, err)
+	agg.Do(value.NewIntValue(1))
+	agg.Do(value.NewStringValue("1"))
+	agg.Do(value.NewIntValue(1))
+	assert.Equal(t, int64(2), agg.Result())
+}
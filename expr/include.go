@@ -40,6 +40,30 @@ func inlineIncludesDepth(ctx Includer, arg Node, depth int) (Node, error) {
 	}
 
 	switch n := arg.(type) {
+	case *CaseNode:
+		// its args are not a single slice so replace them in place
+		inline := func(narg Node) (Node, error) {
+			if narg == nil {
+				return nil, nil
+			}
+			return inlineIncludesDepth(ctx, narg, depth+1)
+		}
+		var err error
+		if n.Operand, err = inline(n.Operand); err != nil {
+			return nil, err
+		}
+		for i := range n.Whens {
+			if n.Whens[i], err = inline(n.Whens[i]); err != nil {
+				return nil, err
+			}
+			if n.Thens[i], err = inline(n.Thens[i]); err != nil {
+				return nil, err
+			}
+		}
+		if n.Else, err = inline(n.Else); err != nil {
+			return nil, err
+		}
+		return arg, nil
 	// FuncNode, BinaryNode, BooleanNode, TriNode, UnaryNode, ArrayNode
 	case NodeArgs:
 		args := n.ChildrenArgs()
@@ -118,6 +142,10 @@ func findAllIncludes(node Node, current []string) []string {
 		for _, arg := range n.Args {
 			current = findAllIncludes(arg, current)
 		}
+	case *CaseNode:
+		for _, arg := range n.ChildrenArgs() {
+			current = findAllIncludes(arg, current)
+		}
 	}
 	return current
 }
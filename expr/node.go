@@ -57,6 +57,7 @@ var (
 	_ NodeArgs = (*FuncNode)(nil)
 	_ NodeArgs = (*UnaryNode)(nil)
 	_ NodeArgs = (*ArrayNode)(nil)
+	_ NodeArgs = (*CaseNode)(nil)
 )
 
 type (
@@ -191,20 +192,23 @@ type (
 	// Func Describes a function expression which wraps and allows native go functions
 	// to be called in expression vm
 	Func struct {
-		Name       string        // name of func, lower-cased
-		Aggregate  bool          // is this aggregate func?
-		CustomFunc               // CustomFunc Is dynamic function that can be registered
-		Eval       EvaluatorFunc // The memoized evaluation function
+		Name       string          // name of func, lower-cased
+		Aggregate  bool            // is this aggregate func?
+		AggMaker   AggregatorMaker // creates group-by aggregator, for aggregate funcs
+		CustomFunc                 // CustomFunc Is dynamic function that can be registered
+		Eval       EvaluatorFunc   // The memoized evaluation function
 	}
 
 	// FuncNode holds a Func, which desribes a go Function as
 	// well as fulfilling the Pos, String() etc for a Node
 	FuncNode struct {
-		Name    string        // Name of func
-		F       Func          // The actual function that this AST maps to
-		Eval    EvaluatorFunc // the evaluator function
-		Missing bool
-		Args    []Node // Arguments are them-selves nodes
+		Name     string            // Name of func
+		F        Func              // The actual function that this AST maps to
+		Eval     EvaluatorFunc     // the evaluator function
+		Lazy     LazyEvaluatorFunc // the lazy evaluator, for a LazyFunc
+		Missing  bool
+		Distinct bool   // aggregates the distinct values of its arg, count(DISTINCT x)
+		Args     []Node // Arguments are them-selves nodes
 	}
 
 	// IdentityNode will look up a value out of a env bag also identities of
@@ -277,6 +281,20 @@ type (
 		Operator lex.Token
 	}
 
+	// CaseNode is a CASE expression, the THEN of the first WHEN that
+	// matches else the ELSE (or NULL).  A searched case has no Operand
+	// and each WHEN is a boolean expression, a simple case compares its
+	// Operand to each WHEN.
+	//
+	//    CASE WHEN x > 5 THEN "big" WHEN x > 1 THEN "small" ELSE "none" END
+	//    CASE status WHEN "a" THEN 1 WHEN "b" THEN 2 END
+	CaseNode struct {
+		Operand Node   // nil on a searched case
+		Whens   []Node // WHEN expressions, one per Thens
+		Thens   []Node
+		Else    Node // nil if no ELSE
+	}
+
 	// UnaryNode negates a single node argument
 	//
 	//    (  not <expression>  |   !<expression> )
@@ -302,6 +320,18 @@ type (
 		Operator   lex.Token
 	}
 
+	// ParamNode is a placeholder for a value bound when the statement is
+	// run, positional (numbered in the order parsed), numbered or named.
+	//
+	//    x = ?
+	//    x = $2
+	//    x = :name
+	ParamNode struct {
+		Text  string // the placeholder as written
+		Name  string // name of a :name placeholder
+		Index int    // 1 based position of a ? or $N placeholder
+	}
+
 	// ArrayNode for holding multiple similar elements
 	//    arg0 IN (arg1,arg2.....)
 	//    5 in (1,2,3,4)
@@ -395,6 +425,10 @@ func findIdentities(node Node, l IdentityNodes) IdentityNodes {
 		for _, arg := range n.Args {
 			l = findIdentities(arg, l)
 		}
+	case *CaseNode:
+		for _, arg := range n.ChildrenArgs() {
+			l = findIdentities(arg, l)
+		}
 	}
 	return l
 }
@@ -532,6 +566,9 @@ func (m *FuncNode) String() string {
 func (m *FuncNode) WriteDialect(w DialectWriter) {
 	io.WriteString(w, m.Name)
 	io.WriteString(w, "(")
+	if m.Distinct {
+		io.WriteString(w, "DISTINCT ")
+	}
 	for i, arg := range m.Args {
 		if i > 0 {
 			io.WriteString(w, ", ")
@@ -550,6 +587,9 @@ func (m *FuncNode) Validate() error {
 		}
 
 		m.Eval = ev
+		if lf, ok := m.F.CustomFunc.(LazyFunc); ok {
+			m.Lazy = lf.LazyEval(m)
+		}
 		return nil
 	}
 
@@ -569,6 +609,7 @@ func (m *FuncNode) ChildrenArgs() []Node {
 func (m *FuncNode) NodePb() *NodePb {
 	n := &FuncNodePb{}
 	n.Name = m.Name
+	n.Distinct = m.Distinct
 	n.Args = make([]NodePb, len(m.Args))
 	for i, a := range m.Args {
 		n.Args[i] = *a.NodePb()
@@ -584,9 +625,10 @@ func (m *FuncNode) FromPB(n *NodePb) Node {
 	}
 
 	f := FuncNode{
-		Name: n.Fn.Name,
-		Args: NodesFromNodesPb(n.Fn.Args),
-		F:    fn,
+		Name:     n.Fn.Name,
+		Args:     NodesFromNodesPb(n.Fn.Args),
+		F:        fn,
+		Distinct: n.Fn.Distinct,
 	}
 
 	if err := f.Validate(); err != nil {
@@ -638,6 +680,9 @@ func (m *FuncNode) FromExpr(e *Expr) error {
 	if m.Eval == nil {
 		m.Eval = fn.Eval
 	}
+	if m.Lazy == nil {
+		m.Lazy = fn.Lazy
+	}
 	return nil
 }
 func (m *FuncNode) Equal(n Node) bool {
@@ -654,6 +699,9 @@ func (m *FuncNode) Equal(n Node) bool {
 		if m.Name != nt.Name {
 			return false
 		}
+		if m.Distinct != nt.Distinct {
+			return false
+		}
 		if len(m.Args) != len(nt.Args) {
 			return false
 		}
@@ -1672,6 +1720,263 @@ func (m *TriNode) Equal(n Node) bool {
 	return false
 }
 
+// NewCaseNode create a CASE expression node, operand is nil for a
+// searched case.
+//
+//    CASE [@operand] WHEN @when THEN @then [...] [ELSE @else] END
+//
+func NewCaseNode(operand Node) *CaseNode {
+	return &CaseNode{Operand: operand}
+}
+func (m *CaseNode) NodeType() string { return "Case" }
+func (m *CaseNode) String() string {
+	w := NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *CaseNode) WriteDialect(w DialectWriter) {
+	io.WriteString(w, "CASE ")
+	if m.Operand != nil {
+		m.Operand.WriteDialect(w)
+		io.WriteString(w, " ")
+	}
+	for i, when := range m.Whens {
+		io.WriteString(w, "WHEN ")
+		when.WriteDialect(w)
+		io.WriteString(w, " THEN ")
+		m.Thens[i].WriteDialect(w)
+		io.WriteString(w, " ")
+	}
+	if m.Else != nil {
+		io.WriteString(w, "ELSE ")
+		m.Else.WriteDialect(w)
+		io.WriteString(w, " ")
+	}
+	io.WriteString(w, "END")
+}
+func (m *CaseNode) Validate() error {
+	if len(m.Whens) == 0 {
+		return fmt.Errorf("CASE requires at least one WHEN: %s", m)
+	}
+	if len(m.Whens) != len(m.Thens) {
+		return fmt.Errorf("CASE requires a THEN for each WHEN: %s", m)
+	}
+	for _, n := range m.ChildrenArgs() {
+		if err := n.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChildrenArgs the operand, then each when followed by its then, then else.
+func (m *CaseNode) ChildrenArgs() []Node {
+	args := make([]Node, 0, len(m.Whens)*2+2)
+	if m.Operand != nil {
+		args = append(args, m.Operand)
+	}
+	for i, when := range m.Whens {
+		args = append(args, when, m.Thens[i])
+	}
+	if m.Else != nil {
+		args = append(args, m.Else)
+	}
+	return args
+}
+func (m *CaseNode) NodePb() *NodePb {
+	n := &CaseNodePb{
+		Whens: make([]NodePb, len(m.Whens)),
+		Thens: make([]NodePb, len(m.Thens)),
+	}
+	if m.Operand != nil {
+		n.Operand = m.Operand.NodePb()
+	}
+	for i, arg := range m.Whens {
+		n.Whens[i] = *arg.NodePb()
+	}
+	for i, arg := range m.Thens {
+		n.Thens[i] = *arg.NodePb()
+	}
+	if m.Else != nil {
+		n.Else = m.Else.NodePb()
+	}
+	return &NodePb{Cn: n}
+}
+func (m *CaseNode) FromPB(n *NodePb) Node {
+	return &CaseNode{
+		Operand: NodeFromNodePb(n.Cn.Operand),
+		Whens:   NodesFromNodesPb(n.Cn.Whens),
+		Thens:   NodesFromNodesPb(n.Cn.Thens),
+		Else:    NodeFromNodePb(n.Cn.Else),
+	}
+}
+
+// Expr convert the CaseNode to Expr, args are the operand, each when and
+// then, then the else.  A missing operand or else is an empty Expr.
+func (m *CaseNode) Expr() *Expr {
+	fe := &Expr{Op: lex.TokenCase.String(), Args: make([]*Expr, 0, len(m.Whens)*2+2)}
+	if m.Operand != nil {
+		fe.Args = append(fe.Args, m.Operand.Expr())
+	} else {
+		fe.Args = append(fe.Args, &Expr{})
+	}
+	for i, when := range m.Whens {
+		fe.Args = append(fe.Args, when.Expr(), m.Thens[i].Expr())
+	}
+	if m.Else != nil {
+		fe.Args = append(fe.Args, m.Else.Expr())
+	} else {
+		fe.Args = append(fe.Args, &Expr{})
+	}
+	return fe
+}
+func (m *CaseNode) FromExpr(e *Expr) error {
+	if !strings.EqualFold(e.Op, lex.TokenCase.String()) {
+		return fmt.Errorf("Expected 'case' but got %v", e.Op)
+	}
+	if len(e.Args) < 4 || len(e.Args)%2 != 0 {
+		return fmt.Errorf("Invalid CaseNode, expected operand, when, then, else args %+v", e)
+	}
+	caseArg := func(e *Expr) (Node, error) {
+		if e.Op == "" && e.Identity == "" && e.Value == "" && len(e.Args) == 0 {
+			return nil, nil
+		}
+		return NodeFromExpr(e)
+	}
+	var err error
+	if m.Operand, err = caseArg(e.Args[0]); err != nil {
+		return err
+	}
+	last := len(e.Args) - 1
+	if m.Else, err = caseArg(e.Args[last]); err != nil {
+		return err
+	}
+	m.Whens, m.Thens = nil, nil
+	for i := 1; i < last; i += 2 {
+		when, err := NodeFromExpr(e.Args[i])
+		if err != nil {
+			return err
+		}
+		then, err := NodeFromExpr(e.Args[i+1])
+		if err != nil {
+			return err
+		}
+		m.Whens = append(m.Whens, when)
+		m.Thens = append(m.Thens, then)
+	}
+	return nil
+}
+func (m *CaseNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	if nt, ok := n.(*CaseNode); ok {
+		if (m.Operand == nil) != (nt.Operand == nil) || (m.Else == nil) != (nt.Else == nil) {
+			return false
+		}
+		if m.Operand != nil && !m.Operand.Equal(nt.Operand) {
+			return false
+		}
+		if m.Else != nil && !m.Else.Equal(nt.Else) {
+			return false
+		}
+		if len(m.Whens) != len(nt.Whens) || len(m.Thens) != len(nt.Thens) {
+			return false
+		}
+		for i, arg := range nt.Whens {
+			if !arg.Equal(m.Whens[i]) {
+				return false
+			}
+		}
+		for i, arg := range nt.Thens {
+			if !arg.Equal(m.Thens[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// NewParamNode create a placeholder node from its text, a ? placeholder
+// is given its position by the parser.
+func NewParamNode(text string) (*ParamNode, error) {
+	m := &ParamNode{Text: text}
+	switch {
+	case text == "?":
+	case len(text) > 1 && text[0] == '$':
+		idx, err := strconv.Atoi(text[1:])
+		if err != nil || idx < 1 {
+			return nil, fmt.Errorf("invalid placeholder %q", text)
+		}
+		m.Index = idx
+	case len(text) > 1 && text[0] == ':':
+		m.Name = text[1:]
+	default:
+		return nil, fmt.Errorf("invalid placeholder %q", text)
+	}
+	return m, nil
+}
+func (m *ParamNode) NodeType() string { return "Param" }
+func (m *ParamNode) String() string   { return m.Text }
+func (m *ParamNode) WriteDialect(w DialectWriter) {
+	io.WriteString(w, m.Text)
+}
+func (m *ParamNode) Validate() error {
+	if m.Name == "" && m.Index < 1 {
+		return fmt.Errorf("placeholder %q has no position", m.Text)
+	}
+	return nil
+}
+func (m *ParamNode) NodePb() *NodePb {
+	return &NodePb{Pn: &ParamNodePb{Text: m.Text, Index: int32(m.Index)}}
+}
+func (m *ParamNode) FromPB(n *NodePb) Node {
+	pn, err := NewParamNode(n.Pn.Text)
+	if err != nil {
+		return nil
+	}
+	pn.Index = int(n.Pn.Index)
+	return pn
+}
+func (m *ParamNode) Expr() *Expr {
+	return &Expr{Op: lex.TokenPlaceholder.String(), Identity: m.Text, Value: strconv.Itoa(m.Index)}
+}
+func (m *ParamNode) FromExpr(e *Expr) error {
+	pn, err := NewParamNode(e.Identity)
+	if err != nil {
+		return err
+	}
+	if e.Value != "" {
+		if pn.Index, err = strconv.Atoi(e.Value); err != nil {
+			return err
+		}
+	}
+	*m = *pn
+	return nil
+}
+func (m *ParamNode) Equal(n Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil && n != nil {
+		return false
+	}
+	if m != nil && n == nil {
+		return false
+	}
+	if nt, ok := n.(*ParamNode); ok {
+		return m.Text == nt.Text && m.Name == nt.Name && m.Index == nt.Index
+	}
+	return false
+}
+
 // Unary nodes
 //
 //    NOT <expression>
@@ -2060,6 +2365,9 @@ func NodeFromNodePb(n *NodePb) Node {
 	case n.An != nil:
 		var an *ArrayNode
 		return an.FromPB(n)
+	case n.Cn != nil:
+		var cn *CaseNode
+		return cn.FromPB(n)
 	case n.Nn != nil:
 		var nn *NumberNode
 		return nn.FromPB(n)
@@ -2077,6 +2385,9 @@ func NodeFromNodePb(n *NodePb) Node {
 		return in.FromPB(n)
 	case n.Niln != nil:
 		return &NullNode{}
+	case n.Pn != nil:
+		var pn *ParamNode
+		return pn.FromPB(n)
 	}
 	return nil
 }
@@ -2201,8 +2512,12 @@ func NodeFromExpr(e *Expr) (Node, error) {
 			n = &UnaryNode{}
 		case "BETWEEN":
 			n = &TriNode{}
+		case "CASE":
+			n = &CaseNode{}
+		case "PLACEHOLDER":
+			n = &ParamNode{}
 		case "=", "-", "+", "++", "+=", "/", "%", "==", "<=", "!=", ">=", ">", "<", "*",
-			"LIKE", "CONTAINS", "INTERSECTS", "IN":
+			"LIKE", "CONTAINS", "INTERSECTS", "IN", "IS":
 
 			// very weird special case for FILTER * where the * is an ident not op
 			if e.Op == "*" && len(e.Args) == 0 {
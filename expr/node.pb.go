@@ -23,6 +23,8 @@
 		NumberNodePb
 		ValueNodePb
 		NullNodePb
+		CaseNodePb
+		ParamNodePb
 */
 package expr
 
@@ -71,12 +73,14 @@ type NodePb struct {
 	Fn               *FuncNodePb     `protobuf:"bytes,4,opt,name=fn" json:"fn,omitempty"`
 	Tn               *TriNodePb      `protobuf:"bytes,5,opt,name=tn" json:"tn,omitempty"`
 	An               *ArrayNodePb    `protobuf:"bytes,6,opt,name=an" json:"an,omitempty"`
+	Cn               *CaseNodePb     `protobuf:"bytes,7,opt,name=cn" json:"cn,omitempty"`
 	Nn               *NumberNodePb   `protobuf:"bytes,10,opt,name=nn" json:"nn,omitempty"`
 	Vn               *ValueNodePb    `protobuf:"bytes,11,opt,name=vn" json:"vn,omitempty"`
 	In               *IdentityNodePb `protobuf:"bytes,12,opt,name=in" json:"in,omitempty"`
 	Sn               *StringNodePb   `protobuf:"bytes,13,opt,name=sn" json:"sn,omitempty"`
 	Incn             *IncludeNodePb  `protobuf:"bytes,14,opt,name=incn" json:"incn,omitempty"`
 	Niln             *NullNodePb     `protobuf:"bytes,15,opt,name=niln" json:"niln,omitempty"`
+	Pn               *ParamNodePb    `protobuf:"bytes,16,opt,name=pn" json:"pn,omitempty"`
 	XXX_unrecognized []byte          `json:"-"`
 }
 
@@ -140,6 +144,7 @@ func (*UnaryNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []
 type FuncNodePb struct {
 	Name             string   `protobuf:"bytes,1,req,name=name" json:"name"`
 	Args             []NodePb `protobuf:"bytes,2,rep,name=args" json:"args"`
+	Distinct         bool     `protobuf:"varint,3,opt,name=distinct" json:"distinct"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -235,6 +240,32 @@ func (m *NullNodePb) String() string            { return proto.CompactTextString
 func (*NullNodePb) ProtoMessage()               {}
 func (*NullNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{13} }
 
+// Case Node, the operand is nil on a searched case
+type CaseNodePb struct {
+	Operand          *NodePb  `protobuf:"bytes,1,opt,name=operand" json:"operand,omitempty"`
+	Whens            []NodePb `protobuf:"bytes,2,rep,name=whens" json:"whens"`
+	Thens            []NodePb `protobuf:"bytes,3,rep,name=thens" json:"thens"`
+	Else             *NodePb  `protobuf:"bytes,4,opt,name=else" json:"else,omitempty"`
+	XXX_unrecognized []byte   `json:"-"`
+}
+
+func (m *CaseNodePb) Reset()                    { *m = CaseNodePb{} }
+func (m *CaseNodePb) String() string            { return proto.CompactTextString(m) }
+func (*CaseNodePb) ProtoMessage()               {}
+func (*CaseNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{14} }
+
+// Param Node, an unbound placeholder
+type ParamNodePb struct {
+	Text             string `protobuf:"bytes,1,opt,name=text" json:"text"`
+	Index            int32  `protobuf:"varint,2,opt,name=index" json:"index"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *ParamNodePb) Reset()                    { *m = ParamNodePb{} }
+func (m *ParamNodePb) String() string            { return proto.CompactTextString(m) }
+func (*ParamNodePb) ProtoMessage()               {}
+func (*ParamNodePb) Descriptor() ([]byte, []int) { return fileDescriptorNode, []int{15} }
+
 func init() {
 	proto.RegisterType((*ExprPb)(nil), "expr.ExprPb")
 	proto.RegisterType((*NodePb)(nil), "expr.NodePb")
@@ -250,6 +281,8 @@ func init() {
 	proto.RegisterType((*NumberNodePb)(nil), "expr.NumberNodePb")
 	proto.RegisterType((*ValueNodePb)(nil), "expr.ValueNodePb")
 	proto.RegisterType((*NullNodePb)(nil), "expr.NullNodePb")
+	proto.RegisterType((*CaseNodePb)(nil), "expr.CaseNodePb")
+	proto.RegisterType((*ParamNodePb)(nil), "expr.ParamNodePb")
 }
 func (m *ExprPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -396,65 +429,87 @@ func (m *NodePb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n6
 	}
+	if m.Cn != nil {
+		data[i] = 0x3a
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Cn.Size()))
+		n7, err := m.Cn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
 	if m.Nn != nil {
 		data[i] = 0x52
 		i++
 		i = encodeVarintNode(data, i, uint64(m.Nn.Size()))
-		n7, err := m.Nn.MarshalTo(data[i:])
+		n8, err := m.Nn.MarshalTo(data[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n7
+		i += n8
 	}
 	if m.Vn != nil {
 		data[i] = 0x5a
 		i++
 		i = encodeVarintNode(data, i, uint64(m.Vn.Size()))
-		n8, err := m.Vn.MarshalTo(data[i:])
+		n9, err := m.Vn.MarshalTo(data[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n8
+		i += n9
 	}
 	if m.In != nil {
 		data[i] = 0x62
 		i++
 		i = encodeVarintNode(data, i, uint64(m.In.Size()))
-		n9, err := m.In.MarshalTo(data[i:])
+		n10, err := m.In.MarshalTo(data[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n9
+		i += n10
 	}
 	if m.Sn != nil {
 		data[i] = 0x6a
 		i++
 		i = encodeVarintNode(data, i, uint64(m.Sn.Size()))
-		n10, err := m.Sn.MarshalTo(data[i:])
+		n11, err := m.Sn.MarshalTo(data[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n10
+		i += n11
 	}
 	if m.Incn != nil {
 		data[i] = 0x72
 		i++
 		i = encodeVarintNode(data, i, uint64(m.Incn.Size()))
-		n11, err := m.Incn.MarshalTo(data[i:])
+		n12, err := m.Incn.MarshalTo(data[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n11
+		i += n12
 	}
 	if m.Niln != nil {
 		data[i] = 0x7a
 		i++
 		i = encodeVarintNode(data, i, uint64(m.Niln.Size()))
-		n12, err := m.Niln.MarshalTo(data[i:])
+		n13, err := m.Niln.MarshalTo(data[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n12
+		i += n13
+	}
+	if m.Pn != nil {
+		data[i] = 0x82
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Pn.Size()))
+		n14, err := m.Pn.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
 	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
@@ -571,11 +626,11 @@ func (m *IncludeNodePb) MarshalTo(data []byte) (int, error) {
 	data[i] = 0x1a
 	i++
 	i = encodeVarintNode(data, i, uint64(m.Identity.Size()))
-	n13, err := m.Identity.MarshalTo(data[i:])
+	n14, err := m.Identity.MarshalTo(data[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n13
+	i += n14
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -611,11 +666,11 @@ func (m *UnaryNodePb) MarshalTo(data []byte) (int, error) {
 	data[i] = 0x1a
 	i++
 	i = encodeVarintNode(data, i, uint64(m.Arg.Size()))
-	n14, err := m.Arg.MarshalTo(data[i:])
+	n15, err := m.Arg.MarshalTo(data[i:])
 	if err != nil {
 		return 0, err
 	}
-	i += n14
+	i += n15
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -653,6 +708,14 @@ func (m *FuncNodePb) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	data[i] = 0x18
+	i++
+	if m.Distinct {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -908,6 +971,99 @@ func (m *NullNodePb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
+func (m *CaseNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *CaseNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Operand != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Operand.Size()))
+		n16, err := m.Operand.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if len(m.Whens) > 0 {
+		for _, msg := range m.Whens {
+			data[i] = 0x12
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Thens) > 0 {
+		for _, msg := range m.Thens {
+			data[i] = 0x1a
+			i++
+			i = encodeVarintNode(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Else != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintNode(data, i, uint64(m.Else.Size()))
+		n17, err := m.Else.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *ParamNodePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *ParamNodePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0xa
+	i++
+	i = encodeVarintNode(data, i, uint64(len(m.Text)))
+	i += copy(data[i:], m.Text)
+	data[i] = 0x10
+	i++
+	i = encodeVarintNode(data, i, uint64(m.Index))
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func encodeFixed64Node(data []byte, offset int, v uint64) int {
 	data[offset] = uint8(v)
 	data[offset+1] = uint8(v >> 8)
@@ -997,6 +1153,10 @@ func (m *NodePb) Size() (n int) {
 		l = m.An.Size()
 		n += 1 + l + sovNode(uint64(l))
 	}
+	if m.Cn != nil {
+		l = m.Cn.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
 	if m.Nn != nil {
 		l = m.Nn.Size()
 		n += 1 + l + sovNode(uint64(l))
@@ -1021,6 +1181,10 @@ func (m *NodePb) Size() (n int) {
 		l = m.Niln.Size()
 		n += 1 + l + sovNode(uint64(l))
 	}
+	if m.Pn != nil {
+		l = m.Pn.Size()
+		n += 2 + l + sovNode(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1097,6 +1261,7 @@ func (m *FuncNodePb) Size() (n int) {
 			n += 1 + l + sovNode(uint64(l))
 		}
 	}
+	n += 2
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1207,6 +1372,47 @@ func (m *NullNodePb) Size() (n int) {
 	return n
 }
 
+func (m *CaseNodePb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Operand != nil {
+		l = m.Operand.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if len(m.Whens) > 0 {
+		for _, e := range m.Whens {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if len(m.Thens) > 0 {
+		for _, e := range m.Thens {
+			l = e.Size()
+			n += 1 + l + sovNode(uint64(l))
+		}
+	}
+	if m.Else != nil {
+		l = m.Else.Size()
+		n += 1 + l + sovNode(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ParamNodePb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Text)
+	n += 1 + l + sovNode(uint64(l))
+	n += 1 + sovNode(uint64(m.Index))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovNode(x uint64) (n int) {
 	for {
 		n++
@@ -1669,6 +1875,39 @@ func (m *NodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cn == nil {
+				m.Cn = &CaseNodePb{}
+			}
+			if err := m.Cn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 10:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Nn", wireType)
@@ -1867,6 +2106,39 @@ func (m *NodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pn", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Pn == nil {
+				m.Pn = &ParamNodePb{}
+			}
+			if err := m.Pn.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNode(data[iNdEx:])
@@ -2474,6 +2746,26 @@ func (m *FuncNodePb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Distinct = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipNode(data[iNdEx:])
@@ -3283,6 +3575,284 @@ func (m *NullNodePb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *CaseNodePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CaseNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CaseNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operand", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Operand == nil {
+				m.Operand = &NodePb{}
+			}
+			if err := m.Operand.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Whens", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Whens = append(m.Whens, NodePb{})
+			if err := m.Whens[len(m.Whens)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Thens", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Thens = append(m.Thens, NodePb{})
+			if err := m.Thens[len(m.Thens)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Else", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Else == nil {
+				m.Else = &NodePb{}
+			}
+			if err := m.Else.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ParamNodePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowNode
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ParamNodePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ParamNodePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthNode
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowNode
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Index |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipNode(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthNode
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipNode(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -3391,52 +3961,59 @@ var (
 func init() { proto.RegisterFile("node.proto", fileDescriptorNode) }
 
 var fileDescriptorNode = []byte{
-	// 741 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x54, 0xcf, 0x6f, 0xd3, 0x4a,
-	0x10, 0x8e, 0x1d, 0xa7, 0x4d, 0xc6, 0x69, 0xfb, 0xde, 0xbe, 0xea, 0xc9, 0xea, 0x21, 0xaf, 0xb2,
-	0x1e, 0x50, 0x21, 0x9a, 0x4a, 0x39, 0x70, 0xa7, 0x12, 0x45, 0x3d, 0x10, 0x50, 0xa1, 0xdc, 0xed,
-	0x64, 0x9d, 0xae, 0xe4, 0x8e, 0x83, 0x63, 0x9b, 0xf6, 0xc0, 0x9d, 0x23, 0x47, 0xfe, 0xa4, 0x1c,
-	0xf9, 0x0b, 0x10, 0x3f, 0xfe, 0x11, 0x76, 0x67, 0x6d, 0x67, 0x5d, 0x5a, 0x54, 0xd4, 0x43, 0xa4,
-	0xec, 0xf7, 0x7d, 0x9e, 0xd9, 0x99, 0x6f, 0x76, 0x00, 0x30, 0x99, 0xf2, 0xe1, 0x3c, 0x4d, 0xb2,
-	0x84, 0x39, 0xfc, 0x62, 0x9e, 0xee, 0xec, 0xcf, 0x44, 0x76, 0x96, 0x87, 0xc3, 0x49, 0x72, 0x7e,
-	0x30, 0x4b, 0x66, 0xc9, 0x01, 0x91, 0x61, 0x1e, 0xd1, 0x89, 0x0e, 0xf4, 0x4f, 0x7f, 0xe4, 0x2f,
-	0x2d, 0x58, 0x7b, 0x2a, 0xbf, 0x7b, 0x19, 0xb2, 0x6d, 0xb0, 0x93, 0xb9, 0x67, 0xed, 0x5a, 0x7b,
-	0x9d, 0x43, 0x67, 0xf9, 0xe5, 0x3f, 0xeb, 0x44, 0x9e, 0xd9, 0x7d, 0x70, 0x82, 0x74, 0xb6, 0xf0,
-	0xec, 0xdd, 0xf6, 0x9e, 0x3b, 0xea, 0x0f, 0x55, 0x92, 0xa1, 0xfe, 0xa2, 0x54, 0x11, 0xcf, 0x76,
-	0xa0, 0x23, 0xa6, 0x1c, 0x33, 0xcf, 0x91, 0x01, 0x7a, 0x25, 0xa5, 0x21, 0xf6, 0x2f, 0xb4, 0x8b,
-	0x20, 0xf6, 0x3a, 0x06, 0xa3, 0x00, 0xe6, 0x81, 0x23, 0x14, 0xb1, 0x26, 0x89, 0x76, 0x15, 0x4d,
-	0x94, 0x4c, 0xa8, 0x98, 0x75, 0xc9, 0x74, 0x2b, 0x26, 0x2c, 0x99, 0x48, 0x31, 0x5d, 0xc9, 0x58,
-	0x15, 0xa3, 0x10, 0xff, 0x83, 0x03, 0x6b, 0x63, 0xd9, 0x0e, 0x59, 0xca, 0x1e, 0xd8, 0x21, 0x52,
-	0x29, 0xee, 0x88, 0xe9, 0x2b, 0x1f, 0x0a, 0x0c, 0xd2, 0x4b, 0xcd, 0x57, 0xe5, 0x85, 0xc8, 0x0e,
-	0xa0, 0x13, 0x26, 0x49, 0x8c, 0xb2, 0x3e, 0x25, 0xfe, 0xa7, 0x14, 0x4b, 0x88, 0x07, 0xd8, 0x50,
-	0x6b, 0x1d, 0x7b, 0x00, 0x76, 0x8e, 0x5e, 0x9b, 0xd4, 0x7f, 0x6b, 0xf5, 0xe9, 0xaf, 0x91, 0x73,
-	0x94, 0x8d, 0xb3, 0x23, 0xa4, 0x6e, 0xb8, 0xa3, 0xbf, 0xb4, 0xf0, 0x28, 0xc7, 0x49, 0x53, 0x17,
-	0x21, 0xbb, 0x07, 0x76, 0x86, 0xd4, 0x1b, 0x77, 0xb4, 0xa5, 0x75, 0xaf, 0x53, 0xd1, 0x94, 0x65,
-	0x94, 0x37, 0x40, 0xea, 0x54, 0x9d, 0xf7, 0x49, 0x9a, 0x06, 0x57, 0xf2, 0x06, 0xa8, 0x6a, 0x47,
-	0xf4, 0xc0, 0xac, 0x7d, 0x9c, 0x9f, 0x87, 0x3c, 0x6d, 0x2a, 0x91, 0x42, 0x16, 0xe8, 0xb9, 0x66,
-	0xc8, 0x37, 0x41, 0x9c, 0xf3, 0xa6, 0xb0, 0x40, 0xf6, 0x10, 0x6c, 0x81, 0x5e, 0x9f, 0x84, 0xdb,
-	0x5a, 0x78, 0xac, 0x8c, 0x15, 0xd9, 0x95, 0xf4, 0x82, 0xd2, 0x2f, 0xd0, 0xdb, 0x30, 0xd3, 0xbf,
-	0xca, 0x52, 0x81, 0xb3, 0xa6, 0x72, 0x81, 0x6c, 0x5f, 0xba, 0x8f, 0x13, 0xf4, 0x36, 0xcd, 0xce,
-	0x1f, 0xe3, 0x24, 0xce, 0xa7, 0xcd, 0x2b, 0x90, 0x4c, 0x5e, 0xc2, 0x41, 0x21, 0x8d, 0xda, 0x32,
-	0x3b, 0x3a, 0xce, 0xe3, 0xb8, 0xa9, 0x55, 0x1a, 0xff, 0x0c, 0xfa, 0xa6, 0xdf, 0xf5, 0x68, 0xdb,
-	0xe5, 0x68, 0xb7, 0x68, 0xb4, 0xe5, 0xc8, 0xce, 0x83, 0x94, 0x6b, 0xef, 0xbb, 0x25, 0xa1, 0xa1,
-	0x7a, 0xec, 0xdb, 0xe6, 0xd8, 0x1b, 0x99, 0x5a, 0x7a, 0xec, 0xfd, 0xe7, 0xb0, 0xd1, 0x18, 0x96,
-	0x1b, 0x52, 0x5d, 0xfb, 0x8a, 0xae, 0x09, 0xf7, 0x1e, 0x36, 0x1a, 0x1d, 0xb8, 0x21, 0xdc, 0x00,
-	0xd6, 0x91, 0xcf, 0x82, 0x8c, 0x4f, 0x65, 0x44, 0xbb, 0xbe, 0x7b, 0x05, 0xb2, 0xc7, 0xd0, 0x15,
-	0xa5, 0x41, 0xb2, 0x02, 0xfb, 0xb7, 0xb6, 0xb5, 0x4e, 0x6a, 0xad, 0xcf, 0xc1, 0x3d, 0xbd, 0x53,
-	0xdb, 0xfe, 0x87, 0xb6, 0xac, 0xa3, 0xcc, 0x79, 0x5d, 0x99, 0x8a, 0xf6, 0xc7, 0x00, 0xab, 0xa7,
-	0xa0, 0x5e, 0x34, 0x06, 0xe7, 0x9c, 0xf2, 0xf4, 0xaa, 0x6e, 0x28, 0xe4, 0xd6, 0x5d, 0x3b, 0x86,
-	0x5e, 0xfd, 0x64, 0xee, 0x68, 0xc0, 0x0b, 0x70, 0x8d, 0x67, 0xa5, 0xee, 0xf6, 0x2e, 0x0d, 0xcc,
-	0x70, 0x72, 0xc4, 0x14, 0x72, 0xeb, 0x01, 0x99, 0x42, 0xdf, 0x9c, 0x7f, 0xb2, 0x2e, 0x79, 0x9b,
-	0x27, 0x19, 0xa7, 0xfd, 0x54, 0x2d, 0xb7, 0x0a, 0x54, 0xdd, 0xd5, 0xac, 0x6d, 0x2c, 0x62, 0x0d,
-	0xa9, 0xdb, 0x64, 0xfc, 0x22, 0xa3, 0xed, 0x53, 0x77, 0x4a, 0x21, 0xfe, 0x11, 0x6c, 0x36, 0xad,
-	0x5d, 0xc5, 0xb1, 0xfe, 0x24, 0xce, 0x47, 0x0b, 0xfa, 0xe6, 0xb6, 0xa0, 0xb5, 0xbe, 0x10, 0x72,
-	0xad, 0x5b, 0xa6, 0xd9, 0x04, 0xa9, 0x52, 0xc4, 0x22, 0x8a, 0x93, 0x20, 0x6b, 0x8c, 0x42, 0x05,
-	0x2a, 0x27, 0x44, 0x41, 0xb3, 0xd0, 0xae, 0x9c, 0x10, 0x85, 0x42, 0xa3, 0x42, 0xee, 0x45, 0xbb,
-	0x5c, 0xdf, 0x12, 0x8d, 0x8a, 0xfa, 0x4a, 0x1d, 0x73, 0x08, 0xe8, 0x4a, 0xcf, 0xc0, 0x35, 0xb6,
-	0x12, 0xf3, 0xa1, 0x57, 0xa8, 0x63, 0x76, 0x39, 0xe7, 0x0d, 0x97, 0x57, 0xb0, 0x4c, 0xd1, 0xa1,
-	0x03, 0x3d, 0x8e, 0xfe, 0x89, 0x3e, 0xf8, 0x8f, 0x00, 0x56, 0xeb, 0x82, 0x7c, 0x10, 0x71, 0x19,
-	0xc5, 0xaa, 0xa3, 0x54, 0xe0, 0xe1, 0xf6, 0xf2, 0xdb, 0xa0, 0xb5, 0xfc, 0x3e, 0xb0, 0x3e, 0xcb,
-	0xdf, 0x57, 0xf9, 0xfb, 0xf4, 0x63, 0xd0, 0xfa, 0x19, 0x00, 0x00, 0xff, 0xff, 0xe8, 0xbc, 0x7b,
-	0x5f, 0x70, 0x07, 0x00, 0x00,
+	// 858 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x4f, 0x6f, 0xdb, 0x36,
+	0x14, 0x37, 0x25, 0x39, 0x71, 0x9e, 0x9c, 0x36, 0xe3, 0x82, 0x81, 0xc8, 0xc1, 0x13, 0x84, 0xad,
+	0x33, 0x8a, 0x36, 0x01, 0x72, 0xd8, 0x7d, 0x29, 0xd6, 0x21, 0x87, 0x65, 0x81, 0xb6, 0xee, 0x4e,
+	0x59, 0xb4, 0x43, 0x40, 0x79, 0xd4, 0x64, 0x49, 0x4d, 0x0f, 0xfb, 0x0e, 0xfb, 0x34, 0xfb, 0x0c,
+	0x39, 0xee, 0xba, 0xcb, 0xb0, 0xe5, 0x93, 0x0c, 0x24, 0x25, 0x99, 0x4a, 0x9d, 0xa2, 0x45, 0x6e,
+	0xe6, 0xef, 0xf7, 0xf3, 0xfb, 0x3d, 0xbe, 0x3f, 0x14, 0x00, 0xaa, 0x4c, 0x1c, 0x17, 0xa5, 0xaa,
+	0x14, 0x0d, 0xc4, 0x4d, 0x51, 0x1e, 0xbd, 0x5c, 0xc9, 0xea, 0xaa, 0x4e, 0x8f, 0x17, 0xea, 0xfa,
+	0x64, 0xa5, 0x56, 0xea, 0xc4, 0x90, 0x69, 0xbd, 0x34, 0x27, 0x73, 0x30, 0xbf, 0xec, 0x9f, 0xe2,
+	0x5b, 0x02, 0x3b, 0xdf, 0xdf, 0x14, 0xe5, 0x65, 0x4a, 0x0f, 0xc1, 0x53, 0x05, 0x23, 0x11, 0x99,
+	0x8f, 0xcf, 0x82, 0xdb, 0x7f, 0xbe, 0x24, 0x89, 0xa7, 0x0a, 0xfa, 0x0c, 0x02, 0x5e, 0xae, 0xd6,
+	0xcc, 0x8b, 0xfc, 0x79, 0x78, 0x3a, 0x3d, 0xd6, 0x26, 0xc7, 0xf6, 0x1f, 0xad, 0xca, 0xf0, 0xf4,
+	0x08, 0xc6, 0x32, 0x13, 0x58, 0xb1, 0x20, 0x22, 0xf3, 0xbd, 0x96, 0xb2, 0x10, 0xfd, 0x02, 0xfc,
+	0x86, 0xe7, 0x6c, 0xec, 0x30, 0x1a, 0xa0, 0x0c, 0x02, 0xa9, 0x89, 0x9d, 0x88, 0xcc, 0xfd, 0x2e,
+	0x9a, 0x6c, 0x99, 0x54, 0x33, 0xbb, 0x11, 0x99, 0x4f, 0x3a, 0x26, 0x6d, 0x99, 0xa5, 0x66, 0x26,
+	0x11, 0x99, 0x93, 0x8e, 0xd1, 0x48, 0xfc, 0x77, 0x00, 0x3b, 0x17, 0x2a, 0x13, 0x97, 0x29, 0x9d,
+	0x83, 0x97, 0xa2, 0xb9, 0x4a, 0x78, 0x4a, 0x6d, 0xca, 0x67, 0x12, 0x79, 0xf9, 0xce, 0xf2, 0xdd,
+	0xf5, 0x52, 0xa4, 0x27, 0x30, 0x4e, 0x95, 0xca, 0x91, 0x79, 0x46, 0xfc, 0x79, 0x2b, 0x56, 0x2a,
+	0x17, 0x1c, 0x07, 0x6a, 0xab, 0xa3, 0xdf, 0x80, 0x57, 0x23, 0xf3, 0x8d, 0xfa, 0x33, 0xab, 0x7e,
+	0xf3, 0x7e, 0xe4, 0x1a, 0xe9, 0x33, 0xf0, 0x96, 0x68, 0xaa, 0x11, 0x9e, 0x1e, 0x58, 0xe1, 0xeb,
+	0x1a, 0x17, 0x43, 0xdd, 0x12, 0xe9, 0xd7, 0xe0, 0x55, 0x68, 0x6a, 0x13, 0x9e, 0x3e, 0xb5, 0xba,
+	0x5f, 0x4a, 0x39, 0x94, 0x55, 0xc6, 0x97, 0x23, 0xdb, 0x71, 0x7d, 0xbf, 0x2b, 0x4b, 0x7e, 0xcf,
+	0x97, 0x1b, 0xdf, 0x05, 0xb2, 0x5d, 0xd7, 0xf7, 0x15, 0x5f, 0x8b, 0xa1, 0x6e, 0x81, 0xba, 0x46,
+	0x88, 0x0c, 0xdc, 0x1a, 0x5d, 0xd4, 0xd7, 0xa9, 0x28, 0x87, 0x4a, 0x34, 0xd6, 0x0d, 0xb2, 0xd0,
+	0xb5, 0xfe, 0x95, 0xe7, 0xf5, 0xbd, 0x90, 0x0d, 0xd2, 0xe7, 0xe0, 0x49, 0x64, 0x53, 0x23, 0x3c,
+	0xb4, 0xc2, 0x73, 0x3d, 0x00, 0xb2, 0xba, 0x97, 0xa6, 0x34, 0xf6, 0x6b, 0x64, 0xfb, 0xae, 0xfd,
+	0xcf, 0x55, 0x29, 0x71, 0x35, 0x54, 0xae, 0x91, 0xbe, 0x84, 0x40, 0xe2, 0x02, 0xd9, 0x13, 0xb7,
+	0x43, 0xe7, 0xb8, 0xc8, 0xeb, 0x6c, 0x98, 0x82, 0x91, 0xd1, 0xe7, 0x10, 0xa0, 0xcc, 0x91, 0x3d,
+	0x75, 0x2b, 0x70, 0x51, 0xe7, 0xf9, 0x50, 0xab, 0x35, 0xfa, 0x66, 0x05, 0xb2, 0x03, 0xf7, 0x66,
+	0x97, 0xbc, 0xe4, 0xd7, 0xc3, 0x1c, 0x0a, 0x8c, 0xaf, 0x60, 0xea, 0x0e, 0x50, 0xbf, 0x2b, 0x5e,
+	0xbb, 0x2b, 0x23, 0xb3, 0x2b, 0x47, 0x30, 0x2e, 0x78, 0x29, 0xec, 0x30, 0x4d, 0x5a, 0xc2, 0x42,
+	0xfd, 0x1e, 0xf9, 0xee, 0x1e, 0x39, 0x3e, 0x23, 0xbb, 0x47, 0xf1, 0x8f, 0xb0, 0x3f, 0x98, 0xbe,
+	0x07, 0xac, 0xb6, 0xae, 0xe5, 0x96, 0x70, 0xbf, 0xc3, 0xfe, 0xa0, 0x54, 0x0f, 0x84, 0x9b, 0xc1,
+	0x2e, 0x8a, 0x15, 0xaf, 0x44, 0xc6, 0xbc, 0xc8, 0xeb, 0x73, 0xef, 0x40, 0xfa, 0x2d, 0x4c, 0x64,
+	0xdb, 0x49, 0xe6, 0x47, 0xde, 0x07, 0xfb, 0x3b, 0x4a, 0x7a, 0x6d, 0x2c, 0x20, 0x7c, 0xf3, 0xa8,
+	0xb2, 0x7d, 0x05, 0x3e, 0x2f, 0x57, 0xad, 0xe7, 0xb6, 0x6b, 0x6a, 0x3a, 0x2e, 0x00, 0x36, 0xbb,
+	0xa5, 0x9f, 0x08, 0xe4, 0xd7, 0xc2, 0xf8, 0xec, 0x75, 0xd5, 0xd0, 0xc8, 0xc7, 0x56, 0x8d, 0x46,
+	0x30, 0xc9, 0xe4, 0xba, 0x92, 0xb8, 0xa8, 0x98, 0xef, 0x24, 0xd5, 0xa3, 0xf1, 0x39, 0xec, 0xf5,
+	0x5b, 0xfa, 0xc8, 0x16, 0xfd, 0x04, 0xa1, 0xb3, 0xc9, 0x3a, 0xfb, 0xb7, 0x25, 0x77, 0xc3, 0x91,
+	0xc4, 0x20, 0x1f, 0x3d, 0x42, 0x19, 0x4c, 0xdd, 0x55, 0x32, 0xcd, 0x55, 0xbf, 0xd5, 0xaa, 0x12,
+	0x8c, 0xf4, 0x97, 0x21, 0x49, 0x07, 0xea, 0xfa, 0x5b, 0xd6, 0x73, 0xde, 0x7e, 0x0b, 0xe9, 0x6c,
+	0x2a, 0x71, 0x63, 0xab, 0xd0, 0xd7, 0x52, 0x23, 0xf1, 0x6b, 0x78, 0x32, 0x6c, 0xfe, 0x26, 0x0e,
+	0xf9, 0x94, 0x38, 0x7f, 0x10, 0x98, 0xba, 0x0f, 0x8f, 0xf9, 0x92, 0xac, 0x25, 0x56, 0x4e, 0xb2,
+	0xa3, 0xc4, 0x42, 0xfa, 0x2a, 0x72, 0xbd, 0xcc, 0x15, 0xaf, 0x06, 0xc3, 0xd2, 0x81, 0xba, 0x13,
+	0xb2, 0x31, 0xd3, 0xe2, 0x77, 0x9d, 0x90, 0x8d, 0x46, 0x97, 0x0d, 0x0b, 0x22, 0xaf, 0xfd, 0x62,
+	0x8c, 0x12, 0x6f, 0xd9, 0xf4, 0x29, 0x8d, 0xdd, 0x31, 0x31, 0x29, 0xfd, 0x00, 0xa1, 0xf3, 0xc0,
+	0xd1, 0x18, 0xf6, 0x1a, 0x7d, 0xac, 0xde, 0x15, 0x62, 0xd0, 0xe5, 0x0d, 0x4c, 0x0f, 0x61, 0x6c,
+	0x0e, 0x66, 0x7d, 0xa6, 0x89, 0x3d, 0xc4, 0x2f, 0x00, 0x36, 0x2f, 0x8f, 0xe9, 0x83, 0xcc, 0xdb,
+	0x28, 0xa4, 0x8f, 0xd2, 0x81, 0xf1, 0x9f, 0x04, 0x60, 0xf3, 0x54, 0xd3, 0x17, 0xb0, 0xab, 0x0a,
+	0x51, 0x72, 0xcc, 0xda, 0x2f, 0xd9, 0xfb, 0x1d, 0x27, 0x49, 0x27, 0xa1, 0x73, 0x18, 0xbf, 0xbd,
+	0x12, 0xf8, 0xa1, 0x71, 0xb3, 0x02, 0xad, 0xac, 0x8c, 0xf2, 0xe1, 0x39, 0xb2, 0x02, 0x3d, 0x70,
+	0x22, 0x5f, 0x8b, 0xf6, 0x23, 0xb6, 0xcd, 0xde, 0xf0, 0xf1, 0x2b, 0x08, 0x9d, 0x67, 0xb3, 0x2f,
+	0x2c, 0xb9, 0xdf, 0x6b, 0xd3, 0x5a, 0xcc, 0xc4, 0x8d, 0x33, 0x69, 0xa3, 0xc4, 0x42, 0x67, 0x07,
+	0xb7, 0x77, 0x33, 0xf2, 0xef, 0xdd, 0x8c, 0xfc, 0x75, 0x37, 0x23, 0xb7, 0xff, 0xcd, 0x46, 0xff,
+	0x0f, 0x00, 0x14, 0x18, 0x9f, 0xb5, 0xdd, 0x08, 0x00, 0x00,
 }
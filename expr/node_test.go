@@ -22,6 +22,11 @@ var pbTests = []string{
 	`AND ( EXISTS x, INCLUDE ref_name )`,
 	`company = "Toys R"" Us"`,
 	`providers.id != NULL`,
+	`providers.id IS NULL`,
+	`CASE WHEN x > 5 THEN "big" ELSE "small" END`,
+	`CASE status WHEN "a" THEN 1 WHEN "b" THEN 2 END`,
+	`name = ? AND age > $2 AND email = :email`,
+	`count(DISTINCT x) > 5`,
 }
 
 func TestNodePb(t *testing.T) {
@@ -0,0 +1,143 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	// Ensure our pager implements ParamPager
+	_ ParamPager = (*LexTokenPager)(nil)
+)
+
+// ParamPager is a TokenPager that numbers the ? placeholders of a statement
+// in the order they are found, and may have values bound to them.
+type ParamPager interface {
+	TokenPager
+	// Param the placeholder node of the placeholder token.
+	Param(tok lex.Token) (*ParamNode, error)
+	// Bound the values bound to the placeholders, nil if unbound.
+	Bound() *Params
+}
+
+// Params are the values bound to the placeholders of a statement, ? and $N
+// placeholders by position and :name placeholders by name.
+type Params struct {
+	Args  []value.Value
+	Named map[string]value.Value
+}
+
+// NewParams create a binding of args by position and named by name.
+func NewParams(args []value.Value, named map[string]value.Value) *Params {
+	return &Params{Args: args, Named: named}
+}
+
+// Value the value bound to placeholder n.
+func (m *Params) Value(n *ParamNode) (value.Value, error) {
+	var v value.Value
+	if n.Name != "" {
+		bv, ok := m.Named[n.Name]
+		if !ok {
+			return nil, fmt.Errorf("no value bound for parameter %s", n.Text)
+		}
+		v = bv
+	} else {
+		if n.Index < 1 || n.Index > len(m.Args) {
+			return nil, fmt.Errorf("no value bound for parameter %s at position %d", n.Text, n.Index)
+		}
+		v = m.Args[n.Index-1]
+	}
+	if v == nil {
+		return value.NilValueVal, nil
+	}
+	return v, nil
+}
+
+// Node the literal node of the value bound to placeholder n, so the bound
+// statement is evaluated, and written for a source, as if it had been
+// written with the value.
+func (m *Params) Node(n *ParamNode) (Node, error) {
+	v, err := m.Value(n)
+	if err != nil {
+		return nil, err
+	}
+	if v.Nil() {
+		return &NullNode{}, nil
+	}
+	switch vt := v.(type) {
+	case value.StringValue:
+		return NewStringNode(vt.Val()), nil
+	case value.ByteSliceValue:
+		return NewStringNode(string(vt.Val())), nil
+	case value.IntValue:
+		return NewNumberStr(strconv.FormatInt(vt.Val(), 10))
+	case value.NumberValue:
+		return NewNumberStr(strconv.FormatFloat(vt.Val(), 'f', -1, 64))
+	case value.BoolValue:
+		return NewIdentityNodeVal(strconv.FormatBool(vt.Val())), nil
+	case value.TimeValue:
+		return NewStringNode(vt.Val().Format(time.RFC3339Nano)), nil
+	case value.StringsValue, value.SliceValue:
+		return NewValueNode(v), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T for parameter %s", v, n.Text)
+}
+
+// Bind the values of params to the placeholders parsed by this pager,
+// nil leaves them unbound.
+func (m *LexTokenPager) Bind(params *Params) {
+	m.bound = params
+}
+
+// Bound the values bound to the placeholders, nil if unbound.
+func (m *LexTokenPager) Bound() *Params {
+	return m.bound
+}
+
+// Params the placeholders found by this pager, in the order found.
+func (m *LexTokenPager) Params() []*ParamNode {
+	return m.params
+}
+
+// Param the node for placeholder tok, a ? placeholder is numbered by its
+// position among the ? placeholders of the statement.
+func (m *LexTokenPager) Param(tok lex.Token) (*ParamNode, error) {
+	n, err := NewParamNode(tok.V)
+	if err != nil {
+		return nil, err
+	}
+	if n.Text == "?" {
+		// the parser may backup and parse a token again, the same
+		// placeholder keeps its position
+		if m.positional == nil {
+			m.positional = make(map[int]int)
+		}
+		idx, ok := m.positional[tok.Pos]
+		if !ok {
+			idx = len(m.positional) + 1
+			m.positional[tok.Pos] = idx
+		}
+		n.Index = idx
+	}
+	m.params = append(m.params, n)
+	return n, nil
+}
+
+// ParamCount the number of values to bind to params, the positional ones
+// followed by one for each distinct name.
+func ParamCount(params []*ParamNode) int {
+	positional := 0
+	names := make(map[string]struct{})
+	for _, p := range params {
+		if p.Name != "" {
+			names[p.Name] = struct{}{}
+		} else if p.Index > positional {
+			positional = p.Index
+		}
+	}
+	return positional + len(names)
+}
@@ -0,0 +1,48 @@
+package expr_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/value"
+)
+
+func TestParseParams(t *testing.T) {
+	t.Parallel()
+	parse := func(exprText string, params *expr.Params) (expr.Node, *expr.LexTokenPager, error) {
+		pager := expr.NewLexTokenPager(lex.NewLexer(exprText, lex.LogicalExpressionDialect))
+		pager.Bind(params)
+		n, err := expr.ParsePager(pager)
+		return n, pager, err
+	}
+
+	// unbound the placeholders are numbered, ? by position
+	n, pager, err := parse(`a = ? AND b = $3 AND c = ? AND d = :name AND e = :name`, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `a = ? AND b = $3 AND c = ? AND d = :name AND e = :name`, n.String())
+	params := pager.Params()
+	assert.Equal(t, 5, len(params))
+	assert.Equal(t, 1, params[0].Index)
+	assert.Equal(t, 3, params[1].Index)
+	assert.Equal(t, 2, params[2].Index)
+	assert.Equal(t, "name", params[3].Name)
+	assert.Equal(t, 4, expr.ParamCount(params))
+
+	// bound they are replaced by the value bound to them, a quote in a
+	// value is only ever a value
+	n, _, err = parse(`a = ? AND b = $2 AND c = :name`, expr.NewParams(
+		[]value.Value{value.NewStringValue(`bob's "x"`), value.NewIntValue(5)},
+		map[string]value.Value{"name": value.NilValueVal},
+	))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `a = "bob's ""x""" AND b = 5 AND c = NULL`, n.String())
+
+	// a placeholder with no value bound is an error
+	_, _, err = parse(`a = ? AND b = ?`, expr.NewParams([]value.Value{value.NewIntValue(5)}, nil))
+	assert.NotEqual(t, nil, err)
+	_, _, err = parse(`a = :name`, expr.NewParams(nil, nil))
+	assert.NotEqual(t, nil, err)
+}
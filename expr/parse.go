@@ -48,6 +48,15 @@ type TokenPager interface {
 	ErrMsg(msg string) error
 }
 
+// SubQueryPager is a TokenPager that can parse a sub-query used as an
+// expression, ie the SQL parser.  Current token is the left paren.
+//
+//    x IN (SELECT ...)
+type SubQueryPager interface {
+	TokenPager
+	ParseSubQuery() (Node, error)
+}
+
 // SchemaInfo is interface for a Column type
 type SchemaInfo interface {
 	Key() string
@@ -61,10 +70,13 @@ func (m SchemaInfoString) Key() string { return string(m) }
 // TokenPager is responsible for determining end of
 // current tree (column, etc)
 type LexTokenPager struct {
-	done   bool
-	tokens []lex.Token // list of all the tokens
-	cursor int
-	lex    *lex.Lexer
+	done       bool
+	tokens     []lex.Token // list of all the tokens
+	cursor     int
+	lex        *lex.Lexer
+	params     []*ParamNode // placeholders found
+	positional map[int]int  // position of each ? placeholder, by token pos
+	bound      *Params      // values bound to the placeholders, if any
 }
 
 func NewLexTokenPager(lex *lex.Lexer) *LexTokenPager {
@@ -286,7 +298,7 @@ https://developer.mozilla.org/en-US/docs/Web/JavaScript/Reference/Operators/Oper
 http://www.postgresql.org/docs/9.4/static/sql-syntax-lexical.html#SQL-PRECEDENCE
 
 TODO:
- - if/else, case, for
+ - if/else, for
  - call stack & vars
 --------------------------------------
 O -> A {( "||" | OR  ) A}
@@ -295,9 +307,11 @@ C -> P {( "==" | "!=" | ">" | ">=" | "<" | "<=" | "LIKE" | "IN" | "CONTAINS" | "
 P -> M {( "+" | "-" ) M}
 M -> F {( "*" | "/" ) F}
 F -> v | "(" O ")" | "!" v | "-" O | "NOT" C | "EXISTS" v | "IS" O | "AND (" O ")" | "OR (" O ")"
-v -> value | Func | "INCLUDE" <identity>
+v -> value | Func | Case | "INCLUDE" <identity>
+Case -> "CASE" [O] "WHEN" O "THEN" O {"WHEN" O "THEN" O} ["ELSE" O] "END"
 Func -> <identity> "(" value {"," value} ")"
-value -> number | "string" | O | <identity>
+value -> number | "string" | O | <identity> | Param
+Param -> "?" | "$" number | ":" <identity>
 
 
 
@@ -380,8 +394,8 @@ func (t *tree) C(depth int) Node {
 				ne := lex.Token{T: lex.TokenNE, V: "!="}
 				return NewBinaryNode(ne, n, t.P(depth+1))
 			}
-			u.Warnf("TokenIS?  is this supported?")
-			return NewUnary(cur, t.cInner(n, depth+1))
+			is := lex.Token{T: lex.TokenIs, V: "IS"}
+			return NewBinaryNode(is, n, t.P(depth+1))
 		default:
 			return t.cInner(n, depth)
 		}
@@ -418,6 +432,9 @@ func (t *tree) cInner(n Node, depth int) Node {
 				}
 				return NewBinaryNode(cur, n, NewValueNode(val))
 			case lex.TokenLeftParenthesis:
+				if t.Peek().T == lex.TokenSelect {
+					return NewBinaryNode(cur, n, t.subQuery())
+				}
 				// This is a special type of Binary? its 2nd argument is a array node
 				return NewBinaryNode(cur, n, t.ArrayNode(depth))
 			case lex.TokenUdfExpr:
@@ -633,7 +650,15 @@ func (t *tree) v(depth int) Node {
 	case lex.TokenUdfExpr:
 		t.Next() // consume Function Name
 		return t.Func(depth, cur)
+	case lex.TokenCase:
+		return t.caseExpr(depth)
+	case lex.TokenPlaceholder:
+		t.Next() // consume placeholder
+		return t.param(cur)
 	case lex.TokenLeftParenthesis:
+		if t.Peek().T == lex.TokenSelect {
+			return t.subQuery()
+		}
 		t.Next() // Consume  (
 		n := t.O(depth + 1)
 		debugf(depth, "v: paren  T:%T  %v   cur:%v", n, n, t.Cur())
@@ -654,6 +679,68 @@ func (t *tree) v(depth int) Node {
 	return nil
 }
 
+// caseExpr parse a CASE expression
+//
+//    CASE [<expr>] WHEN <expr> THEN <expr> [WHEN ...] [ELSE <expr>] END
+func (t *tree) caseExpr(depth int) Node {
+	t.Next() // consume CASE
+	var operand Node
+	if t.Cur().T != lex.TokenWhen {
+		operand = t.O(depth + 1)
+	}
+	n := NewCaseNode(operand)
+	for t.Cur().T == lex.TokenWhen {
+		t.Next() // consume WHEN
+		n.Whens = append(n.Whens, t.O(depth+1))
+		t.expect(lex.TokenThen, "CASE")
+		t.Next() // consume THEN
+		n.Thens = append(n.Thens, t.O(depth+1))
+	}
+	if len(n.Whens) == 0 {
+		t.unexpected(t.Cur(), "CASE expected WHEN but got")
+	}
+	if t.Cur().T == lex.TokenElse {
+		t.Next() // consume ELSE
+		n.Else = t.O(depth + 1)
+	}
+	t.expect(lex.TokenEnd, "CASE")
+	t.Next() // consume END
+	return n
+}
+
+// subQuery parse a (SELECT ...) sub-query, if our pager supports it.
+// param a placeholder, bound to its value if the pager has them.
+func (t *tree) param(tok lex.Token) Node {
+	pp, ok := t.TokenPager.(ParamPager)
+	if !ok {
+		t.unexpected(tok, "placeholder not supported")
+	}
+	n, err := pp.Param(tok)
+	if err != nil {
+		t.error(err)
+	}
+	if b := pp.Bound(); b != nil {
+		bn, err := b.Node(n)
+		if err != nil {
+			t.error(err)
+		}
+		return bn
+	}
+	return n
+}
+
+func (t *tree) subQuery() Node {
+	sp, ok := t.TokenPager.(SubQueryPager)
+	if !ok {
+		t.unexpected(t.Peek(), "sub-query not supported")
+	}
+	n, err := sp.ParseSubQuery()
+	if err != nil {
+		t.error(err)
+	}
+	return n
+}
+
 func (t *tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 	debugf(depth, "Func: tok: %v cur:%v peek:%v", funcTok.V, t.Cur(), t.Peek())
 	if t.Cur().T != lex.TokenLeftParenthesis {
@@ -679,6 +766,18 @@ func (t *tree) Func(depth int, funcTok lex.Token) (fn *FuncNode) {
 	t.expect(lex.TokenLeftParenthesis, "func")
 	t.Next() // Are we sure we consume?
 
+	// count(DISTINCT x), sum(DISTINCT(x)) aggregate the distinct
+	// values of their argument
+	cur, next := t.Cur(), t.Peek().T
+	if cur.Quote == 0 && strings.EqualFold(cur.V, "distinct") && (cur.T == lex.TokenUdfExpr ||
+		cur.T == lex.TokenIdentity && next != lex.TokenComma && next != lex.TokenRightParenthesis) {
+		if ok && !funcImpl.Aggregate {
+			t.errorf("DISTINCT is only allowed in aggregate functions but got %s()", funcTok.V)
+		}
+		fn.Distinct = true
+		t.Next()
+	}
+
 	defer func() {
 		if err := fn.Validate(); err != nil {
 			t.error(err) // will panic
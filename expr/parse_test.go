@@ -129,6 +129,11 @@ var exprTests = []exprTest{
 		`AND ( EXISTS x, EXISTS y )`,
 		true,
 	},
+	{
+		`email is NULL AND name IS NOT NULL`,
+		`email IS NULL AND name != NULL`,
+		true,
+	},
 	{
 		`AND ( EXISTS x, INCLUDE ref_name )`,
 		`AND ( EXISTS x, INCLUDE ref_name )`,
@@ -264,6 +269,21 @@ var exprTests = []exprTest{
 		"\"value\" IN hosts(@@content_whitelist_domains)",
 		true,
 	},
+	{
+		`case when x > 5 then "big" when x IN (1,2) then "small" else "none" end`,
+		`CASE WHEN x > 5 THEN "big" WHEN x IN (1, 2) THEN "small" ELSE "none" END`,
+		true,
+	},
+	{
+		`CASE status WHEN "a" THEN 1 WHEN "b" THEN 2 END == 1`,
+		`CASE status WHEN "a" THEN 1 WHEN "b" THEN 2 END == 1`,
+		true,
+	},
+	{
+		`tostring(CASE WHEN a THEN CASE b WHEN 1 THEN 2 END END)`,
+		`tostring(CASE WHEN a THEN CASE b WHEN 1 THEN 2 END END)`,
+		true,
+	},
 	// Complex nested statements
 	{
 		`and (
@@ -293,7 +313,52 @@ var exprTests = []exprTest{
 		`version == 4 AND (NOT(exists(@@content_whitelist_domains)) OR len(@@content_whitelist_domains) == 0 OR host(url) IN hosts(@@content_whitelist_domains))`,
 		true,
 	},
+	{
+		`name = ? AND age > $2 AND email == :email`,
+		`name = ? AND age > $2 AND email == :email`,
+		true,
+	},
+	{
+		`eq(name, ?) OR name IN (?, :name)`,
+		`eq(name, ?) OR name IN (?, :name)`,
+		true,
+	},
 	// Invalid Statements
+	{
+		`name = $0`, // placeholders are numbered from 1
+		"",
+		false,
+	},
+	{
+		`CASE ELSE 1 END`, // requires a WHEN
+		"",
+		false,
+	},
+	{
+		`count(DISTINCT x) > 5`,
+		`count(DISTINCT x) > 5`,
+		true,
+	},
+	{
+		`sum(DISTINCT(x))`,
+		`sum(DISTINCT x)`,
+		true,
+	},
+	{
+		`count(distinct)`, // a field named distinct
+		`count(distinct)`,
+		true,
+	},
+	{
+		`tolower(DISTINCT x)`, // only aggregates are distinct
+		"",
+		false,
+	},
+	{
+		`CASE WHEN x > 5 THEN 1`, // requires END
+		"",
+		false,
+	},
 	{
 		"`fieldname` INTERSECTS \"hello\"", // Right Side only allows (identity|array|func)
 		"",
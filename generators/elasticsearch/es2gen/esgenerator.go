@@ -93,6 +93,8 @@ func (fg *FilterGenerator) walkExpr(node expr.Node, depth int) (interface{}, err
 		filter, err = fg.walkExpr(n.ExprNode, depth+1)
 	case *expr.FuncNode:
 		filter, err = fg.funcExpr(n, depth+1)
+	case *expr.CaseNode:
+		filter, err = fg.caseExpr(n, depth+1)
 	default:
 		gou.Warnf("not handled %v", node)
 		return nil, fmt.Errorf("qlindex: unsupported node in expression: %T (%s)", node, node)
@@ -186,6 +188,61 @@ func (fg *FilterGenerator) booleanExpr(bn *expr.BooleanNode, depth int) (interfa
 	return bf, nil
 }
 
+// caseExpr rewrites a CASE used as a filter into the equivalent boolean
+// expression, each THEN only applies when its WHEN matched and none of
+// the earlier ones did:
+//
+//    CASE WHEN a THEN x WHEN b THEN y ELSE z END
+//      =>  (a AND x) OR (NOT a AND b AND y) OR (NOT a AND NOT b AND z)
+//
+// THEN/ELSE must be boolean, a missing ELSE is NULL which matches nothing.
+func (fg *FilterGenerator) caseExpr(node *expr.CaseNode, depth int) (interface{}, error) {
+	if err := node.Validate(); err != nil {
+		return nil, err
+	}
+	and := lex.Token{T: lex.TokenLogicAnd, V: "AND"}
+	not := lex.Token{T: lex.TokenNegate, V: "NOT"}
+
+	branches := make([]expr.Node, 0, len(node.Whens)+1)
+	prior := make([]expr.Node, 0, len(node.Whens))
+	branch := func(then expr.Node, conds ...expr.Node) error {
+		if in, ok := then.(*expr.IdentityNode); ok && in.IsBooleanIdentity() {
+			if !in.Bool() {
+				return nil
+			}
+			then = nil
+		}
+		switch then.(type) {
+		case nil:
+		case *expr.StringNode, *expr.NumberNode, *expr.NullNode:
+			return fmt.Errorf("qlindex: CASE in a filter must return a boolean but found %s", then)
+		default:
+			conds = append(conds, then)
+		}
+		branches = append(branches, expr.NewBooleanNode(and, conds...))
+		return nil
+	}
+	for i, when := range node.Whens {
+		if node.Operand != nil {
+			when = expr.NewBinaryNode(lex.Token{T: lex.TokenEqualEqual, V: "=="}, node.Operand, when)
+		}
+		conds := append(append([]expr.Node{}, prior...), when)
+		if err := branch(node.Thens[i], conds...); err != nil {
+			return nil, err
+		}
+		prior = append(prior, expr.NewUnary(not, when))
+	}
+	if node.Else != nil {
+		if err := branch(node.Else, prior...); err != nil {
+			return nil, err
+		}
+	}
+	if len(branches) == 0 {
+		return MatchNone, nil
+	}
+	return fg.booleanExpr(expr.NewBooleanNode(lex.Token{T: lex.TokenLogicOr, V: "OR"}, branches...), depth+1)
+}
+
 func (fg *FilterGenerator) binaryExpr(node *expr.BinaryNode, depth int) (interface{}, error) {
 	// Type check binary expression arguments as they must be:
 	// Identifier-Operator-Literal
@@ -1 +1,85 @@
 package esgen_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/generators/elasticsearch/esgen"
+	"github.com/araddon/qlbridge/generators/elasticsearch/gentypes"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+type testSchema map[string]value.ValueType
+
+func (m testSchema) Column(col string) (value.ValueType, bool) {
+	vt, ok := m[col]
+	return vt, ok
+}
+func (m testSchema) ColumnInfo(col string) (*gentypes.FieldType, bool) {
+	vt, ok := m[col]
+	if !ok {
+		return nil, false
+	}
+	return &gentypes.FieldType{Field: col, Type: vt}, true
+}
+
+var testCols = testSchema{
+	"age":    value.IntType,
+	"status": value.StringType,
+	"vip":    value.BoolType,
+}
+
+func filterJson(t *testing.T, ql string) (string, error) {
+	fs, err := rel.ParseFilterQL(ql)
+	assert.Equal(t, nil, err, ql)
+	fg := esgen.NewGenerator(time.Now(), nil, testCols)
+	payload, err := fg.Walk(fs)
+	if err != nil {
+		return "", err
+	}
+	by, err := json.Marshal(payload.Filter)
+	assert.Equal(t, nil, err)
+	return string(by), nil
+}
+
+func TestCaseFilter(t *testing.T) {
+	tests := []struct {
+		ql, same string
+	}{
+		{
+			`FILTER CASE WHEN age > 30 THEN vip == true ELSE status == "a" END`,
+			`FILTER OR ( AND (age > 30, vip == true), AND (NOT age > 30, status == "a"))`,
+		},
+		{
+			`FILTER CASE WHEN age > 30 THEN true WHEN age > 20 THEN false ELSE status == "a" END`,
+			`FILTER OR (age > 30, AND (NOT age > 30, NOT age > 20, status == "a"))`,
+		},
+		{
+			`FILTER CASE status WHEN "a" THEN age > 30 WHEN "b" THEN true END`,
+			`FILTER OR ( AND (status == "a", age > 30), AND (NOT status == "a", status == "b"))`,
+		},
+		{
+			`FILTER CASE WHEN age > 30 THEN false END`,
+			`FILTER NOT match_all`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := filterJson(t, tt.ql)
+		assert.Equal(t, nil, err, tt.ql)
+		want, err := filterJson(t, tt.same)
+		assert.Equal(t, nil, err, tt.same)
+		assert.Equal(t, want, got, tt.ql)
+	}
+
+	for _, ql := range []string{
+		`FILTER CASE WHEN age > 30 THEN "old" ELSE "young" END`,
+		`FILTER CASE WHEN age > 30 THEN 1 END`,
+	} {
+		_, err := filterJson(t, ql)
+		assert.NotEqual(t, nil, err, ql)
+	}
+}
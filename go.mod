@@ -22,5 +22,6 @@ require (
 	github.com/pborman/uuid v1.2.0
 	github.com/stretchr/testify v1.4.0
 	golang.org/x/net v0.0.0-20191021144547-ec77196f6094
+	golang.org/x/text v0.3.2
 	google.golang.org/api v0.11.0
 )
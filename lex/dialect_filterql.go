@@ -9,6 +9,7 @@ var (
 	FilterStatement = []*Clause{
 		{Token: TokenFilter, Lexer: LexFilterClause, Optional: true},
 		{Token: TokenFrom, Lexer: LexIdentifier, Optional: true},
+		{Token: TokenOrderBy, Lexer: LexOrderByColumn, Optional: true},
 		{Token: TokenLimit, Lexer: LexNumber, Optional: true},
 		{Token: TokenWith, Lexer: LexJsonOrKeyValue, Optional: true},
 		{Token: TokenAlias, Lexer: LexIdentifier, Optional: true},
@@ -20,6 +21,7 @@ var (
 		{Token: TokenFrom, Lexer: LexIdentifier, Optional: false},
 		{Token: TokenWhere, Lexer: LexConditionalClause, Optional: true},
 		{Token: TokenFilter, Lexer: LexFilterClause, Optional: true},
+		{Token: TokenOrderBy, Lexer: LexOrderByColumn, Optional: true},
 		{Token: TokenLimit, Lexer: LexNumber, Optional: true},
 		{Token: TokenWith, Lexer: LexJsonOrKeyValue, Optional: true},
 		{Token: TokenAlias, Lexer: LexIdentifier, Optional: true},
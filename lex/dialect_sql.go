@@ -11,6 +11,7 @@ var (
 	// SqlDialect is a SQL dialect
 	//
 	//    SELECT
+	//    WITH
 	//    UPDATE
 	//    INSERT
 	//    UPSERT
@@ -31,6 +32,7 @@ var (
 		Statements: []*Clause{
 			{Token: TokenPrepare, Clauses: SqlPrepare},
 			{Token: TokenSelect, Clauses: SqlSelect},
+			{Token: TokenWith, Clauses: SqlWith},
 			{Token: TokenUpdate, Clauses: SqlUpdate},
 			{Token: TokenUpsert, Clauses: SqlUpsert},
 			{Token: TokenInsert, Clauses: SqlInsert},
@@ -46,6 +48,7 @@ var (
 			{Token: TokenUse, Clauses: SqlUse},
 			{Token: TokenRollback, Clauses: SqlRollback},
 			{Token: TokenCommit, Clauses: SqlCommit},
+			{Token: TokenAnalyze, Clauses: SqlAnalyze},
 		},
 	}
 	// SqlSelect Select statement.
@@ -57,6 +60,9 @@ var (
 		{Token: TokenWhere, Lexer: LexConditionalClause, Optional: true, Clauses: whereQuery, Name: "sqlSelect.where"},
 		{Token: TokenGroupBy, Lexer: LexColumns, Optional: true, Name: "sqlSelect.groupby"},
 		{Token: TokenHaving, Lexer: LexConditionalClause, Optional: true, Name: "sqlSelect.having"},
+		{Token: TokenUnion, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.union"},
+		{Token: TokenIntersect, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.intersect"},
+		{Token: TokenExcept, Lexer: LexSetOperation, Optional: true, Name: "sqlSelect.except"},
 		{Token: TokenOrderBy, Lexer: LexOrderByColumn, Optional: true, Name: "sqlSelect.orderby"},
 		{Token: TokenLimit, Lexer: LexLimit, Optional: true, Name: "sqlSelect.limit"},
 		{Token: TokenOffset, Lexer: LexNumber, Optional: true, Name: "sqlSelect.offset"},
@@ -64,6 +70,13 @@ var (
 		{Token: TokenAlias, Lexer: LexIdentifier, Optional: true, Name: "sqlSelect.alias"},
 		{Token: TokenEOF, Lexer: LexEndOfStatement, Optional: false, Name: "sqlSelect.eos"},
 	}
+	// SqlWith select using named sub-queries, each sub-query is lexed by
+	// the select clauses followed by its right paren.
+	//
+	//    WITH [RECURSIVE] name [(col, ...)] AS (SELECT ...) [, ...] SELECT ...
+	SqlWith = append([]*Clause{
+		{Token: TokenWith, Lexer: LexWith, Name: "sqlWith.with"},
+	}, withSelectClauses()...)
 	fromSource = []*Clause{
 		{KeywordMatcher: sourceMatch, Lexer: LexTableReferenceFirst, Name: "fromSource.matcher"},
 		{Token: TokenSelect, Lexer: LexSelectClause, Name: "fromSource.Select"},
@@ -107,6 +120,7 @@ var (
 		{Token: TokenUpdate, Lexer: LexIdentifierOfType(TokenTable)},
 		{Token: TokenSet, Lexer: LexColumns},
 		{Token: TokenWhere, Lexer: LexColumns, Optional: true},
+		{Token: TokenOrderBy, Lexer: LexOrderByColumn, Optional: true},
 		{Token: TokenLimit, Lexer: LexNumber, Optional: true},
 		{Token: TokenWith, Lexer: LexJsonOrKeyValue, Optional: true},
 	}
@@ -208,8 +222,31 @@ var (
 	SqlCommit = []*Clause{
 		{Token: TokenCommit, Lexer: LexEmpty},
 	}
+	// SqlAnalyze
+	SqlAnalyze = []*Clause{
+		{Token: TokenAnalyze, Lexer: LexAnalyze},
+	}
 )
 
+// withSelectClauses a copy of the select clauses, as a clause may only
+// belong to one statement, with the end of a WITH sub-query before EOF.
+func withSelectClauses() []*Clause {
+	clauses := copyClauses(SqlSelect)
+	eos := clauses[len(clauses)-1]
+	clauses[len(clauses)-1] = &Clause{Token: TokenRightParenthesis, Lexer: LexWithEnd, Optional: true, Name: "sqlWith.EndParen"}
+	return append(clauses, eos)
+}
+
+func copyClauses(clauses []*Clause) []*Clause {
+	cc := make([]*Clause, len(clauses))
+	for i, c := range clauses {
+		cp := *c
+		cp.Clauses = copyClauses(c.Clauses)
+		cc[i] = &cp
+	}
+	return cc
+}
+
 // NewSqlLexer creates a new lexer for the input string using SqlDialect
 // this is sql(ish) compatible parser.
 func NewSqlLexer(input string) *Lexer {
@@ -219,7 +256,7 @@ func NewSqlLexer(input string) *Lexer {
 // find any keyword that starts a source
 //    FROM <name>
 //    FROM (select ...)
-//         [(INNER | LEFT)] JOIN
+//         [(INNER | LEFT | RIGHT | FULL)] [OUTER] JOIN
 func sourceMatch(c *Clause, peekWord string, l *Lexer) bool {
 	//u.Debugf("%p sourceMatch?   peekWord: %s", c, peekWord)
 	switch peekWord {
@@ -227,7 +264,7 @@ func sourceMatch(c *Clause, peekWord string, l *Lexer) bool {
 		return true
 	case "select":
 		return true
-	case "left", "right", "inner", "outer", "join":
+	case "left", "right", "full", "inner", "outer", "join":
 		return true
 	}
 	return false
@@ -244,6 +281,84 @@ func LexEndOfSubStatement(l *Lexer) StateFn {
 	return l.errorToken("Unexpected token:" + l.current())
 }
 
+// LexSetOperation the optional ALL | DISTINCT after a set operation keyword,
+// lexing then starts over at the SELECT of the next operand.
+//
+//    SELECT ... UNION [ALL | DISTINCT] SELECT ...
+//    SELECT ... INTERSECT SELECT ...
+//    SELECT ... EXCEPT SELECT ...
+func LexSetOperation(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	keyWord := strings.ToLower(l.PeekWord())
+	switch keyWord {
+	case "all":
+		l.ConsumeWord(keyWord)
+		l.Emit(TokenAll)
+	case "distinct":
+		l.ConsumeWord(keyWord)
+		l.Emit(TokenDistinct)
+	}
+	if l.curClause != nil && l.curClause.parent != nil {
+		l.curClause = l.curClause.parent.Clauses[0]
+	}
+	return nil
+}
+
+// LexWith the optional RECURSIVE and first named sub-query of a WITH
+// statement, lexing then continues at the SELECT of the sub-query.
+//
+//    WITH [RECURSIVE] <name> [ '(' <identity> [, <identity>]* ')' ] AS '(' SELECT ...
+func LexWith(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	if strings.ToLower(l.PeekWord()) == "recursive" {
+		l.ConsumeWord("recursive")
+		l.Emit(TokenRecursive)
+	}
+	return lexWithName
+}
+
+// LexWithEnd the end of a WITH sub-query, its right paren has been consumed,
+// followed by either the next named sub-query or the SELECT using them.
+func LexWithEnd(l *Lexer) StateFn {
+	// both start over at the SELECT of the statement
+	if l.curClause != nil && l.curClause.parent != nil {
+		l.curClause = l.curClause.parent.Clauses[1]
+	}
+	l.SkipWhiteSpaces()
+	if l.Peek() == ',' {
+		l.Next()
+		l.Emit(TokenComma)
+		return lexWithName
+	}
+	return nil
+}
+
+func lexWithName(l *Lexer) StateFn {
+	l.Push("lexWithAs", lexWithAs)
+	return LexIdentifier
+}
+
+func lexWithAs(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	if l.Peek() == '(' {
+		// column names of the sub-query
+		l.Push("lexWithAs", lexWithAs)
+		return LexColumnNames
+	}
+	if strings.ToLower(l.PeekWord()) != "as" {
+		return l.errorToken("expected AS in WITH but got:" + l.PeekWord())
+	}
+	l.ConsumeWord("as")
+	l.Emit(TokenAs)
+	l.SkipWhiteSpaces()
+	if l.Peek() != '(' {
+		return l.errorToken("expected ( after AS in WITH but got:" + l.PeekWord())
+	}
+	l.Next()
+	l.Emit(TokenLeftParenthesis)
+	return nil
+}
+
 // LexShowClause Handle show statement
 //
 //    SHOW [FULL] <multi_word_identifier> <identity> <like_or_where>
@@ -390,7 +505,8 @@ func LexLimit(l *Lexer) StateFn {
 // LexCreate allows us to lex the words after CREATE
 //
 //    CREATE {SCHEMA|DATABASE|SOURCE} [IF NOT EXISTS] <identity>  <WITH>
-//    CREATE {TABLE} <identity> [IF NOT EXISTS] <table_spec> [WITH]
+//    CREATE {TABLE} [IF NOT EXISTS] <identity> <table_spec> [WITH]
+//    CREATE {TABLE} [IF NOT EXISTS] <identity> AS <select_statement>
 //    CREATE [OR REPLACE] {VIEW|CONTINUOUSVIEW} <identity> AS <select_statement> [WITH]
 //
 func LexCreate(l *Lexer) StateFn {
@@ -552,6 +668,19 @@ func LexDrop(l *Lexer) StateFn {
 	return lexNotExists
 }
 
+// LexAnalyze the table of an analyze statement
+//
+//    ANALYZE [TABLE] tbl_name
+func LexAnalyze(l *Lexer) StateFn {
+	l.SkipWhiteSpaces()
+	keyWord := strings.ToLower(l.PeekWord())
+	if keyWord == "table" {
+		l.ConsumeWord(keyWord)
+		l.Emit(TokenTable)
+	}
+	return LexIdentifier
+}
+
 // LexDdlTable data definition language table
 func LexDdlTable(l *Lexer) StateFn {
 
@@ -621,6 +750,17 @@ func LexDdlTable(l *Lexer) StateFn {
 		l.Push("LexDdlTable", l.clauseState())
 		return LexExpressionOrIdentity
 	}
+	switch word {
+	case "if":
+		// IF NOT EXISTS before the table name
+		l.Push("LexDdlTable", LexDdlTable)
+		return lexNotExists
+	case "as":
+		// AS <select_statement>, the select is its own clause
+		l.ConsumeWord(word)
+		l.Emit(TokenAs)
+		return nil
+	}
 	if l.isNextKeyword(word) {
 		return nil
 	}
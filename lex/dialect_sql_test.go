@@ -145,6 +145,26 @@ func TestLexSqlCreate(t *testing.T) {
 			tv(TokenValue, "hello"),
 		})
 
+	verifyTokens(t, `CREATE TABLE IF NOT EXISTS user_ct AS SELECT user_id, count(*) FROM users;`,
+		[]Token{
+			tv(TokenCreate, "CREATE"),
+			tv(TokenTable, "TABLE"),
+			tv(TokenIf, "IF"),
+			tv(TokenNegate, "NOT"),
+			tv(TokenExists, "EXISTS"),
+			tv(TokenIdentity, "user_ct"),
+			tv(TokenAs, "AS"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "user_id"),
+			tv(TokenComma, ","),
+			tv(TokenUdfExpr, "count"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenStar, "*"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "users"),
+		})
+
 	verifyTokens(t, `CREATE OR REPLACE VIEW viewx 
 			AS SELECT a, b FROM mydb.tbl 
 			WITH stuff = "hello";`,
@@ -358,3 +378,46 @@ func TestLexSqlSelect(t *testing.T) {
 			tv(TokenInteger, "100"),
 		})
 }
+
+func TestLexSqlPlaceholder(t *testing.T) {
+	verifyTokens(t, `SELECT a FROM tbl WHERE a = ? AND b > $2 AND c IN (:name, ?)`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "a"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "tbl"),
+			tv(TokenWhere, "WHERE"),
+			tv(TokenIdentity, "a"),
+			tv(TokenEqual, "="),
+			tv(TokenPlaceholder, "?"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "b"),
+			tv(TokenGT, ">"),
+			tv(TokenPlaceholder, "$2"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenIdentity, "c"),
+			tv(TokenIN, "IN"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenPlaceholder, ":name"),
+			tv(TokenComma, ","),
+			tv(TokenPlaceholder, "?"),
+			tv(TokenRightParenthesis, ")"),
+		})
+	verifyTokens(t, `INSERT INTO tbl (a, b) VALUES (?, :b)`,
+		[]Token{
+			tv(TokenInsert, "INSERT"),
+			tv(TokenInto, "INTO"),
+			tv(TokenTable, "tbl"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "a"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "b"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenValues, "VALUES"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenPlaceholder, "?"),
+			tv(TokenComma, ","),
+			tv(TokenPlaceholder, ":b"),
+			tv(TokenRightParenthesis, ")"),
+		})
+}
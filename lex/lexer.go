@@ -105,6 +105,7 @@ type Lexer struct {
 	peekedWordPos int
 	peekedWord    string
 	lastQuoteMark byte
+	caseStack     []int // stack depth at the start of each open CASE expression
 
 	// Due to nested Expressions and evaluation this allows us to descend/ascend
 	// during lex, using push/pop to add and remove states needing evaluation
@@ -263,6 +264,85 @@ func (l *Lexer) peekRunePast(skip int) rune {
 	return rune(0)
 }
 
+// isCaseStart is the word case at the current position the start of a
+// CASE expression, rather than an identity named case.
+func (l *Lexer) isCaseStart() bool {
+	if l.pos+4 > len(l.input) || !strings.EqualFold(l.input[l.pos:l.pos+4], "case") {
+		return false
+	}
+	if l.pos+4 < len(l.input) && IsIdentifierRune(rune(l.input[l.pos+4])) {
+		return false
+	}
+	rest := strings.TrimLeftFunc(l.input[l.pos+4:], unicode.IsSpace)
+	if len(rest) == 0 {
+		return false
+	}
+	switch rest[0] {
+	case ',', ')', ';', '.', '=', '!', '<', '>', '+', '*', '/', '%', '|', '&':
+		return false
+	}
+	word := strings.ToLower(rest)
+	for i, r := range word {
+		if !IsIdentifierRune(r) {
+			word = word[:i]
+			break
+		}
+	}
+	switch word {
+	case "as", "from", "and", "or", "asc", "desc":
+		return false
+	}
+	return true
+}
+
+// placeholderLen the length of the bound parameter placeholder at the
+// current position, 0 if there is none.
+//
+//    ?       positional
+//    $1      numbered, 1 based
+//    :name   named
+func (l *Lexer) placeholderLen() int {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	rest := l.input[l.pos:]
+	switch rest[0] {
+	case '?':
+		if len(rest) > 1 && IsIdentifierRune(rune(rest[1])) {
+			return 0
+		}
+		return 1
+	case '$':
+		i := 1
+		for i < len(rest) && isDigit(rune(rest[i])) {
+			i++
+		}
+		if i == 1 || (i < len(rest) && IsIdentifierRune(rune(rest[i]))) {
+			return 0
+		}
+		return i
+	case ':':
+		if len(rest) < 2 || !(rest[1] == '_' || unicode.IsLetter(rune(rest[1]))) {
+			return 0
+		}
+		i := 1
+		for i < len(rest) && isIdentCh(rune(rest[i])) {
+			i++
+		}
+		return i
+	}
+	return 0
+}
+
+// isSubQueryParen is the next rune a paren opening a sub-query ie "(SELECT".
+func (l *Lexer) isSubQueryParen() bool {
+	if l.Peek() != '(' {
+		return false
+	}
+	rest := strings.TrimLeftFunc(l.input[l.pos+1:], unicode.IsSpace)
+	return len(rest) > 6 && strings.EqualFold(rest[:6], "select") && !IsIdentifierRune(rune(rest[6]))
+}
+
 // PeekWord grab the next word (till whitespace, without consuming)
 func (l *Lexer) PeekWord() string {
 
@@ -671,9 +751,14 @@ func (l *Lexer) isNextKeyword(peekWord string) bool {
 		}
 		// TODO:  allow clauses to reserve keywords, or sub-clause
 		switch kwMaybe {
-		case "select", "insert", "delete", "update", "from", "inner", "outer":
+		case "select", "insert", "delete", "update", "from", "inner", "outer",
+			"union", "intersect", "except":
 			//u.Warnf("doing true: %v", kwMaybe)
 			return true
+		case "left", "right", "full", "join":
+			if l.isJoinStart(kwMaybe) {
+				return true
+			}
 		}
 		if !clause.Optional {
 			return false
@@ -684,6 +769,28 @@ func (l *Lexer) isNextKeyword(peekWord string) bool {
 	return false
 }
 
+// non-consuming check if we are at start of a join
+//
+//    (LEFT | RIGHT | FULL) [OUTER] JOIN
+//
+// as opposed to left(str, 2) or join(a, ",") functions or identities.
+func (l *Lexer) isJoinStart(word string) bool {
+	words := strings.Fields(strings.ToLower(l.PeekX(len(word) + 12)))
+	if len(words) == 0 || words[0] != word {
+		return false
+	}
+	if word == "join" {
+		return true
+	}
+	switch {
+	case len(words) > 1 && words[1] == "join":
+		return true
+	case len(words) > 2 && words[1] == "outer" && words[2] == "join":
+		return true
+	}
+	return false
+}
+
 // non-consuming isIdentity
 // Identities are non-numeric string values that are not quoted
 func (l *Lexer) isIdentity() bool {
@@ -745,6 +852,10 @@ func (l *Lexer) lexIfMatch(tok TokenType, matchState StateFn) StateFn {
 
 // current clause state function, used for repeated clauses
 func (l *Lexer) clauseState() StateFn {
+	if len(l.caseStack) > 0 {
+		// within a CASE expression, whatever the clause, until its END
+		return LexExpression
+	}
 	if l.curClause != nil {
 		if len(l.curClause.Clauses) > 0 {
 			return l.curClause.Clauses[0].Lexer
@@ -886,7 +997,9 @@ func LexStatement(l *Lexer) StateFn {
 				if int(clause.Token) == 0 {
 					nextState := clause.Lexer(l)
 					if nextState == nil {
-						//u.Warnf("nil next state")
+						// we carry on with the next clause ourselves, so
+						// don't leave our push behind on the stack
+						l.pop()
 					} else {
 						//u.Debugf("found next state")
 						return nextState
@@ -1164,6 +1277,12 @@ func LexExpressionOrIdentity(l *Lexer) StateFn {
 		return LexExpressionOrIdentity
 	}
 	// u.Debugf("LexExpressionOrIdentity identity?%v expr?%v %v peek5='%v'", l.isIdentity(), l.isExpr(), string(l.Peek()), string(l.PeekX(5)))
+	if l.placeholderLen() > 0 {
+		return lexPlaceholder(l)
+	}
+	if l.isCaseStart() {
+		return lexCase(l)
+	}
 	// Expressions end in Parens:     LOWER(item)
 	if l.isExpr() {
 		return lexExpressionIdentifier(l)
@@ -1792,6 +1911,10 @@ func LexSelectList(l *Lexer) StateFn {
 		l.Push("LexSelectList", LexSelectList)
 		return LexIdentifier
 	case "if":
+		if strings.ToLower(l.PeekX(3)) == "if(" {
+			// if(cond, a, b) function not an IF guard
+			break
+		}
 		l.skipX(2)
 		l.Emit(TokenIf)
 		l.Push("LexSelectList", LexSelectList)
@@ -1807,7 +1930,7 @@ func LexSelectList(l *Lexer) StateFn {
 //    <sources>      := <source> [, <join_clause> <source>]*
 //    <source>       := ( <table_source> | <subselect> ) [AS <identifier>]
 //    <table_source> := <identifier>
-//    <join_clause>  := (INNER | LEFT | OUTER)? JOIN [ON <conditional_clause>]
+//    <join_clause>  := (INNER | LEFT | RIGHT | FULL)? [OUTER] JOIN [ON <conditional_clause>]
 //    <subselect>    := '(' <select_stmt> ')'
 //
 func LexTableReferenceFirst(l *Lexer) StateFn {
@@ -1840,6 +1963,11 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 		l.Push("LexTableReferenceFirst", LexTableReferenceFirst)
 		return nil
 	case ')':
+		if l.statement != nil && l.statement.Token == TokenWith &&
+			l.curClause != nil && l.curClause.parent == l.statement {
+			// end of a WITH sub-query, let the dialect take over
+			return nil
+		}
 		l.Next()
 		l.Emit(TokenRightParenthesis)
 		return LexSelectClause
@@ -1857,6 +1985,9 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 		l.Push("LexTableReferenceFirst", LexTableReferenceFirst)
 		l.Push("LexIdentifier", LexIdentifier)
 		return nil
+	case "left", "right", "full", "inner", "outer", "join":
+		// start of a join source, let the dialect take over
+		return nil
 	case "in": // are there other functions besides in?
 		l.ConsumeWord(word)
 		l.Emit(TokenIN)
@@ -1897,7 +2028,7 @@ func LexTableReferenceFirst(l *Lexer) StateFn {
 //    <sources>      := <source> [, <join_clause> <source>]*
 //    <source>       := ( <table_source> | <subselect> ) [AS <identifier>]
 //    <table_source> := <identifier>
-//    <join_clause>  := (INNER | LEFT | OUTER)? JOIN [ON <conditional_clause>]
+//    <join_clause>  := (INNER | LEFT | RIGHT | FULL)? [OUTER] JOIN [ON <conditional_clause>]
 //    <subselect>    := '(' <select_stmt> ')'
 //
 func LexTableReferences(l *Lexer) StateFn {
@@ -1968,6 +2099,10 @@ func LexTableReferences(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenRight)
 		return LexTableReferences
+	case "full":
+		l.ConsumeWord(word)
+		l.Emit(TokenFull)
+		return LexTableReferences
 	case "join":
 		l.ConsumeWord(word)
 		l.Emit(TokenJoin)
@@ -2022,7 +2157,7 @@ func LexTableReferences(l *Lexer) StateFn {
 //    <sources>      := <source> [, <join_clause> <source>]*
 //    <source>       := ( <table_source> | <subselect> ) [AS <identifier>]
 //    <table_source> := <identifier>
-//    <join_clause>  := (INNER | LEFT | OUTER)? JOIN [ON <conditional_clause>]
+//    <join_clause>  := (INNER | LEFT | RIGHT | FULL)? [OUTER] JOIN [ON <conditional_clause>]
 //    <subselect>    := '(' <select_stmt> ')'
 //
 func LexJoinEntry(l *Lexer) StateFn {
@@ -2078,6 +2213,10 @@ func LexJoinEntry(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenRight)
 		return LexJoinEntry
+	case "full":
+		l.ConsumeWord(word)
+		l.Emit(TokenFull)
+		return LexJoinEntry
 	case "join":
 		l.ConsumeWord(word)
 		l.Emit(TokenJoin)
@@ -2252,6 +2391,11 @@ func LexConditionalClause(l *Lexer) StateFn {
 		//l.Push("LexParenRight", LexParenRight)
 		return nil
 	case '(':
+		if l.isSubQueryParen() {
+			// scalar sub-query left of an operator:  (SELECT count(*) ...) = 0
+			l.Push("LexConditionalClause", LexConditionalClause)
+			return LexExpression
+		}
 		l.Next()
 		l.Emit(TokenLeftParenthesis)
 		l.Push("LexConditionalClause", LexConditionalClause)
@@ -2361,8 +2505,21 @@ func LexExpression(l *Lexer) StateFn {
 			return LexIdentifier
 		}
 		u.Warnf("un-handled? ")
+	case '?', '$', ':':
+		l.backup()
+		if l.placeholderLen() > 0 {
+			lexPlaceholder(l)
+			return l.clauseState()
+		}
+		l.Next()
 	case '(': // this is a logical Grouping/Ordering and must be a single
 		// logically valid expression
+		if strings.ToLower(l.PeekWord()) == "select" {
+			// sub-query:   x IN (SELECT ...),  x > (SELECT ...)
+			l.Emit(TokenLeftParenthesis)
+			l.Push("LexExpression", l.clauseState())
+			return LexSubQuery
+		}
 		l.Push("LexParenRight", LexParenRight)
 		l.Emit(TokenLeftParenthesis)
 		l.Push("LexExpression", l.clauseState())
@@ -2533,7 +2690,7 @@ func LexExpression(l *Lexer) StateFn {
 	case "exists":
 		l.ConsumeWord(word)
 		r = l.Peek()
-		if r == '(' {
+		if r == '(' && !l.isSubQueryParen() {
 			l.Emit(TokenUdfExpr)
 			l.ConsumeWord("(")
 			l.Emit(TokenLeftParenthesis)
@@ -2551,6 +2708,44 @@ func LexExpression(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenNull)
 		return LexExpression
+	case "case":
+		if l.isCaseStart() {
+			l.Push("LexExpression", l.clauseState())
+			return lexCase(l)
+		}
+	case "when", "then", "else":
+		if len(l.caseStack) > 0 {
+			l.ConsumeWord(word)
+			switch word {
+			case "when":
+				l.Emit(TokenWhen)
+			case "then":
+				l.Emit(TokenThen)
+			default:
+				l.Emit(TokenElse)
+			}
+			return LexExpression
+		}
+	case "end":
+		if len(l.caseStack) > 0 {
+			l.ConsumeWord(word)
+			l.Emit(TokenEnd)
+			// drop any states left by the expressions inside the case
+			last := len(l.caseStack) - 1
+			l.stack = l.stack[:l.caseStack[last]]
+			l.caseStack = l.caseStack[:last]
+			return nil
+		}
+	case "over":
+		// window function   row_number() OVER (...), only after a function
+		// so a column named over is still an identity
+		if l.lastToken.T == TokenRightParenthesis && l.peekRunePast(len(word)) == '(' {
+			l.ConsumeWord(word)
+			l.Emit(TokenOver)
+			l.Push("LexExpression", l.clauseState())
+			l.Push("LexWindow", LexWindow)
+			return LexParenLeft
+		}
 	case "not":
 		// somewhat weird edge case, not is either word not, or expression
 		//
@@ -2641,9 +2836,32 @@ func LexExpression(l *Lexer) StateFn {
 	return LexExpressionOrIdentity
 }
 
+// lexCase the start of a CASE expression, its WHEN, THEN, ELSE and END
+// are lexed by LexExpression until the END which ascends.
+//
+//    CASE [<expression>] WHEN <expression> THEN <expression> [...] [ELSE <expression>] END
+//
+func lexCase(l *Lexer) StateFn {
+	l.ConsumeWord("case")
+	l.Emit(TokenCase)
+	l.caseStack = append(l.caseStack, len(l.stack))
+	l.Push("LexExpression", LexExpression)
+	return LexExpression
+}
+
+// lexPlaceholder a bound parameter placeholder.
+//
+//    x = ?,  x = $1,  x = :name
+//
+func lexPlaceholder(l *Lexer) StateFn {
+	l.pos += l.placeholderLen()
+	l.Emit(TokenPlaceholder)
+	return nil
+}
+
 // Handle columnar identies with keyword appendate (ASC, DESC)
 //
-//     [ORDER BY] ( <identity> | <expr> ) [(ASC | DESC)]
+//     [ORDER BY] ( <identity> | <expr> ) [COLLATE <name>] [(ASC | DESC)] [NULLS (FIRST | LAST)]
 //
 func LexOrderByColumn(l *Lexer) StateFn {
 
@@ -2683,6 +2901,28 @@ func LexOrderByColumn(l *Lexer) StateFn {
 		l.ConsumeWord(word)
 		l.Emit(TokenDesc)
 		return LexOrderByColumn
+	case "nulls":
+		l.ConsumeWord(word)
+		l.Emit(TokenNulls)
+		return LexOrderByColumn
+	case "first", "last":
+		// only a keyword following NULLS, else a column named first/last
+		if l.lastToken.T == TokenNulls {
+			l.ConsumeWord(word)
+			if word == "first" {
+				l.Emit(TokenFirst)
+			} else {
+				l.Emit(TokenLast)
+			}
+			return LexOrderByColumn
+		}
+		l.Push("LexOrderByColumn", LexOrderByColumn)
+		return LexExpressionOrIdentity
+	case "collate":
+		l.ConsumeWord(word)
+		l.Emit(TokenCollate)
+		l.Push("LexOrderByColumn", LexOrderByColumn)
+		return LexIdentifier
 	default:
 		if len(l.stack) < 2 {
 			l.Push("LexOrderByColumn", LexOrderByColumn)
@@ -2696,6 +2936,100 @@ func LexOrderByColumn(l *Lexer) StateFn {
 	return nil
 }
 
+// LexWindow the window specification of an OVER clause, the OVER and
+// its left paren have been consumed.
+//
+//     OVER ( [PARTITION BY <expr>, ...] [ORDER BY <order_col>, ...] [<frame>] )
+//
+//     <frame> := (ROWS | RANGE) ( <bound> | BETWEEN <bound> AND <bound> )
+//     <bound> := UNBOUNDED (PRECEDING | FOLLOWING) | <n> (PRECEDING | FOLLOWING) | CURRENT ROW
+//
+func LexWindow(l *Lexer) StateFn {
+
+	l.SkipWhiteSpaces()
+	if l.IsEnd() {
+		return nil
+	}
+
+	r := l.Peek()
+	switch r {
+	case ')':
+		l.Next()
+		l.Emit(TokenRightParenthesis)
+		return nil
+	case ',':
+		l.Next()
+		l.Emit(TokenComma)
+		l.Push("LexWindow", LexWindow)
+		return LexExpressionOrIdentity
+	}
+	if isDigit(r) {
+		l.Push("LexWindow", LexWindow)
+		return LexNumber
+	}
+
+	word := strings.ToLower(l.PeekWord())
+	switch word {
+	case "partition":
+		if l.tryMatch(TokenPartitionBy.String()) {
+			l.Emit(TokenPartitionBy)
+			l.Push("LexWindow", LexWindow)
+			return LexExpressionOrIdentity
+		}
+	case "order":
+		if l.tryMatch(TokenOrderBy.String()) {
+			l.Emit(TokenOrderBy)
+			l.Push("LexWindow", LexWindow)
+			return LexExpressionOrIdentity
+		}
+	case "current":
+		if l.tryMatch(TokenCurrentRow.String()) {
+			l.Emit(TokenCurrentRow)
+			return LexWindow
+		}
+	case "first", "last":
+		// only a keyword following NULLS, else a column named first/last
+		if l.lastToken.T == TokenNulls {
+			l.ConsumeWord(word)
+			if word == "first" {
+				l.Emit(TokenFirst)
+			} else {
+				l.Emit(TokenLast)
+			}
+			return LexWindow
+		}
+		l.Push("LexWindow", LexWindow)
+		return LexExpressionOrIdentity
+	case "asc", "desc", "nulls", "rows", "range", "between", "and",
+		"unbounded", "preceding", "following":
+		l.ConsumeWord(word)
+		switch word {
+		case "asc":
+			l.Emit(TokenAsc)
+		case "desc":
+			l.Emit(TokenDesc)
+		case "nulls":
+			l.Emit(TokenNulls)
+		case "rows":
+			l.Emit(TokenRows)
+		case "range":
+			l.Emit(TokenRange)
+		case "between":
+			l.Emit(TokenBetween)
+		case "and":
+			l.Emit(TokenLogicAnd)
+		case "unbounded":
+			l.Emit(TokenUnbounded)
+		case "preceding":
+			l.Emit(TokenPreceding)
+		case "following":
+			l.Emit(TokenFollowing)
+		}
+		return LexWindow
+	}
+	return l.errorToken("Unexpected token in window:" + l.current())
+}
+
 // Lex either Json or Key/Value pairs
 //
 //    Must start with { or [ for json
@@ -665,6 +665,26 @@ func TestLexSqlJoin(t *testing.T) {
 		})
 }
 
+func TestLexSqlOuterJoin(t *testing.T) {
+
+	verifyTokenTypes(t, `
+		SELECT 
+			u.name, o.price
+		FROM users AS u 
+		LEFT OUTER JOIN orders AS o ON u.id = o.user_id
+		FULL JOIN items AS i ON o.item_id = i.id
+		WHERE o.price > 10;`,
+		[]TokenType{TokenSelect,
+			TokenIdentity, TokenComma, TokenIdentity,
+			TokenFrom, TokenIdentity, TokenAs, TokenIdentity,
+			TokenLeft, TokenOuter, TokenJoin, TokenIdentity, TokenAs, TokenIdentity,
+			TokenOn, TokenIdentity, TokenEqual, TokenIdentity,
+			TokenFull, TokenJoin, TokenIdentity, TokenAs, TokenIdentity,
+			TokenOn, TokenIdentity, TokenEqual, TokenIdentity,
+			TokenWhere, TokenIdentity, TokenGT, TokenInteger,
+		})
+}
+
 func TestLexSqlSubQuery(t *testing.T) {
 
 	verifyTokenTypes(t, `select
@@ -800,6 +820,135 @@ func TestLexOrderBy(t *testing.T) {
 			tv(TokenAsc, "ASC"),
 			tv(TokenEOS, ";"),
 		})
+
+	verifyTokens(t, "SELECT name FROM users ORDER BY name COLLATE nocase DESC NULLS LAST, age NULLS FIRST LIMIT 10;",
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "name"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "users"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "name"),
+			tv(TokenCollate, "COLLATE"),
+			tv(TokenIdentity, "nocase"),
+			tv(TokenDesc, "DESC"),
+			tv(TokenNulls, "NULLS"),
+			tv(TokenLast, "LAST"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "age"),
+			tv(TokenNulls, "NULLS"),
+			tv(TokenFirst, "FIRST"),
+			tv(TokenLimit, "LIMIT"),
+			tv(TokenInteger, "10"),
+			tv(TokenEOS, ";"),
+		})
+
+	// first, last are only keywords following NULLS
+	verifyTokens(t, "SELECT first, last FROM users ORDER BY last, first DESC NULLS LAST",
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "first"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "last"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "users"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "last"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "first"),
+			tv(TokenDesc, "DESC"),
+			tv(TokenNulls, "NULLS"),
+			tv(TokenLast, "LAST"),
+		})
+	verifyTokens(t, "SELECT first FROM users ORDER BY first DESC",
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "first"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "users"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "first"),
+			tv(TokenDesc, "DESC"),
+		})
+}
+
+func TestLexWindow(t *testing.T) {
+	verifyTokens(t, `SELECT row_number() OVER (PARTITION BY region ORDER BY amount DESC) AS rn,
+		sum(amount) OVER (ORDER BY id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW), over
+		FROM sales`,
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "row_number"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenOver, "OVER"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenPartitionBy, "PARTITION BY"),
+			tv(TokenIdentity, "region"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "amount"),
+			tv(TokenDesc, "DESC"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenAs, "AS"),
+			tv(TokenIdentity, "rn"),
+			tv(TokenComma, ","),
+			tv(TokenUdfExpr, "sum"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "amount"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenOver, "OVER"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "id"),
+			tv(TokenRows, "ROWS"),
+			tv(TokenBetween, "BETWEEN"),
+			tv(TokenInteger, "2"),
+			tv(TokenPreceding, "PRECEDING"),
+			tv(TokenLogicAnd, "AND"),
+			tv(TokenCurrentRow, "CURRENT ROW"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "over"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "sales"),
+		})
+
+	verifyTokens(t, "SELECT avg(amount) OVER (RANGE UNBOUNDED PRECEDING) FROM sales",
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "avg"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "amount"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenOver, "OVER"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenRange, "RANGE"),
+			tv(TokenUnbounded, "UNBOUNDED"),
+			tv(TokenPreceding, "PRECEDING"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "sales"),
+		})
+
+	verifyTokens(t, "SELECT rank() OVER (ORDER BY first, last DESC NULLS FIRST) FROM sales",
+		[]Token{
+			tv(TokenSelect, "SELECT"),
+			tv(TokenUdfExpr, "rank"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenOver, "OVER"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "first"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "last"),
+			tv(TokenDesc, "DESC"),
+			tv(TokenNulls, "NULLS"),
+			tv(TokenFirst, "FIRST"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "sales"),
+		})
 }
 
 func TestLexTSQL(t *testing.T) {
@@ -1124,6 +1273,22 @@ func TestLexUpdate(t *testing.T) {
 			tv(TokenInteger, "10"),
 			tv(TokenEOS, ";"),
 		})
+	verifyTokens(t, `UPDATE users SET hits = hits + 1 ORDER BY created DESC LIMIT 10`,
+		[]Token{
+			tv(TokenUpdate, "UPDATE"),
+			tv(TokenTable, "users"),
+			tv(TokenSet, "SET"),
+			tv(TokenIdentity, "hits"),
+			tv(TokenEqual, "="),
+			tv(TokenIdentity, "hits"),
+			tv(TokenPlus, "+"),
+			tv(TokenInteger, "1"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "created"),
+			tv(TokenDesc, "DESC"),
+			tv(TokenLimit, "LIMIT"),
+			tv(TokenInteger, "10"),
+		})
 }
 
 func TestLexUpsert(t *testing.T) {
@@ -1302,6 +1467,47 @@ func TestLexDelete(t *testing.T) {
 		})
 }
 
+func TestLexWith(t *testing.T) {
+	verifyTokens(t, `WITH RECURSIVE t (n) AS (SELECT id FROM users UNION ALL SELECT n FROM t),
+		u AS (SELECT n FROM t) SELECT n FROM u ORDER BY n`,
+		[]Token{
+			tv(TokenWith, "WITH"),
+			tv(TokenRecursive, "RECURSIVE"),
+			tv(TokenIdentity, "t"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenIdentity, "n"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenAs, "AS"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "id"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "users"),
+			tv(TokenUnion, "UNION"),
+			tv(TokenAll, "ALL"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "n"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenComma, ","),
+			tv(TokenIdentity, "u"),
+			tv(TokenAs, "AS"),
+			tv(TokenLeftParenthesis, "("),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "n"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "t"),
+			tv(TokenRightParenthesis, ")"),
+			tv(TokenSelect, "SELECT"),
+			tv(TokenIdentity, "n"),
+			tv(TokenFrom, "FROM"),
+			tv(TokenIdentity, "u"),
+			tv(TokenOrderBy, "ORDER BY"),
+			tv(TokenIdentity, "n"),
+		})
+}
+
 func TestWithJson(t *testing.T) {
 	// The lexer should be able to parse json
 	verifyTokenTypes(t, `
@@ -38,26 +38,24 @@ func (t Token) ErrMsg(l *Lexer, msg string) error {
 }
 
 /*
-
-	// List of datatypes from MySql, implement them as tokens?   or leave as Identity during
-	// DDL create/alter statements?
-	BOOL	TINYINT
-	BOOLEAN	TINYINT
-	CHARACTER VARYING(M)	VARCHAR(M)
-	FIXED	DECIMAL
-	FLOAT4	FLOAT
-	FLOAT8	DOUBLE
-	INT1	TINYINT
-	INT2	SMALLINT
-	INT3	MEDIUMINT
-	INT4	INT
-	INT8	BIGINT
-	LONG VARBINARY	MEDIUMBLOB
-	LONG VARCHAR	MEDIUMTEXT
-	LONG	MEDIUMTEXT
-	MIDDLEINT	MEDIUMINT
-	NUMERIC	DECIMAL
-
+// List of datatypes from MySql, implement them as tokens?   or leave as Identity during
+// DDL create/alter statements?
+BOOL	TINYINT
+BOOLEAN	TINYINT
+CHARACTER VARYING(M)	VARCHAR(M)
+FIXED	DECIMAL
+FLOAT4	FLOAT
+FLOAT8	DOUBLE
+INT1	TINYINT
+INT2	SMALLINT
+INT3	MEDIUMINT
+INT4	INT
+INT8	BIGINT
+LONG VARBINARY	MEDIUMBLOB
+LONG VARCHAR	MEDIUMTEXT
+LONG	MEDIUMTEXT
+MIDDLEINT	MEDIUMINT
+NUMERIC	DECIMAL
 */
 const (
 	// List of all TokenTypes Note we do NOT use IOTA because it is evil
@@ -123,6 +121,11 @@ const (
 	TokenNull             TokenType = 88 // NULL
 	TokenContains         TokenType = 89 // CONTAINS
 	TokenIntersects       TokenType = 90 // INTERSECTS
+	TokenCase             TokenType = 91 // CASE
+	TokenWhen             TokenType = 92 // WHEN
+	TokenThen             TokenType = 93 // THEN
+	TokenElse             TokenType = 94 // ELSE
+	TokenEnd              TokenType = 95 // END
 
 	// ql top-level keywords, these first keywords determine parser
 	TokenPrepare   TokenType = 200
@@ -142,35 +145,52 @@ const (
 	TokenReplace   TokenType = 214 // Insert/Replace are interchangeable on insert statements
 	TokenRollback  TokenType = 215
 	TokenCommit    TokenType = 216
+	TokenAnalyze   TokenType = 217
 
 	// Other QL Keywords, These are clause-level keywords that mark separation between clauses
-	TokenFrom     TokenType = 300 // from
-	TokenWhere    TokenType = 301 // where
-	TokenHaving   TokenType = 302 // having
-	TokenGroupBy  TokenType = 303 // group by
-	TokenBy       TokenType = 304 // by
-	TokenAlias    TokenType = 305 // alias
-	TokenWith     TokenType = 306 // with
-	TokenValues   TokenType = 307 // values
-	TokenInto     TokenType = 308 // into
-	TokenLimit    TokenType = 309 // limit
-	TokenOrderBy  TokenType = 310 // order by
-	TokenInner    TokenType = 311 // inner , ie of join
-	TokenCross    TokenType = 312 // cross
-	TokenOuter    TokenType = 313 // outer
-	TokenLeft     TokenType = 314 // left
-	TokenRight    TokenType = 315 // right
-	TokenJoin     TokenType = 316 // Join
-	TokenOn       TokenType = 317 // on
-	TokenDistinct TokenType = 318 // DISTINCT
-	TokenAll      TokenType = 319 // all
-	TokenInclude  TokenType = 320 // INCLUDE
-	TokenExists   TokenType = 321 // EXISTS
-	TokenOffset   TokenType = 322 // OFFSET
-	TokenFull     TokenType = 323 // FULL
-	TokenGlobal   TokenType = 324 // GLOBAL
-	TokenSession  TokenType = 325 // SESSION
-	TokenTables   TokenType = 326 // TABLES
+	TokenFrom      TokenType = 300 // from
+	TokenWhere     TokenType = 301 // where
+	TokenHaving    TokenType = 302 // having
+	TokenGroupBy   TokenType = 303 // group by
+	TokenBy        TokenType = 304 // by
+	TokenAlias     TokenType = 305 // alias
+	TokenWith      TokenType = 306 // with
+	TokenValues    TokenType = 307 // values
+	TokenInto      TokenType = 308 // into
+	TokenLimit     TokenType = 309 // limit
+	TokenOrderBy   TokenType = 310 // order by
+	TokenInner     TokenType = 311 // inner , ie of join
+	TokenCross     TokenType = 312 // cross
+	TokenOuter     TokenType = 313 // outer
+	TokenLeft      TokenType = 314 // left
+	TokenRight     TokenType = 315 // right
+	TokenJoin      TokenType = 316 // Join
+	TokenOn        TokenType = 317 // on
+	TokenDistinct  TokenType = 318 // DISTINCT
+	TokenAll       TokenType = 319 // all
+	TokenInclude   TokenType = 320 // INCLUDE
+	TokenExists    TokenType = 321 // EXISTS
+	TokenOffset    TokenType = 322 // OFFSET
+	TokenFull      TokenType = 323 // FULL
+	TokenGlobal    TokenType = 324 // GLOBAL
+	TokenSession   TokenType = 325 // SESSION
+	TokenTables    TokenType = 326 // TABLES
+	TokenUnion     TokenType = 327 // UNION
+	TokenIntersect TokenType = 328 // INTERSECT
+	TokenExcept    TokenType = 329 // EXCEPT
+
+	// Window functions, ie  OVER (PARTITION BY x ORDER BY y ROWS ...)
+	TokenOver        TokenType = 330 // over
+	TokenPartitionBy TokenType = 331 // partition by
+	TokenRows        TokenType = 332 // rows
+	TokenRange       TokenType = 333 // range
+	TokenUnbounded   TokenType = 334 // unbounded
+	TokenPreceding   TokenType = 335 // preceding
+	TokenFollowing   TokenType = 336 // following
+	TokenCurrentRow  TokenType = 337 // current row
+
+	// Common table expressions, ie  WITH RECURSIVE name AS (SELECT ...) SELECT ...
+	TokenRecursive TokenType = 338 // recursive
 
 	// ddl major words
 	TokenSchema         TokenType = 400 // SCHEMA
@@ -203,6 +223,11 @@ const (
 	TokenDesc TokenType = 503 // descending
 	TokenUse  TokenType = 504 // use
 
+	// Order by modifiers
+	TokenNulls   TokenType = 505 // nulls, ie NULLS FIRST
+	TokenLast    TokenType = 506 // last
+	TokenCollate TokenType = 507 // collate
+
 	// User defined function/expression
 	TokenUdfExpr TokenType = 550
 
@@ -212,6 +237,7 @@ const (
 	TokenValueEscaped TokenType = 602 // '' becomes ' inside the string, parser will need to replace the string
 	TokenRegex        TokenType = 603 // regex
 	TokenDuration     TokenType = 604 // 14d , 22w, 3y, 45ms, 45us, 24hr, 2h, 45m, 30s
+	TokenPlaceholder  TokenType = 605 // bound parameter placeholder   ?, $1, :name
 
 	// Data Type Definitions
 	TokenTypeDef     TokenType = 999
@@ -304,6 +330,11 @@ var (
 		TokenNull:       {Kw: "null", Description: "NULL"},
 		TokenContains:   {Kw: "contains", Description: "contains"},
 		TokenIntersects: {Kw: "intersects", Description: "intersects"},
+		TokenCase:       {Kw: "case", Description: "CASE"},
+		TokenWhen:       {Kw: "when", Description: "WHEN"},
+		TokenThen:       {Kw: "then", Description: "THEN"},
+		TokenElse:       {Kw: "else", Description: "ELSE"},
+		TokenEnd:        {Kw: "end", Description: "END"},
 
 		// Identity ish bools
 		TokenTrue:  {Kw: "true", Description: "True"},
@@ -334,6 +365,7 @@ var (
 		TokenReplace:   {Description: "replace"},
 		TokenRollback:  {Description: "rollback"},
 		TokenCommit:    {Description: "commit"},
+		TokenAnalyze:   {Description: "analyze"},
 
 		// Top Level dml ql clause keywords
 		TokenInto:    {Description: "into"},
@@ -343,27 +375,43 @@ var (
 		TokenHaving:  {Description: "having"},
 		TokenGroupBy: {Description: "group by"},
 		// Other Ql Keywords
-		TokenAlias:    {Description: "alias"},
-		TokenWith:     {Description: "with"},
-		TokenValues:   {Description: "values"},
-		TokenLimit:    {Description: "limit"},
-		TokenOrderBy:  {Description: "order by"},
-		TokenInner:    {Description: "inner"},
-		TokenCross:    {Description: "cross"},
-		TokenOuter:    {Description: "outer"},
-		TokenLeft:     {Description: "left"},
-		TokenRight:    {Description: "right"},
-		TokenJoin:     {Description: "join"},
-		TokenOn:       {Description: "on"},
-		TokenDistinct: {Description: "distinct"},
-		TokenAll:      {Description: "all"},
-		TokenInclude:  {Description: "include"},
-		TokenExists:   {Description: "exists"},
-		TokenOffset:   {Description: "offset"},
-		TokenFull:     {Description: "full"},
-		TokenGlobal:   {Description: "global"},
-		TokenSession:  {Description: "session"},
-		TokenTables:   {Description: "tables"},
+		TokenAlias:     {Description: "alias"},
+		TokenWith:      {Description: "with"},
+		TokenValues:    {Description: "values"},
+		TokenLimit:     {Description: "limit"},
+		TokenOrderBy:   {Description: "order by"},
+		TokenInner:     {Description: "inner"},
+		TokenCross:     {Description: "cross"},
+		TokenOuter:     {Description: "outer"},
+		TokenLeft:      {Description: "left"},
+		TokenRight:     {Description: "right"},
+		TokenJoin:      {Description: "join"},
+		TokenOn:        {Description: "on"},
+		TokenDistinct:  {Description: "distinct"},
+		TokenAll:       {Description: "all"},
+		TokenInclude:   {Description: "include"},
+		TokenExists:    {Description: "exists"},
+		TokenOffset:    {Description: "offset"},
+		TokenFull:      {Description: "full"},
+		TokenGlobal:    {Description: "global"},
+		TokenSession:   {Description: "session"},
+		TokenTables:    {Description: "tables"},
+		TokenUnion:     {Description: "union"},
+		TokenIntersect: {Description: "intersect"},
+		TokenExcept:    {Description: "except"},
+
+		// Window functions
+		TokenOver:        {Description: "over"},
+		TokenPartitionBy: {Description: "partition by"},
+		TokenRows:        {Description: "rows"},
+		TokenRange:       {Description: "range"},
+		TokenUnbounded:   {Description: "unbounded"},
+		TokenPreceding:   {Description: "preceding"},
+		TokenFollowing:   {Description: "following"},
+		TokenCurrentRow:  {Description: "current row"},
+
+		// Common table expressions
+		TokenRecursive: {Description: "recursive"},
 
 		// ddl keywords
 		TokenSchema:         {Description: "schema"},
@@ -395,12 +443,18 @@ var (
 		TokenDesc: {Description: "desc"},
 		TokenUse:  {Description: "use"},
 
+		// Order by modifiers
+		TokenNulls:   {Description: "nulls"},
+		TokenLast:    {Description: "last"},
+		TokenCollate: {Description: "collate"},
+
 		// special value types
 		TokenIdentity:     {Description: "identity"},
 		TokenValue:        {Description: "value"},
 		TokenValueEscaped: {Description: "value-escaped"},
 		TokenRegex:        {Description: "regex"},
 		TokenDuration:     {Description: "duration"},
+		TokenPlaceholder:  {Description: "placeholder"},
 
 		// Data TYPES:  ie type system
 		TokenTypeDef:     {Description: "TypeDef"}, // Generic DataType
@@ -2,6 +2,7 @@ package plan
 
 import (
 	"math/rand"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
@@ -18,6 +19,10 @@ type NextIdFunc func() uint64
 // NextId is the global next id generation function
 var NextId NextIdFunc
 
+// DefaultMemoryLimit is the MemoryLimit (bytes) given to new Contexts,
+// 0 = no limit.
+var DefaultMemoryLimit int64
+
 var rs = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 func init() {
@@ -50,9 +55,18 @@ type Context struct {
 	Session expr.ContextReadWriter // Session for this connection
 	Schema  *schema.Schema         // this schema for this connection
 	Funcs   expr.FuncResolver      // Local/Dialect specific functions
+	Txn     *schema.Transaction    // open transaction of this connection, optional
+	Ctes    map[string]*Cte        // WITH sub-queries of the statement, by lower case name
+	Params  *expr.Params           // values bound to the placeholders of the statement, optional
 
 	// From configuration
 	DisableRecover bool
+	// MemoryLimit is the approximate number of bytes of rows a buffering
+	// task (order by, join build side) may hold in memory before spilling
+	// to disk.  0 = no limit.
+	MemoryLimit int64
+	// TempDir is directory for spill files, defaults to os.TempDir().
+	TempDir string
 
 	// Local State
 	Errors     []error
@@ -61,12 +75,47 @@ type Context struct {
 
 // NewContext plan context
 func NewContext(query string) *Context {
-	return &Context{Raw: query}
+	return &Context{Raw: query, MemoryLimit: DefaultMemoryLimit}
 }
 func NewContextFromPb(pb *ContextPb) *Context {
 	return &Context{id: pb.Id, fingerprint: pb.Fingerprint, SchemaName: pb.Schema}
 }
 
+// OpenConn open a conn to this schema's table, as part of the open
+// transaction if there is one.
+func (m *Context) OpenConn(table string) (schema.Conn, error) {
+	if m.Txn != nil {
+		return m.Txn.OpenConn(m.Schema, table)
+	}
+	return m.Schema.OpenConn(table)
+}
+
+// OpenSource open a conn to the table of ds, as part of the open
+// transaction if there is one.
+func (m *Context) OpenSource(ds schema.Source, table string) (schema.Conn, error) {
+	if m != nil && m.Txn != nil {
+		return m.Txn.Open(ds, table)
+	}
+	return ds.Open(table)
+}
+
+// Cte the WITH sub-query of the statement named name, nil if none.
+func (m *Context) Cte(name string) *Cte {
+	if m == nil || len(m.Ctes) == 0 {
+		return nil
+	}
+	return m.Ctes[strings.ToLower(name)]
+}
+
+// Table the table of name, a WITH sub-query of the statement shadows the
+// tables of the schema.
+func (m *Context) Table(name string) (*schema.Table, error) {
+	if cte := m.Cte(name); cte != nil {
+		return cte.Tbl, nil
+	}
+	return m.Schema.Table(name)
+}
+
 // called by go routines/tasks to ensure any recovery panics are captured
 func (m *Context) Recover() {
 	if m == nil {
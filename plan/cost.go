@@ -0,0 +1,449 @@
+package plan
+
+import (
+	"database/sql/driver"
+	"math"
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+)
+
+const (
+	// default selectivities of predicates on columns without stats
+	selectivityEqual = 0.1
+	selectivityRange = 1.0 / 3.0
+	selectivityLike  = 0.1
+)
+
+// estimateRows the number of rows a task will produce from its table
+// stats and the selectivity of its where, false if unknown.
+func estimateRows(t Task) (int64, bool) {
+	switch tt := t.(type) {
+	case *Source:
+		if tt.Tbl == nil {
+			return 0, false
+		}
+		stats := tt.Tbl.Stats()
+		if stats == nil {
+			return 0, false
+		}
+		rows := float64(stats.RowCt)
+		if tt.Stmt != nil && tt.Stmt.Source != nil && tt.Stmt.Source.Where != nil {
+			rows *= selectivity(stats, tt.Stmt.Source.Where.Expr)
+		}
+		return int64(math.Ceil(rows)), true
+	case *JoinMerge:
+		lct, ok := estimateRows(tt.Left)
+		if !ok {
+			return 0, false
+		}
+		rct, ok := estimateRows(tt.Right)
+		if !ok {
+			return 0, false
+		}
+		// assume a key to foreign key join, each row of the larger
+		// side matching one of the smaller
+		if lct > rct {
+			return lct, true
+		}
+		return rct, true
+	}
+	return 0, false
+}
+
+// chooseBuildSide build the join hash table on the smaller side if
+// we have stats for both.
+func (m *JoinMerge) chooseBuildSide() {
+	m.BuildLeft = false
+	if lct, ok := estimateRows(m.Left); ok {
+		if rct, ok := estimateRows(m.Right); ok && lct < rct {
+			m.BuildLeft = true
+		}
+	}
+}
+
+// selectivity the estimated fraction of rows of a table matching the
+// predicate n, from the tables column stats where known.
+func selectivity(stats *schema.TableStats, n expr.Node) float64 {
+	switch nt := n.(type) {
+	case *expr.BinaryNode:
+		if len(nt.Args) != 2 {
+			break
+		}
+		switch nt.Operator.T {
+		case lex.TokenLogicAnd, lex.TokenAnd:
+			return selectivity(stats, nt.Args[0]) * selectivity(stats, nt.Args[1])
+		case lex.TokenLogicOr, lex.TokenOr:
+			l, r := selectivity(stats, nt.Args[0]), selectivity(stats, nt.Args[1])
+			return l + r - l*r
+		case lex.TokenIN:
+			sel := selectivityEqual
+			if arr, ok := nt.Args[1].(*expr.ArrayNode); ok {
+				sel = 0
+				for _, arg := range arr.Args {
+					sel += compareSelectivity(stats, lex.TokenEqual, nt.Args[0], arg)
+				}
+			}
+			return math.Min(sel, 1)
+		case lex.TokenLike:
+			return selectivityLike
+		case lex.TokenIs:
+			cs := columnStats(stats, nt.Args[0])
+			sel := selectivityEqual
+			if cs != nil {
+				sel = cs.NullFrac
+			}
+			return sel
+		default:
+			return compareSelectivity(stats, nt.Operator.T, nt.Args[0], nt.Args[1])
+		}
+	case *expr.TriNode:
+		if nt.Operator.T == lex.TokenBetween && len(nt.Args) == 3 {
+			cs := columnStats(stats, nt.Args[0])
+			lo, ok1 := literalValue(nt.Args[1])
+			hi, ok2 := literalValue(nt.Args[2])
+			sel := selectivityRange
+			if cs != nil && ok1 && ok2 {
+				sel = math.Max(0, cs.FracLess(hi, true)-cs.FracLess(lo, false))
+			}
+			return negate(sel, nt.Negated())
+		}
+	case *expr.BooleanNode:
+		sel := 1.0
+		isOr := nt.Operator.T == lex.TokenLogicOr || nt.Operator.T == lex.TokenOr
+		if isOr {
+			sel = 0
+		}
+		for _, arg := range nt.Args {
+			s := selectivity(stats, arg)
+			if isOr {
+				sel = sel + s - sel*s
+			} else {
+				sel *= s
+			}
+		}
+		return negate(sel, nt.Negated())
+	case *expr.UnaryNode:
+		if nt.Operator.T == lex.TokenNegate {
+			return 1 - selectivity(stats, nt.Arg)
+		}
+	}
+	return selectivityRange
+}
+
+// compareSelectivity of a comparison of a column to a literal.
+func compareSelectivity(stats *schema.TableStats, op lex.TokenType, l, r expr.Node) float64 {
+	cs := columnStats(stats, l)
+	if _, isNull := r.(*expr.NullNode); isNull {
+		// x IS NOT NULL
+		sel := selectivityEqual
+		if cs != nil {
+			sel = cs.NullFrac
+		}
+		if op == lex.TokenNE {
+			return 1 - sel
+		}
+		return sel
+	}
+	v, ok := literalValue(r)
+	if cs == nil || !ok {
+		// literal on the left, flip the comparison
+		cs = columnStats(stats, r)
+		v, ok = literalValue(l)
+		switch op {
+		case lex.TokenLT:
+			op = lex.TokenGT
+		case lex.TokenLE:
+			op = lex.TokenGE
+		case lex.TokenGT:
+			op = lex.TokenLT
+		case lex.TokenGE:
+			op = lex.TokenLE
+		}
+	}
+	switch op {
+	case lex.TokenEqual, lex.TokenEqualEqual:
+		if cs == nil || !ok {
+			return selectivityEqual
+		}
+		return cs.FracEqual(v)
+	case lex.TokenNE:
+		if cs == nil || !ok {
+			return 1 - selectivityEqual
+		}
+		return 1 - cs.NullFrac - cs.FracEqual(v)
+	case lex.TokenLT, lex.TokenLE:
+		if cs == nil || !ok {
+			return selectivityRange
+		}
+		return cs.FracLess(v, op == lex.TokenLE)
+	case lex.TokenGT, lex.TokenGE:
+		if cs == nil || !ok {
+			return selectivityRange
+		}
+		return math.Max(0, 1-cs.NullFrac-cs.FracLess(v, op == lex.TokenGT))
+	}
+	return selectivityRange
+}
+
+func negate(sel float64, negated bool) float64 {
+	if negated {
+		return 1 - sel
+	}
+	return sel
+}
+
+// columnStats the stats of the column identity n, nil if unknown.
+func columnStats(stats *schema.TableStats, n expr.Node) *schema.ColumnStats {
+	in, ok := n.(*expr.IdentityNode)
+	if !ok || stats == nil || stats.Columns == nil {
+		return nil
+	}
+	name := in.Text
+	if _, right, ok := in.LeftRight(); ok {
+		name = right
+	}
+	if cs, ok := stats.Columns[name]; ok {
+		return cs
+	}
+	for col, cs := range stats.Columns {
+		if strings.EqualFold(col, name) {
+			return cs
+		}
+	}
+	return nil
+}
+
+func literalValue(n expr.Node) (driver.Value, bool) {
+	switch nt := n.(type) {
+	case *expr.NumberNode:
+		if nt.IsInt {
+			return nt.Int64, true
+		}
+		return nt.Float64, true
+	case *expr.StringNode:
+		return nt.Text, true
+	case *expr.ValueNode:
+		if nt.Value != nil {
+			return nt.Value.Value(), true
+		}
+	}
+	return nil, false
+}
+
+// joinSource a source of a join being ordered.
+type joinSource struct {
+	from  *rel.SqlSource
+	alias string
+	stats *schema.TableStats
+	rows  float64
+}
+
+// orderJoins reorder the sources of an inner join by their estimated
+// rows from the table stats, starting with the smallest and then the
+// source whose join to the one before it is estimated smallest.  The join
+// expressions are re-assigned to the source each joins onto the others,
+// false if the sources are left in their FROM order.
+func orderJoins(ctx *Context, stmt *rel.SqlSelect) bool {
+	if len(stmt.From) < 2 || stmt.Star || ctx == nil || ctx.Schema == nil {
+		return false
+	}
+	for _, col := range stmt.Columns {
+		// the columns of a star are in FROM order
+		if col.Star {
+			return false
+		}
+	}
+
+	srcs := make([]*joinSource, len(stmt.From))
+	pool := make([]expr.Node, 0)
+	for i, from := range stmt.From {
+		if from.SubQuery != nil || from.Source != nil {
+			return false
+		}
+		if i > 0 {
+			left, right := from.OuterJoin()
+			if left || right || from.JoinExpr == nil {
+				return false
+			}
+			pool = append(pool, conjuncts(from.JoinExpr)...)
+		}
+		tbl, err := ctx.Table(from.SourceName())
+		if err != nil || tbl == nil {
+			return false
+		}
+		stats := tbl.Stats()
+		if stats == nil {
+			return false
+		}
+		js := &joinSource{
+			from:  from,
+			alias: sourceAlias(from),
+			stats: stats,
+			rows:  float64(stats.RowCt),
+		}
+		if stmt.Where != nil {
+			for _, n := range conjuncts(stmt.Where.Expr) {
+				if aliases, ok := nodeAliases(n); ok && len(aliases) == 1 && aliases[js.alias] {
+					js.rows *= selectivity(stats, n)
+				}
+			}
+		}
+		srcs[i] = js
+	}
+
+	poolAliases := make([]map[string]bool, len(pool))
+	for i, n := range pool {
+		aliases, ok := nodeAliases(n)
+		if !ok {
+			return false
+		}
+		poolAliases[i] = aliases
+	}
+
+	first := srcs[0]
+	for _, js := range srcs[1:] {
+		if js.rows < first.rows {
+			first = js
+		}
+	}
+	order := []*joinSource{first}
+	joinExprs := [][]expr.Node{nil}
+	placed := map[string]bool{first.alias: true}
+	used := make([]bool, len(pool))
+	cur := first.rows
+
+	for len(order) < len(srcs) {
+		last := order[len(order)-1]
+		var best *joinSource
+		var bestConj []int
+		bestRows := 0.0
+	candidates:
+		for _, js := range srcs {
+			if placed[js.alias] {
+				continue
+			}
+			conj := make([]int, 0)
+			for i, aliases := range poolAliases {
+				if used[i] || !aliases[js.alias] {
+					continue
+				}
+				for alias := range aliases {
+					// a join to a source before the last could never be applied
+					if alias != last.alias && placed[alias] {
+						continue candidates
+					}
+				}
+				if onlyAliases(aliases, js.alias, last.alias) {
+					conj = append(conj, i)
+				}
+			}
+			if len(conj) == 0 {
+				continue
+			}
+			rows := cur * js.rows / math.Max(1, joinDistinct(pool, conj, last, js, cur))
+			if best == nil || rows < bestRows || (rows == bestRows && js.rows < best.rows) {
+				best, bestConj, bestRows = js, conj, rows
+			}
+		}
+		if best == nil {
+			return false
+		}
+		nodes := make([]expr.Node, len(bestConj))
+		for i, ci := range bestConj {
+			used[ci] = true
+			nodes[i] = pool[ci]
+		}
+		order = append(order, best)
+		joinExprs = append(joinExprs, nodes)
+		placed[best.alias] = true
+		cur = bestRows
+	}
+	for _, u := range used {
+		if !u {
+			return false
+		}
+	}
+
+	changed := false
+	for i, js := range order {
+		if js != srcs[i] {
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	joined := stmt.From[1]
+	from := make([]*rel.SqlSource, len(order))
+	for i, js := range order {
+		if i == 0 {
+			js.from.Op, js.from.LeftOrRight, js.from.JoinType = 0, 0, 0
+			js.from.JoinExpr = nil
+		} else {
+			js.from.Op, js.from.LeftOrRight, js.from.JoinType = joined.Op, joined.LeftOrRight, joined.JoinType
+			js.from.JoinExpr = andNodes(joinExprs[i])
+		}
+		from[i] = js.from
+	}
+	stmt.From = from
+	return true
+}
+
+// joinDistinct the estimated number of distinct join key values of
+// the join of the last source to js on the conjuncts conj.
+func joinDistinct(pool []expr.Node, conj []int, last, js *joinSource, cur float64) float64 {
+	ndv := 0.0
+	for _, ci := range conj {
+		bn, ok := pool[ci].(*expr.BinaryNode)
+		if !ok || len(bn.Args) != 2 || (bn.Operator.T != lex.TokenEqual && bn.Operator.T != lex.TokenEqualEqual) {
+			continue
+		}
+		for _, arg := range bn.Args {
+			in, ok := arg.(*expr.IdentityNode)
+			if !ok {
+				continue
+			}
+			left, _, _ := in.LeftRight()
+			stats := last.stats
+			if strings.ToLower(left) == js.alias {
+				stats = js.stats
+			}
+			if cs := columnStats(stats, in); cs != nil && float64(cs.NDV) > ndv {
+				ndv = float64(cs.NDV)
+			}
+		}
+	}
+	if ndv == 0 {
+		// no key stats, assume key to foreign key
+		return math.Min(cur, js.rows)
+	}
+	return ndv
+}
+
+// nodeAliases the source aliases the identities of n are qualified with,
+// false if any are not qualified.
+func nodeAliases(n expr.Node) (map[string]bool, bool) {
+	aliases := make(map[string]bool)
+	for _, in := range expr.FindAllIdentities(n) {
+		left, _, ok := in.LeftRight()
+		if !ok {
+			return nil, false
+		}
+		aliases[strings.ToLower(left)] = true
+	}
+	return aliases, true
+}
+
+func onlyAliases(aliases map[string]bool, a, b string) bool {
+	for alias := range aliases {
+		if alias != a && alias != b {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package plan
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+)
+
+// Cte a named sub-query of a WITH statement, read as a source by the
+// statement.  Read by a single source it is run inline as its own job
+// streaming its rows, read by more than one (or recursive) it is
+// materialized once and its rows shared.
+type Cte struct {
+	Stmt        *rel.SqlCte
+	Cols        []string      // names of the columns of its rows
+	Tbl         *schema.Table // table of its columns, for sources reading it
+	Refs        int           // number of sources of the statement reading it
+	Recursive   bool          // reads its own rows, iterated until no new rows
+	Materialize bool          // run once, rows shared by the sources reading it
+
+	mu   sync.Mutex
+	done bool
+	rows [][]driver.Value
+	err  error
+}
+
+// NewCte a named sub-query with the given column names, read by refs sources.
+func NewCte(stmt *rel.SqlCte, cols []string, refs int) *Cte {
+	tbl := schema.NewTable(stmt.Name)
+	for _, col := range cols {
+		tbl.AddFieldType(col, value.UnknownType)
+	}
+	tbl.SetColumns(cols)
+	return &Cte{Stmt: stmt, Cols: cols, Tbl: tbl, Refs: refs, Materialize: refs > 1}
+}
+
+// NewCteRows a copy of a named sub-query whose rows are already known,
+// ie the working rows of an iteration of a recursive sub-query.
+func NewCteRows(cte *Cte, rows [][]driver.Value) *Cte {
+	return &Cte{Stmt: cte.Stmt, Cols: cte.Cols, Tbl: cte.Tbl, Refs: cte.Refs,
+		Recursive: cte.Recursive, Materialize: true, done: true, rows: rows}
+}
+
+// Rows the materialized rows of the sub-query, run is called once by the
+// first source to read them.
+func (m *Cte) Rows(run func() ([][]driver.Value, error)) ([][]driver.Value, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.done {
+		m.rows, m.err = run()
+		m.done = true
+	}
+	return m.rows, m.err
+}
+
+// Anchor, Step the selects of a recursive sub-query, the anchor that
+// doesn't read its own rows and the step that does.
+func (m *Cte) Anchor() rel.SqlStatement { return m.Stmt.Stmt.(*rel.SqlUnion).Left }
+func (m *Cte) Step() *rel.SqlSelect     { return m.Stmt.Stmt.(*rel.SqlUnion).Right.(*rel.SqlSelect) }
+
+// Distinct does a recursive sub-query drop the duplicate rows, UNION
+// rather than UNION ALL.
+func (m *Cte) Distinct() bool { return !m.Stmt.Stmt.(*rel.SqlUnion).All }
+
+// walkCtes register the WITH sub-queries of stmt in the context, for the
+// sources of stmt (and of later sub-queries) naming them.
+func (m *PlannerDefault) walkCtes(ctes []*rel.SqlCte, stmt rel.SqlStatement) error {
+	if len(ctes) == 0 {
+		return nil
+	}
+	// the sub-queries of a child statement must not leak into ours
+	named := make(map[string]*Cte, len(m.Ctx.Ctes)+len(ctes))
+	for name, cte := range m.Ctx.Ctes {
+		named[name] = cte
+	}
+	m.Ctx.Ctes = named
+
+	for i, sc := range ctes {
+		refs := cteRefs(stmt, sc.Name)
+		for _, later := range ctes[i+1:] {
+			refs += cteRefs(later.Stmt, sc.Name)
+		}
+		recursive := sc.Recursive && cteRefs(sc.Stmt, sc.Name) > 0
+		if recursive {
+			if err := validateRecursiveCte(sc); err != nil {
+				return err
+			}
+		}
+		cols, err := m.cteColumns(sc, recursive)
+		if err != nil {
+			return err
+		}
+		cte := NewCte(sc, cols, refs)
+		cte.Recursive = recursive
+		cte.Materialize = cte.Materialize || recursive
+		named[strings.ToLower(sc.Name)] = cte
+	}
+	return nil
+}
+
+// validateRecursiveCte a recursive sub-query must be the UNION [ALL] of an
+// anchor select not reading its rows and a step select that does.
+func validateRecursiveCte(sc *rel.SqlCte) error {
+	un, ok := sc.Stmt.(*rel.SqlUnion)
+	if ok && un.Op == lex.TokenUnion && len(un.OrderBy) == 0 && un.Limit == 0 {
+		step, isSelect := un.Right.(*rel.SqlSelect)
+		if isSelect && cteRefs(un.Left, sc.Name) == 0 && cteRefs(step, sc.Name) == 1 {
+			if step.IsAggQuery() {
+				return fmt.Errorf("recursive WITH sub-query %q may not aggregate its own rows", sc.Name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("recursive WITH sub-query %q must be a UNION [ALL] of a select not reading %s and a select reading it once",
+		sc.Name, sc.Name)
+}
+
+// cteColumns the names of the columns of a sub-query's rows, its column
+// list else those of its first select.
+func (m *PlannerDefault) cteColumns(sc *rel.SqlCte, recursive bool) ([]string, error) {
+	sels := sc.Selects()
+	first := sels[0]
+	if len(sc.Columns) > 0 {
+		if !first.Star && len(first.Columns) != len(sc.Columns) {
+			return nil, fmt.Errorf("WITH sub-query %q has %d column names but its select has %d columns",
+				sc.Name, len(sc.Columns), len(first.Columns))
+		}
+		return sc.Columns, nil
+	}
+	if !first.Star {
+		return sc.ColumnNames(), nil
+	}
+	if len(first.From) != 1 {
+		return nil, fmt.Errorf("WITH sub-query %q must name its columns to SELECT * from more than one source", sc.Name)
+	}
+	cols := make([]string, 0, len(first.Columns))
+	for _, col := range first.Columns {
+		if !col.Star {
+			cols = append(cols, col.As)
+			continue
+		}
+		tbl, err := m.Ctx.Table(first.From[0].SourceName())
+		if err != nil || tbl == nil {
+			return nil, fmt.Errorf("WITH sub-query %q could not find columns of %q", sc.Name, first.From[0].SourceName())
+		}
+		cols = append(cols, tbl.Columns()...)
+	}
+	return cols, nil
+}
+
+// cteRefs the number of sources of stmt, and its sub-queries, reading
+// the named sub-query.
+func cteRefs(stmt rel.SqlStatement, name string) int {
+	var sels []*rel.SqlSelect
+	switch st := stmt.(type) {
+	case *rel.SqlSelect:
+		sels = []*rel.SqlSelect{st}
+	case *rel.SqlUnion:
+		sels = st.Selects()
+	}
+	refs := 0
+	for _, sel := range sels {
+		for _, from := range sel.From {
+			if from.SubQuery != nil {
+				refs += cteRefs(from.SubQuery, name)
+			} else if from.Schema == "" && strings.EqualFold(from.Name, name) {
+				refs++
+			}
+		}
+		if sel.Where != nil && sel.Where.Expr != nil {
+			for _, sq := range rel.SubQueries(sel.Where.Expr) {
+				refs += cteRefs(sq.Select, name)
+			}
+		}
+	}
+	return refs
+}
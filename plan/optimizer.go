@@ -0,0 +1,751 @@
+package plan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+	"github.com/araddon/qlbridge/vm"
+)
+
+var (
+	// Ensure our rules implement Rule interface
+	_ Rule = (*FoldConstants)(nil)
+	_ Rule = (*PushDownPredicates)(nil)
+	_ Rule = (*PruneColumns)(nil)
+	_ Rule = (*EliminateProjections)(nil)
+
+	rulesMu sync.Mutex
+	rules   []Rule
+)
+
+// optimizerMaxPasses the max number of passes over the rules, rules
+// are re-applied until none of them change the plan.
+const optimizerMaxPasses = 10
+
+// Rule is an optimizer rule, it rewrites the plan dag of a select in
+// place.  Apply returns true if it changed the plan.
+type Rule interface {
+	Name() string
+	Apply(ctx *Context, p *Select) (bool, error)
+}
+
+// Optimizer is a rule based optimizer over the plan dag of a select,
+// run by the default planner once the select has been planned.
+type Optimizer struct {
+	Rules []Rule
+}
+
+// RegisterRule adds a Rule to the rules of optimizers created after, in
+// addition to the default rules.
+func RegisterRule(r Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules = append(rules, r)
+}
+
+// DefaultRules the built in rules followed by the registered rules.
+func DefaultRules() []Rule {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rs := []Rule{
+		&FoldConstants{},
+		&PushDownPredicates{},
+		&PruneColumns{},
+		&EliminateProjections{},
+	}
+	return append(rs, rules...)
+}
+
+// NewOptimizer create an optimizer applying rules in order.
+func NewOptimizer(rules ...Rule) *Optimizer {
+	return &Optimizer{Rules: rules}
+}
+
+// Optimize apply the rules to the select plan until none of them change it.
+func (m *Optimizer) Optimize(ctx *Context, p *Select) error {
+	for pass := 0; pass < optimizerMaxPasses; pass++ {
+		changed := false
+		for _, r := range m.Rules {
+			ruleChanged, err := r.Apply(ctx, p)
+			if err != nil {
+				return fmt.Errorf("optimizer rule %s: %v", r.Name(), err)
+			}
+			if ruleChanged {
+				//u.Debugf("optimizer rule %s changed plan", r.Name())
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+	u.Debugf("optimizer did not converge after %d passes", optimizerMaxPasses)
+	return nil
+}
+
+// FoldConstants evaluates the constant expressions of where and having
+// filters at plan time, ie "price > 10 * 2" becomes "price > 20", and
+// removes filters that are always true.
+type FoldConstants struct{}
+
+// Name of rule.
+func (m *FoldConstants) Name() string { return "fold-constants" }
+
+// Apply fold constants on the where and having filters of the select.
+func (m *FoldConstants) Apply(ctx *Context, p *Select) (bool, error) {
+	changed := false
+	walkTasks(p, func(parent *PlanBase, t Task) {
+		var stmt *rel.SqlSelect
+		var node *expr.Node
+		switch tt := t.(type) {
+		case *Where:
+			stmt = tt.Stmt
+			if stmt != nil && stmt.Where != nil {
+				node = &stmt.Where.Expr
+			}
+		case *Having:
+			stmt = tt.Stmt
+			if stmt != nil {
+				node = &stmt.Having
+			}
+		}
+		if node == nil || *node == nil {
+			return
+		}
+		folded, ok := foldNode(*node)
+		if ok {
+			*node = folded
+			changed = true
+		}
+		if isBoolLiteral(folded, true) && parent != nil && parent.remove(t) {
+			changed = true
+		}
+	})
+	return changed, nil
+}
+
+// PushDownPredicates moves the conjuncts of the where of a join that
+// only reference the columns of one source below the join into that
+// sources where, and removes filters already applied by a source.
+type PushDownPredicates struct{}
+
+// Name of rule.
+func (m *PushDownPredicates) Name() string { return "push-down-predicates" }
+
+// Apply push the post-join where conjuncts to the sources.
+func (m *PushDownPredicates) Apply(ctx *Context, p *Select) (bool, error) {
+	if p.Stmt == nil || p.Stmt.Where == nil || p.Stmt.Where.Expr == nil {
+		return false, nil
+	}
+	where := finalWhere(p)
+	if where == nil {
+		return false, nil
+	}
+
+	sources := selectSources(p)
+	if len(p.Stmt.From) == 1 {
+		// The where of a single source is applied by the source itself
+		// unless the source planned it, no need to re-apply it.
+		for _, src := range sources {
+			if sw := sourceWhere(src); sw != nil && sw.Stmt.Where == p.Stmt.Where {
+				return p.remove(where), nil
+			}
+		}
+		return false, nil
+	}
+
+	changed := false
+	remaining := make([]expr.Node, 0)
+	for _, n := range conjuncts(p.Stmt.Where.Expr) {
+		src, alias := conjunctSource(p, sources, n)
+		if src == nil {
+			remaining = append(remaining, n)
+			continue
+		}
+		n = unqualify(n, alias)
+		sub := src.Stmt.Source
+		if sub.Where == nil || sub.Where.Expr == nil {
+			sub.Where = &rel.SqlWhere{Expr: n}
+		} else if !hasConjunct(sub.Where.Expr, n) {
+			sub.Where.Expr = andNode(sub.Where.Expr, n)
+		}
+		if sourceWhere(src) == nil {
+			src.insert(0, NewWhere(sub))
+		}
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	// the pushed filters change the estimated rows of the join inputs
+	walkTasks(p, func(parent *PlanBase, t Task) {
+		if jm, ok := t.(*JoinMerge); ok {
+			jm.chooseBuildSide()
+		}
+	})
+	if len(remaining) == 0 {
+		p.remove(where)
+		p.Stmt.Where = nil
+		return true, nil
+	}
+	p.Stmt.Where.Expr = andNodes(remaining)
+	return true, nil
+}
+
+// PruneColumns removes the columns a join source only read for the
+// post-join where, once that where no longer needs them.
+type PruneColumns struct{}
+
+// Name of rule.
+func (m *PruneColumns) Name() string { return "prune-columns" }
+
+// Apply prune the unused columns of the join sources.
+func (m *PruneColumns) Apply(ctx *Context, p *Select) (bool, error) {
+	if p.Stmt == nil || p.Stmt.Star || len(p.Stmt.From) < 2 {
+		return false, nil
+	}
+	needed := neededIdentities(p.Stmt)
+	parentCt := len(p.Stmt.Columns)
+
+	changed := false
+	sources := selectSources(p)
+	for _, src := range sources {
+		if !plannedByDefault(src) || src.Stmt.Source == nil {
+			continue
+		}
+		alias := sourceAlias(src.Stmt)
+		sub := src.Stmt.Source
+		cols := make(rel.Columns, 0, len(sub.Columns))
+		for _, col := range sub.Columns {
+			_, isIdent := col.Expr.(*expr.IdentityNode)
+			if isIdent && col.ParentIndex >= parentCt && !needed[alias+"."+col.Key()] && !needed[col.Key()] {
+				//u.Debugf("pruning %s.%s", alias, col.Key())
+				continue
+			}
+			cols = append(cols, col)
+		}
+		if len(cols) == len(sub.Columns) {
+			continue
+		}
+		for i, col := range cols {
+			col.Index = i
+		}
+		sub.Columns = cols
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+
+	// Compact the parent row positions of the columns beyond the parent
+	// projection and re-index the joins on them.
+	pos := make(map[int]int)
+	next := parentCt
+	for _, src := range sources {
+		if src.Stmt == nil || src.Stmt.Source == nil {
+			continue
+		}
+		for _, col := range src.Stmt.Source.Columns {
+			if col.ParentIndex < parentCt {
+				continue
+			}
+			if _, ok := pos[col.ParentIndex]; !ok {
+				pos[col.ParentIndex] = next
+				next++
+			}
+			col.ParentIndex = pos[col.ParentIndex]
+		}
+	}
+	walkTasks(p, func(parent *PlanBase, t Task) {
+		if jm, ok := t.(*JoinMerge); ok {
+			jm.buildColIndex()
+		}
+	})
+	return true, nil
+}
+
+// EliminateProjections removes the in-process projection of a join
+// source that selects every column of its table in table order, as the
+// source rows are already in that shape.
+type EliminateProjections struct{}
+
+// Name of rule.
+func (m *EliminateProjections) Name() string { return "eliminate-projections" }
+
+// Apply remove the identity projections of the sources.
+func (m *EliminateProjections) Apply(ctx *Context, p *Select) (bool, error) {
+	changed := false
+	for _, src := range selectSources(p) {
+		if src.Final || !plannedByDefault(src) || src.Tbl == nil || src.Stmt.Source == nil {
+			continue
+		}
+		var proj *Projection
+		hasJoinKey := false
+		for _, t := range src.Children() {
+			switch tt := t.(type) {
+			case *Projection:
+				proj = tt
+			case *JoinKey:
+				hasJoinKey = true
+			}
+		}
+		// the join key converts full table rows for the join
+		if proj == nil || !hasJoinKey || !identityProjection(src.Stmt.Source.Columns, src.Tbl) {
+			continue
+		}
+		if src.remove(proj) {
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// identityProjection are these columns each column of the table in order.
+func identityProjection(cols rel.Columns, tbl *schema.Table) bool {
+	tblCols := tbl.Columns()
+	if len(cols) != len(tblCols) {
+		return false
+	}
+	for i, col := range cols {
+		in, ok := col.Expr.(*expr.IdentityNode)
+		if !ok || col.Star || col.Guard != nil || col.Index != i {
+			return false
+		}
+		name := strings.ToLower(tblCols[i])
+		if strings.ToLower(in.Text) != name || strings.ToLower(col.Key()) != name {
+			return false
+		}
+		if pos, ok := tbl.FieldPositions[tblCols[i]]; ok && pos != i {
+			return false
+		}
+	}
+	return true
+}
+
+// walkTasks call fn for each task of the dag below t with the PlanBase
+// holding it, which is nil for the inputs of a join or partition scan.
+func walkTasks(t Task, fn func(parent *PlanBase, t Task)) {
+	var base *PlanBase
+	switch tt := t.(type) {
+	case *Select:
+		base = tt.PlanBase
+	case *Source:
+		base = tt.PlanBase
+	case *JoinMerge:
+		for _, in := range []Task{tt.Left, tt.Right} {
+			fn(nil, in)
+			walkTasks(in, fn)
+		}
+	case *PartitionScan:
+		for _, part := range tt.Parts {
+			fn(nil, part)
+			walkTasks(part, fn)
+		}
+	}
+	if base == nil {
+		return
+	}
+	// copy, fn may remove tasks
+	children := append([]Task(nil), base.Children()...)
+	for _, c := range children {
+		fn(base, c)
+		walkTasks(c, fn)
+	}
+}
+
+// selectSources the sources of the select, including those of its joins.
+func selectSources(p *Select) []*Source {
+	sources := make([]*Source, 0, len(p.Stmt.From))
+	walkTasks(p, func(parent *PlanBase, t Task) {
+		if src, ok := t.(*Source); ok {
+			sources = append(sources, src)
+		}
+	})
+	return sources
+}
+
+// plannedByDefault is the source planned by the default planner, ie its
+// where and projection are in-process tasks we may rewrite.
+func plannedByDefault(src *Source) bool {
+	if src.Stmt == nil || src.SourceExec || src.Complete {
+		return false
+	}
+	if _, ok := src.Conn.(SourcePlanner); ok {
+		return false
+	}
+	for _, t := range src.Children() {
+		if _, ok := t.(*Projection); ok {
+			return true
+		}
+	}
+	return src.Final
+}
+
+// sourceWhere the where task of the source, nil if none.
+func sourceWhere(src *Source) *Where {
+	for _, t := range src.Children() {
+		if w, ok := t.(*Where); ok && w.Stmt != nil && w.Stmt.Where != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// finalWhere the where task applied to the joined rows of the select.
+func finalWhere(p *Select) *Where {
+	for _, t := range p.Children() {
+		if w, ok := t.(*Where); ok && w.Stmt == p.Stmt {
+			return w
+		}
+	}
+	return nil
+}
+
+func sourceAlias(from *rel.SqlSource) string {
+	if from.Alias != "" {
+		return strings.ToLower(from.Alias)
+	}
+	return strings.ToLower(from.Name)
+}
+
+// conjunctSource the source, and its alias, whose columns are the only
+// ones the conjunct references if it may be evaluated below the join.
+func conjunctSource(p *Select, sources []*Source, n expr.Node) (*Source, string) {
+	if !pushable(n) {
+		return nil, ""
+	}
+	alias := ""
+	idents := expr.FindAllIdentities(n)
+	if len(idents) == 0 {
+		return nil, ""
+	}
+	for _, in := range idents {
+		left, _, ok := in.LeftRight()
+		if !ok {
+			return nil, ""
+		}
+		left = strings.ToLower(left)
+		if alias != "" && left != alias {
+			return nil, ""
+		}
+		alias = left
+	}
+	for _, src := range sources {
+		if src.Stmt == nil || sourceAlias(src.Stmt) != alias {
+			continue
+		}
+		// filtering the null-supplying side of an outer join before the
+		// join changes which rows are unmatched
+		if !plannedByDefault(src) || src.Stmt.Source == nil || src.Stmt.NullableIn(p.Stmt) {
+			return nil, ""
+		}
+		return src, alias
+	}
+	return nil, ""
+}
+
+// pushable may the expression be evaluated against a source row, ie
+// has no sub-queries or other nodes needing the full row.
+func pushable(n expr.Node) bool {
+	switch nt := n.(type) {
+	case *expr.IdentityNode, *expr.NumberNode, *expr.StringNode, *expr.ValueNode, *expr.NullNode:
+		return true
+	case *expr.BinaryNode:
+		return allPushable(nt.Args)
+	case *expr.BooleanNode:
+		return allPushable(nt.Args)
+	case *expr.TriNode:
+		return allPushable(nt.Args)
+	case *expr.FuncNode:
+		return allPushable(nt.Args)
+	case *expr.ArrayNode:
+		return allPushable(nt.Args)
+	case *expr.CaseNode:
+		return allPushable(nt.ChildrenArgs())
+	case *expr.UnaryNode:
+		return pushable(nt.Arg)
+	}
+	return false
+}
+
+func allPushable(args []expr.Node) bool {
+	for _, arg := range args {
+		if !pushable(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// unqualify remove the alias of the identities of n, in place.
+func unqualify(n expr.Node, alias string) expr.Node {
+	switch nt := n.(type) {
+	case *expr.IdentityNode:
+		if left, right, ok := nt.LeftRight(); ok && strings.ToLower(left) == alias {
+			return &expr.IdentityNode{Text: right}
+		}
+	case *expr.BinaryNode:
+		unqualifyArgs(nt.Args, alias)
+	case *expr.BooleanNode:
+		unqualifyArgs(nt.Args, alias)
+	case *expr.TriNode:
+		unqualifyArgs(nt.Args, alias)
+	case *expr.FuncNode:
+		unqualifyArgs(nt.Args, alias)
+	case *expr.ArrayNode:
+		unqualifyArgs(nt.Args, alias)
+	case *expr.CaseNode:
+		if nt.Operand != nil {
+			nt.Operand = unqualify(nt.Operand, alias)
+		}
+		unqualifyArgs(nt.Whens, alias)
+		unqualifyArgs(nt.Thens, alias)
+		if nt.Else != nil {
+			nt.Else = unqualify(nt.Else, alias)
+		}
+	case *expr.UnaryNode:
+		nt.Arg = unqualify(nt.Arg, alias)
+	}
+	return n
+}
+
+func unqualifyArgs(args []expr.Node, alias string) {
+	for i, arg := range args {
+		args[i] = unqualify(arg, alias)
+	}
+}
+
+// neededIdentities the identities, alias qualified or not, that the
+// select reads from the joined rows.
+func neededIdentities(stmt *rel.SqlSelect) map[string]bool {
+	nodes := make([]expr.Node, 0)
+	for _, col := range stmt.Columns {
+		nodes = append(nodes, col.Expr, col.Guard)
+		if col.Over != nil {
+			for _, wc := range append(col.Over.PartitionBy, col.Over.OrderBy...) {
+				nodes = append(nodes, wc.Expr)
+			}
+		}
+	}
+	for _, col := range stmt.GroupBy {
+		nodes = append(nodes, col.Expr)
+	}
+	for _, col := range stmt.OrderBy {
+		nodes = append(nodes, col.Expr)
+	}
+	for _, from := range stmt.From {
+		nodes = append(nodes, from.JoinExpr)
+	}
+	if stmt.Where != nil {
+		nodes = append(nodes, stmt.Where.Expr)
+		for _, sq := range rel.SubQueries(stmt.Where.Expr) {
+			nodes = append(nodes, sq.Outer...)
+		}
+	}
+	nodes = append(nodes, stmt.Having)
+
+	needed := make(map[string]bool)
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		for _, in := range expr.FindAllIdentities(n) {
+			left, right, ok := in.LeftRight()
+			if ok {
+				needed[strings.ToLower(left)+"."+right] = true
+			} else {
+				needed[in.Text] = true
+			}
+		}
+	}
+	return needed
+}
+
+// conjuncts split the expression into its AND'd parts.
+func conjuncts(n expr.Node) []expr.Node {
+	if bn, ok := n.(*expr.BinaryNode); ok && isAnd(bn.Operator.T) && len(bn.Args) == 2 {
+		return append(conjuncts(bn.Args[0]), conjuncts(bn.Args[1])...)
+	}
+	return []expr.Node{n}
+}
+
+func isAnd(t lex.TokenType) bool {
+	return t == lex.TokenLogicAnd || t == lex.TokenAnd
+}
+
+func hasConjunct(n, c expr.Node) bool {
+	s := c.String()
+	for _, cn := range conjuncts(n) {
+		if cn.String() == s {
+			return true
+		}
+	}
+	return false
+}
+
+func andNode(l, r expr.Node) expr.Node {
+	return &expr.BinaryNode{
+		Operator: lex.Token{T: lex.TokenLogicAnd, V: "AND"},
+		Args:     []expr.Node{l, r},
+	}
+}
+
+func andNodes(nodes []expr.Node) expr.Node {
+	n := nodes[0]
+	for _, r := range nodes[1:] {
+		n = andNode(n, r)
+	}
+	return n
+}
+
+// foldNode evaluate the constant parts of n, in place, returns the
+// folded node and true if anything was folded.
+func foldNode(n expr.Node) (expr.Node, bool) {
+	changed := false
+	switch nt := n.(type) {
+	case *expr.BinaryNode:
+		changed = foldArgs(nt.Args)
+		if isAnd(nt.Operator.T) || nt.Operator.T == lex.TokenLogicOr || nt.Operator.T == lex.TokenOr {
+			if s, ok := simplifyLogic(nt); ok {
+				return s, true
+			}
+		}
+	case *expr.TriNode:
+		changed = foldArgs(nt.Args)
+	case *expr.UnaryNode:
+		if f, ok := foldNode(nt.Arg); ok {
+			nt.Arg = f
+			changed = true
+		}
+	case *expr.BooleanNode:
+		return n, foldArgs(nt.Args)
+	case *expr.FuncNode:
+		return n, foldArgs(nt.Args)
+	case *expr.ArrayNode:
+		return n, foldArgs(nt.Args)
+	case *expr.CaseNode:
+		changed = foldArgs(nt.Whens)
+		if foldArgs(nt.Thens) {
+			changed = true
+		}
+		if nt.Else != nil {
+			if f, ok := foldNode(nt.Else); ok {
+				nt.Else = f
+				changed = true
+			}
+		}
+		return n, changed
+	default:
+		return n, false
+	}
+	if !isConstant(n) {
+		return n, changed
+	}
+	v, ok := vm.Eval(nil, n)
+	if !ok || v == nil {
+		return n, changed
+	}
+	if lit := literalNode(v); lit != nil {
+		return lit, true
+	}
+	return n, changed
+}
+
+func foldArgs(args []expr.Node) bool {
+	changed := false
+	for i, arg := range args {
+		if f, ok := foldNode(arg); ok {
+			args[i] = f
+			changed = true
+		}
+	}
+	return changed
+}
+
+// simplifyLogic an AND/OR with a literal bool side.
+func simplifyLogic(n *expr.BinaryNode) (expr.Node, bool) {
+	if len(n.Args) != 2 {
+		return nil, false
+	}
+	isOr := n.Operator.T == lex.TokenLogicOr || n.Operator.T == lex.TokenOr
+	for i, arg := range n.Args {
+		other := n.Args[1-i]
+		switch {
+		case isBoolLiteral(arg, !isOr):
+			// x AND true, x OR false
+			return other, true
+		case isBoolLiteral(arg, isOr):
+			// x AND false, x OR true
+			return arg, true
+		}
+	}
+	return nil, false
+}
+
+// isConstant an operator over literals.
+func isConstant(n expr.Node) bool {
+	var args []expr.Node
+	switch nt := n.(type) {
+	case *expr.BinaryNode:
+		args = nt.Args
+	case *expr.TriNode:
+		args = nt.Args
+	case *expr.UnaryNode:
+		args = []expr.Node{nt.Arg}
+	default:
+		return false
+	}
+	for _, arg := range args {
+		if !isLiteral(arg) {
+			return false
+		}
+	}
+	return len(args) > 0
+}
+
+func isLiteral(n expr.Node) bool {
+	switch nt := n.(type) {
+	case *expr.NumberNode, *expr.StringNode:
+		return true
+	case *expr.ValueNode:
+		return nt.Value != nil
+	case *expr.IdentityNode:
+		return nt.IsBooleanIdentity()
+	}
+	return false
+}
+
+// isBoolLiteral is n the literal true, or false, as parsed and folded.
+func isBoolLiteral(n expr.Node, b bool) bool {
+	in, ok := n.(*expr.IdentityNode)
+	return ok && in.IsBooleanIdentity() && in.Bool() == b
+}
+
+// literalNode the literal expression of v, nil if it has none.  Bools
+// are the true/false identities the parser produces, which vm evaluates.
+func literalNode(v value.Value) expr.Node {
+	switch vt := v.(type) {
+	case value.BoolValue:
+		return expr.NewIdentityNodeVal(strconv.FormatBool(vt.Val()))
+	case value.IntValue:
+		if nn, err := expr.NewNumberStr(strconv.FormatInt(vt.Val(), 10)); err == nil {
+			return nn
+		}
+	case value.NumberValue:
+		if nn, err := expr.NewNumberStr(strconv.FormatFloat(vt.Val(), 'f', -1, 64)); err == nil {
+			return nn
+		}
+	case value.StringValue:
+		return expr.NewStringNode(vt.Val())
+	}
+	return nil
+}
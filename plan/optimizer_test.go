@@ -0,0 +1,179 @@
+package plan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/datasource/mockcsv"
+	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+)
+
+// countRule counts the selects it is applied to, never changing them.
+type countRule struct {
+	ct int
+}
+
+func (m *countRule) Name() string { return "count" }
+func (m *countRule) Apply(ctx *plan.Context, p *plan.Select) (bool, error) {
+	m.ct++
+	return false, nil
+}
+
+func findSource(p *plan.Select, alias string) *plan.Source {
+	var found *plan.Source
+	var walk func(t plan.Task)
+	walk = func(t plan.Task) {
+		switch tt := t.(type) {
+		case *plan.Source:
+			if tt.Stmt.Alias == alias {
+				found = tt
+			}
+		case *plan.JoinMerge:
+			walk(tt.Left)
+			walk(tt.Right)
+		}
+		for _, c := range t.Children() {
+			walk(c)
+		}
+	}
+	walk(p)
+	return found
+}
+
+func hasTask(tasks []plan.Task, match func(t plan.Task) bool) bool {
+	for _, t := range tasks {
+		if match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWhere(t plan.Task) bool {
+	_, ok := t.(*plan.Where)
+	return ok
+}
+
+func hasColumn(cols rel.Columns, key string) bool {
+	for _, col := range cols {
+		if col.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOptimizerPushDown(t *testing.T) {
+	// both conjuncts are pushed to their sources, including the < and the
+	// folded constant which the source rewrite doesn't push itself
+	p := selectPlan(t, td.TestContext(`SELECT u.email, o.item_id FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.price < 30 AND u.referral_count > 10 * 2`))
+	assert.Nil(t, p.Stmt.Where)
+	assert.False(t, hasTask(p.Children(), isWhere))
+
+	orders := findSource(p, "o")
+	assert.NotNil(t, orders)
+	assert.True(t, hasTask(orders.Children(), isWhere))
+	assert.Equal(t, "price < 30", orders.Stmt.Source.Where.Expr.String())
+	users := findSource(p, "u")
+	assert.NotNil(t, users)
+	assert.True(t, hasTask(users.Children(), isWhere))
+	assert.Equal(t, "referral_count > 20", users.Stmt.Source.Where.Expr.String())
+
+	// the where only columns are no longer read from the sources
+	assert.False(t, hasColumn(orders.Stmt.Source.Columns, "price"))
+	assert.False(t, hasColumn(users.Stmt.Source.Columns, "referral_count"))
+	jm := findJoinMerge(p)
+	assert.NotNil(t, jm)
+	_, hasPrice := jm.ColIndex["o.price"]
+	assert.False(t, hasPrice)
+	for key, idx := range jm.ColIndex {
+		assert.True(t, idx < len(jm.ColIndex), "%s out of range %d", key, idx)
+	}
+
+	// the null-supplying side of an outer join filters after the join
+	p = selectPlan(t, td.TestContext(`SELECT u.email, o.item_id FROM users AS u
+		LEFT JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.price < 30 AND u.referral_count > 20`))
+	assert.True(t, hasTask(p.Children(), isWhere))
+	assert.Equal(t, "o.price < 30", p.Stmt.Where.Expr.String())
+	assert.Equal(t, "referral_count > 20", findSource(p, "u").Stmt.Source.Where.Expr.String())
+	assert.True(t, hasColumn(findSource(p, "o").Stmt.Source.Columns, "price"))
+
+	// sub-queries are evaluated after the join
+	p = selectPlan(t, td.TestContext(`SELECT u.email, o.item_id FROM users AS u
+		INNER JOIN orders AS o ON u.user_id = o.user_id
+		WHERE o.item_id IN (SELECT item_id FROM orders WHERE price > 30)`))
+	assert.True(t, hasTask(p.Children(), isWhere))
+}
+
+func TestOptimizerProjections(t *testing.T) {
+	mockcsv.LoadTable(mockcsv.SchemaName, "optpairs", "id,user_id\n1,9Ip1aKbeZe2njCDM")
+
+	isProjection := func(t plan.Task) bool {
+		_, ok := t.(*plan.Projection)
+		return ok
+	}
+	// every column of optpairs in table order, its rows need no projection
+	p := selectPlan(t, td.TestContext(`SELECT s.id, s.user_id, u.email FROM optpairs AS s
+		INNER JOIN users AS u ON s.user_id = u.user_id`))
+	assert.False(t, hasTask(findSource(p, "s").Children(), isProjection))
+	assert.True(t, hasTask(findSource(p, "u").Children(), isProjection))
+
+	p = selectPlan(t, td.TestContext(`SELECT s.user_id, s.id, u.email FROM optpairs AS s
+		INNER JOIN users AS u ON s.user_id = u.user_id`))
+	assert.True(t, hasTask(findSource(p, "s").Children(), isProjection))
+}
+
+func TestOptimizerSingleSource(t *testing.T) {
+	// the source applies the where, it isn't re-applied
+	p := selectPlan(t, td.TestContext("SELECT user_id FROM orders WHERE price > 30"))
+	assert.False(t, hasTask(p.Children(), isWhere))
+	src := findSource(p, "")
+	assert.NotNil(t, src)
+	assert.True(t, hasTask(src.Children(), isWhere))
+
+	// always true filters are removed
+	p = selectPlan(t, td.TestContext("SELECT user_id FROM orders WHERE 2 > 1 + 0"))
+	assert.False(t, hasTask(p.Children(), isWhere))
+	assert.False(t, hasTask(findSource(p, "").Children(), isWhere))
+
+	p = selectPlan(t, td.TestContext(`SELECT user_id FROM orders WHERE price > 30 OR 1 = 2`))
+	assert.Equal(t, "price > 30", p.Stmt.Where.Expr.String())
+
+	// always false filters are the false literal
+	p = selectPlan(t, td.TestContext(`SELECT user_id FROM orders WHERE price > 30 AND 1 = 2`))
+	assert.Equal(t, "false", p.Stmt.Where.Expr.String())
+}
+
+func TestOptimizerRules(t *testing.T) {
+	rule := &countRule{}
+	plan.RegisterRule(rule)
+	rules := plan.DefaultRules()
+	assert.Equal(t, rule, rules[len(rules)-1])
+
+	ctx := td.TestContext("SELECT user_id FROM orders WHERE price > 30")
+	stmt, err := rel.ParseSql(ctx.Raw)
+	assert.Equal(t, nil, err)
+	ctx.Stmt = stmt
+	planner := plan.NewPlanner(ctx)
+	_, err = plan.WalkStmt(ctx, stmt, planner)
+	assert.Equal(t, nil, err)
+	// no rule changed the plan on the second pass
+	assert.Equal(t, 2, rule.ct)
+
+	// without an optimizer the plan is left as is
+	ctx = td.TestContext("SELECT user_id FROM orders WHERE price > 30")
+	ctx.Stmt, _ = rel.ParseSql(ctx.Raw)
+	planner = plan.NewPlanner(ctx)
+	planner.Optimizer = nil
+	pln, err := plan.WalkStmt(ctx, ctx.Stmt, planner)
+	assert.Equal(t, nil, err)
+	assert.True(t, hasTask(pln.Children(), isWhere))
+	assert.True(t, strings.Contains(pln.(*plan.Select).Stmt.Where.Expr.String(), "price > 30"))
+}
@@ -12,6 +12,8 @@ import (
 	u "github.com/araddon/gou"
 	"github.com/golang/protobuf/proto"
 
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
@@ -32,6 +34,7 @@ var (
 	_ Task = (*Update)(nil)
 	_ Task = (*Delete)(nil)
 	_ Task = (*Command)(nil)
+	_ Task = (*Explain)(nil)
 	_ Task = (*Create)(nil)
 	_ Task = (*Projection)(nil)
 	_ Task = (*Source)(nil)
@@ -40,6 +43,8 @@ var (
 	_ Task = (*Having)(nil)
 	_ Task = (*GroupBy)(nil)
 	_ Task = (*Order)(nil)
+	_ Task = (*Window)(nil)
+	_ Task = (*Distinct)(nil)
 	_ Task = (*JoinMerge)(nil)
 	_ Task = (*JoinKey)(nil)
 
@@ -113,6 +118,18 @@ type (
 		WalkAlter(p *Alter) error
 	}
 
+	// UnionPlanner Planners that can plan set operations (UNION, INTERSECT,
+	// EXCEPT) of selects, optional so as to not break existing Planners.
+	UnionPlanner interface {
+		WalkUnion(p *Union) error
+	}
+
+	// ExplainPlanner Planners that can plan EXPLAIN of a statement, optional
+	// so as to not break existing Planners.
+	ExplainPlanner interface {
+		WalkExplain(p *Explain) error
+	}
+
 	// SourcePlanner Sources can often do their own planning for sub-select statements
 	// ie mysql can do its own (select, projection) mongo, es can as well
 	// - provide interface to allow passing down select planning to source
@@ -129,10 +146,13 @@ type (
 		RootTask Task   // Root task
 		tasks    []Task // Children tasks
 	}
-	// PreparedStatement plan
+	// PreparedStatement plan, a statement parsed once and run many
+	// times with values bound to its placeholders
 	PreparedStatement struct {
 		*PlanBase
+		Raw  string // statement text
 		Stmt *rel.PreparedStatement
+		pb   *rel.SqlStatementPb // copied for each run of a select, nil if parsed each run
 	}
 	// Select plan
 	Select struct {
@@ -143,6 +163,24 @@ type (
 		ChildDag bool
 		pbplan   *PlanPb
 	}
+	// Union plan for the set operations (UNION, INTERSECT, EXCEPT) of
+	// selects, a SetOp child combining the selects followed by the order
+	// by and limit of the combined rows.
+	Union struct {
+		*PlanBase
+		Ctx  *Context
+		Stmt *rel.SqlUnion
+	}
+	// SetOp combines the rows of 2 input tasks, each a *Select or *SetOp.
+	SetOp struct {
+		*PlanBase
+		Op    lex.TokenType // TokenUnion, TokenIntersect, TokenExcept
+		All   bool          // keep duplicate rows
+		Left  Task
+		Right Task
+		// ColIndex of the result columns, named by the first select
+		ColIndex map[string]int
+	}
 	// Insert plan
 	Insert struct {
 		*PlanBase
@@ -173,13 +211,21 @@ type (
 		Ctx  *Context
 		Stmt *rel.SqlCommand
 	}
+	// Explain plan for EXPLAIN [ANALYZE] statement, the explained
+	// statement is planned when the explain is run.
+	Explain struct {
+		*PlanBase
+		Ctx  *Context
+		Stmt *rel.SqlDescribe
+	}
 	// Projection holds original query for column info and schema/field types
 	Projection struct {
 		*PlanBase
-		Final bool // Is this final projection or not?
-		P     *Select
-		Stmt  *rel.SqlSelect
-		Proj  *rel.Projection
+		Final     bool // Is this final projection or not?
+		LimitOnly bool // Only apply LIMIT/OFFSET to already projected rows
+		P         *Select
+		Stmt      *rel.SqlSelect
+		Proj      *rel.Projection
 	}
 	// Source defines a source Within a Select query, it optionally has multiple
 	// sources such as sub-select, join, etc this is the plan for a each source
@@ -193,6 +239,11 @@ type (
 		Proj     *rel.Projection // projection for this sub-query
 		ExecPlan Proto           // If SourceExec has a plan?
 		Custom   u.JsonHelper    // Source specific context info
+		// Partition of the source this scans, nil for all of it.
+		Partition *schema.Partition
+		// LimitPushdown the source (a SourcePlanner) applied the select's
+		// LIMIT and OFFSET itself, so OFFSET must not be re-applied.
+		LimitPushdown bool
 
 		// Schema and underlying Source provider info, not serialized or transported
 		ctx        *Context       // query context, shared across all parts of this request
@@ -202,6 +253,7 @@ type (
 		Tbl        *schema.Table  // Table schema for this From
 		Static     []driver.Value // this is static data source
 		Cols       []string
+		Cte        *Cte // WITH sub-query this reads, instead of a table
 	}
 	// Into Select INTO table
 	Into struct {
@@ -212,13 +264,40 @@ type (
 	GroupBy struct {
 		*PlanBase
 		Stmt    *rel.SqlSelect
-		Partial bool
+		Partial bool // emit the partial aggregates of a partition, per group
+		Final   bool // merge the Partial aggregates of each partition
+	}
+	// PartitionScan scan of a source split across its partitions, each
+	// partition a Source (with its where, partial group-by) run in parallel
+	// and their rows merged.
+	//
+	//   partition 0 source -> where -> groupby partial ->
+	//                                                     \
+	//   partition 1 source -> where -> groupby partial ->  -- merge -->
+	//                                                     /
+	//   partition n source -> where -> groupby partial ->
+	//
+	PartitionScan struct {
+		*PlanBase
+		Parts []*Source
 	}
 	// Order By clause
 	Order struct {
 		*PlanBase
 		Stmt *rel.SqlSelect
 	}
+	// Window evaluates the window functions (col OVER (...)) of the
+	// select columns over all of the rows of their partition.
+	Window struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
+	}
+	// Distinct drops the duplicate rows of a SELECT DISTINCT, after they
+	// are projected.
+	Distinct struct {
+		*PlanBase
+		Stmt *rel.SqlSelect
+	}
 	// Where pre-aggregation filter
 	Where struct {
 		*PlanBase
@@ -238,6 +317,19 @@ type (
 		LeftFrom  *rel.SqlSource
 		RightFrom *rel.SqlSource
 		ColIndex  map[string]int
+		// LeftOuter, RightOuter keep unmatched rows from that side
+		// with NULL's for the other sides columns (LEFT, RIGHT, FULL joins)
+		LeftOuter  bool
+		RightOuter bool
+		// BuildLeft build the join hash table from the left input and
+		// stream the right, default is build right stream left.
+		BuildLeft bool
+		// Seek the right rows by key (ConnSeeker) as left rows stream
+		// in, instead of scanning the right source.
+		Seek bool
+		// NestedLoop the join expression has no equality between the
+		// sides to hash on, so every left row is compared to every right.
+		NestedLoop bool
 	}
 	// JoinKey plan
 	JoinKey struct {
@@ -275,6 +367,8 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 	switch st := stmt.(type) {
 	case *rel.SqlSelect:
 		p = &Select{Stmt: st, PlanBase: base, Ctx: ctx}
+	case *rel.SqlUnion:
+		p = &Union{Stmt: st, PlanBase: base, Ctx: ctx}
 	case *rel.SqlInsert:
 		p = &Insert{Stmt: st, PlanBase: base}
 	case *rel.SqlUpsert:
@@ -291,6 +385,10 @@ func WalkStmt(ctx *Context, stmt rel.SqlStatement, planner Planner) (Task, error
 		ctx.Stmt = sel
 		p = &Select{Stmt: sel, PlanBase: base, Ctx: ctx}
 	case *rel.SqlDescribe:
+		if st.Stmt != nil {
+			p = &Explain{Stmt: st, PlanBase: base, Ctx: ctx}
+			break
+		}
 		sel, err := RewriteDescribeAsSelect(st, ctx)
 		if err != nil {
 			return nil, err
@@ -340,6 +438,10 @@ func SelectTaskFromTaskPb(pb *PlanPb, ctx *Context, sel *rel.SqlSelect) (Task, e
 		return GroupByFromPB(pb), nil
 	case pb.Order != nil:
 		return OrderFromPB(pb), nil
+	case pb.Window != nil:
+		return WindowFromPB(pb), nil
+	case pb.Distinct != nil:
+		return DistinctFromPB(pb), nil
 	case pb.Projection != nil:
 		return ProjectionFromPB(pb, sel), nil
 	case pb.JoinMerge != nil:
@@ -359,6 +461,24 @@ func (m *PlanBase) Add(task Task) error {
 	m.tasks = append(m.tasks, task)
 	return nil
 }
+
+// insert task at position i of the children.
+func (m *PlanBase) insert(i int, task Task) {
+	m.tasks = append(m.tasks, nil)
+	copy(m.tasks[i+1:], m.tasks[i:])
+	m.tasks[i] = task
+}
+
+// remove task from the children, false if not a child.
+func (m *PlanBase) remove(task Task) bool {
+	for i, t := range m.tasks {
+		if t == task {
+			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
 func (m *PlanBase) Close() error       { return ErrNotImplemented }
 func (m *PlanBase) Run() error         { return ErrNotImplemented }
 func (m *PlanBase) IsParallel() bool   { return m.parallel }
@@ -414,6 +534,55 @@ func (m *Create) Walk(p Planner) error            { return p.WalkCreate(m) }
 func (m *Drop) Walk(p Planner) error              { return p.WalkDrop(m) }
 func (m *Alter) Walk(p Planner) error             { return p.WalkAlter(m) }
 
+// Walk an explain with a Planner that is an ExplainPlanner.
+func (m *Explain) Walk(p Planner) error {
+	if ep, ok := p.(ExplainPlanner); ok {
+		return ep.WalkExplain(m)
+	}
+	return ErrNotImplemented
+}
+
+// Walk a set operation with a Planner that is a UnionPlanner.
+func (m *Union) Walk(p Planner) error {
+	if up, ok := p.(UnionPlanner); ok {
+		return up.WalkUnion(m)
+	}
+	return ErrNotImplemented
+}
+
+// NewPreparedStatement parse query as a statement to be run with values
+// bound to its placeholders.
+func NewPreparedStatement(query string) (*PreparedStatement, error) {
+	stmt, err := rel.ParseSqlPrepared(query)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedStatement{
+		Raw:      query,
+		Stmt:     stmt,
+		PlanBase: NewPlanBase(false),
+		pb:       rel.StatementToPb(stmt.Statement),
+	}, nil
+}
+
+// NumInput the number of values to bind to the placeholders.
+func (m *PreparedStatement) NumInput() int { return expr.ParamCount(m.Stmt.Params) }
+
+// Context the context to run the statement with params bound to its
+// placeholders.  Selects are not parsed again, the context Stmt is a copy
+// of the prepared statement with the params bound to it.  Others, and
+// params that can not be bound, are parsed when run.
+func (m *PreparedStatement) Context(params *expr.Params) *Context {
+	ctx := NewContext(m.Raw)
+	ctx.Params = params
+	if m.pb != nil {
+		if stmt, err := boundCopy(m.pb, m.Raw, paramBinder(params, nil)); err == nil {
+			ctx.Stmt = stmt
+		}
+	}
+	return ctx
+}
+
 // NewCreate creates a new Create Task plan.
 func NewCreate(ctx *Context, stmt *rel.SqlCreate) *Create {
 	return &Create{Stmt: stmt, PlanBase: NewPlanBase(false), Ctx: ctx}
@@ -454,6 +623,9 @@ func (m *Select) Unmarshal(data []byte) error {
 	return m.pbplan.Unmarshal(data)
 }
 func (m *Select) serializeToPb() error {
+	if err := rel.CheckPb(m.Stmt); err != nil {
+		return err
+	}
 	if m.pbplan == nil {
 		pbp, err := m.PlanBase.ToPb()
 		if err != nil {
@@ -504,11 +676,21 @@ func (m *Select) Equal(t Task) bool {
 	return true
 }
 func (m *Select) NeedsFinalProjection() bool {
-	if m.Stmt.Limit > 0 {
+	if m.Stmt.Limit > 0 || m.Stmt.Offset > 0 {
 		return true
 	}
 	return false
 }
+// Offset the number of rows to skip before the LIMIT, 0 if a source
+// has already applied it.
+func (m *Select) Offset() int {
+	for _, from := range m.From {
+		if from.LimitPushdown {
+			return 0
+		}
+	}
+	return m.Stmt.Offset
+}
 func (m *Select) IsSchemaQuery() bool {
 	// For Single Source statements, lets see if they are switching schema
 	if len(m.From) == 1 {
@@ -588,6 +770,11 @@ func SourceFromPB(pb *PlanPb, ctx *Context) (*Source, error) {
 				u.Errorf("%T not implemented? %v", pbt, err)
 				return nil, err
 			}
+			// a source only has a group-by when it is a partition of a
+			// PartitionScan, whose partials are merged by the select.
+			if gb, ok := childPlan.(*GroupBy); ok {
+				gb.Partial = true
+			}
 			m.tasks[i] = childPlan
 		}
 	}
@@ -597,6 +784,11 @@ func SourceFromPB(pb *PlanPb, ctx *Context) (*Source, error) {
 		u.Errorf("could not load? %v", err)
 		return nil, err
 	}
+	if i, ok := m.Custom.IntSafe("partition"); ok {
+		if parts := sourcePartitions(&m); i < len(parts) {
+			m.Partition = parts[i]
+		}
+	}
 	if m.Conn == nil {
 		err = m.LoadConn()
 		if err != nil {
@@ -661,7 +853,15 @@ func (m *Source) LoadConn() error {
 			return nil
 		}
 	}
-	source, err := m.DataSource.Open(m.Stmt.SourceName())
+	if ps, ok := m.DataSource.(schema.SourcePartitionable); ok && m.Partition != nil {
+		conn, err := ps.PartitionSource(m.Partition)
+		if err != nil {
+			return err
+		}
+		m.Conn = conn
+		return nil
+	}
+	source, err := m.ctx.OpenSource(m.DataSource, m.Stmt.SourceName())
 	if err != nil {
 		u.Debugf("no source? %T for source %q", m.DataSource, m.Stmt.SourceName())
 		return err
@@ -762,6 +962,13 @@ func (m *Source) load() error {
 		return fmt.Errorf("Missing schema for %v", fromName)
 	}
 
+	if cte := m.ctx.Cte(fromName); cte != nil && m.Stmt.SubQuery == nil && m.Stmt.Schema == "" {
+		// rows of a WITH sub-query, run by the executor
+		m.Cte = cte
+		m.Tbl = cte.Tbl
+		return projectionForSourcePlan(m)
+	}
+
 	ss, err := m.ctx.Schema.SchemaForTable(fromName)
 	if err != nil {
 		// u.Debugf("no schema found for %T  %q.%q ? err=%v", m.ctx.Schema, m.Stmt.Schema, fromName, err)
@@ -852,7 +1059,6 @@ func NewJoinMerge(l, r Task, lf, rf *rel.SqlSource) *JoinMerge {
 
 	m := &JoinMerge{
 		PlanBase: NewPlanBase(false),
-		ColIndex: make(map[string]int),
 	}
 	m.SetParallel()
 
@@ -860,20 +1066,48 @@ func NewJoinMerge(l, r Task, lf, rf *rel.SqlSource) *JoinMerge {
 	m.Right = r
 	m.LeftFrom = lf
 	m.RightFrom = rf
+	m.LeftOuter, m.RightOuter = rf.OuterJoin()
+
+	m.chooseBuildSide()
+	m.Seek = isSeekable(l, r, rf) && !m.RightOuter
+	m.NestedLoop = len(rf.JoinNodes()) == 0
 
-	// Build an index of source to destination column indexing
-	for _, col := range lf.Source.Columns {
+	m.buildColIndex()
+	return m
+}
+
+// buildColIndex build an index of source to destination column indexing.
+func (m *JoinMerge) buildColIndex() {
+	m.ColIndex = make(map[string]int)
+	for _, col := range m.LeftFrom.Source.Columns {
 		//u.Debugf("left col:  idx=%d  key=%q as=%q col=%v parentidx=%v", len(m.colIndex), col.Key(), col.As, col.String(), col.ParentIndex)
-		m.ColIndex[lf.Alias+"."+col.Key()] = col.ParentIndex
+		m.ColIndex[m.LeftFrom.Alias+"."+col.Key()] = col.ParentIndex
 		//u.Debugf("left  colIndex:  %15q : idx:%d sidx:%d pidx:%d", m.leftStmt.Alias+"."+col.Key(), col.Index, col.SourceIndex, col.ParentIndex)
 	}
-	for _, col := range rf.Source.Columns {
+	for _, col := range m.RightFrom.Source.Columns {
 		//u.Debugf("right col:  idx=%d  key=%q as=%q col=%v", len(m.colIndex), col.Key(), col.As, col.String())
-		m.ColIndex[rf.Alias+"."+col.Key()] = col.ParentIndex
+		m.ColIndex[m.RightFrom.Alias+"."+col.Key()] = col.ParentIndex
 		//u.Debugf("right colIndex:  %15q : idx:%d sidx:%d pidx:%d", m.rightStmt.Alias+"."+col.Key(), col.Index, col.SourceIndex, col.ParentIndex)
 	}
+}
 
-	return m
+// isSeekable can the right side of join be looked up by key for each left
+// row, ie the right join expression is its tables single column primary key.
+func isSeekable(l, r Task, rf *rel.SqlSource) bool {
+	if _, ok := l.(*Source); !ok {
+		return false
+	}
+	src, ok := r.(*Source)
+	if !ok || src.Tbl == nil {
+		return false
+	}
+	pk := src.Tbl.PrimaryKey()
+	nodes := rf.JoinNodes()
+	if len(pk) != 1 || len(nodes) != 1 {
+		return false
+	}
+	in, ok := nodes[0].(*expr.IdentityNode)
+	return ok && strings.ToLower(in.Text) == strings.ToLower(pk[0])
 }
 
 // NewJoinKey creates JoinKey from Source.
@@ -901,11 +1135,31 @@ func NewGroupBy(stmt *rel.SqlSelect) *GroupBy {
 	return &GroupBy{Stmt: stmt, PlanBase: NewPlanBase(false)}
 }
 
+// NewGroupByPartial group by of a single partition from SqlSelect statement.
+func NewGroupByPartial(stmt *rel.SqlSelect) *GroupBy {
+	return &GroupBy{Stmt: stmt, Partial: true, PlanBase: NewPlanBase(false)}
+}
+
+// NewGroupByFinal group by merging the partials of each partition.
+func NewGroupByFinal(stmt *rel.SqlSelect) *GroupBy {
+	return &GroupBy{Stmt: stmt, Final: true, PlanBase: NewPlanBase(false)}
+}
+
 // NewOrder from SqlSelect statement.
 func NewOrder(stmt *rel.SqlSelect) *Order {
 	return &Order{Stmt: stmt, PlanBase: NewPlanBase(false)}
 }
 
+// NewWindow from SqlSelect statement.
+func NewWindow(stmt *rel.SqlSelect) *Window {
+	return &Window{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
+
+// NewDistinct from SqlSelect statement.
+func NewDistinct(stmt *rel.SqlSelect) *Distinct {
+	return &Distinct{Stmt: stmt, PlanBase: NewPlanBase(false)}
+}
+
 // Equal compares equality of two tasks.
 func (m *Into) Equal(t Task) bool {
 	if m == nil && t == nil {
@@ -928,6 +1182,9 @@ func (m *Into) Equal(t Task) bool {
 	return true
 }
 func (m *Where) ToPb() (*PlanPb, error) {
+	if err := rel.CheckPb(m.Stmt); err != nil {
+		return nil, err
+	}
 	pbp, err := m.PlanBase.ToPb()
 	if err != nil {
 		return nil, err
@@ -965,6 +1222,9 @@ func WhereFromPB(pb *PlanPb) *Where {
 }
 
 func (m *Having) ToPb() (*PlanPb, error) {
+	if err := rel.CheckPb(m.Stmt); err != nil {
+		return nil, err
+	}
 	pbp, err := m.PlanBase.ToPb()
 	if err != nil {
 		return nil, err
@@ -1022,7 +1282,9 @@ func (m *GroupBy) Equal(t Task) bool {
 	if !ok {
 		return false
 	}
-
+	if m.Partial != s.Partial || m.Final != s.Final {
+		return false
+	}
 	if !m.PlanBase.EqualBase(s.PlanBase) {
 		return false
 	}
@@ -1072,6 +1334,78 @@ func OrderFromPB(pb *PlanPb) *Order {
 	return &m
 }
 
+func (m *Window) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.Window = &WindowPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *Window) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Window)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func WindowFromPB(pb *PlanPb) *Window {
+	m := Window{
+		Stmt: rel.SqlSelectFromPb(pb.Window.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
+func (m *Distinct) ToPb() (*PlanPb, error) {
+	pbp, err := m.PlanBase.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	pbp.Distinct = &DistinctPb{Select: m.Stmt.ToPB()}
+	return pbp, nil
+}
+func (m *Distinct) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Distinct)
+	if !ok {
+		return false
+	}
+
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+func DistinctFromPB(pb *PlanPb) *Distinct {
+	m := Distinct{
+		Stmt: rel.SqlSelectFromPb(pb.Distinct.Select),
+	}
+	m.PlanBase = NewPlanBase(pb.Parallel)
+	return &m
+}
+
 func (m *JoinMerge) Equal(t Task) bool {
 	if m == nil && t == nil {
 		return true
@@ -1086,12 +1420,122 @@ func (m *JoinMerge) Equal(t Task) bool {
 	if !ok {
 		return false
 	}
+	if m.LeftOuter != s.LeftOuter || m.RightOuter != s.RightOuter || m.BuildLeft != s.BuildLeft || m.Seek != s.Seek ||
+		m.NestedLoop != s.NestedLoop {
+		return false
+	}
 
 	if !m.PlanBase.EqualBase(s.PlanBase) {
 		return false
 	}
 	return true
 }
+func (m *Union) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*Union)
+	if !ok {
+		return false
+	}
+	if !m.Stmt.Equal(s.Stmt) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+
+// NewSetOp create a set operation of two input tasks.
+//
+//   left select  ->
+//                  \
+//                    --  union/intersect/except  -->
+//                  /
+//   right select ->
+//
+func NewSetOp(stmt *rel.SqlUnion, l, r Task, colIndex map[string]int) *SetOp {
+	m := &SetOp{
+		PlanBase: NewPlanBase(false),
+		Op:       stmt.Op,
+		All:      stmt.All,
+		Left:     l,
+		Right:    r,
+		ColIndex: colIndex,
+	}
+	m.SetParallel()
+	return m
+}
+func (m *SetOp) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*SetOp)
+	if !ok {
+		return false
+	}
+	if m.Op != s.Op || m.All != s.All {
+		return false
+	}
+	if !m.Left.Equal(s.Left) || !m.Right.Equal(s.Right) {
+		return false
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+
+// NewPartitionScan create a scan of the partition sources.
+func NewPartitionScan(parts []*Source) *PartitionScan {
+	m := &PartitionScan{
+		PlanBase: NewPlanBase(false),
+		Parts:    parts,
+	}
+	m.SetParallel()
+	return m
+}
+func (m *PartitionScan) Equal(t Task) bool {
+	if m == nil && t == nil {
+		return true
+	}
+	if m == nil && t != nil {
+		return false
+	}
+	if m != nil && t == nil {
+		return false
+	}
+	s, ok := t.(*PartitionScan)
+	if !ok {
+		return false
+	}
+	if len(m.Parts) != len(s.Parts) {
+		return false
+	}
+	for i, part := range m.Parts {
+		if !part.Equal(s.Parts[i]) {
+			return false
+		}
+	}
+	if !m.PlanBase.EqualBase(s.PlanBase) {
+		return false
+	}
+	return true
+}
+
 func (m *JoinKey) Equal(t Task) bool {
 	if m == nil && t == nil {
 		return true
@@ -19,6 +19,8 @@
 		OrderPb
 		JoinMergePb
 		JoinKeyPb
+		WindowPb
+		DistinctPb
 */
 package plan
 
@@ -55,6 +57,8 @@ type PlanPb struct {
 	JoinKey          *JoinKeyPb        `protobuf:"bytes,10,opt,name=joinKey" json:"joinKey,omitempty"`
 	Projection       *rel.ProjectionPb `protobuf:"bytes,11,opt,name=projection" json:"projection,omitempty"`
 	Children         []*PlanPb         `protobuf:"bytes,12,rep,name=children" json:"children,omitempty"`
+	Window           *WindowPb         `protobuf:"bytes,13,opt,name=window" json:"window,omitempty"`
+	Distinct         *DistinctPb       `protobuf:"bytes,14,opt,name=distinct" json:"distinct,omitempty"`
 	XXX_unrecognized []byte            `json:"-"`
 }
 
@@ -173,6 +177,26 @@ func (m *JoinKeyPb) String() string            { return proto.CompactTextString(
 func (*JoinKeyPb) ProtoMessage()               {}
 func (*JoinKeyPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{9} }
 
+type WindowPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *WindowPb) Reset()                    { *m = WindowPb{} }
+func (m *WindowPb) String() string            { return proto.CompactTextString(m) }
+func (*WindowPb) ProtoMessage()               {}
+func (*WindowPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{10} }
+
+type DistinctPb struct {
+	Select           *rel.SqlSelectPb `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *DistinctPb) Reset()                    { *m = DistinctPb{} }
+func (m *DistinctPb) String() string            { return proto.CompactTextString(m) }
+func (*DistinctPb) ProtoMessage()               {}
+func (*DistinctPb) Descriptor() ([]byte, []int) { return fileDescriptorPlan, []int{11} }
+
 func init() {
 	proto.RegisterType((*PlanPb)(nil), "plan.PlanPb")
 	proto.RegisterType((*SelectPb)(nil), "plan.SelectPb")
@@ -184,6 +208,8 @@ func init() {
 	proto.RegisterType((*OrderPb)(nil), "plan.OrderPb")
 	proto.RegisterType((*JoinMergePb)(nil), "plan.JoinMergePb")
 	proto.RegisterType((*JoinKeyPb)(nil), "plan.JoinKeyPb")
+	proto.RegisterType((*WindowPb)(nil), "plan.WindowPb")
+	proto.RegisterType((*DistinctPb)(nil), "plan.DistinctPb")
 }
 func (m *PlanPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -310,6 +336,26 @@ func (m *PlanPb) MarshalTo(data []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.Window != nil {
+		data[i] = 0x6a
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Window.Size()))
+		n20, err := m.Window.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	if m.Distinct != nil {
+		data[i] = 0x72
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Distinct.Size()))
+		n22, err := m.Distinct.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -698,6 +744,68 @@ func encodeVarintPlan(data []byte, offset int, v uint64) int {
 	data[offset] = uint8(v)
 	return offset + 1
 }
+func (m *WindowPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *WindowPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n21, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *DistinctPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *DistinctPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Select != nil {
+		data[i] = 0xa
+		i++
+		i = encodeVarintPlan(data, i, uint64(m.Select.Size()))
+		n23, err := m.Select.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func (m *PlanPb) Size() (n int) {
 	var l int
 	_ = l
@@ -744,6 +852,14 @@ func (m *PlanPb) Size() (n int) {
 			n += 1 + l + sovPlan(uint64(l))
 		}
 	}
+	if m.Window != nil {
+		l = m.Window.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.Distinct != nil {
+		l = m.Distinct.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -885,6 +1001,32 @@ func (m *JoinKeyPb) Size() (n int) {
 	return n
 }
 
+func (m *WindowPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DistinctPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovPlan(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovPlan(x uint64) (n int) {
 	for {
 		n++
@@ -1277,6 +1419,72 @@ func (m *PlanPb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Window == nil {
+				m.Window = &WindowPb{}
+			}
+			if err := m.Window.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Distinct", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Distinct == nil {
+				m.Distinct = &DistinctPb{}
+			}
+			if err := m.Distinct.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPlan(data[iNdEx:])
@@ -2353,6 +2561,174 @@ func (m *JoinKeyPb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *WindowPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DistinctPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPlan
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DistinctPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DistinctPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Select", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPlan
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPlan
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Select == nil {
+				m.Select = &rel.SqlSelectPb{}
+			}
+			if err := m.Select.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPlan(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPlan
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPlan(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -2459,43 +2835,50 @@ var (
 )
 
 var fileDescriptorPlan = []byte{
-	// 602 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x54, 0x5b, 0x8e, 0xd3, 0x3c,
-	0x18, 0x9d, 0xf4, 0x9a, 0x7c, 0xed, 0xff, 0x53, 0xc2, 0x08, 0x99, 0x79, 0x28, 0x55, 0xb8, 0xa8,
-	0x30, 0x22, 0x11, 0x65, 0x07, 0x45, 0xc0, 0x68, 0x10, 0x43, 0xa5, 0x79, 0x40, 0xe2, 0x2d, 0x17,
-	0x4f, 0x92, 0x91, 0x1b, 0xa7, 0x6e, 0x0a, 0x9d, 0x9d, 0xb0, 0x05, 0x76, 0xd2, 0x47, 0x56, 0x80,
-	0xb8, 0x3c, 0xb0, 0x0d, 0x1c, 0x3b, 0x71, 0x5d, 0x24, 0x46, 0xe5, 0x21, 0x52, 0x72, 0x7c, 0x7c,
-	0xfc, 0xf9, 0x3b, 0xe7, 0x0b, 0x40, 0x4e, 0xfc, 0xcc, 0xcd, 0x19, 0x2d, 0xa8, 0xdd, 0x2a, 0xdf,
-	0x8f, 0x9e, 0xc4, 0x69, 0x91, 0xac, 0x02, 0x37, 0xa4, 0x73, 0x2f, 0xa6, 0x31, 0xf5, 0xc4, 0x62,
-	0xb0, 0xba, 0x10, 0x5f, 0xe2, 0x43, 0xbc, 0xc9, 0x4d, 0x47, 0x8f, 0x34, 0xba, 0xcf, 0xfc, 0x28,
-	0xa2, 0x99, 0xb7, 0x20, 0x01, 0x4b, 0xa3, 0x18, 0x7b, 0x0c, 0x13, 0x6f, 0xb9, 0x20, 0x15, 0xf5,
-	0xf8, 0x3a, 0x2a, 0x5e, 0xe7, 0xcc, 0xcb, 0x68, 0x84, 0x25, 0xd9, 0xf9, 0xdc, 0x84, 0xce, 0x8c,
-	0xd7, 0x33, 0x0b, 0xec, 0xdb, 0x60, 0xe6, 0x9c, 0x4f, 0x08, 0x26, 0xc8, 0x18, 0x35, 0xc6, 0xe6,
-	0xb4, 0xb5, 0xf9, 0x7a, 0xf7, 0xc0, 0xbe, 0x0f, 0x9d, 0x25, 0x26, 0x38, 0x2c, 0x50, 0x73, 0x64,
-	0x8c, 0x7b, 0x93, 0xff, 0x5d, 0x71, 0x99, 0x73, 0x81, 0xcd, 0x02, 0xc1, 0x32, 0x04, 0x8b, 0xae,
-	0x58, 0x88, 0x51, 0x6b, 0x87, 0x25, 0x30, 0xc5, 0x72, 0xa0, 0xfd, 0x31, 0xc1, 0x0c, 0xa3, 0xb6,
-	0x20, 0xfd, 0x27, 0x49, 0xef, 0x4a, 0x48, 0x57, 0x4a, 0xfc, 0x0f, 0x69, 0x16, 0xa3, 0x8e, 0xae,
-	0x74, 0x22, 0x30, 0xc5, 0x7a, 0x08, 0xdd, 0x98, 0xd1, 0x55, 0x3e, 0xbd, 0x42, 0x5d, 0x41, 0xbb,
-	0x21, 0x69, 0xaf, 0x24, 0xa8, 0x9f, 0x48, 0x59, 0x84, 0x19, 0x32, 0xf5, 0x13, 0xdf, 0x96, 0x90,
-	0xe2, 0x3c, 0x06, 0xeb, 0x92, 0xa6, 0xd9, 0x1b, 0xcc, 0x62, 0x8c, 0x2c, 0xc1, 0xbb, 0x29, 0x79,
-	0xa7, 0x35, 0xac, 0x9f, 0x5b, 0x72, 0x5f, 0xe3, 0x2b, 0x04, 0xfa, 0xb9, 0xa7, 0x12, 0x54, 0xbc,
-	0x63, 0xee, 0x39, 0xa3, 0x97, 0xbc, 0x45, 0x29, 0xcd, 0x50, 0xaf, 0x12, 0xe5, 0x4e, 0xb9, 0x33,
-	0x05, 0x6b, 0x57, 0x36, 0xc3, 0x24, 0x25, 0x11, 0xc3, 0x19, 0xea, 0x8f, 0x9a, 0x9c, 0xda, 0x97,
-	0xaa, 0xd2, 0x1a, 0xc9, 0x72, 0xde, 0x83, 0x59, 0x37, 0x9d, 0x97, 0x51, 0x9b, 0x52, 0x5a, 0xd5,
-	0x9b, 0x0c, 0x84, 0xf4, 0xf9, 0x82, 0xfc, 0x61, 0x0b, 0x2f, 0x37, 0xa4, 0x59, 0x81, 0xd7, 0x05,
-	0x6a, 0xe8, 0xe5, 0x3e, 0x97, 0xa0, 0xd2, 0x3e, 0x03, 0x4b, 0x41, 0xf6, 0x21, 0x17, 0x0f, 0x13,
-	0x3c, 0xf7, 0x85, 0xb8, 0x55, 0xe5, 0x60, 0x00, 0x8d, 0x34, 0xe2, 0x2a, 0x8d, 0x71, 0xab, 0x42,
-	0xee, 0x40, 0xef, 0x82, 0x7b, 0x82, 0x59, 0xce, 0xd2, 0xac, 0x8c, 0x87, 0x5a, 0x72, 0x7e, 0x19,
-	0xbc, 0xd8, 0xca, 0x7b, 0x7b, 0x08, 0x83, 0x0c, 0xe3, 0x68, 0x79, 0xe2, 0x2f, 0x13, 0x3f, 0x20,
-	0xb8, 0x6c, 0x5e, 0x43, 0x4b, 0xd8, 0x2d, 0x68, 0x73, 0x1d, 0x9f, 0x08, 0x85, 0x1a, 0xe4, 0x71,
-	0xe4, 0x09, 0xce, 0x09, 0x2e, 0xca, 0x48, 0x6d, 0x71, 0x1b, 0x5a, 0xa5, 0x01, 0x3c, 0x41, 0x5b,
-	0x0c, 0x01, 0xc8, 0xf0, 0xbd, 0x58, 0xe3, 0x90, 0xc7, 0x66, 0xbb, 0xc2, 0xaf, 0x12, 0xae, 0x96,
-	0x05, 0x9d, 0x8b, 0x94, 0xf4, 0xab, 0xae, 0xdc, 0x03, 0x8b, 0xcf, 0x8b, 0xac, 0xaf, 0x0a, 0xc6,
-	0xb6, 0x81, 0x75, 0xd5, 0x0f, 0x76, 0x1c, 0xb4, 0xfe, 0xe2, 0xa0, 0xf3, 0x12, 0xba, 0x55, 0x7e,
-	0x77, 0x4c, 0x31, 0xae, 0x31, 0x45, 0xdd, 0x57, 0x6b, 0x82, 0xf3, 0x0c, 0x2c, 0x95, 0xdd, 0x7d,
-	0x95, 0x9c, 0x09, 0x98, 0xf5, 0x5c, 0xec, 0xbd, 0xe7, 0x29, 0x74, 0xab, 0xf8, 0xff, 0xc3, 0x96,
-	0x9e, 0x36, 0x09, 0x7c, 0xa6, 0xea, 0x09, 0x95, 0xdb, 0xfa, 0x6e, 0xf9, 0x5b, 0x71, 0xcf, 0xf8,
-	0x6f, 0x45, 0x6d, 0xf1, 0xc0, 0x52, 0x23, 0xb1, 0xcf, 0x86, 0xe9, 0xe1, 0xe6, 0xfb, 0xf0, 0x60,
-	0xf3, 0x63, 0x68, 0x7c, 0xe1, 0xcf, 0x37, 0xfe, 0x7c, 0xfa, 0x39, 0x3c, 0xf8, 0x1d, 0x00, 0x00,
-	0xff, 0xff, 0xed, 0x08, 0xa3, 0x5b, 0x39, 0x05, 0x00, 0x00,
+	// 713 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0xdb, 0x6e, 0xd3, 0x4a,
+	0x14, 0x86, 0x6b, 0x37, 0x07, 0x7b, 0x25, 0xed, 0xee, 0x1e, 0xed, 0x8b, 0x51, 0xb5, 0x95, 0x1d,
+	0x59, 0x5b, 0x28, 0xe5, 0x60, 0x43, 0x25, 0x54, 0x51, 0xb8, 0x2a, 0x20, 0xaa, 0x22, 0x20, 0x6a,
+	0x85, 0x7a, 0xed, 0xd8, 0x53, 0xc7, 0x65, 0x32, 0xe3, 0x8c, 0x1d, 0xda, 0x3e, 0x1b, 0x2f, 0xd0,
+	0x4b, 0x9e, 0x00, 0x41, 0x1f, 0x80, 0x67, 0x40, 0x33, 0xe3, 0xc3, 0x94, 0xaa, 0x88, 0x70, 0xe7,
+	0xf9, 0xd7, 0xb7, 0xe6, 0xb0, 0xfc, 0xaf, 0x05, 0x90, 0xd1, 0x90, 0xf9, 0x99, 0xe0, 0x05, 0x47,
+	0x2d, 0xf9, 0xbd, 0xf9, 0x20, 0x49, 0x8b, 0xe9, 0x62, 0xe2, 0x47, 0x7c, 0x16, 0x24, 0x3c, 0xe1,
+	0x81, 0x0a, 0x4e, 0x16, 0x27, 0x6a, 0xa5, 0x16, 0xea, 0x4b, 0x27, 0x6d, 0x6e, 0x19, 0x78, 0x28,
+	0xc2, 0x38, 0xe6, 0x2c, 0x98, 0xd3, 0x89, 0x48, 0xe3, 0x84, 0x04, 0x82, 0xd0, 0x20, 0x9f, 0xd3,
+	0x12, 0xbd, 0xf7, 0x2b, 0x94, 0x9c, 0x67, 0x22, 0x60, 0x3c, 0x26, 0x1a, 0xf6, 0xbe, 0xb7, 0xa0,
+	0x33, 0xa6, 0x21, 0x1b, 0x4f, 0xd0, 0x10, 0x9c, 0x2c, 0x14, 0x21, 0xa5, 0x84, 0x62, 0x6b, 0x68,
+	0x8f, 0x9c, 0xbd, 0xd6, 0xe5, 0x97, 0xff, 0x56, 0x0e, 0x6b, 0x15, 0xdd, 0x87, 0x4e, 0x4e, 0x28,
+	0x89, 0x0a, 0xbc, 0x3a, 0xb4, 0x46, 0xbd, 0xed, 0x75, 0x5f, 0x3d, 0xeb, 0x48, 0x69, 0xe3, 0x89,
+	0xe2, 0xad, 0xc3, 0x92, 0x51, 0x34, 0x5f, 0x88, 0x88, 0xe0, 0xd6, 0x35, 0x5a, 0x69, 0x06, 0xad,
+	0xd6, 0x68, 0x0b, 0xda, 0x67, 0x53, 0x22, 0x08, 0x6e, 0x2b, 0x78, 0x4d, 0xc3, 0xc7, 0x52, 0xaa,
+	0x59, 0x4d, 0xc8, 0x8d, 0xa7, 0xe1, 0xc7, 0x94, 0x25, 0xb8, 0x63, 0x6e, 0xbc, 0xaf, 0xb4, 0x66,
+	0x63, 0xcd, 0xa0, 0x00, 0xba, 0x89, 0xe0, 0x8b, 0x6c, 0xef, 0x02, 0x77, 0x15, 0xfe, 0x97, 0xc6,
+	0x5f, 0x69, 0xb1, 0xe6, 0x2b, 0x4a, 0xde, 0x84, 0x8b, 0x98, 0x08, 0xec, 0x98, 0x37, 0x79, 0x27,
+	0xa5, 0xe6, 0x26, 0x8a, 0x40, 0x8f, 0xc1, 0x3d, 0xe5, 0x29, 0x7b, 0x43, 0x44, 0x42, 0xb0, 0xab,
+	0xf0, 0xbf, 0x35, 0x7e, 0x50, 0xc9, 0x75, 0x4a, 0x43, 0xca, 0x2b, 0xc9, 0xc5, 0x6b, 0x72, 0x81,
+	0xc1, 0xbc, 0xd2, 0x81, 0x16, 0x9b, 0x2b, 0x95, 0x14, 0xda, 0x01, 0xc8, 0x04, 0x3f, 0x25, 0x51,
+	0x91, 0x72, 0x86, 0x7b, 0xe5, 0x41, 0x82, 0x50, 0x7f, 0x5c, 0xcb, 0x75, 0x96, 0x81, 0x22, 0x1f,
+	0x9c, 0x68, 0x9a, 0xd2, 0x58, 0x10, 0x86, 0xfb, 0xc3, 0xd5, 0x51, 0x6f, 0xbb, 0xaf, 0x8f, 0xd2,
+	0xff, 0xbc, 0xcc, 0xa8, 0x19, 0x59, 0xda, 0xb3, 0x94, 0xc5, 0xfc, 0x0c, 0xaf, 0x99, 0xa5, 0x3d,
+	0x56, 0x5a, 0x53, 0x5a, 0xcd, 0xa0, 0x6d, 0x70, 0xe2, 0x34, 0x2f, 0x52, 0x16, 0x15, 0x78, 0x5d,
+	0xf1, 0x1b, 0x9a, 0x7f, 0x51, 0xaa, 0xcd, 0x09, 0x15, 0xe7, 0x7d, 0x00, 0xa7, 0xf2, 0x0b, 0xf2,
+	0x6b, 0x3f, 0x49, 0xbf, 0xc9, 0x6c, 0xf9, 0xa4, 0xa3, 0x39, 0xbd, 0xc5, 0x51, 0x01, 0x74, 0x23,
+	0xce, 0x0a, 0x72, 0x5e, 0x60, 0xdb, 0xac, 0xdb, 0x73, 0x2d, 0x36, 0x75, 0x2b, 0x29, 0x2f, 0x01,
+	0xb7, 0x8e, 0xa1, 0x7f, 0xa1, 0x93, 0x47, 0x53, 0x32, 0x0b, 0xd5, 0x69, 0x6e, 0xe9, 0xee, 0x52,
+	0x43, 0xff, 0x80, 0x9d, 0xc6, 0xd8, 0x1e, 0xda, 0xa3, 0x56, 0x19, 0xb1, 0xd3, 0x18, 0xdd, 0x81,
+	0xde, 0x49, 0xca, 0x12, 0x22, 0x32, 0x91, 0x32, 0x69, 0xfb, 0x26, 0x6c, 0x06, 0xbc, 0x4f, 0x36,
+	0x38, 0x95, 0xb1, 0xd1, 0x43, 0xd8, 0x60, 0x84, 0xc4, 0xf9, 0x7e, 0x98, 0x4f, 0xc3, 0x09, 0x25,
+	0xf2, 0x3f, 0xdb, 0x46, 0x43, 0xdd, 0x88, 0xa2, 0x4d, 0x68, 0x9f, 0xa4, 0x2c, 0xa4, 0x78, 0xd5,
+	0xc0, 0xb4, 0x24, 0xdb, 0x32, 0xe2, 0xb3, 0x8c, 0x92, 0x42, 0x36, 0x52, 0x13, 0xae, 0x55, 0x84,
+	0xa1, 0x25, 0x8d, 0x82, 0xdb, 0x46, 0x54, 0x29, 0xe8, 0x7f, 0x00, 0xdd, 0x5e, 0x2f, 0xcf, 0x49,
+	0x84, 0x3b, 0x46, 0xdc, 0xd0, 0x65, 0x61, 0xa2, 0x45, 0x5e, 0xf0, 0x99, 0x6a, 0x90, 0x7e, 0x55,
+	0x74, 0xad, 0x21, 0x1f, 0xdc, 0x7c, 0x4e, 0xf5, 0xe3, 0xca, 0x96, 0x68, 0xfe, 0x53, 0xf9, 0xe4,
+	0xc3, 0x06, 0x41, 0x8f, 0xae, 0x79, 0xd5, 0xbd, 0xc5, 0xab, 0xa6, 0x4b, 0xbd, 0xf7, 0xd0, 0x2d,
+	0x1b, 0xfd, 0x9a, 0x25, 0xac, 0xdf, 0xb0, 0x44, 0x5d, 0x39, 0xfb, 0x46, 0xe5, 0xbc, 0xa7, 0xe0,
+	0xd6, 0x4d, 0xbe, 0xec, 0xc6, 0xde, 0x2e, 0x38, 0xd5, 0x40, 0x59, 0x3a, 0xf7, 0x09, 0x74, 0xcb,
+	0x71, 0xf1, 0x07, 0xa9, 0x3d, 0x63, 0x74, 0xa0, 0xbb, 0xf5, 0xa8, 0xd3, 0xe9, 0x7d, 0x5f, 0x0e,
+	0x70, 0xff, 0x2d, 0x8f, 0xc9, 0xcf, 0x83, 0xce, 0xdb, 0x01, 0xb7, 0x1e, 0x20, 0x4b, 0x25, 0xee,
+	0x82, 0x53, 0x35, 0xf8, 0xd2, 0xf7, 0x7d, 0x06, 0xd0, 0x34, 0xfb, 0xb2, 0xd9, 0x7b, 0x1b, 0x9f,
+	0xaf, 0x06, 0xd6, 0xe5, 0xb7, 0xc1, 0xca, 0xe5, 0xd5, 0xc0, 0xfa, 0x7a, 0x35, 0xb0, 0x7e, 0x0c,
+	0x00, 0xbf, 0xb5, 0x45, 0x85, 0x29, 0x07, 0x00, 0x00,
 }
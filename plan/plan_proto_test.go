@@ -117,6 +117,12 @@ func TestSelectSerialization(t *testing.T) {
 		assert.True(t, p2.Stmt.Raw == p.Stmt.Raw)
 		assert.True(t, p.Equal(p2), "Should be equal plans")
 	}
+
+	// sub-queries outside of the where clause can not be serialized
+	p := selectPlan(t, td.TestContext(`SELECT user_id, count(*) AS ct FROM orders
+		GROUP BY user_id HAVING ct > (SELECT count(*) FROM users)`))
+	_, err = p.Marshal()
+	assert.NotEqual(t, nil, err)
 }
 
 var (
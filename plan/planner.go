@@ -8,7 +8,9 @@ import (
 
 var (
 	// Ensure our default planner meets Planner interface.
-	_ Planner = (*PlannerDefault)(nil)
+	_ Planner        = (*PlannerDefault)(nil)
+	_ UnionPlanner   = (*PlannerDefault)(nil)
+	_ ExplainPlanner = (*PlannerDefault)(nil)
 )
 
 // PlannerDefault is implementation of Planner that creates a dag of plan.Tasks
@@ -17,17 +19,18 @@ var (
 // supercede any single or more visit methods.
 // - stateful, specific to a single request
 type PlannerDefault struct {
-	Planner  Planner
-	Ctx      *Context
-	distinct bool
-	children []Task
+	Planner   Planner
+	Ctx       *Context
+	Optimizer *Optimizer // rewrites the planned select dags, nil to skip
+	children  []Task
 }
 
 // NewPlanner creates a new default planner with context.
 func NewPlanner(ctx *Context) *PlannerDefault {
 	p := &PlannerDefault{
-		Ctx:      ctx,
-		children: make([]Task, 0),
+		Ctx:       ctx,
+		Optimizer: NewOptimizer(DefaultRules()...),
+		children:  make([]Task, 0),
 	}
 	p.Planner = p
 	return p
@@ -45,6 +48,13 @@ func (m *PlannerDefault) WalkCommand(p *Command) error {
 	return nil
 }
 
+// WalkExplain walks the explain statement, the explained statement
+// is planned by the executor.
+func (m *PlannerDefault) WalkExplain(p *Explain) error {
+	u.Debugf("WalkExplain %+v", p.Stmt)
+	return nil
+}
+
 // WalkDrop walks the draop statement
 func (m *PlannerDefault) WalkDrop(p *Drop) error {
 	u.Debugf("WalkDrop %+v", p.Stmt)
@@ -54,7 +64,7 @@ func (m *PlannerDefault) WalkDrop(p *Drop) error {
 // WalkCreate walk a Create Plan to create the dag of tasks for Create.
 func (m *PlannerDefault) WalkCreate(p *Create) error {
 	u.Debugf("WalkCreate %#v", p)
-	if len(p.Stmt.With) == 0 {
+	if len(p.Stmt.With) == 0 && p.Stmt.Select == nil {
 		return fmt.Errorf("CREATE {SCHEMA|SOURCE|DATABASE}")
 	}
 	return nil
@@ -17,11 +17,27 @@ func (m *PlannerDefault) WalkInto(p *Into) error {
 	return ErrNotImplemented
 }
 
+// mutateConn open a conn to table for a mutation, if a transaction is
+// open the source must support transactions.
+func mutateConn(ctx *Context, table string) (schema.Conn, error) {
+	conn, err := ctx.OpenConn(table)
+	if err != nil {
+		u.Warnf("%p no schema for %q err=%v", ctx.Schema, table, err)
+		return nil, err
+	}
+	if ctx.Txn != nil {
+		if _, ok := conn.(schema.ConnTransaction); !ok {
+			conn.Close()
+			return nil, fmt.Errorf("%T does not implement schema.ConnTransaction required for mutations in a transaction", conn)
+		}
+	}
+	return conn, nil
+}
+
 func upsertSource(ctx *Context, table string) (schema.ConnUpsert, error) {
 
-	conn, err := ctx.Schema.OpenConn(table)
+	conn, err := mutateConn(ctx, table)
 	if err != nil {
-		u.Warnf("%p no schema for %q err=%v", ctx.Schema, table, err)
 		return nil, err
 	}
 
@@ -75,9 +91,8 @@ func (m *PlannerDefault) WalkUpsert(p *Upsert) error {
 
 func (m *PlannerDefault) WalkDelete(p *Delete) error {
 	u.Debugf("VisitDelete %+v", p.Stmt)
-	conn, err := m.Ctx.Schema.OpenConn(p.Stmt.Table)
+	conn, err := mutateConn(m.Ctx, p.Stmt.Table)
 	if err != nil {
-		u.Warnf("%p no schema for %q err=%v", m.Ctx.Schema, p.Stmt.Table, err)
 		return err
 	}
 
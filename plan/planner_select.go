@@ -5,6 +5,7 @@ import (
 
 	u "github.com/araddon/gou"
 
+	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/schema"
 )
@@ -20,6 +21,15 @@ func needsFinalProjection(s *rel.SqlSelect) bool {
 	if len(s.GroupBy) > 0 {
 		return true
 	}
+	if s.Offset > 0 {
+		return true
+	}
+	if s.IsWindowQuery() {
+		return true
+	}
+	if s.Distinct {
+		return true
+	}
 	return false
 }
 
@@ -29,6 +39,21 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 	// u.Debugf("VisitSelect ctx:%p  %+v", p.Ctx, p.Stmt)
 
 	needsFinalProject := true
+	partitioned := false
+
+	if err := m.walkCtes(p.Stmt.Ctes, p.Stmt); err != nil {
+		return err
+	}
+
+	// Sub-queries are materialized once at execution, decorrelate those
+	// that reference our rows so they can be run independently.
+	if p.Stmt.Where != nil {
+		for _, sq := range rel.SubQueries(p.Stmt.Where.Expr) {
+			if err := sq.Decorrelate(p.Stmt); err != nil {
+				return err
+			}
+		}
+	}
 
 	if len(p.Stmt.From) == 0 {
 
@@ -42,16 +67,27 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		if err != nil {
 			return err
 		}
-		p.From = append(p.From, srcPlan)
-		p.Add(srcPlan)
 
-		err = m.Planner.WalkSourceSelect(srcPlan)
-		if err != nil {
-			return err
-		}
+		if parts := sourcePartitions(srcPlan); len(parts) > 1 && partitionable(p.Stmt) {
+			scan, err := m.walkPartitionScan(p, srcPlan, parts)
+			if err != nil {
+				return err
+			}
+			p.From = append(p.From, scan.Parts[0])
+			p.Add(scan)
+			partitioned = true
+		} else {
+			p.From = append(p.From, srcPlan)
+			p.Add(srcPlan)
 
-		if srcPlan.Complete && !needsFinalProjection(p.Stmt) {
-			goto finalProjection
+			err = m.Planner.WalkSourceSelect(srcPlan)
+			if err != nil {
+				return err
+			}
+
+			if srcPlan.Complete && !needsFinalProjection(p.Stmt) {
+				goto finalProjection
+			}
 		}
 
 	} else {
@@ -59,6 +95,9 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		var prevSource *Source
 		var prevTask Task
 
+		// Join the sources smallest first if we have stats for them
+		orderJoins(m.Ctx, p.Stmt)
+
 		for i, from := range p.Stmt.From {
 
 			// Need to rewrite the From statement to ensure all fields necessary to support
@@ -76,9 +115,9 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 			// now fold into previous task
 			if i != 0 {
-				from.Seekable = true
 				// fold this source into previous
 				curMergeTask := NewJoinMerge(prevTask, srcPlan, prevSource.Stmt, srcPlan.Stmt)
+				from.Seekable = curMergeTask.Seek
 				prevTask = curMergeTask
 			} else {
 				prevTask = srcPlan
@@ -90,12 +129,9 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 	}
 
-	if p.Stmt.Where != nil {
+	// each partition has applied the where
+	if p.Stmt.Where != nil && !partitioned {
 		switch {
-		case p.Stmt.Where.Source != nil:
-			// SELECT id from article WHERE id in (select article_id from comments where comment_ct > 50);
-			u.Warnf("Found un-supported subquery: %#v", p.Stmt.Where)
-			return ErrNotImplemented
 		case p.Stmt.Where.Expr != nil:
 			p.Add(NewWhere(p.Stmt))
 		default:
@@ -106,7 +142,11 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 
 	if p.Stmt.IsAggQuery() {
 		//u.Debugf("Adding aggregate/group by? %#v", m.Planner)
-		p.Add(NewGroupBy(p.Stmt))
+		if partitioned {
+			p.Add(NewGroupByFinal(p.Stmt))
+		} else {
+			p.Add(NewGroupBy(p.Stmt))
+		}
 		needsFinalProject = false
 	}
 
@@ -114,6 +154,14 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		p.Add(NewHaving(p.Stmt))
 	}
 
+	// window functions see all of the rows, after the merge of partitions
+	if p.Stmt.IsWindowQuery() {
+		if p.Stmt.IsAggQuery() {
+			return fmt.Errorf("window functions are not supported with group by or aggregates: %s", p.Stmt)
+		}
+		p.Add(NewWindow(p.Stmt))
+	}
+
 	if len(p.Stmt.OrderBy) > 0 {
 		p.Add(NewOrder(p.Stmt))
 	}
@@ -125,6 +173,22 @@ func (m *PlannerDefault) WalkSelect(p *Select) error {
 		}
 	}
 
+	// the projected rows are de-duplicated before they are limited
+	if p.Stmt.Distinct {
+		p.Add(NewDistinct(p.Stmt))
+	}
+
+	if (!needsFinalProject || p.Stmt.Distinct) && (p.Stmt.Limit > 0 || p.Stmt.Offset > 0) {
+		// group by, distinct have projected the rows, but not limited them
+		p.Add(NewProjectionLimit(p))
+	}
+
+	if m.Optimizer != nil {
+		if err := m.Optimizer.Optimize(m.Ctx, p); err != nil {
+			return err
+		}
+	}
+
 finalProjection:
 	if m.Ctx.Projection == nil {
 		proj, err := NewProjectionFinal(m.Ctx, p)
@@ -140,6 +204,76 @@ finalProjection:
 	return nil
 }
 
+// walkPartitionScan plan a source per partition of the select's single
+// source, each applying the where and partial group-by of its rows.
+func (m *PlannerDefault) walkPartitionScan(p *Select, src *Source, parts []*schema.Partition) (*PartitionScan, error) {
+	sources := make([]*Source, len(parts))
+	for i, part := range parts {
+		ps, err := NewSource(m.Ctx, src.Stmt, true)
+		if err != nil {
+			return nil, err
+		}
+		ps.Partition = part
+		ps.Custom = u.JsonHelper{"partition": i}
+		if err = m.Planner.WalkSourceSelect(ps); err != nil {
+			return nil, err
+		}
+		if p.Stmt.IsAggQuery() {
+			ps.Add(NewGroupByPartial(p.Stmt))
+		}
+		sources[i] = ps
+	}
+	return NewPartitionScan(sources), nil
+}
+
+// sourcePartitions the partitions a scan of the source may be split
+// across, nil unless it is a partitionable source that can open a conn
+// to each.
+func sourcePartitions(src *Source) []*schema.Partition {
+	if ps, ok := src.DataSource.(schema.SourcePartitionable); ok {
+		return ps.Partitions()
+	}
+	return nil
+}
+
+// partitionable can the select's single source be scanned in parallel
+// partitions, and its group-by be merged from the partials of each.
+func partitionable(stmt *rel.SqlSelect) bool {
+	if stmt.From[0].SubQuery != nil {
+		return false
+	}
+	// sub-queries would be materialized by the where of each partition
+	if stmt.Where != nil && len(rel.SubQueries(stmt.Where.Expr)) > 0 {
+		return false
+	}
+	if !stmt.IsAggQuery() {
+		return true
+	}
+	for _, col := range stmt.Columns {
+		// aggregates whose partials can be merged
+		if fn, ok := col.Expr.(*expr.FuncNode); ok && fn.F.AggMaker != nil {
+			if _, err := fn.NewAggregator(true); err != nil {
+				return false
+			}
+			continue
+		}
+		// group by values
+		if !isGroupByColumn(stmt, col) {
+			return false
+		}
+	}
+	return true
+}
+
+func isGroupByColumn(stmt *rel.SqlSelect, col *rel.Column) bool {
+	for _, gb := range stmt.GroupBy {
+		if gb.As == col.As || (col.Expr != nil && col.Expr.Equal(gb.Expr)) {
+			return true
+		}
+	}
+	return false
+}
+
 // WalkProjectionFinal walk the select plan to create final projection.
 func (m *PlannerDefault) WalkProjectionFinal(p *Select) error {
 	// Add a Final Projection to choose the columns for results
@@ -184,7 +318,9 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 
 	// We need to build a ColIndex of source column/select/projection column
 	//u.Debugf("datasource? %#v", p.Conn)
-	if p.Conn == nil {
+	if p.Cte != nil {
+		// rows of a WITH sub-query, run by the executor
+	} else if p.Conn == nil {
 		err := p.LoadConn()
 		if err != nil {
 			u.Errorf("no conn? %v", err)
@@ -217,7 +353,13 @@ func (m *PlannerDefault) WalkSourceSelect(p *Source) error {
 
 	} else {
 
-		if schemaCols, ok := p.Conn.(schema.ConnColumns); ok {
+		if p.Cte != nil {
+			if p.Stmt.Source != nil {
+				if err := p.Stmt.BuildColIndex(p.Cte.Cols); err != nil {
+					return err
+				}
+			}
+		} else if schemaCols, ok := p.Conn.(schema.ConnColumns); ok {
 			if err := buildColIndex(schemaCols, p); err != nil {
 				return err
 			}
@@ -284,3 +426,75 @@ func (m *PlannerDefault) WalkLiteralQuery(p *Select) error {
 	}
 	return nil
 }
+
+// WalkUnion walk a set operation of selects, each select is planned
+// as its own dag whose rows are combined by SetOp tasks.
+func (m *PlannerDefault) WalkUnion(p *Union) error {
+
+	if err := m.walkCtes(p.Stmt.Ctes, p.Stmt); err != nil {
+		return err
+	}
+
+	colIndex := make(map[string]int)
+	op, err := m.walkSetOp(p.Stmt, colIndex)
+	if err != nil {
+		return err
+	}
+	p.Add(op)
+
+	// the first (left most) select names the result columns
+	if m.Ctx.Projection != nil && m.Ctx.Projection.Proj != nil {
+		for i, col := range m.Ctx.Projection.Proj.Columns {
+			colIndex[col.As] = i
+		}
+	}
+
+	if len(p.Stmt.OrderBy) == 0 && p.Stmt.Limit == 0 && p.Stmt.Offset == 0 {
+		return nil
+	}
+
+	// order by, limit of the combined rows
+	sel := &Select{
+		Stmt:     &rel.SqlSelect{OrderBy: p.Stmt.OrderBy, Limit: p.Stmt.Limit, Offset: p.Stmt.Offset},
+		PlanBase: NewPlanBase(false),
+		Ctx:      p.Ctx,
+	}
+	if len(sel.Stmt.OrderBy) > 0 {
+		p.Add(NewOrder(sel.Stmt))
+	}
+	if sel.Stmt.Limit > 0 || sel.Stmt.Offset > 0 {
+		p.Add(NewProjectionLimit(sel))
+	}
+	return nil
+}
+
+func (m *PlannerDefault) walkSetOp(stmt *rel.SqlUnion, colIndex map[string]int) (*SetOp, error) {
+
+	var colCt []int
+	var sides [2]Task
+	for i, side := range []rel.SqlStatement{stmt.Left, stmt.Right} {
+		switch st := side.(type) {
+		case *rel.SqlSelect:
+			sel := &Select{Stmt: st, PlanBase: NewPlanBase(false), Ctx: m.Ctx}
+			if err := m.Planner.WalkSelect(sel); err != nil {
+				return nil, err
+			}
+			if !st.Star {
+				colCt = append(colCt, len(st.Columns))
+			}
+			sides[i] = sel
+		case *rel.SqlUnion:
+			op, err := m.walkSetOp(st, colIndex)
+			if err != nil {
+				return nil, err
+			}
+			sides[i] = op
+		default:
+			return nil, fmt.Errorf("unsupported %s operand %T", stmt.Op, side)
+		}
+	}
+	if len(colCt) == 2 && colCt[0] != colCt[1] {
+		return nil, fmt.Errorf("each SELECT of %s must have the same number of columns", stmt.Op)
+	}
+	return NewSetOp(stmt, sides[0], sides[1], colIndex), nil
+}
@@ -1,12 +1,19 @@
 package plan_test
 
 import (
+	"database/sql/driver"
+	"strconv"
 	"testing"
 
 	u "github.com/araddon/gou"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/araddon/qlbridge/datasource/memdb"
+	"github.com/araddon/qlbridge/datasource/mockcsv"
 	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/schema"
 )
 
 type plantest struct {
@@ -37,3 +44,339 @@ func TestPlans(t *testing.T) {
 
 	}
 }
+
+func findJoinMerge(t plan.Task) *plan.JoinMerge {
+	if jm, ok := t.(*plan.JoinMerge); ok {
+		return jm
+	}
+	for _, child := range t.Children() {
+		if jm := findJoinMerge(child); jm != nil {
+			return jm
+		}
+	}
+	return nil
+}
+
+func TestPlanJoinBuildSide(t *testing.T) {
+	mockcsv.LoadTable(mockcsv.SchemaName, "joinsmall", "id,user_id\n1,9Ip1aKbeZe2njCDM")
+
+	// smaller table on left, build on left
+	ctx := td.TestContext(`SELECT s.id, u.email FROM joinsmall AS s
+		INNER JOIN users AS u ON s.user_id = u.user_id`)
+	jm := findJoinMerge(selectPlan(t, ctx))
+	assert.NotNil(t, jm)
+	assert.True(t, jm.BuildLeft)
+	assert.False(t, jm.LeftOuter || jm.RightOuter)
+
+	// smaller table on right, default of build right
+	ctx = td.TestContext(`SELECT s.id, u.email FROM users AS u
+		LEFT JOIN joinsmall AS s ON s.user_id = u.user_id`)
+	jm = findJoinMerge(selectPlan(t, ctx))
+	assert.NotNil(t, jm)
+	assert.False(t, jm.BuildLeft)
+	assert.True(t, jm.LeftOuter)
+}
+
+func TestPlanJoinOrder(t *testing.T) {
+	mockcsv.LoadTable(mockcsv.SchemaName, "joinsmall", "id,user_id\n1,9Ip1aKbeZe2njCDM")
+	mockcsv.LoadTable(mockcsv.SchemaName, "joinbig", "id,user_id\n1,a\n2,b\n3,c\n4,d\n5,e\n6,f")
+
+	// the smaller joinsmall is read first, the join expression moving to joinbig
+	ctx := td.TestContext(`SELECT b.id, s.id FROM joinbig AS b
+		INNER JOIN joinsmall AS s ON b.user_id = s.user_id`)
+	p := selectPlan(t, ctx)
+	sel := ctx.Stmt.(*rel.SqlSelect)
+	assert.Equal(t, "s", sel.From[0].Alias)
+	assert.Equal(t, nil, sel.From[0].JoinExpr)
+	assert.Equal(t, "b.user_id = s.user_id", sel.From[1].JoinExpr.String())
+	jm := findJoinMerge(p)
+	assert.NotNil(t, jm)
+	assert.Equal(t, "s", jm.LeftFrom.Alias)
+	assert.True(t, jm.BuildLeft)
+
+	// a filter on joinbig makes it the smaller side
+	ctx = td.TestContext(`SELECT b.id, s.id FROM joinsmall AS s
+		INNER JOIN joinbig AS b ON b.user_id = s.user_id WHERE b.id = 3`)
+	selectPlan(t, ctx)
+	assert.Equal(t, "b", ctx.Stmt.(*rel.SqlSelect).From[0].Alias)
+
+	// outer joins keep their order
+	ctx = td.TestContext(`SELECT b.id, s.id FROM joinbig AS b
+		LEFT JOIN joinsmall AS s ON b.user_id = s.user_id`)
+	selectPlan(t, ctx)
+	assert.Equal(t, "b", ctx.Stmt.(*rel.SqlSelect).From[0].Alias)
+}
+
+func TestPlanJoinSeek(t *testing.T) {
+	// users is keyed by user_id, so can be looked up for each order
+	ctx := td.TestContext(`SELECT o.order_id, u.email FROM orders AS o
+		LEFT JOIN users AS u ON o.user_id = u.user_id`)
+	jm := findJoinMerge(selectPlan(t, ctx))
+	assert.NotNil(t, jm)
+	assert.True(t, jm.Seek)
+	assert.True(t, jm.RightFrom.Seekable)
+
+	// orders is keyed by order_id not user_id
+	ctx = td.TestContext(`SELECT o.order_id, u.email FROM users AS u
+		INNER JOIN orders AS o ON o.user_id = u.user_id`)
+	jm = findJoinMerge(selectPlan(t, ctx))
+	assert.NotNil(t, jm)
+	assert.False(t, jm.Seek)
+
+	// right outer joins need the unmatched right rows so must scan
+	ctx = td.TestContext(`SELECT o.order_id, u.email FROM orders AS o
+		RIGHT JOIN users AS u ON o.user_id = u.user_id`)
+	jm = findJoinMerge(selectPlan(t, ctx))
+	assert.NotNil(t, jm)
+	assert.False(t, jm.Seek)
+}
+
+// partSource a memdb announcing itself split into partitions, each
+// partition opens the whole table as only its plan is tested.
+type partSource struct {
+	*memdb.MemDb
+	ct int
+}
+
+func (m *partSource) Partitions() []*schema.Partition {
+	parts := make([]*schema.Partition, m.ct)
+	for i := range parts {
+		parts[i] = &schema.Partition{Id: strconv.Itoa(i)}
+	}
+	return parts
+}
+func (m *partSource) PartitionSource(p *schema.Partition) (schema.Conn, error) {
+	return m.Open("partplan")
+}
+
+func TestPlanPartitionScan(t *testing.T) {
+	db, err := memdb.NewMemDbData("partplan", [][]driver.Value{{1, "a", 10}, {2, "b", 20}, {3, "a", 30}},
+		[]string{"id", "user_id", "price"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, schema.RegisterSourceAsSchema("partplan", &partSource{MemDb: db, ct: 3}))
+	s, ok := schema.DefaultRegistry().Schema("partplan")
+	assert.True(t, ok)
+	partContext := func(sql string) *plan.Context {
+		ctx := plan.NewContext(sql)
+		ctx.Schema = s
+		return ctx
+	}
+
+	isPartitionScan := func(t plan.Task) bool {
+		_, ok := t.(*plan.PartitionScan)
+		return ok
+	}
+	isGroupBy := func(partial, final bool) func(t plan.Task) bool {
+		return func(t plan.Task) bool {
+			gb, ok := t.(*plan.GroupBy)
+			return ok && gb.Partial == partial && gb.Final == final
+		}
+	}
+
+	// each partition filters and partially aggregates its rows
+	p := selectPlan(t, partContext(`SELECT user_id, count(*), sum(price) FROM partplan
+		WHERE price > 5 GROUP BY user_id`))
+	assert.True(t, hasTask(p.Children(), isPartitionScan))
+	assert.True(t, hasTask(p.Children(), isGroupBy(false, true)))
+	assert.False(t, hasTask(p.Children(), isWhere))
+	scan := p.Children()[0].(*plan.PartitionScan)
+	assert.True(t, scan.IsParallel())
+	assert.Equal(t, 3, len(scan.Parts))
+	for i, part := range scan.Parts {
+		assert.Equal(t, strconv.Itoa(i), part.Partition.Id)
+		assert.True(t, hasTask(part.Children(), isWhere))
+		assert.True(t, hasTask(part.Children(), isGroupBy(true, false)))
+	}
+
+	// columns that aren't aggregates or group-by values are grouped after the scan
+	p = selectPlan(t, partContext(`SELECT user_id, price, count(*) FROM partplan GROUP BY user_id`))
+	assert.False(t, hasTask(p.Children(), isPartitionScan))
+	assert.True(t, hasTask(p.Children(), isGroupBy(false, false)))
+
+	// a partition count on a source that can't open a partition is a single scan
+	mockcsv.LoadTable(mockcsv.SchemaName, "partcsv", "id,user_id,price\n1,a,10\n2,b,20\n3,a,30")
+	tbl, err := td.TestContext("").Schema.Table("partcsv")
+	assert.Equal(t, nil, err)
+	tbl.PartitionCt = 3
+	p = selectPlan(t, td.TestContext(`SELECT user_id FROM partcsv WHERE price > 5`))
+	assert.False(t, hasTask(p.Children(), isPartitionScan))
+}
+
+func TestPlanWindow(t *testing.T) {
+	taskIndex := func(tasks []plan.Task, match func(t plan.Task) bool) int {
+		for i, t := range tasks {
+			if match(t) {
+				return i
+			}
+		}
+		return -1
+	}
+	isWindow := func(t plan.Task) bool {
+		_, ok := t.(*plan.Window)
+		return ok
+	}
+	isOrder := func(t plan.Task) bool {
+		_, ok := t.(*plan.Order)
+		return ok
+	}
+	isProjection := func(t plan.Task) bool {
+		_, ok := t.(*plan.Projection)
+		return ok
+	}
+
+	// windows are evaluated after the where, before the order by
+	p := selectPlan(t, td.TestContext(`SELECT user_id,
+		row_number() OVER (PARTITION BY user_id ORDER BY email) AS rn
+		FROM users WHERE email != "" ORDER BY rn`))
+	wi := taskIndex(p.Children(), isWindow)
+	assert.True(t, wi > taskIndex(p.Children(), isWhere), "%v", p.Children())
+	assert.True(t, wi < taskIndex(p.Children(), isOrder), "%v", p.Children())
+	assert.True(t, hasTask(p.Children(), isProjection))
+
+	pb, err := p.Marshal()
+	assert.Equal(t, nil, err)
+	p2, err := plan.SelectPlanFromPbBytes(pb, td.SchemaLoader)
+	assert.Equal(t, nil, err)
+	assert.True(t, p.Equal(p2))
+	assert.True(t, hasTask(p2.Children(), isWindow))
+
+	// window values are projected after the window, not by the source
+	p = selectPlan(t, td.TestContext(`SELECT user_id, rank() OVER (ORDER BY email) FROM users`))
+	assert.True(t, hasTask(p.Children(), isWindow))
+	assert.True(t, hasTask(p.Children(), isProjection))
+
+	ctx := td.TestContext(`SELECT user_id, count(*) OVER () FROM users GROUP BY user_id`)
+	stmt, err := rel.ParseSql(ctx.Raw)
+	assert.Equal(t, nil, err)
+	ctx.Stmt = stmt
+	_, err = plan.WalkStmt(ctx, stmt, plan.NewPlanner(ctx))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPlanDistinct(t *testing.T) {
+	taskIndex := func(tasks []plan.Task, match func(t plan.Task) bool) int {
+		for i, t := range tasks {
+			if match(t) {
+				return i
+			}
+		}
+		return -1
+	}
+	isDistinct := func(t plan.Task) bool {
+		_, ok := t.(*plan.Distinct)
+		return ok
+	}
+	isProjection := func(t plan.Task) bool {
+		_, ok := t.(*plan.Projection)
+		return ok
+	}
+	isGroupBy := func(t plan.Task) bool {
+		_, ok := t.(*plan.GroupBy)
+		return ok
+	}
+
+	// rows are de-duplicated after they are projected, before the limit
+	p := selectPlan(t, td.TestContext(`SELECT DISTINCT user_id FROM orders ORDER BY user_id LIMIT 1`))
+	di := taskIndex(p.Children(), isDistinct)
+	assert.True(t, di > taskIndex(p.Children(), isProjection), "%v", p.Children())
+	assert.Equal(t, len(p.Children())-2, di, "%v", p.Children())
+
+	p = selectPlan(t, td.TestContext(`SELECT DISTINCT user_id, item_id FROM orders`))
+	assert.True(t, hasTask(p.Children(), isDistinct))
+	pb, err := p.Marshal()
+	assert.Equal(t, nil, err)
+	p2, err := plan.SelectPlanFromPbBytes(pb, td.SchemaLoader)
+	assert.Equal(t, nil, err)
+	assert.True(t, p.Equal(p2))
+	assert.True(t, hasTask(p2.Children(), isDistinct))
+
+	// distinct aggregates are evaluated by the group by
+	p = selectPlan(t, td.TestContext(`SELECT count(DISTINCT user_id) FROM orders`))
+	assert.True(t, !hasTask(p.Children(), isDistinct))
+	assert.True(t, hasTask(p.Children(), isGroupBy), "%v", p.Children())
+}
+
+func TestPlanCte(t *testing.T) {
+	// read by a single source, run inline
+	ctx := td.TestContext(`WITH u AS (SELECT user_id, email FROM users) SELECT email FROM u`)
+	selectPlan(t, ctx)
+	cte := ctx.Cte("U")
+	assert.True(t, cte != nil)
+	assert.Equal(t, 1, cte.Refs)
+	assert.False(t, cte.Materialize)
+	assert.Equal(t, []string{"user_id", "email"}, cte.Cols)
+
+	// read twice, by the statement and a later sub-query, materialized
+	ctx = td.TestContext(`WITH u AS (SELECT user_id FROM users), o AS (SELECT user_id FROM u)
+		SELECT user_id FROM u UNION SELECT user_id FROM o`)
+	planStmt(t, ctx)
+	assert.Equal(t, 2, ctx.Cte("u").Refs)
+	assert.True(t, ctx.Cte("u").Materialize)
+	assert.False(t, ctx.Cte("o").Materialize)
+
+	// star columns are those of the table
+	ctx = td.TestContext(`WITH u AS (SELECT * FROM users) SELECT email FROM u`)
+	selectPlan(t, ctx)
+	tbl, err := ctx.Schema.Table("users")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tbl.Columns(), ctx.Cte("u").Cols)
+
+	// recursive sub-queries are always materialized
+	ctx = td.TestContext(`WITH RECURSIVE r (n) AS (SELECT 1 FROM users UNION ALL SELECT n + 1 FROM r WHERE n < 3) SELECT n FROM r`)
+	selectPlan(t, ctx)
+	assert.True(t, ctx.Cte("r").Recursive)
+	assert.True(t, ctx.Cte("r").Materialize)
+
+	for _, sql := range []string{
+		// the step may read it only once
+		`WITH RECURSIVE r (n) AS (SELECT 1 FROM users UNION ALL SELECT r.n FROM r INNER JOIN r AS r2 ON r.n = r2.n) SELECT n FROM r`,
+		// the anchor may not read it
+		`WITH RECURSIVE r (n) AS (SELECT n FROM r UNION ALL SELECT 1 FROM users) SELECT n FROM r`,
+		// column names must match the select
+		`WITH u (a, b) AS (SELECT user_id FROM users) SELECT a FROM u`,
+	} {
+		ctx = td.TestContext(sql)
+		stmt, err := rel.ParseSql(ctx.Raw)
+		assert.Equal(t, nil, err, sql)
+		ctx.Stmt = stmt
+		_, err = plan.WalkStmt(ctx, stmt, plan.NewPlanner(ctx))
+		assert.NotEqual(t, nil, err, sql)
+	}
+}
+
+// selectPlanner a Planner implemented without any of the optional walks.
+type selectPlanner struct {
+	plan.Planner
+}
+
+func TestPlanUnionPlanner(t *testing.T) {
+	sql := `SELECT user_id FROM users UNION SELECT user_id FROM orders`
+	ctx := td.TestContext(sql)
+	stmt, err := rel.ParseSql(ctx.Raw)
+	assert.Equal(t, nil, err)
+	ctx.Stmt = stmt
+	_, err = plan.WalkStmt(ctx, stmt, plan.NewPlanner(ctx))
+	assert.Equal(t, nil, err)
+
+	// set operations are optional for planners
+	ctx = td.TestContext(sql)
+	ctx.Stmt = stmt
+	_, err = plan.WalkStmt(ctx, stmt, &selectPlanner{plan.NewPlanner(ctx)})
+	assert.Equal(t, plan.ErrNotImplemented, err)
+}
+
+func TestPlanExplainPlanner(t *testing.T) {
+	sql := `EXPLAIN SELECT user_id FROM users`
+	ctx := td.TestContext(sql)
+	stmt, err := rel.ParseSql(ctx.Raw)
+	assert.Equal(t, nil, err)
+	ctx.Stmt = stmt
+	_, err = plan.WalkStmt(ctx, stmt, plan.NewPlanner(ctx))
+	assert.Equal(t, nil, err)
+
+	// explain is optional for planners
+	ctx = td.TestContext(sql)
+	ctx.Stmt = stmt
+	_, err = plan.WalkStmt(ctx, stmt, &selectPlanner{plan.NewPlanner(ctx)})
+	assert.Equal(t, plan.ErrNotImplemented, err)
+}
@@ -39,6 +39,18 @@ func NewProjectionFinal(ctx *Context, p *Select) (*Projection, error) {
 	}
 	return s, nil
 }
+// NewProjectionLimit a final projection which only applies the LIMIT and
+// OFFSET, for rows already projected (ie by group by).
+func NewProjectionLimit(p *Select) *Projection {
+	return &Projection{
+		P:         p,
+		Stmt:      p.Stmt,
+		PlanBase:  NewPlanBase(false),
+		Final:     true,
+		LimitOnly: true,
+	}
+}
+
 func NewProjectionInProcess(stmt *rel.SqlSelect) *Projection {
 	s := &Projection{
 		Stmt:     stmt,
@@ -96,7 +108,7 @@ func (m *Projection) loadFinal(ctx *Context, isFinal bool) error {
 	for _, from := range m.Stmt.From {
 
 		fromName := strings.ToLower(from.SourceName())
-		tbl, err := ctx.Schema.Table(fromName)
+		tbl, err := ctx.Table(fromName)
 		if err != nil {
 			u.Errorf("could not get table: %v", err)
 			return err
@@ -0,0 +1,228 @@
+package plan
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
+)
+
+var (
+	// DefaultStatementCacheSize the number of statements held by the default
+	// statement cache.
+	DefaultStatementCacheSize = 1000
+
+	// DefaultStatementCache the cache of parsed statements used when building
+	// jobs, nil to parse every statement.
+	DefaultStatementCache = NewStatementCache(DefaultStatementCacheSize)
+)
+
+type (
+	// StatementCache is a LRU cache of parsed select statements, keyed by their
+	// fingerprint (the statement with its literals replaced by ?
+	// placeholders) and the schema and schema version they are run
+	// against.  Statements differing only in their literal values share
+	// an entry, each run gets its own copy of the cached statement with
+	// its values bound to it so is spared the parsing.
+	//
+	// The cached statement is a template kept as protobuf, so a hit only
+	// builds the copy from it.  Statements with placeholders of their own,
+	// such as prepared statements, and those without literals are their own
+	// fingerprint and are found without lexing them.  Plans are not cached
+	// as a plan holds the context, connections and state of a single run,
+	// each run plans its copy of the statement.
+	StatementCache struct {
+		mu        sync.Mutex
+		size      int
+		ll        *list.List
+		items     map[StatementCacheKey]*list.Element
+		hits      uint64
+		misses    uint64
+		evictions uint64
+	}
+	// StatementCacheKey the key of a cached statement.
+	StatementCacheKey struct {
+		Schema      string
+		Version     uint64 // schema version, changes on DDL and refresh
+		FingerPrint string
+	}
+	// StatementCacheStats counters of a statement cache.
+	StatementCacheStats struct {
+		Hits      uint64 // statements copied from the cache
+		Misses    uint64 // statements parsed
+		Evictions uint64 // statements evicted as least recently used
+		Len       int    // statements in the cache
+	}
+	// cachedStmt the protobuf of a statement parsed from its fingerprint,
+	// nil if its fingerprint does not parse to the same statement (ie, a
+	// literal where a placeholder is not allowed) so it is always parsed.
+	cachedStmt struct {
+		key  StatementCacheKey
+		stmt *rel.SqlStatementPb
+	}
+)
+
+// NewStatementCache a cache holding at most size statements.
+func NewStatementCache(size int) *StatementCache {
+	return &StatementCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[StatementCacheKey]*list.Element),
+	}
+}
+
+// Statement the statement of ctx.Raw with the values of ctx.Params bound
+// to it, a copy of the cached statement of its fingerprint if there is
+// one.  A nil cache parses every statement.
+func (m *StatementCache) Statement(ctx *Context) (rel.SqlStatement, error) {
+	if m == nil {
+		return rel.ParseSqlParams(ctx.Raw, ctx.Params)
+	}
+	key := StatementCacheKey{FingerPrint: ctx.Raw}
+	if ctx.Schema != nil {
+		key.Schema = ctx.Schema.Name
+		key.Version = ctx.Schema.Version()
+	}
+	// a statement that is its own fingerprint has no literals to find
+	if cs, ok := m.get(key); ok && cs.stmt != nil {
+		atomic.AddUint64(&m.hits, 1)
+		return boundCopy(cs.stmt, ctx.Raw, paramBinder(ctx.Params, nil))
+	}
+
+	fp, literals, ok := rel.FingerPrintSql(ctx.Raw)
+	if !ok {
+		return rel.ParseSqlParams(ctx.Raw, ctx.Params)
+	}
+	key.FingerPrint = fp
+	bind := paramBinder(ctx.Params, literals)
+
+	if cs, ok := m.get(key); ok {
+		if cs.stmt == nil {
+			atomic.AddUint64(&m.misses, 1)
+			return rel.ParseSqlParams(ctx.Raw, ctx.Params)
+		}
+		atomic.AddUint64(&m.hits, 1)
+		return boundCopy(cs.stmt, ctx.Raw, bind)
+	}
+
+	atomic.AddUint64(&m.misses, 1)
+	stmt, err := rel.ParseSqlParams(ctx.Raw, ctx.Params)
+	if err != nil {
+		return nil, err
+	}
+	// only cache the fingerprint if, bound, it is the statement we parsed
+	cs := &cachedStmt{key: key}
+	if ps, err := rel.ParseSqlPrepared(fp); err == nil && (len(literals) == 0 || expr.ParamCount(ps.Params) == len(literals)) {
+		if pb := rel.StatementToPb(ps.Statement); pb != nil {
+			if bound, err := boundCopy(pb, ctx.Raw, bind); err == nil && bound.String() == stmt.String() {
+				cs.stmt = pb
+			}
+		}
+	}
+	m.add(cs)
+	return stmt, nil
+}
+
+// Stats the counters of this cache.
+func (m *StatementCache) Stats() StatementCacheStats {
+	return StatementCacheStats{
+		Hits:      atomic.LoadUint64(&m.hits),
+		Misses:    atomic.LoadUint64(&m.misses),
+		Evictions: atomic.LoadUint64(&m.evictions),
+		Len:       m.Len(),
+	}
+}
+
+// Len the number of statements in the cache.
+func (m *StatementCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// Purge remove all statements from the cache, ie after DDL.
+func (m *StatementCache) Purge() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ll.Init()
+	m.items = make(map[StatementCacheKey]*list.Element)
+}
+
+func (m *StatementCache) get(key StatementCacheKey) (*cachedStmt, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	m.ll.MoveToFront(el)
+	return el.Value.(*cachedStmt), true
+}
+
+func (m *StatementCache) add(cs *cachedStmt) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.items[cs.key]; ok {
+		el.Value = cs
+		m.ll.MoveToFront(el)
+		return
+	}
+	m.items[cs.key] = m.ll.PushFront(cs)
+	for m.ll.Len() > m.size && m.size > 0 {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*cachedStmt).key)
+		atomic.AddUint64(&m.evictions, 1)
+	}
+}
+
+// boundCopy a copy of the cached statement with values bound to its
+// placeholders.
+func boundCopy(pb *rel.SqlStatementPb, raw string, bind rel.ParamBinder) (rel.SqlStatement, error) {
+	cp := rel.StatementFromPb(pb)
+	if cp == nil {
+		return nil, fmt.Errorf("could not copy statement %s", raw)
+	}
+	if err := rel.BindParams(cp, bind); err != nil {
+		return nil, err
+	}
+	switch st := cp.(type) {
+	case *rel.SqlSelect:
+		st.Raw = raw
+	case *rel.SqlUnion:
+		st.Raw = raw
+	}
+	return cp, nil
+}
+
+// paramBinder the binder of the placeholders of a fingerprint, to the
+// literals it replaced or the values bound to a statement with
+// placeholders of its own.
+func paramBinder(params *expr.Params, literals []lex.Token) rel.ParamBinder {
+	return func(n *expr.ParamNode) (expr.Node, error) {
+		if len(literals) == 0 {
+			if params == nil {
+				return n, nil
+			}
+			return params.Node(n)
+		}
+		if n.Index < 1 || n.Index > len(literals) {
+			return nil, fmt.Errorf("no literal for parameter %s at position %d", n.Text, n.Index)
+		}
+		tok := literals[n.Index-1]
+		switch tok.T {
+		case lex.TokenInteger, lex.TokenFloat:
+			return expr.NewNumberStr(tok.V)
+		case lex.TokenValueEscaped:
+			return expr.NewStringNeedsEscape(tok), nil
+		}
+		return expr.NewStringNodeToken(tok), nil
+	}
+}
@@ -0,0 +1,188 @@
+package plan_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/memdb"
+	td "github.com/araddon/qlbridge/datasource/mockcsvtestdata"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/value"
+)
+
+func TestStatementCache(t *testing.T) {
+	pc := plan.NewStatementCache(2)
+
+	ctx := td.TestContext(`SELECT user_id, email FROM users WHERE user_id = "9Ip1aKbeZe2njCDM" LIMIT 10`)
+	stmt, err := pc.Statement(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plan.StatementCacheStats{Misses: 1, Len: 1}, pc.Stats())
+
+	// same statement with another value is a copy of the cached statement
+	ctx = td.TestContext(`SELECT user_id, email FROM users WHERE user_id = "hT2impsOPUREcVPc" LIMIT 10`)
+	stmt2, err := pc.Statement(ctx)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plan.StatementCacheStats{Hits: 1, Misses: 1, Len: 1}, pc.Stats())
+	assert.Equal(t, `SELECT user_id, email FROM users WHERE user_id = "hT2impsOPUREcVPc" LIMIT 10`, stmt2.String())
+	assert.NotEqual(t, stmt.String(), stmt2.String())
+
+	// the copy plans like a parsed statement
+	ctx.Stmt = stmt2
+	p, err := plan.WalkStmt(ctx, stmt2, plan.NewPlanner(ctx))
+	assert.Equal(t, nil, err)
+	assert.True(t, p != nil)
+
+	// another LIMIT is another statement
+	_, err = pc.Statement(td.TestContext(`SELECT user_id, email FROM users WHERE user_id = "hT2impsOPUREcVPc" LIMIT 5`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plan.StatementCacheStats{Hits: 1, Misses: 2, Len: 2}, pc.Stats())
+
+	// least recently used statement is evicted
+	_, err = pc.Statement(td.TestContext(`SELECT email FROM users WHERE user_id = "hT2impsOPUREcVPc"`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plan.StatementCacheStats{Hits: 1, Misses: 3, Evictions: 1, Len: 2}, pc.Stats())
+	_, err = pc.Statement(td.TestContext(`SELECT user_id, email FROM users WHERE user_id = "x" LIMIT 10`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(4), pc.Stats().Misses)
+
+	pc.Purge()
+	assert.Equal(t, 0, pc.Len())
+
+	// a nil cache parses every statement
+	var nilCache *plan.StatementCache
+	stmt, err = nilCache.Statement(td.TestContext(`SELECT email FROM users`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `SELECT email FROM users`, stmt.String())
+	nilCache.Purge()
+}
+
+func TestStatementCacheSchemaVersion(t *testing.T) {
+	a := schema.NewApplyer(func(s *schema.Schema) schema.Source {
+		sdb := datasource.NewSchemaDb(s)
+		s.InfoSchema.DS = sdb
+		return sdb
+	})
+	reg := schema.NewRegistry(a)
+	a.Init(reg)
+
+	db, err := memdb.NewMemDbData("users", [][]driver.Value{{122, "bob"}}, []string{"user_id", "name"})
+	assert.Equal(t, nil, err)
+	s := schema.NewSchema("plancache")
+	s.DS = db
+	err = a.AddOrUpdateOnSchema(s, s)
+	assert.Equal(t, nil, err)
+
+	pc := plan.NewStatementCache(10)
+	statement := func() {
+		ctx := plan.NewContext(`SELECT name FROM users WHERE user_id = 122`)
+		ctx.Schema = s
+		_, err := pc.Statement(ctx)
+		assert.Equal(t, nil, err)
+	}
+	statement()
+	statement()
+	assert.Equal(t, plan.StatementCacheStats{Hits: 1, Misses: 1, Len: 1}, pc.Stats())
+
+	// a changed schema is a new entry
+	err = a.AddOrUpdateOnSchema(s, s)
+	assert.Equal(t, nil, err)
+	statement()
+	assert.Equal(t, plan.StatementCacheStats{Hits: 1, Misses: 2, Len: 2}, pc.Stats())
+}
+
+func TestStatementCacheParams(t *testing.T) {
+	pc := plan.NewStatementCache(10)
+
+	// a statement with placeholders is cached as is, each run binds its own
+	statement := func(id string) string {
+		ctx := td.TestContext(`SELECT user_id, email FROM users WHERE user_id = ? LIMIT 10`)
+		ctx.Params = expr.NewParams([]value.Value{value.NewStringValue(id)}, nil)
+		stmt, err := pc.Statement(ctx)
+		assert.Equal(t, nil, err)
+		return stmt.String()
+	}
+	assert.Equal(t, `SELECT user_id, email FROM users WHERE user_id = "9Ip1aKbeZe2njCDM" LIMIT 10`, statement("9Ip1aKbeZe2njCDM"))
+	assert.Equal(t, `SELECT user_id, email FROM users WHERE user_id = "hT2impsOPUREcVPc" LIMIT 10`, statement("hT2impsOPUREcVPc"))
+	assert.Equal(t, plan.StatementCacheStats{Hits: 1, Misses: 1, Len: 1}, pc.Stats())
+
+	// the same statement with literals shares the entry
+	_, err := pc.Statement(td.TestContext(`SELECT user_id, email FROM users WHERE user_id = "x" LIMIT 10`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plan.StatementCacheStats{Hits: 2, Misses: 1, Len: 1}, pc.Stats())
+}
+
+func BenchmarkStatementCache(b *testing.B) {
+	raw := `SELECT user_id, email FROM users WHERE user_id = "9Ip1aKbeZe2njCDM" AND referral_count > 2 ORDER BY email LIMIT 10`
+	b.Run("miss", func(b *testing.B) {
+		var pc *plan.StatementCache
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := pc.Statement(plan.NewContext(raw)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("hit", func(b *testing.B) {
+		pc := plan.NewStatementCache(10)
+		if _, err := pc.Statement(plan.NewContext(raw)); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := pc.Statement(plan.NewContext(raw)); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if pc.Stats().Misses != 1 {
+			b.Fatalf("expected hits, got %+v", pc.Stats())
+		}
+	})
+	b.Run("prepared", func(b *testing.B) {
+		prepared := `SELECT user_id, email FROM users WHERE user_id = ? AND referral_count > ? ORDER BY email LIMIT 10`
+		params := expr.NewParams([]value.Value{value.NewStringValue("9Ip1aKbeZe2njCDM"), value.NewIntValue(2)}, nil)
+		pc := plan.NewStatementCache(10)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ctx := plan.NewContext(prepared)
+			ctx.Params = params
+			if _, err := pc.Statement(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestPreparedStatementContext(t *testing.T) {
+	ps, err := plan.NewPreparedStatement(`SELECT user_id, email FROM users WHERE user_id = ?`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, ps.NumInput())
+
+	// each run is a copy of the prepared select with its params bound
+	context := func(id string) *plan.Context {
+		return ps.Context(expr.NewParams([]value.Value{value.NewStringValue(id)}, nil))
+	}
+	ctx := context("9Ip1aKbeZe2njCDM")
+	ctx2 := context("hT2impsOPUREcVPc")
+	assert.Equal(t, `SELECT user_id, email FROM users WHERE user_id = "9Ip1aKbeZe2njCDM"`, ctx.Stmt.String())
+	assert.Equal(t, `SELECT user_id, email FROM users WHERE user_id = "hT2impsOPUREcVPc"`, ctx2.Stmt.String())
+	assert.Equal(t, ps.Raw, ctx.Raw)
+
+	// the copy plans like a parsed statement
+	ctx.Schema = td.TestContext(ctx.Raw).Schema
+	p, err := plan.WalkStmt(ctx, ctx.Stmt, plan.NewPlanner(ctx))
+	assert.Equal(t, nil, err)
+	assert.True(t, p != nil)
+
+	// other statements are parsed when run
+	ps, err = plan.NewPreparedStatement(`INSERT INTO users (user_id, email) VALUES (?, ?)`)
+	assert.Equal(t, nil, err)
+	ctx = ps.Context(expr.NewParams([]value.Value{value.NewStringValue("a"), value.NewStringValue("b")}, nil))
+	assert.True(t, ctx.Stmt == nil)
+}
@@ -69,6 +69,10 @@ func (m *FilterStatement) WriteDialect(w expr.DialectWriter) {
 		io.WriteString(w, " FROM ")
 		w.WriteIdentity(m.From)
 	}
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, " ORDER BY ")
+		m.OrderBy.WriteDialect(w)
+	}
 	if m.Limit > 0 {
 		io.WriteString(w, fmt.Sprintf(" LIMIT %d", m.Limit))
 	}
@@ -129,6 +133,14 @@ func (m *FilterStatement) Equal(s *FilterStatement) bool {
 	if m.Limit != s.Limit {
 		return false
 	}
+	if len(m.OrderBy) != len(s.OrderBy) {
+		return false
+	}
+	for i, c := range m.OrderBy {
+		if !c.Equal(s.OrderBy[i]) {
+			return false
+		}
+	}
 	if m.Alias != s.Alias {
 		return false
 	}
@@ -172,6 +184,10 @@ func (m *FilterSelect) WriteDialect(w expr.DialectWriter) {
 
 	m.Filter.WriteDialect(w)
 
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, " ORDER BY ")
+		m.OrderBy.WriteDialect(w)
+	}
 	if m.Limit > 0 {
 		io.WriteString(w, fmt.Sprintf(" LIMIT %d", m.Limit))
 	}
@@ -168,7 +168,7 @@ func (m *filterTokenPager) ClauseEnd() bool {
 	tok := m.Cur()
 	switch tok.T {
 	// List of possible tokens that would indicate a end to the current clause
-	case lex.TokenEOF, lex.TokenEOS, lex.TokenLimit, lex.TokenWith, lex.TokenAlias:
+	case lex.TokenEOF, lex.TokenEOS, lex.TokenOrderBy, lex.TokenLimit, lex.TokenWith, lex.TokenAlias:
 		return true
 	}
 	return false
@@ -310,6 +310,12 @@ func (m *FilterQLParser) parseSelect() (*FilterSelect, error) {
 		return nil, fmt.Errorf("expected SELECT * FROM <table> { <WHERE> | <FILTER> } but got %v instead of WHERE/FILTER", t)
 	}
 
+	// ORDER BY - Optional
+	m.discardCommentsNewLines()
+	if err = m.parseOrderBy(req.FilterStatement); err != nil {
+		return nil, err
+	}
+
 	// LIMIT  - Optional
 	m.discardCommentsNewLines()
 	req.Limit, err = m.parseLimit()
@@ -368,6 +374,12 @@ func (m *FilterQLParser) parseFilter() (*FilterStatement, error) {
 		}
 	}
 
+	// ORDER BY - Optional
+	m.discardCommentsNewLines()
+	if err = m.parseOrderBy(req); err != nil {
+		return nil, err
+	}
+
 	// LIMIT - Optional
 	m.discardCommentsNewLines()
 	req.Limit, err = m.parseLimit()
@@ -443,6 +455,14 @@ func (m *FilterQLParser) parseFirstFilters() (expr.Node, error) {
 	return n, nil
 }
 
+func (m *FilterQLParser) parseOrderBy(req *FilterStatement) (err error) {
+	if m.Cur().T != lex.TokenOrderBy {
+		return nil
+	}
+	req.OrderBy, err = parseOrderByColumns(m, m.funcs)
+	return err
+}
+
 func (m *FilterQLParser) parseLimit() (int, error) {
 	if m.Cur().T != lex.TokenLimit {
 		return 0, nil
@@ -29,6 +29,7 @@ var FilterTests = []string{
 	`FILTER AND ( NOT INCLUDE abcd, (lastvisit_ts > "now-1M") ) FROM user`,
 	`FILTER COMPANY IN ("Toys R"" Us", "Toys R' Us, Inc.")`,
 	`FILTER *`,
+	`FILTER score > 5 FROM user ORDER BY score DESC NULLS LAST, name COLLATE nocase LIMIT 10`,
 	`
 	FILTER AND (
         a IN ("Analyst")
@@ -295,6 +296,22 @@ func TestFilterSelectParse(t *testing.T) {
 	sel, err = rel.ParseFilterSelect(ql)
 	assert.True(t, err == nil && sel != nil, "Must parse: %s  \n\t%v", ql, err)
 	assert.True(t, len(sel.With) == 2, "Wanted 3 withs's got : %v", sel.With)
+
+	ql = `
+    SELECT a, b
+    FROM users
+    FILTER momentum > 20
+    ORDER BY momentum DESC NULLS FIRST, b
+    LIMIT 20
+	`
+	sel, err = rel.ParseFilterSelect(ql)
+	assert.True(t, err == nil && sel != nil, "Must parse: %s  \n\t%v", ql, err)
+	assert.Equal(t, 2, len(sel.OrderBy))
+	assert.Equal(t, "FIRST", sel.OrderBy[0].Nulls)
+	assert.Equal(t, false, sel.OrderBy[0].Asc())
+	assert.Equal(t, true, sel.OrderBy[1].Asc())
+	assert.Equal(t, 20, sel.Limit)
+	assert.Equal(t, "SELECT a, b FROM users FILTER momentum > 20 ORDER BY momentum DESC NULLS FIRST, b LIMIT 20", sel.String())
 }
 
 func TestFilterQLAstCheck(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
 
 	u "github.com/araddon/gou"
 
@@ -39,6 +40,31 @@ func parseSqlResolver(sqlQuery string, fr expr.FuncResolver) (SqlStatement, erro
 	return s, nil
 }
 
+// ParseSqlParams parse a statement binding the values of params to its
+// placeholders, the statement is as if written with the values.
+func ParseSqlParams(sqlQuery string, params *expr.Params) (SqlStatement, error) {
+	l := lex.NewSqlLexer(sqlQuery)
+	m := Sqlbridge{l: l, SqlTokenPager: NewSqlTokenPager(l)}
+	m.Bind(params)
+	s, err := m.parse()
+	if err != nil {
+		return nil, &ParseError{err}
+	}
+	return s, nil
+}
+
+// ParseSqlPrepared parse a statement to be run with values bound to its
+// placeholders, which are left unbound.
+func ParseSqlPrepared(sqlQuery string) (*PreparedStatement, error) {
+	l := lex.NewSqlLexer(sqlQuery)
+	m := Sqlbridge{l: l, SqlTokenPager: NewSqlTokenPager(l)}
+	s, err := m.parse()
+	if err != nil {
+		return nil, &ParseError{err}
+	}
+	return &PreparedStatement{Statement: s, Params: m.Params()}, nil
+}
+
 // ParseSqlSelect parse a sql statement as SELECT (or else error)
 func ParseSqlSelect(sqlQuery string) (*SqlSelect, error) {
 	stmt, err := ParseSql(sqlQuery)
@@ -104,7 +130,9 @@ func (m *Sqlbridge) parse() (SqlStatement, error) {
 	case lex.TokenPrepare:
 		return m.parsePrepare()
 	case lex.TokenSelect:
-		return m.parseSqlSelect()
+		return m.parseSqlSelectOrUnion()
+	case lex.TokenWith:
+		return m.parseSqlWith()
 	case lex.TokenInsert, lex.TokenReplace:
 		return m.parseSqlInsert()
 	case lex.TokenUpdate:
@@ -121,6 +149,8 @@ func (m *Sqlbridge) parse() (SqlStatement, error) {
 		return m.parseCommand()
 	case lex.TokenRollback, lex.TokenCommit:
 		return m.parseTransaction()
+	case lex.TokenAnalyze:
+		return m.parseAnalyze()
 	case lex.TokenCreate:
 		return m.parseCreate()
 	case lex.TokenDrop:
@@ -196,7 +226,7 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 
 	// SPECIAL END CASE for simple selects
 	// SELECT last_insert_id();
-	if m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenEOF {
+	if m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenEOF || isSetOperation(m.Cur().T) {
 		// valid end
 		return req, nil
 	}
@@ -263,7 +293,8 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 		return nil, err
 	}
 
-	if m.Cur().T == lex.TokenEOF || m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenRightParenthesis {
+	if m.Cur().T == lex.TokenEOF || m.Cur().T == lex.TokenEOS || m.Cur().T == lex.TokenRightParenthesis ||
+		isSetOperation(m.Cur().T) {
 
 		if err := req.Finalize(); err != nil {
 			return nil, err
@@ -277,6 +308,188 @@ func (m *Sqlbridge) parseSqlSelect() (*SqlSelect, error) {
 	return nil, fmt.Errorf("Did not complete parsing input: %v", m.LexTokenPager.Cur().V)
 }
 
+// parseSqlSelectOrUnion a select, or a set operation of several selects
+//
+//    SELECT ... UNION [ALL] SELECT ... [ORDER BY ...] [LIMIT ...]
+//
+// INTERSECT binds tighter than UNION and EXCEPT, which are left associative.
+// The ORDER BY, LIMIT, OFFSET after the last select apply to the whole result.
+func (m *Sqlbridge) parseSqlSelectOrUnion() (SqlStatement, error) {
+
+	raw := m.l.RawInput()
+	sel, err := m.parseSqlSelect()
+	if err != nil {
+		return nil, err
+	}
+	if !isSetOperation(m.Cur().T) {
+		return sel, nil
+	}
+
+	type setOp struct {
+		op  lex.TokenType
+		all bool
+	}
+	selects := []*SqlSelect{sel}
+	var ops []setOp
+	for isSetOperation(m.Cur().T) {
+		op := setOp{op: m.Cur().T}
+		m.Next()
+		switch m.Cur().T {
+		case lex.TokenAll:
+			op.all = true
+			m.Next()
+		case lex.TokenDistinct:
+			m.Next()
+		}
+		if m.Cur().T != lex.TokenSelect {
+			return nil, m.ErrMsg(fmt.Sprintf("expected SELECT after %s", op.op))
+		}
+		prev := selects[len(selects)-1]
+		if len(prev.OrderBy) > 0 || prev.Limit > 0 || prev.Offset > 0 {
+			return nil, fmt.Errorf("ORDER BY, LIMIT only allowed after the last SELECT of %s", op.op)
+		}
+		sel, err = m.parseSqlSelect()
+		if err != nil {
+			return nil, err
+		}
+		selects = append(selects, sel)
+		ops = append(ops, op)
+	}
+
+	// ORDER BY, LIMIT of the last select belong to the set operation
+	last := selects[len(selects)-1]
+	orderBy, limit, offset := last.OrderBy, last.Limit, last.Offset
+	last.OrderBy, last.Limit, last.Offset = nil, 0, 0
+	for _, sel := range selects {
+		sel.Raw = sel.String()
+	}
+
+	// INTERSECT first, then fold UNION, EXCEPT left to right
+	terms := []SqlStatement{selects[0]}
+	var termOps []setOp
+	for i, op := range ops {
+		if op.op == lex.TokenIntersect {
+			ti := len(terms) - 1
+			terms[ti] = NewSqlUnion(op.op, op.all, terms[ti], selects[i+1])
+			continue
+		}
+		terms = append(terms, selects[i+1])
+		termOps = append(termOps, op)
+	}
+	stmt := terms[0]
+	for i, op := range termOps {
+		stmt = NewSqlUnion(op.op, op.all, stmt, terms[i+1])
+	}
+
+	un := stmt.(*SqlUnion)
+	un.Raw = raw
+	un.OrderBy = orderBy
+	un.Limit = limit
+	un.Offset = offset
+	return un, nil
+}
+
+// parseSqlWith the named sub-queries of a WITH statement, and the select
+// or set operation of selects reading them.
+//
+//    WITH [RECURSIVE] name [(col, ...)] AS (SELECT ...) [, name AS (...)]* SELECT ...
+func (m *Sqlbridge) parseSqlWith() (SqlStatement, error) {
+
+	raw := m.l.RawInput()
+	m.Next() // Consume WITH
+
+	recursive := false
+	if m.Cur().T == lex.TokenRecursive {
+		recursive = true
+		m.Next()
+	}
+
+	var ctes []*SqlCte
+	for {
+		if m.Cur().T != lex.TokenIdentity {
+			return nil, m.ErrMsg("expected name of WITH sub-query")
+		}
+		cte := &SqlCte{Name: m.Next().V, Recursive: recursive}
+		if FindCte(ctes, cte.Name) != nil {
+			return nil, fmt.Errorf("WITH sub-query name %q specified more than once", cte.Name)
+		}
+
+		// optional column names
+		if m.Cur().T == lex.TokenLeftParenthesis {
+			m.Next()
+			for m.Cur().T == lex.TokenIdentity {
+				cte.Columns = append(cte.Columns, m.Next().V)
+				if m.Cur().T == lex.TokenComma {
+					m.Next()
+				}
+			}
+			if m.Cur().T != lex.TokenRightParenthesis {
+				return nil, m.ErrMsg("expected right paren ) after WITH column names")
+			}
+			m.Next()
+		}
+
+		if m.Cur().T != lex.TokenAs {
+			return nil, m.ErrMsg("expected AS")
+		}
+		m.Next()
+		if m.Cur().T != lex.TokenLeftParenthesis {
+			return nil, m.ErrMsg("expected left paren ( after AS")
+		}
+		m.Next()
+		if m.Cur().T != lex.TokenSelect {
+			return nil, m.ErrMsg("expected SELECT in WITH sub-query")
+		}
+		stmt, err := m.parseSqlSelectOrUnion()
+		if err != nil {
+			return nil, err
+		}
+		if m.Cur().T != lex.TokenRightParenthesis {
+			return nil, m.ErrMsg("expected right paren ) ")
+		}
+		m.Next()
+
+		switch st := stmt.(type) {
+		case *SqlSelect:
+			st.Raw = st.String()
+		case *SqlUnion:
+			st.Raw = st.String()
+		}
+		cte.Stmt = stmt
+		ctes = append(ctes, cte)
+
+		if m.Cur().T != lex.TokenComma {
+			break
+		}
+		m.Next()
+	}
+
+	if m.Cur().T != lex.TokenSelect {
+		return nil, m.ErrMsg("expected SELECT after WITH")
+	}
+	stmt, err := m.parseSqlSelectOrUnion()
+	if err != nil {
+		return nil, err
+	}
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		st.Ctes = ctes
+		st.Raw = raw
+	case *SqlUnion:
+		st.Ctes = ctes
+		st.Raw = raw
+	}
+	return stmt, nil
+}
+
+func isSetOperation(t lex.TokenType) bool {
+	switch t {
+	case lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
+		return true
+	}
+	return false
+}
+
 // First keyword was INSERT, REPLACE
 func (m *Sqlbridge) parseSqlInsert() (*SqlInsert, error) {
 
@@ -300,14 +513,16 @@ func (m *Sqlbridge) parseSqlInsert() (*SqlInsert, error) {
 		return nil, fmt.Errorf("expected table name but got : %v", m.Cur().V)
 	}
 
-	// list of fields
-	cols, err := m.parseFieldList()
-	if err != nil {
-		return nil, err
+	// list of fields, optional for INSERT INTO t SELECT ... which puts
+	// the select's columns in the table's column order
+	if m.Cur().T != lex.TokenSelect {
+		cols, err := m.parseFieldList()
+		if err != nil {
+			return nil, err
+		}
+		req.Columns = cols
+		m.Next() // left paren starts lisf of values
 	}
-	req.Columns = cols
-
-	m.Next() // left paren starts lisf of values
 	switch m.Cur().T {
 	case lex.TokenValues:
 		m.Next() // Consume Values keyword
@@ -366,6 +581,22 @@ func (m *Sqlbridge) parseSqlUpdate() (*SqlUpdate, error) {
 		return nil, err
 	}
 
+	// ORDER BY, LIMIT the rows updated
+	if m.Cur().T == lex.TokenOrderBy {
+		if req.OrderBy, err = parseOrderByColumns(m, m.funcs); err != nil {
+			return nil, err
+		}
+	}
+	if m.Cur().T == lex.TokenLimit {
+		m.Next()
+		if m.Cur().T != lex.TokenInteger {
+			return nil, m.ErrMsg("Limit must be an integer")
+		}
+		if req.Limit, err = strconv.Atoi(m.Next().V); err != nil {
+			return nil, m.ErrMsg("Could not convert limit to integer")
+		}
+	}
+
 	return req, nil
 }
 
@@ -493,11 +724,12 @@ func (m *Sqlbridge) parsePrepare() (*PreparedStatement, error) {
 	if m.Cur().T != lex.TokenValue {
 		return nil, m.ErrMsg("expected statement value ")
 	}
-	stmt, err := ParseSql(m.Cur().V)
+	ps, err := ParseSqlPrepared(m.Cur().V)
 	if err != nil {
 		return nil, err
 	}
-	req.Statement = stmt
+	req.Statement = ps.Statement
+	req.Params = ps.Params
 	// we are good
 	return req, nil
 }
@@ -509,11 +741,12 @@ func (m *Sqlbridge) parseDescribe() (SqlStatement, error) {
 	req.Tok = m.Cur()
 	m.Next() // Consume Describe
 
-	//u.Debugf("token:  %v", m.Cur())
-	switch nextWord := strings.ToLower(m.Cur().V); nextWord {
-	case "select":
-		// TODO:  make the lexer handle this
-		sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
+	// the explained statement is parsed from the raw text, the lexer
+	// does not know statements or options follow EXPLAIN
+	// TODO:  make the lexer handle this
+	sqlText := strings.TrimSpace(strings.Replace(m.l.RawInput(), req.Tok.V, "", 1))
+	switch firstWord(sqlText) {
+	case "select", "with":
 		sqlSel, err := ParseSql(sqlText)
 		if err != nil {
 			return nil, err
@@ -521,26 +754,77 @@ func (m *Sqlbridge) parseDescribe() (SqlStatement, error) {
 		req.Stmt = sqlSel
 		return req, nil
 	case "extended":
-		sqlText := strings.Replace(m.l.RawInput(), req.Tok.V, "", 1)
-		sqlText = strings.Replace(sqlText, m.Cur().V, "", 1)
-		sqlSel, err := ParseSql(sqlText)
+		sqlSel, err := ParseSql(sqlText[len("extended"):])
 		if err != nil {
 			return nil, err
 		}
 		req.Stmt = sqlSel
 		return req, nil
-	default:
-		if lex.TokenIdentity == m.Cur().T {
-			req.Identity = m.Cur().V
-		} else {
-			return nil, m.ErrMsg("expected idenity")
+	case "analyze", "format":
+		// EXPLAIN [ANALYZE] [FORMAT = JSON] statement
+		sqlText, err := parseExplainOptions(req, sqlText)
+		if err != nil {
+			return nil, err
 		}
-
+		stmt, err := ParseSql(sqlText)
+		if err != nil {
+			return nil, err
+		}
+		req.Stmt = stmt
+		return req, nil
 	}
 
+	//u.Debugf("token:  %v", m.Cur())
+	if lex.TokenIdentity != m.Cur().T {
+		return nil, m.ErrMsg("expected idenity")
+	}
+	req.Identity = m.Cur().V
 	return req, nil
 }
 
+// parseExplainOptions consume the leading ANALYZE, FORMAT = name options
+// of an explain returning the remaining statement text.
+func parseExplainOptions(req *SqlDescribe, sqlText string) (string, error) {
+	for {
+		sqlText = strings.TrimSpace(sqlText)
+		switch word := firstWord(sqlText); word {
+		case "analyze":
+			req.Analyze = true
+			sqlText = sqlText[len(word):]
+		case "format":
+			sqlText = strings.TrimSpace(sqlText[len(word):])
+			if !strings.HasPrefix(sqlText, "=") {
+				return "", fmt.Errorf("expected FORMAT = name but got %q", sqlText)
+			}
+			sqlText = strings.TrimSpace(sqlText[1:])
+			parts := strings.Fields(sqlText)
+			if len(parts) == 0 {
+				return "", fmt.Errorf("expected FORMAT = name")
+			}
+			switch format := strings.ToLower(parts[0]); format {
+			case "json", "traditional":
+				req.Format = format
+			default:
+				return "", fmt.Errorf("unsupported explain format %q", parts[0])
+			}
+			sqlText = sqlText[len(parts[0]):]
+		default:
+			return sqlText, nil
+		}
+	}
+}
+
+// firstWord the lower cased first word of sql text.
+func firstWord(sqlText string) string {
+	words := strings.FieldsFunc(sqlText, func(r rune) bool {
+		return r == '=' || unicode.IsSpace(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0])
+}
+
 // First keyword was SHOW
 func (m *Sqlbridge) parseShow() (*SqlShow, error) {
 
@@ -769,6 +1053,20 @@ func (m *Sqlbridge) parseCreate() (*SqlCreate, error) {
 	switch req.Tok.T {
 	case lex.TokenTable:
 		discardComments(m)
+		if m.Cur().T == lex.TokenAs {
+			// CREATE TABLE <identity> AS <select_stmt>, columns are the
+			// select's projection
+			m.Next() // Consume AS
+			if m.Cur().T != lex.TokenSelect {
+				return nil, m.ErrMsg("Expected CREATE TABLE <identity> AS <select_stmt>")
+			}
+			sel, err := m.parseSqlSelect()
+			if err != nil {
+				return nil, err
+			}
+			req.Select = sel
+			return req, nil
+		}
 		if m.Cur().T != lex.TokenLeftParenthesis {
 			return nil, m.ErrMsg("Expected (cols) ")
 		}
@@ -873,6 +1171,21 @@ func (m *Sqlbridge) parseTransaction() (*SqlCommand, error) {
 	return req, nil
 }
 
+func (m *Sqlbridge) parseAnalyze() (*SqlCommand, error) {
+
+	// ANALYZE [TABLE] tbl_name
+	req := &SqlCommand{Columns: make(CommandColumns, 0)}
+	req.kw = m.Next().T
+	if m.Cur().T == lex.TokenTable {
+		m.Next()
+	}
+	if m.Cur().T != lex.TokenIdentity {
+		return nil, m.ErrMsg("Expected table name for ANALYZE got")
+	}
+	req.Identity = m.Next().V
+	return req, nil
+}
+
 func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement) error {
 
 	var col *Column
@@ -945,6 +1258,11 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 				return err
 			}
 			col.Expr = exprNode
+		case lex.TokenCase:
+			var err error
+			if col, err = parseCaseColumn(m, fr); err != nil {
+				return err
+			}
 		case lex.TokenValue, lex.TokenInteger:
 			// Value Literal
 			col = NewColumnValue(m.Cur())
@@ -953,6 +1271,14 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 				return err
 			}
 			col.Expr = exprNode
+		case lex.TokenPlaceholder:
+			// bound value, named for its placeholder whatever the value
+			col = &Column{As: m.Cur().V}
+			exprNode, err := expr.ParseExprWithFuncs(m, fr)
+			if err != nil {
+				return err
+			}
+			col.Expr = exprNode
 		}
 		//u.Debugf("after colstart?:   %v  ", m.Cur())
 		comment += readComment(m)
@@ -970,7 +1296,8 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 				continue
 			}
 			return m.ErrMsg("expected identity")
-		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenFrom, lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			// This indicates we have come to the End of the columns
 			col.Comment = comment
 			stmt.AddColumn(*col)
@@ -985,6 +1312,18 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 			col.Guard = exprNode
 			// Hm, we need to backup here?  Parse Node went to deep?
 			continue
+		case lex.TokenOver:
+			if col == nil {
+				return m.ErrMsg("Expected window function before OVER")
+			}
+			over, err := parseWindow(m, fr)
+			if err != nil {
+				return err
+			}
+			col.Over = over
+			// evaluated over the rows of its window, not a group-by aggregate
+			col.Agg = false
+			continue
 		case lex.TokenRightParenthesis:
 			// loop on my friend
 		case lex.TokenComma:
@@ -1001,6 +1340,21 @@ func parseColumns(m expr.TokenPager, fr expr.FuncResolver, stmt ColumnsStatement
 	}
 }
 
+// parseCaseColumn parse a column starting with a CASE expression, which
+// un-aliased is named by its expression.
+func parseCaseColumn(m expr.TokenPager, fr expr.FuncResolver) (*Column, error) {
+	exprNode, err := expr.ParseExprWithFuncs(m, fr)
+	if err != nil {
+		return nil, err
+	}
+	col := &Column{Expr: exprNode, As: exprNode.String()}
+	col.SourceField = expr.FindFirstIdentity(exprNode)
+	if _, r, ok := expr.LeftRight(col.SourceField); ok {
+		col.SourceField = r
+	}
+	return col, nil
+}
+
 func (m *Sqlbridge) parseFieldList() (Columns, error) {
 
 	if m.Cur().T != lex.TokenLeftParenthesis {
@@ -1044,29 +1398,20 @@ func (m *Sqlbridge) parseUpdateList() (map[string]*ValueColumn, error) {
 
 		//u.Debugf("col:%v    cur:%v", lastColName, m.Cur().String())
 		switch m.Cur().T {
-		case lex.TokenWhere, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenWhere, lex.TokenOrderBy, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
 			return cols, nil
-		case lex.TokenValue:
-			cols[lastColName] = &ValueColumn{Value: value.NewStringValue(m.Cur().V)}
-		case lex.TokenInteger:
-			iv, _ := strconv.ParseInt(m.Cur().V, 10, 64)
-			cols[lastColName] = &ValueColumn{Value: value.NewIntValue(iv)}
-		case lex.TokenComma, lex.TokenEqual:
+		case lex.TokenComma:
 			// don't need to do anything
 		case lex.TokenIdentity:
-			// TODO:  this is a bug in lexer
-			lv := m.Cur().V
-			if bv, err := strconv.ParseBool(lv); err == nil {
-				cols[lastColName] = &ValueColumn{Value: value.NewBoolValue(bv)}
-			} else {
-				lastColName = m.Cur().V
-			}
-		case lex.TokenUdfExpr:
-			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+			lastColName = m.Cur().V
+		case lex.TokenEqual:
+			m.Next() // consume =
+			vc, err := m.parseUpdateValue()
 			if err != nil {
 				return nil, err
 			}
-			cols[lastColName] = &ValueColumn{Expr: exprNode}
+			cols[lastColName] = vc
+			continue
 		default:
 			u.Warnf("don't know how to handle ?  %v", m.Cur())
 			return nil, m.ErrMsg("expected column")
@@ -1075,6 +1420,45 @@ func (m *Sqlbridge) parseUpdateList() (map[string]*ValueColumn, error) {
 	}
 }
 
+// parseUpdateValue the value of a SET column, a literal or an expression
+// which may reference the row being updated.
+//
+//     SET name = "bob", hits = hits + 1, email = tolower(email)
+//
+func (m *Sqlbridge) parseUpdateValue() (*ValueColumn, error) {
+	tok := m.Cur()
+	switch m.Peek().T {
+	case lex.TokenComma, lex.TokenWhere, lex.TokenOrderBy, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		switch tok.T {
+		case lex.TokenValue:
+			m.Next()
+			return &ValueColumn{Value: value.NewStringValue(tok.V)}, nil
+		case lex.TokenInteger:
+			m.Next()
+			iv, _ := strconv.ParseInt(tok.V, 10, 64)
+			return &ValueColumn{Value: value.NewIntValue(iv)}, nil
+		case lex.TokenIdentity:
+			// TODO:  this is a bug in lexer
+			if bv, err := strconv.ParseBool(tok.V); err == nil {
+				m.Next()
+				return &ValueColumn{Value: value.NewBoolValue(bv)}, nil
+			}
+		case lex.TokenPlaceholder:
+			vc, err := m.paramColumn()
+			if err != nil {
+				return nil, err
+			}
+			m.Next()
+			return vc, nil
+		}
+	}
+	exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+	if err != nil {
+		return nil, err
+	}
+	return &ValueColumn{Expr: exprNode}, nil
+}
+
 func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 
 	if m.Cur().T != lex.TokenLeftParenthesis {
@@ -1147,6 +1531,12 @@ func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 				return nil, err
 			}
 			row = append(row, &ValueColumn{Expr: exprNode})
+		case lex.TokenPlaceholder:
+			vc, err := m.paramColumn()
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, vc)
 		default:
 			u.Warnf("don't know how to handle ?  %v", m.Cur())
 			return nil, m.ErrMsg("expected column")
@@ -1155,6 +1545,23 @@ func (m *Sqlbridge) parseValueList() ([][]*ValueColumn, error) {
 	}
 }
 
+// paramColumn the value of a placeholder in a VALUES or SET list, its
+// bound value as given or else the unbound placeholder.
+func (m *Sqlbridge) paramColumn() (*ValueColumn, error) {
+	n, err := m.Param(m.Cur())
+	if err != nil {
+		return nil, err
+	}
+	if b := m.Bound(); b != nil {
+		v, err := b.Value(n)
+		if err != nil {
+			return nil, err
+		}
+		return &ValueColumn{Value: v}, nil
+	}
+	return &ValueColumn{Expr: n}, nil
+}
+
 func (m *Sqlbridge) parseSources(req *SqlSelect) error {
 
 	discardComments(m)
@@ -1189,13 +1596,14 @@ func (m *Sqlbridge) parseSources(req *SqlSelect) error {
 			if m.Cur().T == lex.TokenRightParenthesis {
 				m.Next()
 			}
-		case lex.TokenLeft, lex.TokenRight, lex.TokenInner, lex.TokenOuter, lex.TokenJoin:
+		case lex.TokenLeft, lex.TokenRight, lex.TokenFull, lex.TokenInner, lex.TokenOuter, lex.TokenJoin:
 			// JOIN
 			if err := m.parseSourceJoin(src); err != nil {
 				return err
 			}
 		case lex.TokenEOF, lex.TokenEOS, lex.TokenWhere, lex.TokenGroupBy, lex.TokenLimit,
-			lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenOrderBy:
+			lex.TokenOffset, lex.TokenWith, lex.TokenAlias, lex.TokenOrderBy,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 			return nil
 		default:
 			return m.ErrMsg("unexpected token")
@@ -1264,7 +1672,7 @@ func (m *Sqlbridge) parseSourceTable(req *SqlSelect) error {
 func (m *Sqlbridge) parseSourceJoin(src *SqlSource) error {
 
 	switch m.Cur().T {
-	case lex.TokenLeft, lex.TokenRight:
+	case lex.TokenLeft, lex.TokenRight, lex.TokenFull:
 		src.LeftOrRight = m.Cur().T
 		m.Next()
 	}
@@ -1316,17 +1724,25 @@ func (m *Sqlbridge) parseInto(req *SqlSelect) error {
 	return nil
 }
 
-func (m *Sqlbridge) parseWhereSubSelect(req *SqlSelect) error {
+// ParseSubQuery parse a sub-query used in an expression, implements
+// expr.SubQueryPager.  Current token is the left paren.
+//
+//    WHERE user_id IN (SELECT user_id FROM orders)
+func (m *Sqlbridge) ParseSubQuery() (expr.Node, error) {
 
-	if m.Cur().T != lex.TokenSelect {
-		return nil
-	}
-	stmt, err := m.parseSqlSelect()
+	m.Next() // page forward off of (
+
+	subQuery, err := m.parseSqlSelect()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	req = stmt
-	return nil
+	subQuery.Raw = subQuery.String()
+
+	if m.Cur().T != lex.TokenRightParenthesis {
+		return nil, m.ErrMsg("expected right paren ) ")
+	}
+	m.Next() // discard right paren
+	return NewSubQueryNode(subQuery), nil
 }
 
 func (m *Sqlbridge) parseWhereSelect(req *SqlSelect) error {
@@ -1338,12 +1754,6 @@ func (m *Sqlbridge) parseWhereSelect(req *SqlSelect) error {
 	defer func() {
 		if r := recover(); r != nil {
 			u.Errorf("where error? %v \n %v\n%s", r, m.Cur(), m.Lexer().RawInput())
-			if m.Cur().T == lex.TokenSelect {
-				// TODO this is deeply flawed, need to fix/use tokenpager
-				//    with rewind ability
-				err = m.parseWhereSubSelect(req)
-				return
-			}
 			err = fmt.Errorf("panic err: %v", r)
 		}
 	}()
@@ -1369,39 +1779,10 @@ func (m *Sqlbridge) parseWhere() (*SqlWhere, error) {
 
 	where := SqlWhere{}
 
-	// We are going to Peek forward at the next 3 tokens used
-	// to determine which type of where clause
-	m.Next() // x
-	t2 := m.Cur().T
-	m.Next()
-	t3 := m.Cur().T
-	m.Next()
-	t4 := m.Cur().T
-	m.Backup()
-	m.Backup()
-	m.Backup()
-
-	// Check for Types of Where
-	//                                 t1            T2      T3     T4
+	// Sub-queries are parsed as expression nodes (SubQueryNode)
 	//    SELECT x FROM user   WHERE user_id         IN      (      SELECT user_id from orders where ...)
 	//    SELECT * FROM t1     WHERE column1         =       (      SELECT column1 FROM t2);
-	//    select a FROM movies WHERE director        IN      (     "Quentin","copola","Bay","another")
-	//    select b FROM movies WHERE director        =       "bob";
-	//    select b FROM movies WHERE create          BETWEEN "2015" AND "2010";
-	//    select b from movies WHERE director        LIKE    "%bob"
-	// TODO:
-	//    SELECT * FROM t3     WHERE ROW(5*t2.s1,77) =       (      SELECT 50,11*s1 FROM t4)
-	switch {
-	case (t2 == lex.TokenIN || t2 == lex.TokenEqual) && t3 == lex.TokenLeftParenthesis && t4 == lex.TokenSelect:
-		//u.Infof("in parseWhere: %v", m.Cur())
-		m.Next() // T1  ?? this might be udf?
-		m.Next() // t2  (IN | =)
-		m.Next() // t3 = (
-		//m.Next() // t4 = SELECT
-		where.Op = t2
-		where.Source = &SqlSelect{}
-		return &where, m.parseWhereSubSelect(where.Source)
-	}
+	//    SELECT * FROM t1     WHERE EXISTS (SELECT 1 FROM t2 WHERE t2.id = t1.id)
 	exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
 	if err != nil {
 		return nil, err
@@ -1458,6 +1839,11 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 				return err
 			}
 			col.Expr = exprNode
+		case lex.TokenCase:
+			var err error
+			if col, err = parseCaseColumn(m, m.funcs); err != nil {
+				return err
+			}
 		case lex.TokenValue:
 			// Value Literal
 			col = NewColumnFromToken(m.Cur())
@@ -1483,8 +1869,9 @@ func (m *Sqlbridge) parseGroupBy(req *SqlSelect) (err error) {
 				continue
 			}
 			return m.ErrMsg("expected identity")
-		case lex.TokenFrom, lex.TokenOrderBy, lex.TokenInto, lex.TokenLimit, lex.TokenHaving,
-			lex.TokenWith, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenFrom, lex.TokenOrderBy, lex.TokenInto, lex.TokenLimit, lex.TokenOffset,
+			lex.TokenHaving, lex.TokenWith, lex.TokenEOS, lex.TokenEOF,
+			lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 
 			// This indicates we have come to the End of the columns
 			req.GroupBy = append(req.GroupBy, col)
@@ -1542,6 +1929,17 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 	if m.Cur().T != lex.TokenOrderBy {
 		return nil
 	}
+	req.OrderBy, err = parseOrderByColumns(m, m.funcs)
+	return err
+}
+
+// parseOrderByColumns parse the columns of an ORDER BY clause, shared by
+// sql and filterql parsers.
+//
+//     ORDER BY ( <identity> | <expr> ) [COLLATE <name>] [(ASC | DESC)] [NULLS (FIRST | LAST)], ...
+//
+func parseOrderByColumns(m expr.TokenPager, fr expr.FuncResolver) (cols Columns, err error) {
+
 	m.Next() // Consume Order By
 
 	var col *Column
@@ -1553,9 +1951,9 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 		case lex.TokenUdfExpr:
 			// we have a udf/functional expression column
 			col = NewColumnFromToken(m.Cur())
-			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+			exprNode, err := expr.ParseExprWithFuncs(m, fr)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			col.Expr = exprNode
 			switch n := col.Expr.(type) {
@@ -1572,37 +1970,205 @@ func (m *Sqlbridge) parseOrderBy(req *SqlSelect) (err error) {
 			}
 		case lex.TokenIdentity:
 			col = NewColumnFromToken(m.Cur())
-			exprNode, err := expr.ParseExprWithFuncs(m, m.funcs)
+			exprNode, err := expr.ParseExprWithFuncs(m, fr)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			col.Expr = exprNode
+		case lex.TokenCase:
+			if col, err = parseCaseColumn(m, fr); err != nil {
+				return nil, err
+			}
 		}
 		//u.Debugf("OrderBy after colstart?:   %v  ", m.Cur())
+		if col == nil {
+			return nil, m.ErrMsg("expected order by column")
+		}
 
 		// since we can loop inside switch statement
 		switch m.Cur().T {
 		case lex.TokenAsc, lex.TokenDesc:
 			col.Order = strings.ToUpper(m.Cur().V)
 
-		case lex.TokenInto, lex.TokenLimit, lex.TokenEOS, lex.TokenEOF:
+		case lex.TokenNulls:
+			m.Next()
+			switch m.Cur().T {
+			case lex.TokenFirst, lex.TokenLast:
+				col.Nulls = strings.ToUpper(m.Cur().V)
+			default:
+				return nil, m.ErrMsg("expected FIRST or LAST after NULLS")
+			}
+		case lex.TokenCollate:
+			m.Next()
+			switch m.Cur().T {
+			case lex.TokenIdentity, lex.TokenValue:
+				col.Collate = m.Cur().V
+			default:
+				return nil, m.ErrMsg("expected collation name")
+			}
+		case lex.TokenInto, lex.TokenLimit, lex.TokenOffset, lex.TokenWith, lex.TokenAlias,
+			lex.TokenEOS, lex.TokenEOF:
 			// This indicates we have come to the End of the columns
-			req.OrderBy = append(req.OrderBy, col)
-			return nil
+			cols = append(cols, col)
+			return cols, nil
 		case lex.TokenCommentSingleLine:
 			m.Next()
 			col.Comment = m.Cur().V
 		case lex.TokenRightParenthesis:
 			// loop on my friend
 		case lex.TokenComma:
-			req.OrderBy = append(req.OrderBy, col)
+			cols = append(cols, col)
 		default:
-			return m.ErrMsg("expected order by column")
+			return nil, m.ErrMsg("expected order by column")
 		}
 		m.Next()
 	}
 }
 
+// parseWindow parse the window of a window function column, the current
+// token is OVER.
+//
+//     OVER ( [PARTITION BY <expr>, ...] [ORDER BY <expr> [(ASC | DESC)] [NULLS (FIRST | LAST)], ...] [<frame>] )
+//
+//     <frame> := (ROWS | RANGE) ( <bound> | BETWEEN <bound> AND <bound> )
+//     <bound> := UNBOUNDED (PRECEDING | FOLLOWING) | <n> (PRECEDING | FOLLOWING) | CURRENT ROW
+//
+func parseWindow(m expr.TokenPager, fr expr.FuncResolver) (*Window, error) {
+
+	m.Next() // Consume OVER
+	if m.Cur().T != lex.TokenLeftParenthesis {
+		return nil, m.ErrMsg("expected ( after OVER")
+	}
+	m.Next()
+
+	w := &Window{}
+	if m.Cur().T == lex.TokenPartitionBy {
+		m.Next()
+		for {
+			col, err := parseWindowColumn(m, fr)
+			if err != nil {
+				return nil, err
+			}
+			w.PartitionBy = append(w.PartitionBy, col)
+			if m.Cur().T != lex.TokenComma {
+				break
+			}
+			m.Next()
+		}
+	}
+	if m.Cur().T == lex.TokenOrderBy {
+		m.Next()
+		for {
+			col, err := parseWindowColumn(m, fr)
+			if err != nil {
+				return nil, err
+			}
+			switch m.Cur().T {
+			case lex.TokenAsc, lex.TokenDesc:
+				col.Order = strings.ToUpper(m.Cur().V)
+				m.Next()
+			}
+			if m.Cur().T == lex.TokenNulls {
+				m.Next()
+				switch m.Cur().T {
+				case lex.TokenFirst, lex.TokenLast:
+					col.Nulls = strings.ToUpper(m.Cur().V)
+					m.Next()
+				default:
+					return nil, m.ErrMsg("expected FIRST or LAST after NULLS")
+				}
+			}
+			w.OrderBy = append(w.OrderBy, col)
+			if m.Cur().T != lex.TokenComma {
+				break
+			}
+			m.Next()
+		}
+	}
+	switch m.Cur().T {
+	case lex.TokenRows, lex.TokenRange:
+		frame := &WindowFrame{Range: m.Cur().T == lex.TokenRange}
+		m.Next()
+		var err error
+		if m.Cur().T == lex.TokenBetween {
+			m.Next()
+			if frame.Start, err = parseWindowBound(m); err != nil {
+				return nil, err
+			}
+			if m.Cur().T != lex.TokenLogicAnd {
+				return nil, m.ErrMsg("expected AND in window frame")
+			}
+			m.Next()
+			if frame.End, err = parseWindowBound(m); err != nil {
+				return nil, err
+			}
+		} else if frame.Start, err = parseWindowBound(m); err != nil {
+			return nil, err
+		}
+		if frame.Start.Unbounded && frame.Start.Offset > 0 {
+			return nil, m.ErrMsg("window frame cannot start at UNBOUNDED FOLLOWING")
+		}
+		if frame.End.Unbounded && frame.End.Offset < 0 {
+			return nil, m.ErrMsg("window frame cannot end at UNBOUNDED PRECEDING")
+		}
+		if !frame.Start.Unbounded && !frame.End.Unbounded && frame.Start.Offset > frame.End.Offset {
+			return nil, m.ErrMsg("window frame starts after its end")
+		}
+		w.Frame = frame
+	}
+	if m.Cur().T != lex.TokenRightParenthesis {
+		return nil, m.ErrMsg("expected ) to end window")
+	}
+	m.Next()
+	return w, nil
+}
+
+func parseWindowColumn(m expr.TokenPager, fr expr.FuncResolver) (*Column, error) {
+	switch m.Cur().T {
+	case lex.TokenIdentity, lex.TokenUdfExpr:
+	default:
+		return nil, m.ErrMsg("expected window column")
+	}
+	col := NewColumnFromToken(m.Cur())
+	exprNode, err := expr.ParseExprWithFuncs(m, fr)
+	if err != nil {
+		return nil, err
+	}
+	col.Expr = exprNode
+	return col, nil
+}
+
+// parseWindowBound parse the start or end of a window frame.
+func parseWindowBound(m expr.TokenPager) (WindowBound, error) {
+	var b WindowBound
+	switch m.Cur().T {
+	case lex.TokenCurrentRow:
+		m.Next()
+		return b, nil
+	case lex.TokenUnbounded:
+		b.Unbounded = true
+		b.Offset = 1
+	case lex.TokenInteger:
+		n, err := strconv.ParseInt(m.Cur().V, 10, 64)
+		if err != nil {
+			return b, m.ErrMsg("expected window frame offset")
+		}
+		b.Offset = n
+	default:
+		return b, m.ErrMsg("expected window frame bound")
+	}
+	m.Next()
+	switch m.Cur().T {
+	case lex.TokenPreceding:
+		b.Offset = -b.Offset
+	case lex.TokenFollowing:
+	default:
+		return b, m.ErrMsg("expected PRECEDING or FOLLOWING")
+	}
+	m.Next()
+	return b, nil
+}
+
 func (m *Sqlbridge) parseWhereDelete(req *SqlDelete) error {
 	if m.Cur().T != lex.TokenWhere {
 		return nil
@@ -2335,7 +2901,8 @@ func (m *SqlTokenPager) ClauseEnd() bool {
 	tok := m.Cur()
 	switch tok.T {
 	case lex.TokenEOF, lex.TokenEOS, lex.TokenFrom, lex.TokenHaving, lex.TokenComma,
-		lex.TokenIf, lex.TokenAs, lex.TokenLimit, lex.TokenSelect:
+		lex.TokenIf, lex.TokenAs, lex.TokenLimit, lex.TokenSelect,
+		lex.TokenUnion, lex.TokenIntersect, lex.TokenExcept:
 		return true
 	}
 	return false
@@ -1,6 +1,7 @@
 package rel_test
 
 import (
+	"strings"
 	"testing"
 
 	u "github.com/araddon/gou"
@@ -9,6 +10,7 @@ import (
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/lex"
 	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
 )
 
 func init() {
@@ -213,8 +215,9 @@ func TestSqlParseOnly(t *testing.T) {
 		INNER JOIN orders AS t3
 			ON t3.id = t2.fake_id;`)
 
+	parseSqlTest(t, `INSERT INTO events (id,event_date,event) SELECT id,last_logon,"last_logon" FROM users;`)
+	parseSqlTest(t, `INSERT INTO events SELECT id, last_logon FROM users WHERE id > 5 LIMIT 2`)
 	// TODO:
-	//parseSqlTest(t, `INSERT INTO events (id,event_date,event) SELECT id,last_logon,"last_logon" FROM users;`)
 	// parseSqlTest(t, `REPLACE INTO tbl_3 (id,lastname) SELECT id,lastname FROM tbl_1;`)
 	parseSqlTest(t, `insert into mytable (id, str) values (0, "a")`)
 	parseSqlTest(t, `upsert into mytable (id, str) values (0, "a")`)
@@ -308,6 +311,11 @@ func TestSqlParseOnly(t *testing.T) {
 
 	parseSqlTest(t, `PREPARE stmt1 FROM 'SELECT toint(field) + 4 AS field FROM table1';`)
 
+	// DISTINCT rows, DISTINCT aggregates
+	parseSqlTest(t, `SELECT DISTINCT user_id, item_id FROM orders LIMIT 10`)
+	parseSqlTest(t, `SELECT count(DISTINCT user_id), sum(DISTINCT(price)) FROM orders GROUP BY item_id`)
+	parseSqlError(t, `SELECT tolower(DISTINCT email) FROM users`)
+
 	/*
 		SELECT    color, year, tags, price
 		FROM      cars
@@ -358,6 +366,75 @@ func TestSqlParseAstCheck(t *testing.T) {
 	assert.True(t, sel.OrderBy[0].Order == "ASC", "%v", sel.OrderBy[0].String())
 	assert.True(t, sel.OrderBy[1].Order == "DESC", "%v", sel.OrderBy[1].String())
 
+	sql = "select name from users ORDER BY name COLLATE nocase DESC NULLS LAST, age NULLS FIRST, score;"
+	parseSqlTest(t, sql)
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel = req.(*rel.SqlSelect)
+	assert.Equal(t, 3, len(sel.OrderBy))
+	assert.Equal(t, "nocase", sel.OrderBy[0].Collate)
+	assert.Equal(t, "LAST", sel.OrderBy[0].Nulls)
+	assert.Equal(t, false, sel.OrderBy[0].Asc())
+	assert.Equal(t, false, sel.OrderBy[0].NullsFirst())
+	assert.Equal(t, "FIRST", sel.OrderBy[1].Nulls)
+	assert.Equal(t, true, sel.OrderBy[1].Asc())
+	assert.Equal(t, true, sel.OrderBy[1].NullsFirst())
+	assert.Equal(t, true, sel.OrderBy[2].Asc())
+	assert.Equal(t, "SELECT name FROM users ORDER BY name COLLATE nocase DESC NULLS LAST, age NULLS FIRST, score", sel.String())
+	parseSqlError(t, "select name from users ORDER BY name NULLS;")
+
+	// columns named first, last
+	sql = "SELECT first, last FROM users ORDER BY last, first DESC NULLS FIRST"
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel = req.(*rel.SqlSelect)
+	assert.Equal(t, 2, len(sel.OrderBy))
+	assert.Equal(t, "last", sel.OrderBy[0].Expr.String())
+	assert.Equal(t, "first", sel.OrderBy[1].Expr.String())
+	assert.Equal(t, "FIRST", sel.OrderBy[1].Nulls)
+	assert.Equal(t, sql, sel.String())
+
+	// Window functions
+	sql = `SELECT region, row_number() OVER (PARTITION BY region ORDER BY amount DESC) AS rn,
+		sum(amount) over (partition by region order by id rows between 2 preceding and current row) AS running
+		FROM sales;`
+	parseSqlTest(t, sql)
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel = req.(*rel.SqlSelect)
+	assert.Equal(t, 3, len(sel.Columns))
+	assert.Equal(t, (*rel.Window)(nil), sel.Columns[0].Over)
+	rn := sel.Columns[1]
+	assert.Equal(t, "rn", rn.As)
+	assert.Equal(t, false, rn.Agg)
+	assert.Equal(t, 1, len(rn.Over.PartitionBy))
+	assert.Equal(t, 1, len(rn.Over.OrderBy))
+	assert.Equal(t, false, rn.Over.OrderBy[0].Asc())
+	assert.Equal(t, (*rel.WindowFrame)(nil), rn.Over.Frame)
+	running := sel.Columns[2]
+	assert.Equal(t, false, running.Agg)
+	assert.Equal(t, &rel.WindowFrame{Start: rel.WindowBound{Offset: -2}}, running.Over.Frame)
+	assert.Equal(t, false, sel.IsAggQuery())
+	assert.Equal(t, "SELECT region, row_number() OVER (PARTITION BY region ORDER BY amount DESC) AS rn, "+
+		"sum(amount) OVER (PARTITION BY region ORDER BY id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS running FROM sales",
+		sel.String())
+
+	sql = "SELECT id, avg(amount) OVER (ORDER BY id RANGE UNBOUNDED PRECEDING), lag(amount, 1, 0) OVER () FROM sales"
+	parseSqlTest(t, sql)
+	sel, err = rel.ParseSqlSelect(sql)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, &rel.WindowFrame{Range: true, Start: rel.WindowBound{Unbounded: true, Offset: -1}},
+		sel.Columns[1].Over.Frame)
+	assert.Equal(t, &rel.Window{}, sel.Columns[2].Over)
+	assert.Equal(t, "SELECT id, avg(amount) OVER (ORDER BY id RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW), "+
+		"lag(amount, 1, 0) OVER () FROM sales", sel.String())
+
+	parseSqlError(t, "SELECT sum(x) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED FOLLOWING AND CURRENT ROW) FROM t")
+	parseSqlError(t, "SELECT sum(x) OVER (ORDER BY id ROWS BETWEEN CURRENT ROW AND UNBOUNDED PRECEDING) FROM t")
+	parseSqlError(t, "SELECT sum(x) OVER (ORDER BY id ROWS BETWEEN 1 FOLLOWING AND 1 PRECEDING) FROM t")
+	parseSqlError(t, "SELECT sum(x) OVER (ORDER BY id ROWS 2) FROM t")
+	parseSqlError(t, "SELECT sum(x) OVER (PARTITION BY FROM t")
+
 	sql = "select name from `github_public` limit 0, 100;"
 	req, err = rel.ParseSql(sql)
 	assert.True(t, err == nil && req != nil, "Must parse: %s  \n\t%v", sql, err)
@@ -449,6 +526,38 @@ func TestSqlParseAstCheck(t *testing.T) {
 	assert.True(t, ok, "is SqlSelect: %T", req)
 	u.Info(sel.Where.String())
 
+	// Explain options
+	for _, sql := range []string{
+		`EXPLAIN SELECT user_id FROM users WHERE user_id > 1`,
+		`EXPLAIN ANALYZE SELECT user_id FROM users WHERE user_id > 1`,
+		`explain format = json SELECT user_id FROM users WHERE user_id > 1`,
+		`EXPLAIN ANALYZE FORMAT=JSON SELECT user_id FROM users UNION SELECT user_id FROM orders`,
+	} {
+		req, err = rel.ParseSql(sql)
+		assert.Equal(t, nil, err, sql)
+		desc, ok = req.(*rel.SqlDescribe)
+		assert.True(t, ok, "is SqlDescribe: %T", req)
+		assert.NotEqual(t, nil, desc.Stmt, sql)
+		lsql := strings.ToLower(sql)
+		assert.Equal(t, strings.Contains(lsql, "analyze"), desc.Analyze, sql)
+		assert.Equal(t, strings.Contains(lsql, "json"), desc.Format == "json", sql)
+	}
+	_, err = rel.ParseSql(`EXPLAIN FORMAT = XML SELECT user_id FROM users`)
+	assert.NotEqual(t, nil, err)
+
+	// Analyze a table's stats
+	for _, sql := range []string{`ANALYZE TABLE users`, `analyze users;`, "ANALYZE TABLE `users`"} {
+		req, err = rel.ParseSql(sql)
+		assert.Equal(t, nil, err, sql)
+		cmd, ok := req.(*rel.SqlCommand)
+		assert.True(t, ok, "is SqlCommand: %T", req)
+		assert.Equal(t, lex.TokenAnalyze, cmd.Keyword())
+		assert.Equal(t, "users", cmd.Identity, sql)
+		assert.Equal(t, "analyze table users", cmd.String())
+	}
+	_, err = rel.ParseSql(`ANALYZE TABLE`)
+	assert.NotEqual(t, nil, err)
+
 	// Where In Sub-Query Clause
 	sql = `select user_id, email
 				FROM mockcsv.users
@@ -459,7 +568,7 @@ func TestSqlParseAstCheck(t *testing.T) {
 	sel, ok = req.(*rel.SqlSelect)
 	assert.True(t, ok, "is SqlSelect: %T", req)
 	assert.True(t, len(sel.From) == 1, "has 1 from: %v", sel.From)
-	assert.True(t, sel.Where != nil && sel.Where.Source != nil, "has sub-select: %v", sel.Where)
+	assert.True(t, sel.Where != nil && len(rel.SubQueries(sel.Where.Expr)) == 1, "has sub-select: %v", sel.Where)
 }
 
 func TestSqlAggregateTypeSelect(t *testing.T) {
@@ -522,6 +631,50 @@ func TestSqlParseFromTypes(t *testing.T) {
 	u.Info(sel.String())
 }
 
+func TestSqlParseOuterJoin(t *testing.T) {
+	t.Parallel()
+	sql := `SELECT u.name, o.price FROM users AS u LEFT OUTER JOIN orders AS o ON u.id = o.user_id WHERE o.price > 10`
+	req, err := rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel := req.(*rel.SqlSelect)
+	assert.Equal(t, 2, len(sel.From))
+	assert.Equal(t, lex.TokenLeft, sel.From[1].LeftOrRight)
+	assert.Equal(t, lex.TokenOuter, sel.From[1].JoinType)
+	left, right := sel.From[1].OuterJoin()
+	assert.True(t, left && !right)
+	assert.Contains(t, sel.String(), "\tLEFT OUTER JOIN orders AS o ON")
+
+	// where on the null-supplying side is not pushed into its source
+	sel.Rewrite()
+	assert.NotEqual(t, nil, sel.From[0].Source)
+	assert.Equal(t, (*rel.SqlWhere)(nil), sel.From[1].Source.Where)
+
+	sql = `SELECT u.name, o.price FROM users AS u RIGHT JOIN orders AS o ON u.id = o.user_id WHERE u.name = "bob"`
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel = req.(*rel.SqlSelect)
+	assert.Equal(t, lex.TokenRight, sel.From[1].LeftOrRight)
+	assert.Contains(t, sel.String(), "\tRIGHT JOIN orders AS o ON")
+	sel.Rewrite()
+	assert.Equal(t, (*rel.SqlWhere)(nil), sel.From[0].Source.Where)
+
+	sql = `SELECT u.name, o.price FROM users AS u FULL OUTER JOIN orders AS o ON u.id = o.user_id`
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel = req.(*rel.SqlSelect)
+	left, right = sel.From[1].OuterJoin()
+	assert.True(t, left && right)
+	assert.Contains(t, sel.String(), "\tFULL OUTER JOIN orders AS o ON")
+
+	// inner joins still push where down
+	sql = `SELECT u.name, o.price FROM users AS u INNER JOIN orders AS o ON u.id = o.user_id WHERE o.price > 10`
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel = req.(*rel.SqlSelect)
+	sel.Rewrite()
+	assert.NotEqual(t, (*rel.SqlWhere)(nil), sel.From[1].Source.Where)
+}
+
 func TestSqlShowAst(t *testing.T) {
 	t.Parallel()
 	/*
@@ -620,6 +773,99 @@ func TestSqlMultiStatement(t *testing.T) {
 	assert.True(t, len(sel.Columns) == 2, "want 2 cols has %v", len(sel.Columns))
 }
 
+func TestSqlUnion(t *testing.T) {
+	t.Parallel()
+	sql := `SELECT user_id FROM users UNION ALL SELECT user_id FROM orders INTERSECT SELECT user_id FROM orders ORDER BY user_id DESC LIMIT 10`
+	req, err := rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	un, ok := req.(*rel.SqlUnion)
+	assert.True(t, ok, "wanted SqlUnion got %T", req)
+	// intersect binds tighter than union
+	assert.Equal(t, lex.TokenUnion, un.Keyword())
+	assert.True(t, un.All)
+	right, ok := un.Right.(*rel.SqlUnion)
+	assert.True(t, ok, "wanted SqlUnion got %T", un.Right)
+	assert.Equal(t, lex.TokenIntersect, right.Op)
+	assert.Equal(t, 3, len(un.Selects()))
+	assert.Equal(t, "users", un.First().From[0].Name)
+	// order by, limit belong to the union not the last select
+	assert.Equal(t, 1, len(un.OrderBy))
+	assert.Equal(t, 10, un.Limit)
+	assert.Equal(t, 0, len(right.Right.(*rel.SqlSelect).OrderBy))
+
+	pbb, err := un.ToPbStatement().Marshal()
+	assert.Equal(t, nil, err)
+	un2, err := rel.SqlFromPb(pbb)
+	assert.Equal(t, nil, err)
+	assert.True(t, un.Equal(un2))
+
+	parseSqlTest(t, "SELECT a FROM x EXCEPT DISTINCT SELECT a FROM y")
+	parseSqlError(t, "SELECT a FROM x ORDER BY a UNION SELECT a FROM y")
+	parseSqlError(t, "SELECT a FROM x UNION")
+}
+
+func TestSqlWith(t *testing.T) {
+	t.Parallel()
+	sql := `WITH RECURSIVE tree (id, depth) AS (
+		SELECT id, 0 FROM org WHERE parent_id = 0
+		UNION ALL
+		SELECT o.id, tree.depth + 1 FROM org AS o INNER JOIN tree ON o.parent_id = tree.id
+	), leaves AS (SELECT id FROM tree WHERE depth > 2)
+	SELECT id FROM leaves ORDER BY id LIMIT 5`
+	req, err := rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	sel, ok := req.(*rel.SqlSelect)
+	assert.True(t, ok, "wanted SqlSelect got %T", req)
+	assert.Equal(t, 2, len(sel.Ctes))
+	tree := rel.FindCte(sel.Ctes, "TREE")
+	assert.True(t, tree != nil)
+	assert.True(t, tree.Recursive)
+	assert.Equal(t, []string{"id", "depth"}, tree.Columns)
+	_, isUnion := tree.Stmt.(*rel.SqlUnion)
+	assert.True(t, isUnion)
+	assert.Equal(t, []string{"id"}, sel.Ctes[1].ColumnNames())
+	// order by, limit belong to the statement not the sub-queries
+	assert.Equal(t, 1, len(sel.OrderBy))
+	assert.Equal(t, 5, sel.Limit)
+	assert.Equal(t, "leaves", sel.From[0].Name)
+
+	sel2, err := rel.ParseSql(sel.String())
+	assert.Equal(t, nil, err, sel.String())
+	assert.Equal(t, sel.String(), sel2.String())
+	for i, cte := range sel.Ctes {
+		assert.True(t, cte.Equal(sel2.(*rel.SqlSelect).Ctes[i]), cte.String())
+	}
+	parseSqlTest(t, sql)
+
+	// un-aliased qualified columns are named for their field
+	req, err = rel.ParseSql(`WITH a AS (SELECT o.id, count(*) AS ct FROM org AS o) SELECT id, ct FROM a`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"id", "ct"}, req.(*rel.SqlSelect).Ctes[0].ColumnNames())
+
+	// the main statement may be a union
+	req, err = rel.ParseSql(`WITH a AS (SELECT id FROM x) SELECT id FROM a UNION SELECT id FROM y`)
+	assert.Equal(t, nil, err)
+	un, ok := req.(*rel.SqlUnion)
+	assert.True(t, ok, "wanted SqlUnion got %T", req)
+	assert.Equal(t, 1, len(un.Ctes))
+	pbb, err := un.ToPbStatement().Marshal()
+	assert.Equal(t, nil, err)
+	un2, err := rel.SqlFromPb(pbb)
+	assert.Equal(t, nil, err)
+	assert.True(t, un.Equal(un2))
+
+	parseSqlError(t, "WITH a AS (SELECT id FROM x), a AS (SELECT id FROM y) SELECT id FROM a")
+	parseSqlError(t, "WITH a (SELECT id FROM x) SELECT id FROM a")
+	parseSqlError(t, "WITH a AS (SELECT id FROM x)")
+}
+
+func TestSqlJoinOrderBy(t *testing.T) {
+	t.Parallel()
+	req, err := rel.ParseSql(`SELECT u.id, o.item FROM users AS u INNER JOIN orders AS o ON u.id = o.user_id ORDER BY o.item DESC`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(req.(*rel.SqlSelect).OrderBy))
+}
+
 func TestSqlUpdate(t *testing.T) {
 	t.Parallel()
 	sql := `UPDATE users SET name = "was_updated", [deleted] = true WHERE id = "user815"`
@@ -629,6 +875,24 @@ func TestSqlUpdate(t *testing.T) {
 	assert.True(t, ok, "is SqlUpdate: %T", req)
 	assert.True(t, up.Table == "users", "has users: %v", up.Table)
 	assert.True(t, len(up.Values) == 2, "%v", up)
+
+	// values may be expressions of the row, updates may be ordered, limited
+	sql = `UPDATE users SET hits = hits + 1, email = tolower(email), name = "bob" WHERE id > 5 ORDER BY created DESC LIMIT 10`
+	req, err = rel.ParseSql(sql)
+	assert.Equal(t, nil, err)
+	up = req.(*rel.SqlUpdate)
+	assert.Equal(t, 3, len(up.Values))
+	assert.Equal(t, "hits + 1", up.Values["hits"].Expr.String())
+	assert.Equal(t, "bob", up.Values["name"].Value.ToString())
+	assert.Equal(t, 1, len(up.OrderBy))
+	assert.Equal(t, 10, up.Limit)
+	assert.Equal(t, "SELECT * FROM users WHERE id > 5 ORDER BY created DESC LIMIT 10", up.SqlSelect().String())
+
+	req, err = rel.ParseSql(`UPDATE users SET hits = 0`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT * FROM users", req.(*rel.SqlUpdate).SqlSelect().String())
+
+	parseSqlError(t, `UPDATE users SET hits = 0 LIMIT x`)
 }
 
 func TestSqlCreate(t *testing.T) {
@@ -657,6 +921,18 @@ func TestSqlCreate(t *testing.T) {
 	assert.Equal(t, "email hello", c2.Comment, "%+v", c2)
 	assert.Equal(t, "char", c2.DataType, "%+v", c2)
 	assert.Equal(t, 150, c2.DataTypeSize, "%+v", c2)
+
+	// the columns of a table created from a select are its projection
+	req, err = rel.ParseSql(`CREATE TABLE IF NOT EXISTS user_orders AS
+		SELECT user_id, count(*) AS ct FROM orders GROUP BY user_id;`)
+	assert.Equal(t, nil, err)
+	cs = req.(*rel.SqlCreate)
+	assert.Equal(t, "user_orders", cs.Identity)
+	assert.True(t, cs.IfNotExists)
+	assert.Equal(t, 0, len(cs.Cols))
+	assert.Equal(t, "SELECT user_id, count(*) AS ct FROM orders GROUP BY user_id", cs.Select.String())
+
+	parseSqlError(t, `CREATE TABLE user_orders AS user_id`)
 }
 
 func TestSqlDrop(t *testing.T) {
@@ -729,3 +1005,41 @@ func TestWithJson(t *testing.T) {
 	assert.Equal(t, 2, len(sel.With.Helper("keyobj")))
 	u.Infof("sel.With:  \n%s", sel.With.PrettyJson())
 }
+
+func TestSqlParams(t *testing.T) {
+	t.Parallel()
+	// prepared, the placeholders are found and left unbound
+	ps, err := rel.ParseSqlPrepared(`SELECT name FROM users WHERE id = ? AND age > $2 AND email = :email`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(ps.Params))
+	assert.Equal(t, 3, expr.ParamCount(ps.Params))
+	assert.Equal(t, `SELECT name FROM users WHERE id = ? AND age > $2 AND email = :email`, ps.Statement.String())
+
+	ps, err = rel.ParseSqlPrepared(`UPDATE users SET name = ? WHERE id = ?`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, expr.ParamCount(ps.Params))
+
+	// bound, the placeholders are replaced by their values
+	params := expr.NewParams([]value.Value{value.NewStringValue(`o'brien "x"`), value.NewIntValue(30)},
+		map[string]value.Value{"email": value.NewStringValue("bob@email.com")})
+	req, err := rel.ParseSqlParams(`SELECT name FROM users WHERE id = ? AND age > $2 AND email = :email`, params)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `SELECT name FROM users WHERE id = "o'brien ""x""" AND age > 30 AND email = "bob@email.com"`, req.String())
+
+	req, err = rel.ParseSqlParams(`INSERT INTO users (id, age) VALUES (?, $2)`, params)
+	assert.Equal(t, nil, err)
+	ins, ok := req.(*rel.SqlInsert)
+	assert.True(t, ok, "wanted SqlInsert got %T", req)
+	assert.Equal(t, `o'brien "x"`, ins.Rows[0][0].Value.ToString())
+	assert.Equal(t, int64(30), ins.Rows[0][1].Value.Value())
+
+	req, err = rel.ParseSqlParams(`UPDATE users SET age = $2 WHERE id = ?`, params)
+	assert.Equal(t, nil, err)
+	up, ok := req.(*rel.SqlUpdate)
+	assert.True(t, ok, "wanted SqlUpdate got %T", req)
+	assert.Equal(t, int64(30), up.Values["age"].Value.Value())
+
+	// a placeholder without a value bound is an error
+	_, err = rel.ParseSqlParams(`SELECT name FROM users WHERE id = $3`, params)
+	assert.NotEqual(t, nil, err)
+}
@@ -80,6 +80,7 @@ type (
 	PreparedStatement struct {
 		Alias     string
 		Statement SqlStatement
+		Params    []*expr.ParamNode // placeholders of the statement, values bound when run
 	}
 	// SqlSelect SQL Select statement
 	SqlSelect struct {
@@ -98,6 +99,7 @@ type (
 		Offset    int
 		Alias     string       // Non-Standard sql, alias/name of sql another way of expression Prepared Statement
 		With      u.JsonHelper // Non-Standard SQL for properties/config info, similar to Cassandra with, purse json
+		Ctes      []*SqlCte    // WITH named sub-queries read as sources
 		proj      *Projection  // Projected fields
 		isAgg     bool         // is this an aggregate query?  has group-by, or aggregate selector expressions (count, cardinality etc)
 		finalized bool         // have we already finalized, ie formalized left/right aliases
@@ -124,7 +126,7 @@ type (
 		Alias       string             // From name aliased
 		Schema      string             //  FROM `schema`.`table`
 		Op          lex.TokenType      // In, =, ON
-		LeftOrRight lex.TokenType      // Left, Right
+		LeftOrRight lex.TokenType      // Left, Right, Full
 		JoinType    lex.TokenType      // INNER, OUTER
 		JoinExpr    expr.Node          // Join expression       x.y = q.y
 		SubQuery    *SqlSelect         // optional, Join/SubSelect statement
@@ -165,9 +167,11 @@ type (
 	}
 	// SqlUpdate SQL Update Statement
 	SqlUpdate struct {
-		Values map[string]*ValueColumn
-		Where  *SqlWhere
-		Table  string
+		Values  map[string]*ValueColumn
+		Where   *SqlWhere
+		Table   string
+		OrderBy Columns // order the rows are updated in, with Limit
+		Limit   int     // max rows updated, 0 for all
 	}
 	// SqlDelete SQL Delete Statement
 	SqlDelete struct {
@@ -191,10 +195,12 @@ type (
 	}
 	// SQL Describe statement
 	SqlDescribe struct {
-		Raw      string    // full original raw statement
-		Identity string    // Describe
-		Tok      lex.Token // Explain, Describe, Desc
-		Stmt     SqlStatement
+		Raw      string       // full original raw statement
+		Identity string       // Describe
+		Tok      lex.Token    // Explain, Describe, Desc
+		Stmt     SqlStatement // statement to explain
+		Analyze  bool         // EXPLAIN ANALYZE, run the statement and report per-task stats
+		Format   string       // EXPLAIN FORMAT = JSON, "json" or "traditional" (rows)
 	}
 	// SqlInto   INTO statement   (select a,b,c from y INTO z)
 	SqlInto struct {
@@ -253,10 +259,34 @@ type (
 		As              string    // As field, auto-populate the Field Name if exists
 		Comment         string    // optional in-line comments
 		Order           string    // (ASC | DESC)
+		Nulls           string    // (FIRST | LAST) position of nulls in order by
+		Collate         string    // order by collation name
 		Star            bool      // *
 		Agg             bool      // aggregate function column?   count(*), avg(x) etc
 		Expr            expr.Node // Expression, optional, often Identity.Node
 		Guard           expr.Node // column If guard, non-standard sql column guard
+		Over            *Window   // window function OVER clause
+	}
+	// Window the OVER clause of a window function column, the rows of
+	// the current row's partition in OrderBy order are its window.
+	//
+	//    sum(amount) OVER (PARTITION BY region ORDER BY id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW)
+	Window struct {
+		PartitionBy Columns
+		OrderBy     Columns
+		Frame       *WindowFrame // nil for the default frame
+	}
+	// WindowFrame the rows of the window, relative to the current row,
+	// an aggregate window function is evaluated over.
+	WindowFrame struct {
+		Range bool // RANGE (offsets of the order by value) else ROWS
+		Start WindowBound
+		End   WindowBound
+	}
+	// WindowBound the start or end of a window frame.
+	WindowBound struct {
+		Unbounded bool  // UNBOUNDED PRECEDING (Offset < 0) or FOLLOWING (Offset > 0)
+		Offset    int64 // n PRECEDING (-n), CURRENT ROW (0), n FOLLOWING (n)
 	}
 	// ValueColumn List of Value columns in INSERT into TABLE (colnames) VALUES (valuecolumns)
 	ValueColumn struct {
@@ -656,6 +686,10 @@ func (m *Column) WriteDialect(w expr.DialectWriter) {
 			exprStr = w.String()[start:]
 		}
 	}
+	if m.Over != nil {
+		io.WriteString(w, " ")
+		m.Over.WriteDialect(w)
+	}
 
 	if m.asQuoteByte != 0 && m.originalAs != "" {
 		io.WriteString(w, " AS ")
@@ -670,10 +704,18 @@ func (m *Column) WriteDialect(w expr.DialectWriter) {
 		io.WriteString(w, " IF ")
 		m.Guard.WriteDialect(w)
 	}
+	if m.Collate != "" {
+		io.WriteString(w, " COLLATE ")
+		w.WriteIdentity(m.Collate)
+	}
 	if m.Order != "" {
 		io.WriteString(w, " ")
 		io.WriteString(w, m.Order)
 	}
+	if m.Nulls != "" {
+		io.WriteString(w, " NULLS ")
+		io.WriteString(w, m.Nulls)
+	}
 }
 
 // Is this a select count(*) column
@@ -743,8 +785,22 @@ func (m *Column) IsLiteralOrFunc() bool {
 	return false
 }
 
+// Asc is this an ascending order by column, default if no order given.
 func (m *Column) Asc() bool {
-	return strings.ToLower(m.Order) == "asc"
+	return strings.ToLower(m.Order) != "desc"
+}
+
+// NullsFirst should nulls be sorted before non-null values for this order
+// by column.  If not specified nulls are considered smaller than any
+// value, so they are first for ASC and last for DESC.
+func (m *Column) NullsFirst() bool {
+	switch strings.ToLower(m.Nulls) {
+	case "first":
+		return true
+	case "last":
+		return false
+	}
+	return m.Asc()
 }
 func (m *Column) Equal(c *Column) bool {
 	if m == nil && c == nil {
@@ -792,6 +848,12 @@ func (m *Column) Equal(c *Column) bool {
 	if m.Order != c.Order {
 		return false
 	}
+	if m.Nulls != c.Nulls {
+		return false
+	}
+	if m.Collate != c.Collate {
+		return false
+	}
 	if m.Star != c.Star {
 		return false
 	}
@@ -805,6 +867,9 @@ func (m *Column) Equal(c *Column) bool {
 			return false
 		}
 	}
+	if !m.Over.Equal(c.Over) {
+		return false
+	}
 	return true
 }
 
@@ -838,9 +903,12 @@ func (m *Column) Copy() *Column {
 		As:              m.right,
 		Comment:         m.Comment,
 		Order:           m.Order,
+		Nulls:           m.Nulls,
+		Collate:         m.Collate,
 		Star:            m.Star,
 		Expr:            m.Expr,
 		Guard:           m.Guard,
+		Over:            m.Over,
 	}
 }
 func (m *Column) ToPB() *ColumnPb {
@@ -869,6 +937,12 @@ func (m *Column) ToPB() *ColumnPb {
 	if len(m.Order) > 0 {
 		n.Order = &m.Order
 	}
+	if len(m.Nulls) > 0 {
+		n.Nulls = &m.Nulls
+	}
+	if len(m.Collate) > 0 {
+		n.Collate = &m.Collate
+	}
 	if m.Star {
 		n.Star = &m.Star
 	}
@@ -878,6 +952,9 @@ func (m *Column) ToPB() *ColumnPb {
 	if m.Guard != nil {
 		n.Guard = m.Guard.NodePb()
 	}
+	if m.Over != nil {
+		n.Window = m.Over.ToPB()
+	}
 	return &n
 }
 func columnFromPb(c *ColumnPb) *Column {
@@ -893,9 +970,12 @@ func columnFromPb(c *ColumnPb) *Column {
 		SourceField:     c.GetSourceField(),
 		As:              c.GetAs(),
 		Order:           c.GetOrder(),
+		Nulls:           c.GetNulls(),
+		Collate:         c.GetCollate(),
 		Star:            c.GetStar(),
 		Expr:            expr.NodeFromNodePb(c.GetExpr()),
 		Guard:           expr.NodeFromNodePb(c.GetGuard()),
+		Over:            windowFromPb(c.GetWindow()),
 	}
 }
 
@@ -908,6 +988,113 @@ func (m *Column) LeftRight() (string, string, bool) {
 	return m.left, m.right, m.left != ""
 }
 
+func (m *Window) String() string {
+	w := expr.NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *Window) WriteDialect(w expr.DialectWriter) {
+	io.WriteString(w, "OVER (")
+	sep := ""
+	if len(m.PartitionBy) > 0 {
+		io.WriteString(w, "PARTITION BY ")
+		m.PartitionBy.WriteDialect(w)
+		sep = " "
+	}
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, sep)
+		io.WriteString(w, "ORDER BY ")
+		m.OrderBy.WriteDialect(w)
+		sep = " "
+	}
+	if m.Frame != nil {
+		io.WriteString(w, sep)
+		m.Frame.WriteDialect(w)
+	}
+	io.WriteString(w, ")")
+}
+func (m *Window) Equal(s *Window) bool {
+	if m == nil && s == nil {
+		return true
+	}
+	if m == nil || s == nil {
+		return false
+	}
+	if !m.PartitionBy.Equal(s.PartitionBy) {
+		return false
+	}
+	if !m.OrderBy.Equal(s.OrderBy) {
+		return false
+	}
+	if m.Frame == nil || s.Frame == nil {
+		return m.Frame == s.Frame
+	}
+	return *m.Frame == *s.Frame
+}
+func (m *Window) ToPB() *WindowPb {
+	pb := &WindowPb{
+		PartitionBy: ColumnsToPb(m.PartitionBy),
+		OrderBy:     ColumnsToPb(m.OrderBy),
+	}
+	if m.Frame != nil {
+		pb.Frame = &WindowFramePb{
+			Range:          m.Frame.Range,
+			StartUnbounded: m.Frame.Start.Unbounded,
+			StartOffset:    m.Frame.Start.Offset,
+			EndUnbounded:   m.Frame.End.Unbounded,
+			EndOffset:      m.Frame.End.Offset,
+		}
+	}
+	return pb
+}
+func windowFromPb(pb *WindowPb) *Window {
+	if pb == nil {
+		return nil
+	}
+	m := &Window{
+		PartitionBy: ColumnsFromPb(pb.PartitionBy),
+		OrderBy:     ColumnsFromPb(pb.OrderBy),
+	}
+	if f := pb.Frame; f != nil {
+		m.Frame = &WindowFrame{
+			Range: f.Range,
+			Start: WindowBound{Unbounded: f.StartUnbounded, Offset: f.StartOffset},
+			End:   WindowBound{Unbounded: f.EndUnbounded, Offset: f.EndOffset},
+		}
+	}
+	return m
+}
+
+func (m *WindowFrame) String() string {
+	w := expr.NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *WindowFrame) WriteDialect(w expr.DialectWriter) {
+	if m.Range {
+		io.WriteString(w, "RANGE BETWEEN ")
+	} else {
+		io.WriteString(w, "ROWS BETWEEN ")
+	}
+	io.WriteString(w, m.Start.String())
+	io.WriteString(w, " AND ")
+	io.WriteString(w, m.End.String())
+}
+
+func (m WindowBound) String() string {
+	switch {
+	case m.Unbounded && m.Offset < 0:
+		return "UNBOUNDED PRECEDING"
+	case m.Unbounded:
+		return "UNBOUNDED FOLLOWING"
+	case m.Offset < 0:
+		return fmt.Sprintf("%d PRECEDING", -m.Offset)
+	case m.Offset > 0:
+		return fmt.Sprintf("%d FOLLOWING", m.Offset)
+	}
+	return "CURRENT ROW"
+}
+
 func (m *PreparedStatement) Keyword() lex.TokenType { return lex.TokenPrepare }
 func (m *PreparedStatement) String() string {
 	w := expr.NewDefaultWriter()
@@ -997,6 +1184,7 @@ func sqlSelectToPbDepth(m *SqlSelect, depth int) *SqlSelectPb {
 	if m.Into != nil {
 		s.Into = &m.Into.Table
 	}
+	s.Ctes = ctesToPb(m.Ctes)
 	return &s
 }
 func (m *SqlSelect) Equal(ss SqlStatement) bool {
@@ -1088,6 +1276,9 @@ func (m *SqlSelect) Equal(ss SqlStatement) bool {
 	if !m.proj.Equal(s.proj) {
 		return false
 	}
+	if !ctesEqual(m.Ctes, s.Ctes) {
+		return false
+	}
 	return true
 }
 
@@ -1136,6 +1327,7 @@ func SqlSelectFromPb(pb *SqlSelectPb) *SqlSelect {
 		ss.With = make(u.JsonHelper)
 		json.Unmarshal(pb.With, &ss.With)
 	}
+	ss.Ctes = ctesFromPb(pb.Ctes)
 	return &ss
 }
 func (m *SqlSelect) IsAggQuery() bool {
@@ -1144,6 +1336,16 @@ func (m *SqlSelect) IsAggQuery() bool {
 	}
 	return false
 }
+
+// IsWindowQuery does the select have window function (OVER) columns.
+func (m *SqlSelect) IsWindowQuery() bool {
+	for _, col := range m.Columns {
+		if col.Over != nil {
+			return true
+		}
+	}
+	return false
+}
 func (m *SqlSelect) String() string {
 	w := NewSqlDialect()
 	m.writeDialectDepth(0, w)
@@ -1151,6 +1353,9 @@ func (m *SqlSelect) String() string {
 }
 func (m *SqlSelect) writeDialectDepth(depth int, w expr.DialectWriter) {
 
+	if len(m.Ctes) > 0 {
+		writeCtes(w, m.Ctes)
+	}
 	io.WriteString(w, "SELECT ")
 	if m.Distinct {
 		io.WriteString(w, "DISTINCT ")
@@ -1353,8 +1558,12 @@ func (m *SqlSource) writeDialectDepth(depth int, w expr.DialectWriter) {
 		return
 	}
 
-	//   Jointype                Op
-	//  INNER JOIN orders AS o 	ON
+	//   LeftOrRight Jointype               Op
+	//  LEFT OUTER JOIN orders AS o 	ON
+	if int(m.LeftOrRight) != 0 {
+		io.WriteString(w, strings.ToTitle(m.LeftOrRight.String())) // left/right/full
+		io.WriteString(w, " ")
+	}
 	if int(m.JoinType) != 0 {
 		io.WriteString(w, strings.ToTitle(m.JoinType.String())) // inner/outer
 		io.WriteString(w, " ")
@@ -1518,6 +1727,47 @@ func (m *SqlSource) ColumnPositions() map[string]int {
 func (m *SqlSource) JoinNodes() []expr.Node {
 	return m.joinNodes
 }
+
+// OuterJoin for this source joined onto the sources before it, which sides
+// keep their unmatched rows (NULL filling the other side).
+//
+//    LEFT [OUTER] JOIN   =>  left
+//    RIGHT [OUTER] JOIN  =>  right
+//    FULL [OUTER] JOIN   =>  left, right
+//    OUTER JOIN          =>  left
+//
+func (m *SqlSource) OuterJoin() (left, right bool) {
+	switch m.LeftOrRight {
+	case lex.TokenLeft:
+		return true, false
+	case lex.TokenRight:
+		return false, true
+	case lex.TokenFull:
+		return true, true
+	}
+	return m.JoinType == lex.TokenOuter, false
+}
+
+// NullableIn is this source on the null-supplying side of an outer join
+// in stmt, ie its columns may be NULL for rows it didn't match.
+func (m *SqlSource) NullableIn(stmt *SqlSelect) bool {
+	seen := false
+	for _, from := range stmt.From {
+		if from == m {
+			seen = true
+		}
+		keepLeft, keepRight := from.OuterJoin()
+		switch {
+		case keepLeft && from == m:
+			// this source is the right side of a left/full join
+			return true
+		case keepRight && seen && from != m:
+			// a later source right/full joined onto us
+			return true
+		}
+	}
+	return false
+}
 func (m *SqlSource) Finalize() error {
 	if m.final {
 		return nil
@@ -1747,7 +1997,13 @@ func SqlWhereToPb(m *SqlWhere) *SqlWherePb {
 		s.Source = SqlSelectToPb(m.Source)
 	}
 	if m.Expr != nil {
-		s.Expr = m.Expr.NodePb()
+		if len(SubQueries(m.Expr)) > 0 {
+			// sub-query nodes have no pb representation, send the sql text
+			s.Op = int32(lex.TokenSelect)
+			s.Expr = expr.NewStringNode(m.Expr.String()).NodePb()
+		} else {
+			s.Expr = m.Expr.NodePb()
+		}
 	}
 	return &s
 }
@@ -1759,7 +2015,16 @@ func SqlWhereFromPb(pb *SqlWherePb) *SqlWhere {
 		w.Source = SqlSelectFromPb(pb.Source)
 	}
 	if pb.Expr != nil {
-		w.Expr = expr.NodeFromNodePb(pb.GetExpr())
+		if w.Op == lex.TokenSelect && pb.Expr.Sn != nil {
+			n, err := parseSubQueryExpr(pb.Expr.Sn.Text)
+			if err != nil {
+				u.Warnf("could not parse where %q err=%v", pb.Expr.Sn.Text, err)
+			}
+			w.Op = 0
+			w.Expr = n
+		} else {
+			w.Expr = expr.NodeFromNodePb(pb.GetExpr())
+		}
 	}
 	return &w
 }
@@ -1788,6 +2053,11 @@ func (m *SqlInsert) WriteDialect(w expr.DialectWriter) {
 
 	io.WriteString(w, "INSERT INTO ")
 	w.WriteIdentity(m.Table)
+	if m.Select != nil && len(m.Columns) == 0 {
+		io.WriteString(w, " ")
+		m.Select.WriteDialect(w)
+		return
+	}
 	io.WriteString(w, " (")
 
 	for i, col := range m.Columns {
@@ -1796,6 +2066,11 @@ func (m *SqlInsert) WriteDialect(w expr.DialectWriter) {
 		}
 		col.WriteDialect(w)
 	}
+	if m.Select != nil {
+		io.WriteString(w, ") ")
+		m.Select.WriteDialect(w)
+		return
+	}
 	io.WriteString(w, ") VALUES")
 	for i, row := range m.Rows {
 		if i > 0 {
@@ -1879,24 +2154,44 @@ func (m *SqlUpdate) WriteDialect(w expr.DialectWriter) {
 		}
 		firstCol = false
 		w.WriteIdentity(key)
-		w.WriteValue(val.Value)
+		io.WriteString(w, " = ")
+		if val.Expr != nil {
+			val.Expr.WriteDialect(w)
+		} else {
+			w.WriteValue(val.Value)
+		}
 	}
 	if m.Where != nil {
 		io.WriteString(w, " WHERE ")
 		m.Where.WriteDialect(w)
 	}
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, " ORDER BY ")
+		m.OrderBy.WriteDialect(w)
+	}
+	if m.Limit > 0 {
+		io.WriteString(w, fmt.Sprintf(" LIMIT %d", m.Limit))
+	}
 }
 func (m *SqlUpdate) String() string {
 	w := expr.NewDefaultWriter()
 	m.WriteDialect(w)
 	return w.String()
 }
-func (m *SqlUpdate) SqlSelect() *SqlSelect { return sqlSelectFromWhere(m.Table, m.Where) }
+// SqlSelect the select of the rows this update changes, in its order and
+// limited to its limit.
+func (m *SqlUpdate) SqlSelect() *SqlSelect {
+	req := sqlSelectFromWhere(m.Table, m.Where)
+	req.OrderBy = m.OrderBy
+	req.Limit = m.Limit
+	return req
+}
 
 func sqlSelectFromWhere(from string, where *SqlWhere) *SqlSelect {
 	req := NewSqlSelect()
 	req.From = []*SqlSource{NewSqlSource(from)}
 	switch {
+	case where == nil:
 	case where.Expr != nil:
 		req.Where = NewSqlWhere(where.Expr)
 	default:
@@ -1952,9 +2247,14 @@ func (m *CommandColumns) String() string {
 	return strings.Join(s, ", ")
 }
 
-func (m *SqlCommand) Keyword() lex.TokenType            { return m.kw }
-func (m *SqlCommand) FingerPrint(r rune) string         { return m.String() }
-func (m *SqlCommand) String() string                    { return fmt.Sprintf("%s %s", m.Keyword(), m.Columns.String()) }
+func (m *SqlCommand) Keyword() lex.TokenType    { return m.kw }
+func (m *SqlCommand) FingerPrint(r rune) string { return m.String() }
+func (m *SqlCommand) String() string {
+	if m.kw == lex.TokenAnalyze {
+		return fmt.Sprintf("%s table %s", m.Keyword(), m.Identity)
+	}
+	return fmt.Sprintf("%s %s", m.Keyword(), m.Columns.String())
+}
 func (m *SqlCommand) WriteDialect(w expr.DialectWriter) {}
 
 func (m *SqlCreate) Keyword() lex.TokenType            { return lex.TokenCreate }
@@ -1997,6 +2297,8 @@ func statementFromPb(s *SqlStatementPb) SqlStatement {
 	case s.Source != nil:
 		var ss *SqlSource
 		return ss.FromPB(s.Source)
+	case s.Union != nil:
+		return SqlUnionFromPb(s.Union)
 	}
 	return nil
 }
@@ -18,6 +18,10 @@
 		KvInt
 		ColumnPb
 		CommandColumnPb
+		SqlUnionPb
+		WindowPb
+		WindowFramePb
+		SqlCtePb
 */
 package rel
 
@@ -44,6 +48,7 @@ type SqlStatementPb struct {
 	Select           *SqlSelectPb  `protobuf:"bytes,1,opt,name=select" json:"select,omitempty"`
 	Source           *SqlSourcePb  `protobuf:"bytes,2,opt,name=source" json:"source,omitempty"`
 	Projection       *ProjectionPb `protobuf:"bytes,4,opt,name=projection" json:"projection,omitempty"`
+	Union            *SqlUnionPb   `protobuf:"bytes,5,opt,name=union" json:"union,omitempty"`
 	XXX_unrecognized []byte        `json:"-"`
 }
 
@@ -73,6 +78,13 @@ func (m *SqlStatementPb) GetProjection() *ProjectionPb {
 	return nil
 }
 
+func (m *SqlStatementPb) GetUnion() *SqlUnionPb {
+	if m != nil {
+		return m.Union
+	}
+	return nil
+}
+
 type SqlSelectPb struct {
 	Db               string         `protobuf:"bytes,1,req,name=db" json:"db"`
 	Raw              string         `protobuf:"bytes,2,req,name=raw" json:"raw"`
@@ -93,6 +105,7 @@ type SqlSelectPb struct {
 	Finalized        bool           `protobuf:"varint,17,req,name=finalized" json:"finalized"`
 	Schemaqry        bool           `protobuf:"varint,18,req,name=schemaqry" json:"schemaqry"`
 	With             []byte         `protobuf:"bytes,19,opt,name=with" json:"with,omitempty"`
+	Ctes             []*SqlCtePb    `protobuf:"bytes,20,rep,name=ctes" json:"ctes,omitempty"`
 	XXX_unrecognized []byte         `json:"-"`
 }
 
@@ -234,6 +247,13 @@ func (m *SqlSelectPb) GetWith() []byte {
 	return nil
 }
 
+func (m *SqlSelectPb) GetCtes() []*SqlCtePb {
+	if m != nil {
+		return m.Ctes
+	}
+	return nil
+}
+
 type SqlSourcePb struct {
 	Final            bool           `protobuf:"varint,1,opt,name=final" json:"final"`
 	AliasInner       *string        `protobuf:"bytes,2,opt,name=aliasInner" json:"aliasInner,omitempty"`
@@ -544,6 +564,9 @@ type ColumnPb struct {
 	Agg              bool         `protobuf:"varint,15,opt,name=agg" json:"agg"`
 	Expr             *expr.NodePb `protobuf:"bytes,16,opt,name=Expr,json=expr" json:"Expr,omitempty"`
 	Guard            *expr.NodePb `protobuf:"bytes,17,opt,name=Guard,json=guard" json:"Guard,omitempty"`
+	Nulls            *string      `protobuf:"bytes,18,opt,name=nulls" json:"nulls,omitempty"`
+	Collate          *string      `protobuf:"bytes,19,opt,name=collate" json:"collate,omitempty"`
+	Window           *WindowPb    `protobuf:"bytes,20,opt,name=window" json:"window,omitempty"`
 	XXX_unrecognized []byte       `json:"-"`
 }
 
@@ -664,6 +687,27 @@ func (m *ColumnPb) GetGuard() *expr.NodePb {
 	return nil
 }
 
+func (m *ColumnPb) GetNulls() string {
+	if m != nil && m.Nulls != nil {
+		return *m.Nulls
+	}
+	return ""
+}
+
+func (m *ColumnPb) GetCollate() string {
+	if m != nil && m.Collate != nil {
+		return *m.Collate
+	}
+	return ""
+}
+
+func (m *ColumnPb) GetWindow() *WindowPb {
+	if m != nil {
+		return m.Window
+	}
+	return nil
+}
+
 type CommandColumnPb struct {
 	Expr             *expr.NodePb `protobuf:"bytes,1,opt,name=Expr,json=expr" json:"Expr,omitempty"`
 	Name             string       `protobuf:"bytes,2,req,name=name" json:"name"`
@@ -689,6 +733,210 @@ func (m *CommandColumnPb) GetName() string {
 	return ""
 }
 
+type SqlUnionPb struct {
+	Op               int32           `protobuf:"varint,1,req,name=op" json:"op"`
+	All              bool            `protobuf:"varint,2,req,name=all" json:"all"`
+	Raw              string          `protobuf:"bytes,3,req,name=raw" json:"raw"`
+	Left             *SqlStatementPb `protobuf:"bytes,4,opt,name=left" json:"left,omitempty"`
+	Right            *SqlStatementPb `protobuf:"bytes,5,opt,name=right" json:"right,omitempty"`
+	OrderBy          []*ColumnPb     `protobuf:"bytes,6,rep,name=orderBy" json:"orderBy,omitempty"`
+	Limit            int32           `protobuf:"varint,7,opt,name=limit" json:"limit"`
+	Offset           int32           `protobuf:"varint,8,opt,name=offset" json:"offset"`
+	Ctes             []*SqlCtePb     `protobuf:"bytes,9,rep,name=ctes" json:"ctes,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlUnionPb) Reset()                    { *m = SqlUnionPb{} }
+func (m *SqlUnionPb) String() string            { return proto.CompactTextString(m) }
+func (*SqlUnionPb) ProtoMessage()               {}
+func (*SqlUnionPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{9} }
+
+func (m *SqlUnionPb) GetOp() int32 {
+	if m != nil {
+		return m.Op
+	}
+	return 0
+}
+
+func (m *SqlUnionPb) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
+func (m *SqlUnionPb) GetRaw() string {
+	if m != nil {
+		return m.Raw
+	}
+	return ""
+}
+
+func (m *SqlUnionPb) GetLeft() *SqlStatementPb {
+	if m != nil {
+		return m.Left
+	}
+	return nil
+}
+
+func (m *SqlUnionPb) GetRight() *SqlStatementPb {
+	if m != nil {
+		return m.Right
+	}
+	return nil
+}
+
+func (m *SqlUnionPb) GetOrderBy() []*ColumnPb {
+	if m != nil {
+		return m.OrderBy
+	}
+	return nil
+}
+
+func (m *SqlUnionPb) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *SqlUnionPb) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *SqlUnionPb) GetCtes() []*SqlCtePb {
+	if m != nil {
+		return m.Ctes
+	}
+	return nil
+}
+
+type WindowPb struct {
+	PartitionBy      []*ColumnPb    `protobuf:"bytes,1,rep,name=partitionBy" json:"partitionBy,omitempty"`
+	OrderBy          []*ColumnPb    `protobuf:"bytes,2,rep,name=orderBy" json:"orderBy,omitempty"`
+	Frame            *WindowFramePb `protobuf:"bytes,3,opt,name=frame" json:"frame,omitempty"`
+	XXX_unrecognized []byte         `json:"-"`
+}
+
+func (m *WindowPb) Reset()                    { *m = WindowPb{} }
+func (m *WindowPb) String() string            { return proto.CompactTextString(m) }
+func (*WindowPb) ProtoMessage()               {}
+func (*WindowPb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{10} }
+
+func (m *WindowPb) GetPartitionBy() []*ColumnPb {
+	if m != nil {
+		return m.PartitionBy
+	}
+	return nil
+}
+
+func (m *WindowPb) GetOrderBy() []*ColumnPb {
+	if m != nil {
+		return m.OrderBy
+	}
+	return nil
+}
+
+func (m *WindowPb) GetFrame() *WindowFramePb {
+	if m != nil {
+		return m.Frame
+	}
+	return nil
+}
+
+type WindowFramePb struct {
+	Range            bool   `protobuf:"varint,1,opt,name=range" json:"range"`
+	StartUnbounded   bool   `protobuf:"varint,2,opt,name=startUnbounded" json:"startUnbounded"`
+	StartOffset      int64  `protobuf:"varint,3,opt,name=startOffset" json:"startOffset"`
+	EndUnbounded     bool   `protobuf:"varint,4,opt,name=endUnbounded" json:"endUnbounded"`
+	EndOffset        int64  `protobuf:"varint,5,opt,name=endOffset" json:"endOffset"`
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func (m *WindowFramePb) Reset()                    { *m = WindowFramePb{} }
+func (m *WindowFramePb) String() string            { return proto.CompactTextString(m) }
+func (*WindowFramePb) ProtoMessage()               {}
+func (*WindowFramePb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{11} }
+
+func (m *WindowFramePb) GetRange() bool {
+	if m != nil {
+		return m.Range
+	}
+	return false
+}
+
+func (m *WindowFramePb) GetStartUnbounded() bool {
+	if m != nil {
+		return m.StartUnbounded
+	}
+	return false
+}
+
+func (m *WindowFramePb) GetStartOffset() int64 {
+	if m != nil {
+		return m.StartOffset
+	}
+	return 0
+}
+
+func (m *WindowFramePb) GetEndUnbounded() bool {
+	if m != nil {
+		return m.EndUnbounded
+	}
+	return false
+}
+
+func (m *WindowFramePb) GetEndOffset() int64 {
+	if m != nil {
+		return m.EndOffset
+	}
+	return 0
+}
+
+type SqlCtePb struct {
+	Name             string          `protobuf:"bytes,1,req,name=name" json:"name"`
+	Columns          []string        `protobuf:"bytes,2,rep,name=columns" json:"columns,omitempty"`
+	Recursive        bool            `protobuf:"varint,3,opt,name=recursive" json:"recursive"`
+	Stmt             *SqlStatementPb `protobuf:"bytes,4,opt,name=stmt" json:"stmt,omitempty"`
+	XXX_unrecognized []byte          `json:"-"`
+}
+
+func (m *SqlCtePb) Reset()                    { *m = SqlCtePb{} }
+func (m *SqlCtePb) String() string            { return proto.CompactTextString(m) }
+func (*SqlCtePb) ProtoMessage()               {}
+func (*SqlCtePb) Descriptor() ([]byte, []int) { return fileDescriptorSql, []int{12} }
+
+func (m *SqlCtePb) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SqlCtePb) GetColumns() []string {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
+func (m *SqlCtePb) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+func (m *SqlCtePb) GetStmt() *SqlStatementPb {
+	if m != nil {
+		return m.Stmt
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*SqlStatementPb)(nil), "rel.SqlStatementPb")
 	proto.RegisterType((*SqlSelectPb)(nil), "rel.SqlSelectPb")
@@ -699,6 +947,10 @@ func init() {
 	proto.RegisterType((*KvInt)(nil), "rel.KvInt")
 	proto.RegisterType((*ColumnPb)(nil), "rel.ColumnPb")
 	proto.RegisterType((*CommandColumnPb)(nil), "rel.CommandColumnPb")
+	proto.RegisterType((*SqlUnionPb)(nil), "rel.SqlUnionPb")
+	proto.RegisterType((*WindowPb)(nil), "rel.WindowPb")
+	proto.RegisterType((*WindowFramePb)(nil), "rel.WindowFramePb")
+	proto.RegisterType((*SqlCtePb)(nil), "rel.SqlCtePb")
 }
 func (m *SqlStatementPb) Marshal() (data []byte, err error) {
 	size := m.Size()
@@ -745,6 +997,16 @@ func (m *SqlStatementPb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n3
 	}
+	if m.Union != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Union.Size()))
+		n4, err := m.Union.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -924,6 +1186,20 @@ func (m *SqlSelectPb) MarshalTo(data []byte) (int, error) {
 		i = encodeVarintSql(data, i, uint64(len(m.With)))
 		i += copy(data[i:], m.With)
 	}
+	if len(m.Ctes) > 0 {
+		for _, msg := range m.Ctes {
+			data[i] = 0xa2
+			i++
+			data[i] = 0x1
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -1379,6 +1655,34 @@ func (m *ColumnPb) MarshalTo(data []byte) (int, error) {
 		}
 		i += n14
 	}
+	if m.Nulls != nil {
+		data[i] = 0x92
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintSql(data, i, uint64(len(*m.Nulls)))
+		i += copy(data[i:], *m.Nulls)
+	}
+	if m.Collate != nil {
+		data[i] = 0x9a
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintSql(data, i, uint64(len(*m.Collate)))
+		i += copy(data[i:], *m.Collate)
+	}
+	if m.Window != nil {
+		data[i] = 0xa2
+		i++
+		data[i] = 0x1
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Window.Size()))
+		n15, err := m.Window.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(data[i:], m.XXX_unrecognized)
 	}
@@ -1420,52 +1724,306 @@ func (m *CommandColumnPb) MarshalTo(data []byte) (int, error) {
 	return i, nil
 }
 
-func encodeFixed64Sql(data []byte, offset int, v uint64) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	data[offset+4] = uint8(v >> 32)
-	data[offset+5] = uint8(v >> 40)
-	data[offset+6] = uint8(v >> 48)
-	data[offset+7] = uint8(v >> 56)
-	return offset + 8
-}
-func encodeFixed32Sql(data []byte, offset int, v uint32) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	return offset + 4
-}
-func encodeVarintSql(data []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		data[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *SqlUnionPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
 	}
-	data[offset] = uint8(v)
-	return offset + 1
+	return data[:n], nil
 }
-func (m *SqlStatementPb) Size() (n int) {
+
+func (m *SqlUnionPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if m.Select != nil {
-		l = m.Select.Size()
-		n += 1 + l + sovSql(uint64(l))
-	}
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovSql(uint64(l))
+	data[i] = 0x8
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Op))
+	data[i] = 0x10
+	i++
+	if m.All {
+		data[i] = 1
+	} else {
+		data[i] = 0
 	}
-	if m.Projection != nil {
-		l = m.Projection.Size()
-		n += 1 + l + sovSql(uint64(l))
+	i++
+	data[i] = 0x1a
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Raw)))
+	i += copy(data[i:], m.Raw)
+	if m.Left != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Left.Size()))
+		n, err := m.Left.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if m.Right != nil {
+		data[i] = 0x2a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Right.Size()))
+		n, err := m.Right.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
 	}
-	return n
+	if len(m.OrderBy) > 0 {
+		for _, msg := range m.OrderBy {
+			data[i] = 0x32
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	data[i] = 0x38
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Limit))
+	data[i] = 0x40
+	i++
+	i = encodeVarintSql(data, i, uint64(m.Offset))
+	if len(m.Ctes) > 0 {
+		for _, msg := range m.Ctes {
+			data[i] = 0x4a
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *WindowPb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *WindowPb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.PartitionBy) > 0 {
+		for _, msg := range m.PartitionBy {
+			data[i] = 0xa
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.OrderBy) > 0 {
+		for _, msg := range m.OrderBy {
+			data[i] = 0x12
+			i++
+			i = encodeVarintSql(data, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(data[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Frame != nil {
+		data[i] = 0x1a
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Frame.Size()))
+		n, err := m.Frame.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *WindowFramePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *WindowFramePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0x8
+	i++
+	if m.Range {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	data[i] = 0x10
+	i++
+	if m.StartUnbounded {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	data[i] = 0x18
+	i++
+	i = encodeVarintSql(data, i, uint64(m.StartOffset))
+	data[i] = 0x20
+	i++
+	if m.EndUnbounded {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	data[i] = 0x28
+	i++
+	i = encodeVarintSql(data, i, uint64(m.EndOffset))
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func (m *SqlCtePb) Marshal() (data []byte, err error) {
+	size := m.Size()
+	data = make([]byte, size)
+	n, err := m.MarshalTo(data)
+	if err != nil {
+		return nil, err
+	}
+	return data[:n], nil
+}
+
+func (m *SqlCtePb) MarshalTo(data []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	data[i] = 0xa
+	i++
+	i = encodeVarintSql(data, i, uint64(len(m.Name)))
+	i += copy(data[i:], m.Name)
+	if len(m.Columns) > 0 {
+		for _, s := range m.Columns {
+			data[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				data[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			data[i] = uint8(l)
+			i++
+			i += copy(data[i:], s)
+		}
+	}
+	data[i] = 0x18
+	i++
+	if m.Recursive {
+		data[i] = 1
+	} else {
+		data[i] = 0
+	}
+	i++
+	if m.Stmt != nil {
+		data[i] = 0x22
+		i++
+		i = encodeVarintSql(data, i, uint64(m.Stmt.Size()))
+		n, err := m.Stmt.MarshalTo(data[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(data[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
+func encodeFixed64Sql(data []byte, offset int, v uint64) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	data[offset+4] = uint8(v >> 32)
+	data[offset+5] = uint8(v >> 40)
+	data[offset+6] = uint8(v >> 48)
+	data[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Sql(data []byte, offset int, v uint32) int {
+	data[offset] = uint8(v)
+	data[offset+1] = uint8(v >> 8)
+	data[offset+2] = uint8(v >> 16)
+	data[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintSql(data []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		data[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	data[offset] = uint8(v)
+	return offset + 1
+}
+func (m *SqlStatementPb) Size() (n int) {
+	var l int
+	_ = l
+	if m.Select != nil {
+		l = m.Select.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Source != nil {
+		l = m.Source.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Projection != nil {
+		l = m.Projection.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Union != nil {
+		l = m.Union.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
 }
 
 func (m *SqlSelectPb) Size() (n int) {
@@ -1530,6 +2088,12 @@ func (m *SqlSelectPb) Size() (n int) {
 		l = len(m.With)
 		n += 2 + l + sovSql(uint64(l))
 	}
+	if len(m.Ctes) > 0 {
+		for _, e := range m.Ctes {
+			l = e.Size()
+			n += 2 + l + sovSql(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1720,6 +2284,18 @@ func (m *ColumnPb) Size() (n int) {
 		l = m.Guard.Size()
 		n += 2 + l + sovSql(uint64(l))
 	}
+	if m.Nulls != nil {
+		l = len(*m.Nulls)
+		n += 2 + l + sovSql(uint64(l))
+	}
+	if m.Collate != nil {
+		l = len(*m.Collate)
+		n += 2 + l + sovSql(uint64(l))
+	}
+	if m.Window != nil {
+		l = m.Window.Size()
+		n += 2 + l + sovSql(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1741,21 +2317,117 @@ func (m *CommandColumnPb) Size() (n int) {
 	return n
 }
 
-func sovSql(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+func (m *SqlUnionPb) Size() (n int) {
+	var l int
+	_ = l
+	n += 1 + sovSql(uint64(m.Op))
+	n += 2
+	l = len(m.Raw)
+	n += 1 + l + sovSql(uint64(l))
+	if m.Left != nil {
+		l = m.Left.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.Right != nil {
+		l = m.Right.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
 		}
 	}
-	return n
-}
-func sozSql(x uint64) (n int) {
-	return sovSql(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (m *SqlStatementPb) Unmarshal(data []byte) error {
-	l := len(data)
+	n += 1 + sovSql(uint64(m.Limit))
+	n += 1 + sovSql(uint64(m.Offset))
+	if len(m.Ctes) > 0 {
+		for _, e := range m.Ctes {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowPb) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.PartitionBy) > 0 {
+		for _, e := range m.PartitionBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if len(m.OrderBy) > 0 {
+		for _, e := range m.OrderBy {
+			l = e.Size()
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	if m.Frame != nil {
+		l = m.Frame.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *WindowFramePb) Size() (n int) {
+	var l int
+	_ = l
+	n += 2
+	n += 2
+	n += 1 + sovSql(uint64(m.StartOffset))
+	n += 2
+	n += 1 + sovSql(uint64(m.EndOffset))
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SqlCtePb) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovSql(uint64(l))
+	if len(m.Columns) > 0 {
+		for _, s := range m.Columns {
+			l = len(s)
+			n += 1 + l + sovSql(uint64(l))
+		}
+	}
+	n += 2
+	if m.Stmt != nil {
+		l = m.Stmt.Size()
+		n += 1 + l + sovSql(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovSql(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozSql(x uint64) (n int) {
+	return sovSql(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *SqlStatementPb) Unmarshal(data []byte) error {
+	l := len(data)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1882,6 +2554,39 @@ func (m *SqlStatementPb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Union", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Union == nil {
+				m.Union = &SqlUnionPb{}
+			}
+			if err := m.Union.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -2451,6 +3156,37 @@ func (m *SqlSelectPb) Unmarshal(data []byte) error {
 				m.With = []byte{}
 			}
 			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ctes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ctes = append(m.Ctes, &SqlCtePb{})
+			if err := m.Ctes[len(m.Ctes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -4076,6 +4812,99 @@ func (m *ColumnPb) Unmarshal(data []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nulls", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(data[iNdEx:postIndex])
+			m.Nulls = &s
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Collate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(data[iNdEx:postIndex])
+			m.Collate = &s
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Window == nil {
+				m.Window = &WindowPb{}
+			}
+			if err := m.Window.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSql(data[iNdEx:])
@@ -4216,6 +5045,766 @@ func (m *CommandColumnPb) Unmarshal(data []byte) error {
 	}
 	return nil
 }
+func (m *SqlUnionPb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlUnionPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlUnionPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Op", wireType)
+			}
+			m.Op = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Op |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field All", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.All = bool(v != 0)
+			hasFields[0] |= uint64(0x00000002)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Raw = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000004)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Left == nil {
+				m.Left = &SqlStatementPb{}
+			}
+			if err := m.Left.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Right == nil {
+				m.Right = &SqlStatementPb{}
+			}
+			if err := m.Right.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = append(m.OrderBy, &ColumnPb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Limit |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.Offset |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ctes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ctes = append(m.Ctes, &SqlCtePb{})
+			if err := m.Ctes[len(m.Ctes)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000002) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+	if hasFields[0]&uint64(0x00000004) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowPb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowPb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowPb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PartitionBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PartitionBy = append(m.PartitionBy, &ColumnPb{})
+			if err := m.PartitionBy[len(m.PartitionBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderBy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderBy = append(m.OrderBy, &ColumnPb{})
+			if err := m.OrderBy[len(m.OrderBy)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Frame", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Frame == nil {
+				m.Frame = &WindowFramePb{}
+			}
+			if err := m.Frame.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WindowFramePb) Unmarshal(data []byte) error {
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WindowFramePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WindowFramePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Range = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartUnbounded", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.StartUnbounded = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartOffset", wireType)
+			}
+			m.StartOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.StartOffset |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndUnbounded", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.EndUnbounded = bool(v != 0)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EndOffset", wireType)
+			}
+			m.EndOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				m.EndOffset |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SqlCtePb) Unmarshal(data []byte) error {
+	var hasFields [1]uint64
+	l := len(data)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSql
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := data[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SqlCtePb: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SqlCtePb: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(data[iNdEx:postIndex])
+			iNdEx = postIndex
+			hasFields[0] |= uint64(0x00000001)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Columns", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Columns = append(m.Columns, string(data[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recursive", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Recursive = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stmt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSql
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := data[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSql
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stmt == nil {
+				m.Stmt = &SqlStatementPb{}
+			}
+			if err := m.Stmt.Unmarshal(data[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSql(data[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSql
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, data[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+	if hasFields[0]&uint64(0x00000001) == 0 {
+		return new(github_com_golang_protobuf_proto.RequiredNotSetError)
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipSql(data []byte) (n int, err error) {
 	l := len(data)
 	iNdEx := 0
@@ -4322,72 +5911,89 @@ var (
 )
 
 var fileDescriptorSql = []byte{
-	// 1067 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
-	0x10, 0x0e, 0x25, 0x51, 0x96, 0x56, 0x8e, 0xed, 0x6c, 0x82, 0x62, 0x61, 0x14, 0xae, 0x41, 0x14,
-	0x81, 0xd1, 0x34, 0x52, 0x91, 0x1e, 0x7a, 0x8e, 0x83, 0xb6, 0x30, 0x0a, 0xa4, 0x8e, 0x5c, 0xa0,
-	0x67, 0x4a, 0x5c, 0x51, 0x8c, 0x29, 0xae, 0xbc, 0x24, 0xe5, 0x38, 0x4f, 0xd2, 0x4b, 0x81, 0x5e,
-	0x7b, 0xeb, 0x33, 0xf4, 0xe4, 0x63, 0x9f, 0xa0, 0xe8, 0x0f, 0xfa, 0x1e, 0x9d, 0x99, 0x25, 0x97,
-	0x6b, 0x47, 0x72, 0x72, 0x10, 0xc0, 0xfd, 0xe6, 0xdb, 0xdd, 0x99, 0xd9, 0x6f, 0x66, 0xc4, 0xfa,
-	0xf9, 0x45, 0x3a, 0x5c, 0x6a, 0x55, 0x28, 0xde, 0xd6, 0x32, 0xdd, 0x7f, 0x12, 0x27, 0xc5, 0xbc,
-	0x9c, 0x0c, 0xa7, 0x6a, 0x31, 0x0a, 0x75, 0x18, 0x45, 0x2a, 0x1b, 0x5d, 0xa4, 0x13, 0x9d, 0x44,
-	0xb1, 0x1c, 0xc9, 0x37, 0x4b, 0x3d, 0xca, 0x54, 0x24, 0xcd, 0x8e, 0xfd, 0xa7, 0x0e, 0x39, 0x56,
-	0xb1, 0x1a, 0x11, 0x3c, 0x29, 0x67, 0xb4, 0xa2, 0x05, 0x7d, 0x19, 0x7a, 0xf0, 0xab, 0xc7, 0x76,
-	0xce, 0x2e, 0xd2, 0xb3, 0x22, 0x2c, 0xe4, 0x42, 0x66, 0xc5, 0xe9, 0x84, 0x0f, 0x59, 0x37, 0x97,
-	0xa9, 0x9c, 0x16, 0xc2, 0x3b, 0xf4, 0x8e, 0x06, 0xcf, 0xf6, 0x86, 0xe0, 0xc4, 0x10, 0x49, 0x84,
-	0x9e, 0x4e, 0x8e, 0x3b, 0xd7, 0x7f, 0x7e, 0xe2, 0x8d, 0x2b, 0x16, 0xf1, 0x55, 0xa9, 0xa7, 0x52,
-	0xb4, 0x6e, 0xf1, 0x09, 0x75, 0xf8, 0xb4, 0xe6, 0x5f, 0x31, 0x06, 0x77, 0xbf, 0x86, 0xad, 0x89,
-	0xca, 0x44, 0x87, 0xf6, 0x3c, 0xa0, 0x3d, 0xa7, 0x16, 0xb6, 0x9b, 0x1c, 0x6a, 0xf0, 0x9b, 0xcf,
-	0x06, 0x8e, 0x1b, 0xfc, 0x11, 0x6b, 0x45, 0x13, 0x70, 0xb2, 0x75, 0xd4, 0x27, 0xf6, 0xbd, 0x31,
-	0xac, 0xf9, 0x47, 0xac, 0xad, 0xc3, 0x4b, 0xf0, 0xa5, 0x81, 0x11, 0xe0, 0x82, 0x75, 0xf2, 0x22,
-	0xd4, 0xa2, 0x0d, 0x86, 0x5e, 0x65, 0x20, 0x84, 0x1f, 0xb2, 0x5e, 0x94, 0xe4, 0x45, 0x92, 0x41,
-	0xc8, 0x1d, 0xc7, 0x6a, 0x51, 0xfe, 0x94, 0x6d, 0x4d, 0x55, 0x5a, 0x2e, 0xb2, 0x5c, 0xf8, 0x87,
-	0x6d, 0xf0, 0xf7, 0x3e, 0xf9, 0xfb, 0x82, 0x30, 0xeb, 0x6b, 0xcd, 0xe1, 0x9f, 0xb1, 0xce, 0x4c,
-	0xab, 0x85, 0xe8, 0x12, 0x77, 0x53, 0x3e, 0x88, 0x83, 0x6e, 0x25, 0x59, 0xa1, 0xc4, 0x16, 0xe4,
-	0xa1, 0x5f, 0x5b, 0x10, 0xe1, 0x4f, 0x98, 0x7f, 0x39, 0x97, 0x5a, 0x8a, 0x1e, 0xa5, 0x68, 0xb7,
-	0x3e, 0xe6, 0x47, 0x04, 0xed, 0x29, 0x86, 0x03, 0x57, 0x76, 0xe7, 0xe1, 0x2a, 0xc9, 0x62, 0xd1,
-	0x27, 0xf6, 0xf6, 0x10, 0x85, 0x31, 0x7c, 0x09, 0xc2, 0x68, 0x1e, 0xc0, 0x30, 0x30, 0x1a, 0xa5,
-	0x23, 0xa9, 0x8f, 0xaf, 0x04, 0xbb, 0x23, 0x9a, 0x8a, 0x83, 0xf4, 0x58, 0xab, 0x72, 0x09, 0xf4,
-	0xc1, 0x1d, 0xf4, 0x8a, 0xc3, 0xf7, 0x99, 0x9f, 0x26, 0x8b, 0xa4, 0x10, 0xdb, 0xe0, 0x88, 0x5f,
-	0xa5, 0xd2, 0x40, 0xfc, 0x63, 0xd6, 0x55, 0xb3, 0x59, 0x2e, 0x0b, 0x71, 0xdf, 0x31, 0x56, 0x18,
-	0xee, 0x0c, 0xd3, 0x24, 0xcc, 0xc5, 0x8e, 0x93, 0x0b, 0x03, 0xdd, 0x12, 0xcd, 0xee, 0x07, 0x8b,
-	0x06, 0x0f, 0x4d, 0xf2, 0xe7, 0x71, 0x2c, 0xf6, 0x9c, 0x97, 0x35, 0x10, 0x0f, 0x58, 0x7f, 0x96,
-	0x64, 0x70, 0xc1, 0x5b, 0x19, 0x89, 0x07, 0x8e, 0xbd, 0x81, 0x91, 0x93, 0x4f, 0xe7, 0x72, 0x11,
-	0x5e, 0xe8, 0x2b, 0xc1, 0x5d, 0x8e, 0x85, 0xf1, 0x0d, 0x2f, 0xa1, 0xea, 0xc4, 0x43, 0x70, 0x6b,
-	0xbb, 0x7e, 0x43, 0x44, 0x82, 0xdf, 0x3b, 0x46, 0xb2, 0xd5, 0xcb, 0xa3, 0x37, 0x74, 0x34, 0x95,
-	0x96, 0xf5, 0x86, 0x20, 0xfe, 0x29, 0x63, 0x14, 0xeb, 0x49, 0x96, 0x49, 0x4d, 0xb5, 0x54, 0xe7,
-	0xc0, 0xc1, 0x5d, 0x29, 0xb6, 0x3f, 0x40, 0x8a, 0x9f, 0xb3, 0x1e, 0x7c, 0x9e, 0x64, 0x91, 0x7c,
-	0x03, 0xda, 0x46, 0x3e, 0x23, 0xfe, 0x77, 0xab, 0x93, 0xac, 0xa8, 0x75, 0x5e, 0x33, 0xf8, 0x17,
-	0xac, 0xff, 0x5a, 0x25, 0x19, 0xaa, 0xa6, 0x56, 0xfa, 0x3a, 0x21, 0x35, 0x24, 0xa7, 0xf8, 0xbb,
-	0xef, 0x69, 0x16, 0xa6, 0xf8, 0xab, 0xea, 0x6c, 0xd4, 0xde, 0x54, 0x67, 0x16, 0x2e, 0x8c, 0xd6,
-	0x6b, 0x03, 0x21, 0x8d, 0x2a, 0xfa, 0x8e, 0xa9, 0x52, 0x05, 0x74, 0x00, 0xb5, 0x04, 0x11, 0xb7,
-	0xac, 0x96, 0x60, 0xcd, 0x1f, 0xb3, 0x41, 0x2a, 0x67, 0xc5, 0xf7, 0x7a, 0x9c, 0xc4, 0xf3, 0x02,
-	0x44, 0xdb, 0x98, 0x5d, 0x03, 0xd6, 0x3d, 0x06, 0xf2, 0xc3, 0xd5, 0x52, 0x82, 0x58, 0x1b, 0x92,
-	0x45, 0x21, 0x3a, 0xfa, 0xfe, 0x1a, 0x32, 0x40, 0x8a, 0x5d, 0x9f, 0x0e, 0xcb, 0xe1, 0xcf, 0x58,
-	0x2f, 0x2f, 0x27, 0xaf, 0x4a, 0x09, 0x5a, 0xd9, 0xb9, 0x33, 0x1f, 0x96, 0x87, 0x5e, 0xe4, 0x52,
-	0x9e, 0x87, 0x93, 0x54, 0x92, 0xae, 0x6d, 0xf7, 0xa9, 0xd1, 0xe0, 0x2d, 0x63, 0x4d, 0xd9, 0x57,
-	0x31, 0x7b, 0xb7, 0x62, 0xde, 0xdc, 0x84, 0xd7, 0xbf, 0xc3, 0x63, 0xd6, 0xa1, 0xa8, 0xda, 0x1b,
-	0xa3, 0xea, 0x20, 0x14, 0xfc, 0xec, 0xb1, 0x6d, 0xb7, 0xc2, 0x6e, 0x34, 0x4b, 0x6f, 0x6d, 0xb3,
-	0xb4, 0x1a, 0x6f, 0xb9, 0x15, 0x67, 0x34, 0xbe, 0x4f, 0x72, 0x7c, 0x09, 0xef, 0x6a, 0xe4, 0xdb,
-	0x1f, 0xdb, 0x35, 0xff, 0xb2, 0x51, 0xb6, 0x51, 0xea, 0x43, 0x8a, 0x61, 0x2c, 0xf3, 0x32, 0x2d,
-	0x36, 0xe8, 0x3b, 0xf8, 0x0f, 0xe6, 0xd7, 0x4d, 0xc6, 0xba, 0x1a, 0xf3, 0xea, 0xfb, 0x6b, 0x99,
-	0xb9, 0xd3, 0xc1, 0xc8, 0x0c, 0x5a, 0x13, 0x9c, 0x79, 0xaa, 0x72, 0x1a, 0x10, 0xb6, 0x35, 0x19,
-	0x0c, 0x7a, 0x71, 0xd7, 0xdc, 0x58, 0xcd, 0xab, 0xb5, 0x45, 0x57, 0x51, 0xec, 0xa4, 0xf1, 0x9d,
-	0xfb, 0xcd, 0xa4, 0x81, 0xb7, 0x03, 0x21, 0x77, 0xdd, 0x89, 0x05, 0x2a, 0x86, 0x16, 0xb3, 0x0a,
-	0xd3, 0x52, 0x92, 0x10, 0xb7, 0x9c, 0xdb, 0x1b, 0x38, 0x18, 0x31, 0x9f, 0x4a, 0x96, 0x73, 0xe6,
-	0x9d, 0xdf, 0x98, 0x79, 0xde, 0x39, 0x62, 0x2b, 0x0a, 0xa9, 0xde, 0xe8, 0xad, 0x82, 0x5f, 0x3a,
-	0xac, 0x67, 0x53, 0x02, 0x15, 0x61, 0xde, 0xfd, 0x55, 0xa9, 0x0a, 0x49, 0x89, 0xa9, 0xfb, 0x94,
-	0x6b, 0x40, 0x5e, 0x98, 0xd3, 0xe7, 0xf1, 0x55, 0x61, 0xa4, 0x64, 0x79, 0x8e, 0x01, 0x5b, 0x95,
-	0xd2, 0x49, 0x8c, 0x29, 0x7d, 0x9e, 0x93, 0x86, 0x6c, 0xab, 0x6a, 0x70, 0xcc, 0x03, 0x96, 0x1b,
-	0xa5, 0xcc, 0x8e, 0x36, 0x44, 0xf0, 0x89, 0x34, 0xd5, 0xa6, 0xef, 0x76, 0x7a, 0x82, 0xd0, 0x87,
-	0x65, 0xa8, 0xe1, 0xaf, 0x88, 0x69, 0x5a, 0x5d, 0x67, 0x50, 0xb8, 0x06, 0x6a, 0xec, 0xc4, 0xd8,
-	0x72, 0xe7, 0x0c, 0x41, 0x4d, 0xbc, 0xe6, 0x8c, 0x9e, 0x7b, 0x86, 0x63, 0x68, 0x78, 0xdf, 0x24,
-	0x32, 0x8d, 0x9c, 0x0e, 0x63, 0xf3, 0x42, 0x86, 0xea, 0xdd, 0x06, 0x4e, 0x03, 0xc2, 0x77, 0x3b,
-	0x40, 0xc1, 0x2e, 0xf0, 0x5f, 0x13, 0xcd, 0xba, 0x7e, 0xa3, 0x4d, 0x02, 0xd1, 0x43, 0x9a, 0xa1,
-	0xd4, 0x3a, 0x6c, 0x94, 0x04, 0x59, 0x8d, 0xec, 0xbc, 0xa3, 0x11, 0xe8, 0x90, 0x21, 0x8c, 0x2b,
-	0xb7, 0x15, 0x20, 0x60, 0x2b, 0x76, 0xef, 0xee, 0x8a, 0xe5, 0x47, 0xcc, 0xff, 0xb6, 0x0c, 0x35,
-	0x0e, 0xb4, 0x4d, 0x44, 0x3f, 0x46, 0x42, 0x70, 0xc6, 0x76, 0x5f, 0x80, 0xab, 0x61, 0x16, 0x39,
-	0x42, 0x31, 0x97, 0x78, 0xef, 0xb9, 0x64, 0x63, 0x1d, 0x1d, 0x3f, 0xba, 0xfe, 0xfb, 0xc0, 0xbb,
-	0xfe, 0xe7, 0xc0, 0xfb, 0x03, 0x7e, 0x7f, 0xc1, 0xef, 0xa7, 0x7f, 0x0f, 0xee, 0xfd, 0x1f, 0x00,
-	0x00, 0xff, 0xff, 0xa8, 0x40, 0xb5, 0x23, 0xd3, 0x0a, 0x00, 0x00,
+	// 1342 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xcb, 0x8e, 0x1b, 0x45,
+	0x17, 0xfe, 0xab, 0xed, 0xf6, 0xd8, 0x65, 0xcf, 0x25, 0x95, 0x28, 0x2a, 0x8d, 0x7e, 0x0d, 0x56,
+	0x0b, 0x05, 0x2b, 0x17, 0x1b, 0x05, 0x21, 0xd6, 0x99, 0x88, 0xa0, 0x08, 0x29, 0x99, 0x78, 0x88,
+	0xb2, 0x6e, 0xbb, 0xcb, 0x3d, 0x9d, 0x74, 0x57, 0x79, 0xaa, 0xab, 0x3d, 0x71, 0xde, 0x80, 0x27,
+	0x60, 0x85, 0xc4, 0xb3, 0x20, 0x16, 0xd9, 0xc1, 0x0a, 0x89, 0x0d, 0x82, 0x48, 0xbc, 0x07, 0xaa,
+	0xd3, 0xb7, 0xe3, 0x89, 0xed, 0x19, 0x76, 0xf6, 0x77, 0xbe, 0xaa, 0x3e, 0x97, 0xef, 0x9c, 0x53,
+	0xb4, 0x93, 0x9e, 0xc7, 0xc3, 0xb9, 0x56, 0x46, 0xb1, 0x86, 0x16, 0xf1, 0xe1, 0xbd, 0x30, 0x32,
+	0x67, 0xd9, 0x64, 0x38, 0x55, 0xc9, 0xc8, 0xd7, 0x7e, 0x10, 0x28, 0x39, 0x3a, 0x8f, 0x27, 0x3a,
+	0x0a, 0x42, 0x31, 0x12, 0x6f, 0xe7, 0x7a, 0x24, 0x55, 0x20, 0xf2, 0x13, 0x87, 0x0f, 0x10, 0x39,
+	0x54, 0xa1, 0x1a, 0x01, 0x3c, 0xc9, 0x66, 0xf0, 0x0f, 0xfe, 0xc0, 0xaf, 0x9c, 0xee, 0xfd, 0x4e,
+	0xe8, 0xde, 0xe9, 0x79, 0x7c, 0x6a, 0x7c, 0x23, 0x12, 0x21, 0xcd, 0xc9, 0x84, 0x0d, 0x69, 0x2b,
+	0x15, 0xb1, 0x98, 0x1a, 0x4e, 0xfa, 0x64, 0xd0, 0x7d, 0x78, 0x30, 0xd4, 0x22, 0x1e, 0x5a, 0x12,
+	0xa0, 0x27, 0x93, 0xe3, 0xe6, 0xfb, 0x3f, 0x3f, 0x21, 0xe3, 0x82, 0x05, 0x7c, 0x95, 0xe9, 0xa9,
+	0xe0, 0xce, 0x25, 0x3e, 0xa0, 0x88, 0x0f, 0xff, 0xd9, 0x57, 0x94, 0xce, 0xb5, 0x7a, 0x2d, 0xa6,
+	0x26, 0x52, 0x92, 0x37, 0xe1, 0xcc, 0x0d, 0x38, 0x73, 0x52, 0xc1, 0xd5, 0x21, 0x44, 0x65, 0xf7,
+	0xa8, 0x9b, 0x49, 0x7b, 0xc6, 0x85, 0x33, 0xfb, 0xe5, 0x77, 0x5e, 0x4a, 0x7c, 0x22, 0xe7, 0x78,
+	0x7f, 0xb8, 0xb4, 0x8b, 0x7c, 0x66, 0xb7, 0xa8, 0x13, 0x4c, 0x38, 0xe9, 0x3b, 0x83, 0x0e, 0x10,
+	0xff, 0x37, 0x76, 0x82, 0x09, 0xbb, 0x4d, 0x1b, 0xda, 0xbf, 0xe0, 0x0e, 0x82, 0x2d, 0xc0, 0x38,
+	0x6d, 0xa6, 0xc6, 0xd7, 0xbc, 0xd1, 0x77, 0x06, 0xed, 0xc2, 0x00, 0x08, 0xeb, 0xd3, 0x76, 0x10,
+	0xa5, 0x26, 0x92, 0x53, 0xc3, 0x9b, 0xc8, 0x5a, 0xa1, 0xec, 0x01, 0xdd, 0x99, 0xaa, 0x38, 0x4b,
+	0x64, 0xca, 0xdd, 0x7e, 0x63, 0xd0, 0x7d, 0xb8, 0x0b, 0x8e, 0x3e, 0x06, 0xac, 0x72, 0xb3, 0xe4,
+	0xb0, 0xbb, 0xb4, 0x39, 0xd3, 0x2a, 0xe1, 0xad, 0x7e, 0x63, 0x4b, 0xf2, 0x80, 0x63, 0xdd, 0x8a,
+	0xa4, 0x51, 0x7c, 0xa7, 0x4f, 0x0a, 0x7f, 0xc9, 0x18, 0x10, 0x9b, 0x9b, 0x8b, 0x33, 0xa1, 0x05,
+	0x6f, 0xaf, 0xe6, 0xe6, 0x95, 0x05, 0xeb, 0xdc, 0x00, 0x87, 0xdd, 0xa5, 0xad, 0x33, 0x7f, 0x11,
+	0xc9, 0x90, 0x77, 0x80, 0xdd, 0x1b, 0x5a, 0x15, 0x0d, 0x9f, 0xa9, 0x00, 0x55, 0x2b, 0x67, 0xd8,
+	0x68, 0x42, 0xad, 0xb2, 0xf9, 0xf1, 0x92, 0xd3, 0x2d, 0xd1, 0x14, 0x1c, 0x4b, 0x57, 0x3a, 0x10,
+	0xfa, 0x78, 0xc9, 0xbb, 0x5b, 0xe8, 0x05, 0x87, 0x1d, 0x52, 0x37, 0x8e, 0x92, 0xc8, 0xf0, 0x5e,
+	0x9f, 0x0c, 0xdc, 0x22, 0x95, 0x39, 0xc4, 0xfe, 0x4f, 0x5b, 0x6a, 0x36, 0x4b, 0x85, 0xe1, 0xbb,
+	0xc8, 0x58, 0x60, 0xf6, 0xa4, 0x1f, 0x47, 0x7e, 0xca, 0xf7, 0x50, 0x2e, 0x72, 0xe8, 0x92, 0xc2,
+	0xf6, 0xaf, 0xaf, 0xb0, 0x43, 0xea, 0x46, 0xe9, 0xa3, 0x30, 0xe4, 0x07, 0xa8, 0xb2, 0x39, 0xc4,
+	0x3c, 0xda, 0x99, 0x45, 0xd2, 0x8f, 0xa3, 0x77, 0x22, 0xe0, 0x37, 0x90, 0xbd, 0x86, 0x2d, 0x27,
+	0x9d, 0x9e, 0x89, 0xc4, 0x3f, 0xd7, 0x4b, 0xce, 0x30, 0xa7, 0x82, 0x6d, 0x0d, 0x2f, 0x22, 0x73,
+	0xc6, 0x6f, 0xf6, 0xc9, 0xa0, 0x57, 0xd6, 0xd0, 0x22, 0xec, 0x33, 0xda, 0x9c, 0x1a, 0x91, 0xf2,
+	0x5b, 0x28, 0x71, 0xa7, 0xe7, 0xf1, 0x63, 0x83, 0x64, 0x60, 0x09, 0xde, 0xcf, 0x4d, 0xda, 0x45,
+	0x12, 0xb1, 0x6e, 0x83, 0x0f, 0xd0, 0xb0, 0x95, 0xdb, 0x00, 0xb1, 0x4f, 0x29, 0x85, 0xa4, 0x3c,
+	0x95, 0x52, 0x68, 0xee, 0xa0, 0x64, 0x21, 0x1c, 0x6b, 0xb6, 0x71, 0x0d, 0xcd, 0xde, 0xa7, 0xed,
+	0xa9, 0x8a, 0x9f, 0xca, 0x40, 0xbc, 0xe5, 0x4d, 0xe0, 0x53, 0xe0, 0x7f, 0xbb, 0x78, 0x2a, 0x4d,
+	0xd9, 0x10, 0x25, 0x83, 0x7d, 0x4e, 0x3b, 0xaf, 0x55, 0x24, 0xad, 0xbc, 0xca, 0x96, 0x58, 0xa7,
+	0xb8, 0x9a, 0x84, 0x46, 0x4a, 0xeb, 0x8a, 0x11, 0x04, 0xac, 0xb2, 0x8d, 0xeb, 0xb6, 0xa8, 0xdb,
+	0x58, 0xfa, 0x49, 0xde, 0x14, 0xa5, 0x01, 0x90, 0x5a, 0x3e, 0x1d, 0x64, 0xca, 0x21, 0x3b, 0x2a,
+	0xd4, 0x9c, 0xd3, 0xbe, 0x53, 0x89, 0xce, 0x51, 0x73, 0x76, 0x87, 0x76, 0x63, 0x31, 0x33, 0xcf,
+	0xf5, 0x38, 0x0a, 0xcf, 0x0c, 0xef, 0x22, 0x33, 0x36, 0xd8, 0x01, 0x61, 0x03, 0xf9, 0x6e, 0x39,
+	0x17, 0xbc, 0x87, 0x48, 0x15, 0xca, 0x86, 0x39, 0xe3, 0xeb, 0xb7, 0x73, 0x0d, 0xd2, 0x5e, 0x9f,
+	0x8e, 0x8a, 0xc3, 0x1e, 0xd2, 0x76, 0x9a, 0x4d, 0x5e, 0x64, 0x42, 0x2f, 0xf9, 0xde, 0xd6, 0x7c,
+	0x54, 0x3c, 0xeb, 0x45, 0x2a, 0xc4, 0x1b, 0x7f, 0x12, 0x0b, 0xbe, 0x8f, 0x54, 0x51, 0xa1, 0xde,
+	0x3b, 0x4a, 0xeb, 0xf9, 0x50, 0xc4, 0x4c, 0x2e, 0xc5, 0xbc, 0x79, 0xb4, 0xaf, 0xaf, 0xc3, 0x1d,
+	0xda, 0x84, 0xa8, 0x1a, 0x1b, 0xa3, 0x02, 0xbb, 0xf7, 0x23, 0xa1, 0x3d, 0xdc, 0x8a, 0x2b, 0x53,
+	0x95, 0xac, 0x9d, 0xaa, 0x95, 0xc6, 0x1d, 0xdc, 0x9a, 0x00, 0xb1, 0x43, 0x90, 0xe3, 0x33, 0x3f,
+	0x11, 0xb9, 0x7c, 0x3b, 0xe3, 0xea, 0x3f, 0xfb, 0xa2, 0x56, 0x76, 0xae, 0xd4, 0x9b, 0x10, 0xc3,
+	0x58, 0xa4, 0x59, 0x6c, 0x36, 0xe8, 0xdb, 0xfb, 0x87, 0xd0, 0xbd, 0x55, 0xc6, 0xba, 0x1e, 0x23,
+	0xe5, 0xf7, 0x4b, 0x99, 0xe1, 0x35, 0x02, 0x88, 0x9d, 0x61, 0x53, 0x15, 0x9f, 0xa8, 0x94, 0x37,
+	0x50, 0x6a, 0x0b, 0x8c, 0xdd, 0x03, 0x6b, 0x96, 0x94, 0x5b, 0x70, 0x6d, 0xd3, 0x15, 0x94, 0x6a,
+	0x25, 0xb9, 0xe8, 0xfb, 0x80, 0xd8, 0xda, 0xf9, 0x29, 0x6f, 0xe1, 0xd5, 0xe6, 0xa7, 0x76, 0x16,
+	0x2d, 0xfc, 0x38, 0x13, 0x20, 0xc4, 0x1d, 0xf4, 0xf5, 0x1a, 0xf6, 0x46, 0xd4, 0x85, 0x96, 0x65,
+	0x8c, 0x92, 0x37, 0x2b, 0xcb, 0x91, 0xbc, 0xb1, 0xd8, 0x82, 0x3b, 0xe8, 0x20, 0x59, 0x78, 0xdf,
+	0xbb, 0xb4, 0x5d, 0xa5, 0xe4, 0x0e, 0xed, 0xe6, 0x75, 0x7f, 0x91, 0x29, 0x23, 0x38, 0x41, 0x03,
+	0x0d, 0x1b, 0x2c, 0xcf, 0x4f, 0xe1, 0xe7, 0xf1, 0xd2, 0xe4, 0x52, 0xaa, 0x78, 0xc8, 0x60, 0x47,
+	0x95, 0xd2, 0x51, 0x68, 0x53, 0xfa, 0x28, 0x05, 0x0d, 0x55, 0xa3, 0xaa, 0xc6, 0x6d, 0x1e, 0x6c,
+	0xbb, 0xf1, 0x26, 0xb2, 0x03, 0x62, 0x4b, 0xa4, 0xa1, 0x37, 0x5d, 0x64, 0xca, 0x21, 0xeb, 0xc3,
+	0xdc, 0xd7, 0x42, 0x9a, 0x7c, 0x68, 0xb5, 0xd0, 0x46, 0xc1, 0x06, 0xd8, 0x00, 0xc0, 0xd8, 0xc1,
+	0x0b, 0x09, 0xa0, 0x3a, 0xde, 0xfc, 0x8e, 0x36, 0xbe, 0x03, 0x19, 0x6a, 0xde, 0x93, 0x48, 0xc4,
+	0x01, 0x9a, 0x30, 0x64, 0x8c, 0x0d, 0x45, 0xdd, 0xba, 0x7d, 0xb2, 0x52, 0xb7, 0x23, 0x2b, 0xd8,
+	0xc4, 0xbe, 0xc5, 0x78, 0xaf, 0x32, 0x91, 0x71, 0x09, 0x5a, 0x0f, 0x61, 0x7b, 0xf2, 0x5d, 0x64,
+	0xcd, 0xa1, 0x4a, 0x23, 0x7b, 0x1f, 0x69, 0xe4, 0x36, 0x6d, 0xf8, 0x61, 0xb8, 0x32, 0x0a, 0x2c,
+	0x50, 0x75, 0xec, 0xc1, 0xf6, 0x8e, 0x65, 0x03, 0xea, 0x7e, 0x93, 0xf9, 0xda, 0x6e, 0xbe, 0x4d,
+	0xc4, 0x9c, 0x60, 0xfd, 0x93, 0x59, 0x1c, 0xa7, 0x9c, 0x61, 0xff, 0x00, 0xca, 0x63, 0x8b, 0x63,
+	0xdf, 0x08, 0x7e, 0x13, 0x59, 0x4b, 0xd0, 0x36, 0xc4, 0x45, 0x24, 0x03, 0x75, 0xc1, 0x6f, 0xa1,
+	0x86, 0x78, 0x05, 0x50, 0xdd, 0x10, 0x39, 0xc5, 0x3b, 0xa5, 0xfb, 0x8f, 0x55, 0x92, 0xf8, 0x32,
+	0x40, 0x8a, 0xcc, 0xa3, 0x21, 0x57, 0x44, 0xb3, 0xb1, 0x61, 0xbd, 0x5f, 0x1c, 0x4a, 0xeb, 0x27,
+	0xe5, 0x86, 0xb1, 0x68, 0x93, 0x19, 0xaf, 0x4e, 0x22, 0x0b, 0x94, 0x6b, 0xa8, 0x71, 0xf9, 0x35,
+	0xf9, 0x00, 0x49, 0xb6, 0x1c, 0x40, 0xab, 0x8f, 0xee, 0x15, 0x1d, 0x8f, 0xb0, 0x8e, 0xb7, 0xf2,
+	0x0b, 0x71, 0xa3, 0x47, 0x57, 0xeb, 0xbf, 0x3c, 0xba, 0x76, 0xb6, 0x3d, 0xba, 0xda, 0x6b, 0x1e,
+	0x5d, 0xe5, 0x0b, 0xa5, 0x73, 0xd5, 0x0b, 0xe5, 0x27, 0x42, 0xdb, 0x65, 0xd9, 0xd8, 0x97, 0xd0,
+	0x7b, 0x26, 0xb2, 0xb3, 0xfe, 0x78, 0xc9, 0xc9, 0x66, 0x17, 0x31, 0x0f, 0x47, 0xe5, 0x5c, 0x23,
+	0xaa, 0x21, 0x75, 0x67, 0xda, 0x16, 0x35, 0x5f, 0x3e, 0x0c, 0x49, 0xe7, 0x89, 0xc5, 0xeb, 0xa4,
+	0x01, 0xcd, 0xfb, 0x95, 0xd0, 0xdd, 0x15, 0x33, 0xcc, 0x0f, 0x5f, 0x86, 0x62, 0xf5, 0x19, 0x05,
+	0x10, 0xbb, 0x4f, 0xf7, 0x6c, 0x1f, 0x99, 0x97, 0x72, 0xa2, 0x32, 0x19, 0x88, 0x80, 0x3b, 0x88,
+	0x74, 0xc9, 0x06, 0x13, 0xc0, 0x22, 0xcf, 0xf3, 0x54, 0x5a, 0x8f, 0x1a, 0xd5, 0xa4, 0xa8, 0x0d,
+	0x6c, 0x40, 0x7b, 0x42, 0x06, 0xf5, 0x9d, 0x4d, 0x74, 0xe7, 0x8a, 0xc5, 0x4e, 0x73, 0x21, 0x83,
+	0xe2, 0x3e, 0x17, 0xdd, 0x57, 0xc3, 0xde, 0x0f, 0x84, 0xb6, 0xcb, 0x6a, 0x54, 0x12, 0x27, 0x1f,
+	0xed, 0xa4, 0xa3, 0x7a, 0x23, 0xda, 0xbc, 0x96, 0xc6, 0x12, 0xb4, 0x9f, 0xd2, 0x62, 0x9a, 0xe9,
+	0x34, 0x5a, 0xe4, 0xc9, 0xac, 0x1e, 0xb1, 0x15, 0x6c, 0x15, 0x9d, 0x9a, 0xe4, 0x3a, 0x8a, 0xb6,
+	0xb4, 0xe3, 0x83, 0xdf, 0x3e, 0x1c, 0x91, 0xf7, 0x7f, 0x1f, 0x91, 0xf7, 0x1f, 0x8e, 0xc8, 0x5f,
+	0x1f, 0x8e, 0xc8, 0xbf, 0x03, 0x00, 0xce, 0x0c, 0x28, 0x9e, 0xe4, 0x0e, 0x00, 0x00,
 }
@@ -0,0 +1,187 @@
+package rel
+
+import (
+	"io"
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+)
+
+// SqlCte a named sub-query (common table expression) of a WITH statement,
+// the statement reads its rows as a source by name.
+//
+//    WITH active AS (SELECT user_id, name FROM users WHERE active = true)
+//    SELECT name FROM active
+//
+//    WITH RECURSIVE tree (id, depth) AS (
+//       SELECT id, 0 FROM org WHERE parent_id = 0
+//       UNION ALL
+//       SELECT o.id, tree.depth + 1 FROM org AS o INNER JOIN tree ON o.parent_id = tree.id
+//    )
+//    SELECT id, depth FROM tree
+//
+// A recursive sub-query is a UNION [ALL] of a select that does not read the
+// sub-query followed by one that does.
+type SqlCte struct {
+	Name      string
+	Columns   []string     // optional column names, else those of the first select
+	Recursive bool         // WITH RECURSIVE, may read its own rows
+	Stmt      SqlStatement // *SqlSelect or *SqlUnion
+}
+
+func (m *SqlCte) String() string {
+	w := NewSqlDialect()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *SqlCte) WriteDialect(w expr.DialectWriter) {
+	w.WriteIdentity(m.Name)
+	if len(m.Columns) > 0 {
+		io.WriteString(w, " (")
+		for i, col := range m.Columns {
+			if i > 0 {
+				io.WriteString(w, ", ")
+			}
+			w.WriteIdentity(col)
+		}
+		io.WriteString(w, ")")
+	}
+	io.WriteString(w, " AS (")
+	m.Stmt.WriteDialect(w)
+	io.WriteString(w, ")")
+}
+
+// Selects the select statements of the sub-query, left to right.
+func (m *SqlCte) Selects() []*SqlSelect {
+	switch st := m.Stmt.(type) {
+	case *SqlSelect:
+		return []*SqlSelect{st}
+	case *SqlUnion:
+		return st.Selects()
+	}
+	return nil
+}
+
+// ColumnNames the names of the columns of the sub-query rows, its column
+// list else the names of the first select's columns, an un-aliased
+// qualified column (o.id) is named for its field (id).
+func (m *SqlCte) ColumnNames() []string {
+	if len(m.Columns) > 0 {
+		return m.Columns
+	}
+	sels := m.Selects()
+	if len(sels) == 0 {
+		return nil
+	}
+	cols := make([]string, len(sels[0].Columns))
+	for i, col := range sels[0].Columns {
+		cols[i] = col.As
+		if in, ok := col.Expr.(*expr.IdentityNode); ok && in.Text == col.As {
+			_, cols[i], _ = in.LeftRight()
+		}
+	}
+	return cols
+}
+
+func (m *SqlCte) Equal(s *SqlCte) bool {
+	if m == nil && s == nil {
+		return true
+	}
+	if m == nil || s == nil {
+		return false
+	}
+	if m.Name != s.Name || m.Recursive != s.Recursive || len(m.Columns) != len(s.Columns) {
+		return false
+	}
+	for i, col := range m.Columns {
+		if col != s.Columns[i] {
+			return false
+		}
+	}
+	return statementEqual(m.Stmt, s.Stmt)
+}
+
+// writeCtes the WITH clause of ctes before the statement using them.
+func writeCtes(w expr.DialectWriter, ctes []*SqlCte) {
+	io.WriteString(w, "WITH ")
+	for _, cte := range ctes {
+		if cte.Recursive {
+			io.WriteString(w, "RECURSIVE ")
+			break
+		}
+	}
+	for i, cte := range ctes {
+		if i > 0 {
+			io.WriteString(w, ", ")
+		}
+		cte.WriteDialect(w)
+	}
+	io.WriteString(w, " ")
+}
+
+func ctesEqual(a, b []*SqlCte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, cte := range a {
+		if !cte.Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindCte the named sub-query of ctes, names are case insensitive.
+func FindCte(ctes []*SqlCte, name string) *SqlCte {
+	for _, cte := range ctes {
+		if strings.EqualFold(cte.Name, name) {
+			return cte
+		}
+	}
+	return nil
+}
+
+// SqlCteToPb convert a named sub-query to pb.
+func SqlCteToPb(m *SqlCte) *SqlCtePb {
+	return &SqlCtePb{
+		Name:      m.Name,
+		Columns:   m.Columns,
+		Recursive: m.Recursive,
+		Stmt:      statementToPb(m.Stmt),
+	}
+}
+
+// SqlCteFromPb create a named sub-query from pb.
+func SqlCteFromPb(pb *SqlCtePb) *SqlCte {
+	m := &SqlCte{
+		Name:      pb.Name,
+		Columns:   pb.Columns,
+		Recursive: pb.Recursive,
+	}
+	if pb.Stmt != nil {
+		m.Stmt = statementFromPb(pb.Stmt)
+	}
+	return m
+}
+
+func ctesToPb(ctes []*SqlCte) []*SqlCtePb {
+	if len(ctes) == 0 {
+		return nil
+	}
+	pbs := make([]*SqlCtePb, len(ctes))
+	for i, cte := range ctes {
+		pbs[i] = SqlCteToPb(cte)
+	}
+	return pbs
+}
+
+func ctesFromPb(pbs []*SqlCtePb) []*SqlCte {
+	if len(pbs) == 0 {
+		return nil
+	}
+	ctes := make([]*SqlCte, len(pbs))
+	for i, pb := range pbs {
+		ctes[i] = SqlCteFromPb(pb)
+	}
+	return ctes
+}
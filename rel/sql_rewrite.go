@@ -81,29 +81,84 @@ func RewriteSqlSource(m *SqlSource, parentStmt *SqlSelect) *SqlSelect {
 		// We also need to create an expression used for evaluating
 		// the values of Join "Keys"
 		if from.JoinExpr != nil {
-			joinNodesForFrom(parentStmt, m, from.JoinExpr, 0)
+			joinNodesForFrom(m, from.JoinExpr)
 		}
 	}
 
 	if parentStmt.Where != nil {
 		node, cols := rewriteWhere(parentStmt, m, parentStmt.Where.Expr, make(Columns, 0))
-		if node != nil {
+		// Filtering the null-supplying side of an outer join before the
+		// join changes which rows are unmatched, so leave the where to be
+		// evaluated after the join.
+		if node != nil && !m.NullableIn(parentStmt) {
 			sql2.Where = &SqlWhere{Expr: node}
 		}
-		if len(cols) > 0 {
-			parentIdx := len(parentStmt.Columns)
-			for _, col := range cols {
-				col.Index = len(sql2.Columns)
-				col.ParentIndex = parentIdx
-				parentIdx++
-				sql2.Columns = append(sql2.Columns, col)
+		// The full where is evaluated again after the join, so we need
+		// all of our columns it references (including those of un-pushed
+		// down predicates such as sub-queries) in the parent row.
+		for _, in := range whereIdentities(parentStmt.Where.Expr) {
+			left, right, ok := in.LeftRight()
+			if !ok || left != m.alias {
+				continue
 			}
+			cols = append(cols, NewColumn(right))
+		}
+		parentIdx := nextParentIndex(m, parentStmt)
+		for _, col := range cols {
+			if hasSourceField(sql2.Columns, col.SourceField) {
+				continue
+			}
+			col.Index = len(sql2.Columns)
+			col.ParentIndex = parentIdx
+			parentIdx++
+			sql2.Columns = append(sql2.Columns, col)
 		}
 	}
 	m.Source = sql2
 	m.cols = sql2.UnAliasedColumns()
 	return sql2
 }
+// nextParentIndex the parent row position for the next column this source
+// adds beyond the parent projection, after those of the sources before it.
+func nextParentIndex(m *SqlSource, parentStmt *SqlSelect) int {
+	idx := len(parentStmt.Columns)
+	for _, from := range parentStmt.From {
+		if from == m {
+			break
+		}
+		if from.Source == nil {
+			continue
+		}
+		for _, col := range from.Source.Columns {
+			if col.ParentIndex >= idx {
+				idx = col.ParentIndex + 1
+			}
+		}
+	}
+	return idx
+}
+
+func hasSourceField(cols Columns, field string) bool {
+	for _, col := range cols {
+		if col.SourceField == field {
+			return true
+		}
+	}
+	return false
+}
+
+// whereIdentities the identities of a where expression, including the
+// outer row references of any sub-queries.
+func whereIdentities(node expr.Node) expr.IdentityNodes {
+	idents := expr.FindAllIdentities(node)
+	for _, sq := range SubQueries(node) {
+		for _, n := range sq.Outer {
+			idents = append(idents, expr.FindAllIdentities(n)...)
+		}
+	}
+	return idents
+}
+
 func rewriteIntoProjection(sel *SqlSelect, m Columns) {
 	if len(m) == 0 {
 		return
@@ -114,7 +169,7 @@ func rewriteIntoProjection(sel *SqlSelect, m Columns) {
 		switch n := c.Expr.(type) {
 		case *expr.IdentityNode:
 			colsToAdd = append(colsToAdd, c.SourceField)
-		case *expr.FuncNode:
+		case *expr.FuncNode, *expr.CaseNode:
 
 			idents := expr.FindAllIdentities(n)
 			for _, in := range idents {
@@ -188,6 +243,9 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 
 			if n1 != nil && n2 != nil {
 				return &expr.BinaryNode{Operator: nt.Operator, Args: []expr.Node{n1, n2}}, cols
+			} else if nt.Operator.T == lex.TokenLogicOr {
+				// can't filter on only one side of an OR
+				return nil, cols
 			} else if n1 != nil {
 				return n1, cols
 			} else if n2 != nil {
@@ -212,6 +270,8 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 		default:
 			//u.Warnf("un-implemented op: %#v", nt)
 		}
+	case *SubQueryNode:
+		// evaluated after the join
 	default:
 		u.Warnf("%T node types are not suppored yet for where rewrite", node)
 	}
@@ -219,101 +279,53 @@ func rewriteWhere(stmt *SqlSelect, from *SqlSource, node expr.Node, cols Columns
 	return nil, cols
 }
 
-func joinNodesForFrom(stmt *SqlSelect, from *SqlSource, node expr.Node, depth int) expr.Node {
-
-	switch nt := node.(type) {
-	case *expr.IdentityNode:
-		if left, right, hasLeft := nt.LeftRight(); hasLeft {
-			//u.Debugf("joinNodesForFrom  from.Name:%v l:%v  r:%v", from.alias, left, right)
-			if left == from.alias {
-				identNode := expr.IdentityNode{Text: right}
-				//u.Debugf("%d nice, found it! identnode=%q fromnode:%q", depth, identNode.String(), nt.String())
-				if depth == 1 {
-					from.joinNodes = append(from.joinNodes, &identNode)
-					return nil
-				}
-				return &identNode
-			} else {
-				// This is for other side of join, ignore
-				//u.Warnf("what to do? source:%v    %v", from.alias, nt.String())
-			}
-		} else {
-			u.Warnf("dropping join expr node: %q", nt.String())
+// joinNodesForFrom find this sources side of the equality conjuncts of a
+// join expression (x.a = y.a AND x.b = y.b) to use as the join key nodes.
+func joinNodesForFrom(from *SqlSource, node expr.Node) {
+	bn, ok := node.(*expr.BinaryNode)
+	if !ok {
+		return
+	}
+	switch bn.Operator.T {
+	case lex.TokenAnd, lex.TokenLogicAnd:
+		joinNodesForFrom(from, bn.Args[0])
+		joinNodesForFrom(from, bn.Args[1])
+	case lex.TokenEqual, lex.TokenEqualEqual:
+		// Only an equality between this source and another can be hashed
+		// as a join key, anything else (x.a > y.b, x.active = true, OR)
+		// is evaluated as a residual filter on the joined row.
+		left, lok := joinNodeAlias(bn.Args[0])
+		right, rok := joinNodeAlias(bn.Args[1])
+		if !lok || !rok || left == right {
+			return
 		}
-	case *expr.NumberNode, *expr.NullNode, *expr.StringNode, *expr.ValueNode:
-		//u.Warnf("skipping? %v", nt.String())
-		return nt
-	case *expr.FuncNode:
-		//u.Warnf("%v  try join from func node: %v", depth, nt.String())
-		args := make([]expr.Node, len(nt.Args))
-		for i, arg := range nt.Args {
-			args[i] = rewriteNode(from, arg)
-			if args[i] == nil {
-				// What???
-				//u.Infof("error, from:%q   arg:%q", from.String(), arg.String())
-				return nil
-			}
+		var side expr.Node
+		switch from.alias {
+		case left:
+			side = bn.Args[0]
+		case right:
+			side = bn.Args[1]
+		default:
+			return
 		}
-		fn := expr.NewFuncNode(nt.Name, nt.F)
-		fn.Args = args
-		if depth == 1 {
-			//u.Infof("adding func: %s", fn.String())
-			from.joinNodes = append(from.joinNodes, fn)
-			return nil
+		if n := rewriteNode(from, side); n != nil {
+			from.joinNodes = append(from.joinNodes, n)
 		}
-		return fn
-	case *expr.BinaryNode:
-		//u.Infof("%v binaryNode  %v", depth, nt.String())
-		switch nt.Operator.T {
-		case lex.TokenAnd, lex.TokenLogicAnd, lex.TokenLogicOr:
-			n1 := joinNodesForFrom(stmt, from, nt.Args[0], depth+1)
-			n2 := joinNodesForFrom(stmt, from, nt.Args[1], depth+1)
-
-			if n1 != nil && n2 != nil {
-				//u.Debugf("%d neither nil:  n1=%v  n2=%v    %q", depth, n1, n2, nt.String())
-				//return &BinaryNode{Operator: nt.Operator, Args: [2]Node{n1, n2}}
-			} else if n1 != nil {
-				//u.Debugf("%d n1 not nil: n1=%v  n2=%v    %q", depth, n1, n2, nt.String())
-				return n1
-			} else if n2 != nil {
-				//u.Debugf("%d n2 not nil n1=%v  n2=%v    %q", depth, n1, n2, nt.String())
-				return n2
-			} else {
-				//u.Warnf("%d n1=%#v  n2=%#v    %#v", depth, n1, n2, nt)
-			}
-		case lex.TokenEqual, lex.TokenEqualEqual, lex.TokenGT, lex.TokenGE, lex.TokenLE, lex.TokenNE:
-			n1 := joinNodesForFrom(stmt, from, nt.Args[0], depth+1)
-			n2 := joinNodesForFrom(stmt, from, nt.Args[1], depth+1)
+	}
+}
 
-			if n1 != nil && n2 != nil {
-				//u.Debugf("%d neither nil:  n1=%v  n2=%v    %q", depth, n1, n2, nt.String())
-				//return &BinaryNode{Operator: nt.Operator, Args: [2]Node{n1, n2}}
-			} else if n1 != nil {
-				//u.Debugf("%d n1 not nil: n1=%v  n2=%v    %q", depth, n1, n2, nt.String())
-				// 	return n1
-				if depth == 1 {
-					//u.Infof("adding node: %s", n1.String())
-					from.joinNodes = append(from.joinNodes, n1)
-					return nil
-				}
-			} else if n2 != nil {
-				//u.Debugf("%d  n2 not nil n1=%v  n2=%v    %q", depth, n1, n2, nt.String())
-				if depth == 1 {
-					//u.Infof("adding node: %s", n1.String())
-					from.joinNodes = append(from.joinNodes, n2)
-					return nil
-				}
-				// 	return n2
-			} else {
-				//u.Warnf("n1=%#v  n2=%#v    %#v", n1, n2, nt)
-			}
-		default:
-			u.Warnf("un-implemented op: %#v", nt)
+// joinNodeAlias the single source alias that all identities in node
+// are qualified with, false if none or more than one.
+func joinNodeAlias(node expr.Node) (string, bool) {
+	alias := ""
+	for _, in := range expr.FindAllIdentities(node) {
+		left, _, ok := in.LeftRight()
+		if !ok || (alias != "" && left != alias) {
+			return "", false
 		}
-	default:
-		u.Warnf("%T node types are not suppored yet for where rewrite", node)
+		alias = left
 	}
-	return nil
+	return alias, alias != ""
 }
 
 // We need to find all columns used in the given Node (where/join expression)
@@ -322,54 +334,24 @@ func columnsFromJoin(from *SqlSource, node expr.Node, cols Columns) Columns {
 	if node == nil {
 		return cols
 	}
-	//u.Debugf("columnsFromJoin()  T:%T  node=%q", node, node.String())
-	switch nt := node.(type) {
-	case *expr.IdentityNode:
-		if left, right, ok := nt.LeftRight(); ok {
-			//u.Debugf("from.Name:%v AS %v   Joinnode l:%v  r:%v    %#v", from.Name, from.alias, left, right, nt)
-			//u.Warnf("check cols against join expr arg: %#v", nt)
-			if left == from.alias {
-				found := false
-				for _, col := range cols {
-					colLeft, colRight, _ := col.LeftRight()
-					//u.Debugf("left='%s'  colLeft='%s' right='%s'  %#v", left, colLeft, colRight,  col)
-					//u.Debugf("col:  From %s AS '%s'   '%s'.'%s'  JoinExpr: '%v'.'%v' col:%#v", from.Name, from.alias, colLeft, colRight, left, right, col)
-					if left == colLeft || colRight == right {
-						found = true
-						//u.Infof("columnsFromJoin from.Name:%v l:%v  r:%v", from.alias, left, right)
-					} else {
-						//u.Warnf("not? from.Name:%v l:%v  r:%v   col: P:%p %#v", from.alias, left, right, col, col)
-					}
-				}
-				if !found {
-					//u.Debugf("columnsFromJoin from.Name:%v l:%v  r:%v", from.alias, left, right)
-					newCol := &Column{As: right, SourceField: right, Expr: &expr.IdentityNode{Text: right}}
-					newCol.Index = len(cols)
-					newCol.ParentIndex = -1 // if -1, we don't need in parent index
-					cols = append(cols, newCol)
-					//u.Warnf("added col %s idx:%d pidx:%v", right, newCol.Index, newCol.Index)
-				}
-			}
+	for _, in := range expr.FindAllIdentities(node) {
+		left, right, ok := in.LeftRight()
+		if !ok || left != from.alias {
+			continue
 		}
-	case *expr.FuncNode:
-		//u.Warnf("columnsFromJoin func node: %s", nt.String())
-		for _, arg := range nt.Args {
-			cols = columnsFromJoin(from, arg, cols)
+		found := false
+		for _, col := range cols {
+			if _, colRight, _ := col.LeftRight(); colRight == right {
+				found = true
+				break
+			}
 		}
-	case *expr.BinaryNode:
-		switch nt.Operator.T {
-		case lex.TokenAnd, lex.TokenLogicAnd, lex.TokenLogicOr:
-			cols = columnsFromJoin(from, nt.Args[0], cols)
-			cols = columnsFromJoin(from, nt.Args[1], cols)
-		case lex.TokenEqual, lex.TokenEqualEqual:
-			cols = columnsFromJoin(from, nt.Args[0], cols)
-			cols = columnsFromJoin(from, nt.Args[1], cols)
-		default:
-			u.Warnf("un-implemented op: %v", nt.Operator)
+		if !found {
+			newCol := &Column{As: right, SourceField: right, Expr: &expr.IdentityNode{Text: right}}
+			newCol.Index = len(cols)
+			newCol.ParentIndex = -1 // if -1, we don't need in parent index
+			cols = append(cols, newCol)
 		}
-	default:
-		u.LogTracef(u.INFO, "whoops")
-		u.Warnf("%T node types are not suppored yet for join rewrite %s", node, from.String())
 	}
 	return cols
 }
@@ -410,6 +392,7 @@ func rewriteNode(from *SqlSource, node expr.Node) expr.Node {
 		}
 	case *expr.FuncNode:
 		fn := expr.NewFuncNode(nt.Name, nt.F)
+		fn.Distinct = nt.Distinct
 		fn.Args = make([]expr.Node, len(nt.Args))
 		for i, arg := range nt.Args {
 			fn.Args[i] = rewriteNode(from, arg)
@@ -0,0 +1,382 @@
+package rel
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/value"
+)
+
+var (
+	// Ensure SubQueryNode is an expression node
+	_ expr.Node = (*SubQueryNode)(nil)
+)
+
+// subQueryKeyPrefix is the alias prefix of the key columns added to a
+// decorrelated sub-query.
+const subQueryKeyPrefix = "subq_key"
+
+// SubQueryNode a sub-query (SELECT) used as an expression in a where clause,
+// its result is materialized once and the outer rows are evaluated against it.
+//
+//    WHERE user_id IN (SELECT user_id FROM orders)                              -- semi-join
+//    WHERE NOT EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id)   -- anti-join
+//    WHERE referral_count > (SELECT count(*) FROM orders)                       -- scalar
+//
+// Correlated sub-queries (referencing the outer row) are decorrelated by
+// Decorrelate when their outer references are only in equalities.
+type SubQueryNode struct {
+	Select *SqlSelect
+	// Outer are the outer row expressions a decorrelated sub-query is
+	// correlated on, compared to the leading key columns of the Select.
+	Outer []expr.Node
+	// Empty is the scalar value when no rows match, ie 0 for count(*)
+	Empty value.Value
+
+	mu       sync.Mutex
+	resolved bool
+	rows     map[string][]value.Value
+}
+
+// NewSubQueryNode create a sub-query expression node.
+func NewSubQueryNode(sel *SqlSelect) *SubQueryNode {
+	return &SubQueryNode{Select: sel}
+}
+
+func (m *SubQueryNode) NodeType() string { return "SubQuery" }
+func (m *SubQueryNode) String() string {
+	w := expr.NewDefaultWriter()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *SubQueryNode) WriteDialect(w expr.DialectWriter) {
+	io.WriteString(w, "(")
+	m.Select.WriteDialect(w)
+	io.WriteString(w, ")")
+}
+func (m *SubQueryNode) Validate() error {
+	if m.Select == nil {
+		return fmt.Errorf("sub-query requires a select")
+	}
+	return nil
+}
+
+// NodePb sub-queries have no pb representation, nil.  Statements are
+// checked with CheckPb before they are serialized, the where clause sends
+// its un-correlated sub-queries as sql text.
+func (m *SubQueryNode) NodePb() *expr.NodePb { return nil }
+
+// FromPB sub-queries have no pb representation, nil.
+func (m *SubQueryNode) FromPB(n *expr.NodePb) expr.Node { return nil }
+func (m *SubQueryNode) Expr() *expr.Expr {
+	return &expr.Expr{Op: lex.TokenSelect.String(), Value: m.Select.String()}
+}
+func (m *SubQueryNode) FromExpr(e *expr.Expr) error {
+	if e.Op != lex.TokenSelect.String() {
+		return fmt.Errorf("Invalid SubQueryNode %+v", e)
+	}
+	sel, err := ParseSqlSelect(e.Value)
+	if err != nil {
+		return err
+	}
+	m.Select = sel
+	return nil
+}
+func (m *SubQueryNode) Equal(n expr.Node) bool {
+	if m == nil && n == nil {
+		return true
+	}
+	if m == nil || n == nil {
+		return false
+	}
+	nt, ok := n.(*SubQueryNode)
+	if !ok || nt == nil {
+		return false
+	}
+	return m.Select.Equal(nt.Select)
+}
+
+// CheckPb error if stmt has sub-queries that would be lost serializing it
+// to protobuf.  Only the sub-queries of a where clause are serialized, as
+// sql text, and not once decorrelated as the outer keys are not in it.
+func CheckPb(stmt SqlStatement) error {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		return checkSelectPb(st)
+	case *SqlUnion:
+		if err := checkCtesPb(st.Ctes); err != nil {
+			return err
+		}
+		if err := CheckPb(st.Left); err != nil {
+			return err
+		}
+		return CheckPb(st.Right)
+	}
+	return nil
+}
+
+func checkSelectPb(m *SqlSelect) error {
+	if m == nil {
+		return nil
+	}
+	if err := checkCtesPb(m.Ctes); err != nil {
+		return err
+	}
+	for _, cols := range []Columns{m.Columns, m.GroupBy, m.OrderBy} {
+		for _, col := range cols {
+			if len(SubQueries(col.Expr)) > 0 || len(SubQueries(col.Guard)) > 0 {
+				return fmt.Errorf("can not serialize sub-query in column %s", col)
+			}
+		}
+	}
+	if len(SubQueries(m.Having)) > 0 {
+		return fmt.Errorf("can not serialize sub-query in having %s", m.Having)
+	}
+	for _, from := range m.From {
+		if len(SubQueries(from.JoinExpr)) > 0 {
+			return fmt.Errorf("can not serialize sub-query in join %s", from.JoinExpr)
+		}
+		if err := checkSelectPb(from.SubQuery); err != nil {
+			return err
+		}
+	}
+	if m.Where != nil {
+		for _, sq := range SubQueries(m.Where.Expr) {
+			if sq.Correlated() {
+				return fmt.Errorf("can not serialize decorrelated sub-query %s", sq)
+			}
+		}
+		if err := checkSelectPb(m.Where.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkCtesPb(ctes []*SqlCte) error {
+	for _, cte := range ctes {
+		if err := CheckPb(cte.Stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Correlated is this sub-query keyed by outer row values.
+func (m *SubQueryNode) Correlated() bool { return len(m.Outer) > 0 }
+
+// Resolved has the result been materialized.
+func (m *SubQueryNode) Resolved() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resolved
+}
+
+// Resolve materialize the result of this sub-query, run is called to
+// execute the Select only if it has not already been resolved.
+func (m *SubQueryNode) Resolve(run func(sel *SqlSelect) ([][]driver.Value, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.resolved {
+		return nil
+	}
+	rows, err := run(m.Select)
+	if err != nil {
+		return err
+	}
+	m.rows = make(map[string][]value.Value)
+	nkeys := len(m.Outer)
+rowLoop:
+	for _, row := range rows {
+		if len(row) <= nkeys {
+			continue
+		}
+		keys := make([]value.Value, nkeys)
+		for i := range keys {
+			keys[i] = value.NewValue(row[i])
+			if value.IsNilish(keys[i]) {
+				// NULL never equals an outer row
+				continue rowLoop
+			}
+		}
+		key := subQueryKey(keys)
+		m.rows[key] = append(m.rows[key], value.NewValue(row[nkeys]))
+	}
+	m.resolved = true
+	return nil
+}
+
+// Values of the first (non-key) column of the rows matching these outer
+// key values, keys is empty for un-correlated sub-queries.
+func (m *SubQueryNode) Values(keys []value.Value) []value.Value {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rows[subQueryKey(keys)]
+}
+
+func subQueryKey(keys []value.Value) string {
+	vals := make([]string, len(keys))
+	for i, k := range keys {
+		vals[i] = k.ToString()
+	}
+	return strings.Join(vals, string(byte(0)))
+}
+
+// Decorrelate a sub-query that references the rows of its parent select.
+// Equalities between an expression of the outer row and of the sub-query
+// (o.user_id = u.user_id) are removed from the sub-query where and its
+// side added as leading key columns, so the sub-query can be run once
+// and probed by key per outer row (a hash semi/anti join).  Error if the
+// outer row is referenced in any other way.
+func (m *SubQueryNode) Decorrelate(parent *SqlSelect) error {
+
+	outer := make(map[string]bool)
+	for _, from := range parent.From {
+		outer[from.alias] = true
+	}
+	for _, from := range m.Select.From {
+		// sub-query sources shadow the outer ones
+		delete(outer, from.alias)
+	}
+	refsOuter := func(n expr.Node) bool {
+		for _, in := range expr.FindAllIdentities(n) {
+			if left, _, ok := in.LeftRight(); ok && outer[strings.ToLower(left)] {
+				return true
+			}
+		}
+		return false
+	}
+	onlyOuter := func(n expr.Node) bool {
+		idents := expr.FindAllIdentities(n)
+		for _, in := range idents {
+			if left, _, ok := in.LeftRight(); !ok || !outer[strings.ToLower(left)] {
+				return false
+			}
+		}
+		return len(idents) > 0
+	}
+
+	sel := m.Select
+	for _, cols := range []Columns{sel.Columns, sel.GroupBy, sel.OrderBy} {
+		for _, col := range cols {
+			if col.Expr != nil && refsOuter(col.Expr) {
+				return fmt.Errorf("correlated sub-query column not supported: %s", col)
+			}
+		}
+	}
+	if sel.Having != nil && refsOuter(sel.Having) {
+		return fmt.Errorf("correlated sub-query having not supported: %s", sel.Having)
+	}
+	if sel.Where == nil || sel.Where.Expr == nil || !refsOuter(sel.Where.Expr) {
+		return nil
+	}
+
+	var inner, keep []expr.Node
+	for _, c := range conjuncts(sel.Where.Expr, nil) {
+		if !refsOuter(c) {
+			keep = append(keep, c)
+			continue
+		}
+		bn, ok := c.(*expr.BinaryNode)
+		if ok && (bn.Operator.T == lex.TokenEqual || bn.Operator.T == lex.TokenEqualEqual) {
+			switch {
+			case onlyOuter(bn.Args[0]) && !refsOuter(bn.Args[1]):
+				m.Outer = append(m.Outer, bn.Args[0])
+				inner = append(inner, bn.Args[1])
+				continue
+			case onlyOuter(bn.Args[1]) && !refsOuter(bn.Args[0]):
+				m.Outer = append(m.Outer, bn.Args[1])
+				inner = append(inner, bn.Args[0])
+				continue
+			}
+		}
+		m.Outer = nil
+		return fmt.Errorf("could not decorrelate sub-query predicate: %s", c)
+	}
+
+	// count(*) of no rows is 0 not NULL
+	if len(sel.Columns) == 1 {
+		if fn, ok := sel.Columns[0].Expr.(*expr.FuncNode); ok && strings.ToLower(fn.Name) == "count" {
+			m.Empty = value.NewIntValue(0)
+		}
+	}
+
+	cols := make(Columns, 0, len(inner)+len(sel.Columns))
+	for i, n := range inner {
+		as := fmt.Sprintf("%s%d", subQueryKeyPrefix, i)
+		cols = append(cols, &Column{As: as, originalAs: as, Expr: n})
+		if sel.IsAggQuery() {
+			sel.GroupBy = append(sel.GroupBy, &Column{As: n.String(), Expr: n})
+		}
+	}
+	sel.Columns = append(cols, sel.Columns...)
+	sel.Where = nil
+	if len(keep) > 0 {
+		where := keep[0]
+		for _, n := range keep[1:] {
+			where = expr.NewBinaryNode(lex.Token{T: lex.TokenLogicAnd, V: "AND"}, where, n)
+		}
+		sel.Where = &SqlWhere{Expr: where}
+	}
+
+	// re-parse for a clean, finalized statement
+	rewritten, err := ParseSqlSelect(sel.String())
+	if err != nil {
+		return err
+	}
+	m.Select = rewritten
+	return nil
+}
+
+// whereDialect a sql where expression, which may contain sub-queries.
+var whereDialect = &lex.Dialect{
+	Statements: []*lex.Clause{
+		{Token: lex.TokenNil, Clauses: []*lex.Clause{
+			{Token: lex.TokenNil, Lexer: lex.LexConditionalClause},
+		}},
+	},
+}
+
+// parseSubQueryExpr parse a where expression that may contain sub-queries.
+func parseSubQueryExpr(exprText string) (expr.Node, error) {
+	l := lex.NewLexer(exprText, whereDialect)
+	m := &Sqlbridge{l: l, SqlTokenPager: NewSqlTokenPager(l)}
+	return expr.ParseExprWithFuncs(m, nil)
+}
+
+// conjuncts the AND'd predicates of node.
+func conjuncts(node expr.Node, l []expr.Node) []expr.Node {
+	if bn, ok := node.(*expr.BinaryNode); ok {
+		switch bn.Operator.T {
+		case lex.TokenAnd, lex.TokenLogicAnd:
+			l = conjuncts(bn.Args[0], l)
+			return conjuncts(bn.Args[1], l)
+		}
+	}
+	return append(l, node)
+}
+
+// SubQueries find the sub-query expression nodes in node.
+func SubQueries(node expr.Node) []*SubQueryNode {
+	var l []*SubQueryNode
+	var walk func(n expr.Node)
+	walk = func(n expr.Node) {
+		switch nt := n.(type) {
+		case *SubQueryNode:
+			l = append(l, nt)
+		case expr.NodeArgs:
+			for _, arg := range nt.ChildrenArgs() {
+				walk(arg)
+			}
+		}
+	}
+	if node != nil {
+		walk(node)
+	}
+	return l
+}
@@ -0,0 +1,258 @@
+package rel
+
+import (
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+)
+
+// ParamBinder the node to replace placeholder n of a statement with.
+type ParamBinder func(n *expr.ParamNode) (expr.Node, error)
+
+// FingerPrintSql the fingerprint of a select statement, its text with the
+// literal values replaced by ? placeholders so statements differing only
+// in their values share a fingerprint, and the literal tokens replaced.
+// The fingerprint is itself a statement, parsed it has a placeholder for
+// each literal.
+//
+//    SELECT name FROM users WHERE id = 12 AND state = "ca" LIMIT 10
+//    SELECT name FROM users WHERE id = ? AND state = ? LIMIT 10
+//
+// A statement with placeholders of its own keeps its literals.  Returns
+// false for statements that are not a select, or set operation of
+// selects.
+func FingerPrintSql(sqlQuery string) (string, []lex.Token, bool) {
+	l := lex.NewSqlLexer(sqlQuery)
+	var literals []lex.Token
+	var prev lex.TokenType
+	limit := false
+	for i := 0; ; i++ {
+		tok := l.NextToken()
+		switch tok.T {
+		case lex.TokenEOF, lex.TokenEOS:
+			return fingerPrintText(sqlQuery, literals)
+		case lex.TokenError:
+			return "", nil, false
+		case lex.TokenPlaceholder:
+			// values are bound to this statement, its literals are
+			// part of its shape
+			return sqlQuery, nil, true
+		case lex.TokenValue, lex.TokenValueEscaped, lex.TokenInteger, lex.TokenFloat:
+			// LIMIT 10, LIMIT 5, 10 and OFFSET 5 are not expressions
+			if !limit && prev != lex.TokenOffset {
+				literals = append(literals, tok)
+			}
+		case lex.TokenLimit:
+			limit = true
+		}
+		if i == 0 && tok.T != lex.TokenSelect && tok.T != lex.TokenWith {
+			return "", nil, false
+		}
+		if limit && tok.T != lex.TokenLimit && tok.T != lex.TokenInteger && tok.T != lex.TokenComma {
+			limit = false
+		}
+		prev = tok.T
+	}
+}
+
+// fingerPrintText the statement with each of the literals replaced by ?.
+func fingerPrintText(sqlQuery string, literals []lex.Token) (string, []lex.Token, bool) {
+	if len(literals) == 0 {
+		return sqlQuery, nil, true
+	}
+	var buf strings.Builder
+	last := 0
+	for _, tok := range literals {
+		// a token ends at its position, a quoted value is between its
+		// quote marks
+		start, end := tok.Pos-len(tok.V), tok.Pos
+		if tok.Quote != 0 {
+			start, end = start-1, end+1
+		}
+		if start < last || end > len(sqlQuery) || sqlQuery[start+lenQuote(tok):end-lenQuote(tok)] != tok.V {
+			// not where we expected it, keep the statement as is
+			return sqlQuery, nil, true
+		}
+		buf.WriteString(sqlQuery[last:start])
+		buf.WriteByte('?')
+		last = end
+	}
+	buf.WriteString(sqlQuery[last:])
+	return buf.String(), literals, true
+}
+
+func lenQuote(tok lex.Token) int {
+	if tok.Quote != 0 {
+		return 1
+	}
+	return 0
+}
+
+// CopyStatement a deep copy of a select, or set operation of selects,
+// through its protobuf.  Nil for other statements, and those with
+// sub-queries CheckPb can not serialize.
+func CopyStatement(stmt SqlStatement) SqlStatement {
+	pb := StatementToPb(stmt)
+	if pb == nil {
+		return nil
+	}
+	return statementFromPb(pb)
+}
+
+// StatementToPb the protobuf of a select, or set operation of selects, to
+// make copies of it with StatementFromPb.  Nil for other statements, and
+// those with sub-queries CheckPb can not serialize.
+func StatementToPb(stmt SqlStatement) *SqlStatementPb {
+	if CheckPb(stmt) != nil {
+		return nil
+	}
+	return statementToPb(stmt)
+}
+
+// StatementFromPb a new select, or set operation of selects, from its
+// protobuf.
+func StatementFromPb(pb *SqlStatementPb) SqlStatement {
+	return statementFromPb(pb)
+}
+
+// BindParams replace the placeholders of a select, or set operation of
+// selects, with the nodes of bind.  The statement is modified in place.
+func BindParams(stmt SqlStatement, bind ParamBinder) error {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		return bindSelect(st, bind)
+	case *SqlUnion:
+		if err := bindCtes(st.Ctes, bind); err != nil {
+			return err
+		}
+		if err := BindParams(st.Left, bind); err != nil {
+			return err
+		}
+		if err := BindParams(st.Right, bind); err != nil {
+			return err
+		}
+		return bindColumns(st.OrderBy, bind)
+	}
+	return nil
+}
+
+func bindSelect(m *SqlSelect, bind ParamBinder) error {
+	if m == nil {
+		return nil
+	}
+	var err error
+	if err = bindCtes(m.Ctes, bind); err != nil {
+		return err
+	}
+	if err = bindColumns(m.Columns, bind); err != nil {
+		return err
+	}
+	for _, from := range m.From {
+		if from.JoinExpr, err = bindNode(from.JoinExpr, bind); err != nil {
+			return err
+		}
+		if err = bindSelect(from.SubQuery, bind); err != nil {
+			return err
+		}
+		if err = bindSelect(from.Source, bind); err != nil {
+			return err
+		}
+	}
+	if m.Where != nil {
+		if m.Where.Expr, err = bindNode(m.Where.Expr, bind); err != nil {
+			return err
+		}
+		if err = bindSelect(m.Where.Source, bind); err != nil {
+			return err
+		}
+	}
+	if m.Having, err = bindNode(m.Having, bind); err != nil {
+		return err
+	}
+	if err = bindColumns(m.GroupBy, bind); err != nil {
+		return err
+	}
+	return bindColumns(m.OrderBy, bind)
+}
+
+func bindCtes(ctes []*SqlCte, bind ParamBinder) error {
+	for _, cte := range ctes {
+		if err := BindParams(cte.Stmt, bind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindColumns(cols Columns, bind ParamBinder) error {
+	var err error
+	for _, col := range cols {
+		if col.Expr, err = bindNode(col.Expr, bind); err != nil {
+			return err
+		}
+		if col.Guard, err = bindNode(col.Guard, bind); err != nil {
+			return err
+		}
+		if col.Over != nil {
+			if err = bindColumns(col.Over.PartitionBy, bind); err != nil {
+				return err
+			}
+			if err = bindColumns(col.Over.OrderBy, bind); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func bindNodes(nodes []expr.Node, bind ParamBinder) error {
+	var err error
+	for i, n := range nodes {
+		if nodes[i], err = bindNode(n, bind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindNode n with its placeholders replaced by the nodes of bind.
+func bindNode(n expr.Node, bind ParamBinder) (expr.Node, error) {
+	var err error
+	switch nt := n.(type) {
+	case *expr.ParamNode:
+		return bind(nt)
+	case *expr.BinaryNode:
+		err = bindNodes(nt.Args, bind)
+	case *expr.BooleanNode:
+		err = bindNodes(nt.Args, bind)
+	case *expr.TriNode:
+		err = bindNodes(nt.Args, bind)
+	case *expr.FuncNode:
+		err = bindNodes(nt.Args, bind)
+	case *expr.ArrayNode:
+		err = bindNodes(nt.Args, bind)
+	case *expr.UnaryNode:
+		nt.Arg, err = bindNode(nt.Arg, bind)
+	case *expr.CaseNode:
+		if nt.Operand, err = bindNode(nt.Operand, bind); err != nil {
+			return nil, err
+		}
+		if err = bindNodes(nt.Whens, bind); err != nil {
+			return nil, err
+		}
+		if err = bindNodes(nt.Thens, bind); err != nil {
+			return nil, err
+		}
+		nt.Else, err = bindNode(nt.Else, bind)
+	case *SubQueryNode:
+		if err = bindSelect(nt.Select, bind); err != nil {
+			return nil, err
+		}
+		err = bindNodes(nt.Outer, bind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
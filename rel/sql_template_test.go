@@ -0,0 +1,68 @@
+package rel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+func TestFingerPrintSql(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		sql      string
+		fp       string
+		literals int
+	}{
+		{`SELECT name FROM users WHERE id = 12 AND state = "ca" LIMIT 10`,
+			`SELECT name FROM users WHERE id = ? AND state = ? LIMIT 10`, 2},
+		{`SELECT name FROM users WHERE id = 13 AND state = 'nv' LIMIT 10`,
+			`SELECT name FROM users WHERE id = ? AND state = ? LIMIT 10`, 2},
+		{`SELECT name FROM users WHERE score > 1.5 LIMIT 5 OFFSET 20`,
+			`SELECT name FROM users WHERE score > ? LIMIT 5 OFFSET 20`, 1},
+		{`WITH a AS (SELECT id FROM users WHERE id = 5) SELECT id FROM a`,
+			`WITH a AS (SELECT id FROM users WHERE id = ?) SELECT id FROM a`, 1},
+		{`SELECT name FROM users`, `SELECT name FROM users`, 0},
+		// values are bound to placeholders, the literals are kept
+		{`SELECT name FROM users WHERE id = ? AND state = "ca"`,
+			`SELECT name FROM users WHERE id = ? AND state = "ca"`, 0},
+	}
+	for _, tt := range tests {
+		fp, literals, ok := rel.FingerPrintSql(tt.sql)
+		assert.True(t, ok, tt.sql)
+		assert.Equal(t, tt.fp, fp)
+		assert.Equal(t, tt.literals, len(literals), tt.sql)
+	}
+
+	for _, sql := range []string{
+		`DELETE FROM users WHERE id = 12`,
+		`INSERT INTO users (id) VALUES (12)`,
+		`SHOW TABLES`,
+	} {
+		_, _, ok := rel.FingerPrintSql(sql)
+		assert.False(t, ok, sql)
+	}
+}
+
+func TestBindParams(t *testing.T) {
+	t.Parallel()
+	ps, err := rel.ParseSqlPrepared(`SELECT name FROM users WHERE id = ? AND state = ?`)
+	assert.Equal(t, nil, err)
+
+	bound := func(args ...interface{}) string {
+		stmt := rel.CopyStatement(ps.Statement)
+		assert.NotEqual(t, nil, stmt)
+		err := rel.BindParams(stmt, func(n *expr.ParamNode) (expr.Node, error) {
+			return expr.NewValueNode(value.NewValue(args[n.Index-1])), nil
+		})
+		assert.Equal(t, nil, err)
+		return stmt.String()
+	}
+	assert.Equal(t, `SELECT name FROM users WHERE id = 12 AND state = "ca"`, bound(12, "ca"))
+	assert.Equal(t, `SELECT name FROM users WHERE id = 13 AND state = "nv"`, bound(13, "nv"))
+	// the statement copied from is left with its placeholders
+	assert.Equal(t, `SELECT name FROM users WHERE id = ? AND state = ?`, ps.Statement.String())
+}
@@ -373,3 +373,98 @@ func TestSqlFingerPrinting(t *testing.T) {
 	assert.Equal(t, fw1.String(), fw2.String())
 	assert.Equal(t, sql1.FingerPrintID(), sql2.FingerPrintID(), "Should have equal fingerprints")
 }
+
+func TestSqlRewriteJoinResidual(t *testing.T) {
+	t.Parallel()
+	// only the equality between the two sources is a join key, the
+	// other predicates are evaluated on the joined row
+	s := `SELECT u.name, b.title
+			FROM users AS u INNER JOIN blog AS b
+			ON u.name = b.author AND b.active = true AND b.created BETWEEN u.start AND u.end;`
+	sql := parseOrPanic(t, s).(*rel.SqlSelect)
+	sql.Rewrite()
+	jn := sql.From[0].JoinNodes()
+	assert.Equal(t, 1, len(jn))
+	assert.Equal(t, "name", jn[0].String())
+	jn = sql.From[1].JoinNodes()
+	assert.Equal(t, 1, len(jn))
+	assert.Equal(t, "author", jn[0].String())
+	assert.Equal(t, "SELECT name, start, end FROM users", sql.From[0].Source.String())
+	assert.Equal(t, "SELECT title, author, active, created FROM blog", sql.From[1].Source.String())
+
+	// no equality to hash on
+	s = `SELECT u.name, b.title
+			FROM users AS u INNER JOIN blog AS b
+			ON u.name = b.author OR b.created > u.created;`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	sql.Rewrite()
+	assert.Equal(t, 0, len(sql.From[0].JoinNodes()))
+	assert.Equal(t, 0, len(sql.From[1].JoinNodes()))
+	assert.Equal(t, "SELECT name, created FROM users", sql.From[0].Source.String())
+}
+
+func TestSqlSubQueryDecorrelate(t *testing.T) {
+	t.Parallel()
+	s := `SELECT u.name FROM users AS u
+			WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id AND o.price > 10)`
+	sql := parseOrPanic(t, s).(*rel.SqlSelect)
+	sqs := rel.SubQueries(sql.Where.Expr)
+	assert.Equal(t, 1, len(sqs))
+	assert.Equal(t, nil, sqs[0].Decorrelate(sql))
+	assert.True(t, sqs[0].Correlated())
+	assert.Equal(t, "u.user_id", sqs[0].Outer[0].String())
+	assert.Equal(t, "SELECT o.user_id AS subq_key0, 1 FROM orders AS o WHERE o.price > 10", sqs[0].Select.String())
+
+	// aggregates are grouped by the correlation keys
+	s = `SELECT u.name FROM users AS u
+			WHERE (SELECT count(*) FROM orders AS o WHERE u.user_id = o.user_id) > 2`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	sqs = rel.SubQueries(sql.Where.Expr)
+	assert.Equal(t, nil, sqs[0].Decorrelate(sql))
+	assert.Equal(t, "SELECT o.user_id AS subq_key0, count(*) FROM orders AS o GROUP BY o.user_id", sqs[0].Select.String())
+	assert.Equal(t, int64(0), sqs[0].Empty.Value())
+
+	// un-correlated are left as is
+	s = `SELECT name FROM users WHERE user_id IN (SELECT user_id FROM orders)`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	sqs = rel.SubQueries(sql.Where.Expr)
+	assert.Equal(t, nil, sqs[0].Decorrelate(sql))
+	assert.True(t, !sqs[0].Correlated())
+
+	// outer reference we cannot turn into a key lookup
+	s = `SELECT u.name FROM users AS u
+			WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.price > u.limit)`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	sqs = rel.SubQueries(sql.Where.Expr)
+	assert.NotEqual(t, nil, sqs[0].Decorrelate(sql))
+}
+
+func TestSqlSubQueryPb(t *testing.T) {
+	t.Parallel()
+	// where clause sub-queries are copied as sql text
+	s := `SELECT name FROM users WHERE user_id IN (SELECT user_id FROM orders)`
+	sql := parseOrPanic(t, s).(*rel.SqlSelect)
+	assert.Equal(t, nil, rel.CheckPb(sql))
+	cp := rel.CopyStatement(sql)
+	assert.True(t, cp != nil)
+	assert.Equal(t, sql.String(), cp.String())
+
+	// once decorrelated the outer keys would be lost
+	s = `SELECT u.name FROM users AS u
+			WHERE EXISTS (SELECT 1 FROM orders AS o WHERE o.user_id = u.user_id)`
+	sql = parseOrPanic(t, s).(*rel.SqlSelect)
+	assert.Equal(t, nil, rel.CheckPb(sql))
+	assert.Equal(t, nil, rel.SubQueries(sql.Where.Expr)[0].Decorrelate(sql))
+	assert.NotEqual(t, nil, rel.CheckPb(sql))
+	assert.True(t, rel.CopyStatement(sql) == nil)
+
+	// sub-queries outside of the where clause
+	for _, s := range []string{
+		`SELECT user_id, count(*) AS ct FROM orders GROUP BY user_id HAVING ct > (SELECT count(*) FROM users)`,
+		`SELECT u.name FROM users AS u INNER JOIN orders AS o ON u.id = o.user_id AND o.id IN (SELECT id FROM items)`,
+	} {
+		stmt := parseOrPanic(t, s)
+		assert.NotEqual(t, nil, rel.CheckPb(stmt), s)
+		assert.True(t, rel.CopyStatement(stmt) == nil, s)
+	}
+}
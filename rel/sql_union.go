@@ -0,0 +1,187 @@
+package rel
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/lex"
+)
+
+var (
+	// Ensure SqlUnion is a statement
+	_ SqlStatement = (*SqlUnion)(nil)
+)
+
+// SqlUnion a set operation combining the rows of two statements, each
+// side is either a *SqlSelect or another *SqlUnion.
+//
+//    SELECT user_id FROM users UNION ALL SELECT user_id FROM orders
+//    SELECT user_id FROM users INTERSECT SELECT user_id FROM orders
+//    SELECT user_id FROM users EXCEPT SELECT user_id FROM orders ORDER BY user_id LIMIT 10
+//
+// The column names of the result are those of the first select.
+type SqlUnion struct {
+	Raw     string        // full original raw statement
+	Op      lex.TokenType // TokenUnion, TokenIntersect, TokenExcept
+	All     bool          // ALL keeps duplicate rows, else distinct
+	Left    SqlStatement  // *SqlSelect or *SqlUnion
+	Right   SqlStatement  // *SqlSelect or *SqlUnion
+	OrderBy Columns       // order of the combined result
+	Limit   int
+	Offset  int
+	Ctes    []*SqlCte // WITH named sub-queries read as sources
+
+	// Memoized pb
+	pb *SqlStatementPb
+}
+
+// NewSqlUnion create a set operation of left, right statements.
+func NewSqlUnion(op lex.TokenType, all bool, left, right SqlStatement) *SqlUnion {
+	m := &SqlUnion{Op: op, All: all, Left: left, Right: right}
+	m.Raw = m.String()
+	return m
+}
+
+func (m *SqlUnion) Keyword() lex.TokenType { return m.Op }
+func (m *SqlUnion) String() string {
+	w := NewSqlDialect()
+	m.WriteDialect(w)
+	return w.String()
+}
+func (m *SqlUnion) WriteDialect(w expr.DialectWriter) {
+	if len(m.Ctes) > 0 {
+		writeCtes(w, m.Ctes)
+	}
+	m.Left.WriteDialect(w)
+	io.WriteString(w, " ")
+	io.WriteString(w, strings.ToUpper(m.Op.String()))
+	if m.All {
+		io.WriteString(w, " ALL")
+	}
+	io.WriteString(w, " ")
+	m.Right.WriteDialect(w)
+	if len(m.OrderBy) > 0 {
+		io.WriteString(w, " ORDER BY ")
+		m.OrderBy.WriteDialect(w)
+	}
+	if m.Limit > 0 {
+		io.WriteString(w, fmt.Sprintf(" LIMIT %d", m.Limit))
+	}
+	if m.Offset > 0 {
+		io.WriteString(w, fmt.Sprintf(" OFFSET %d", m.Offset))
+	}
+}
+
+// Selects the select statements of this set operation, left to right.
+func (m *SqlUnion) Selects() []*SqlSelect {
+	var sels []*SqlSelect
+	for _, stmt := range []SqlStatement{m.Left, m.Right} {
+		switch st := stmt.(type) {
+		case *SqlSelect:
+			sels = append(sels, st)
+		case *SqlUnion:
+			sels = append(sels, st.Selects()...)
+		}
+	}
+	return sels
+}
+
+// First the left most select, whose columns name the result.
+func (m *SqlUnion) First() *SqlSelect {
+	return m.Selects()[0]
+}
+
+func (m *SqlUnion) Equal(ss SqlStatement) bool {
+	s, ok := ss.(*SqlUnion)
+	if !ok {
+		return false
+	}
+	if m == nil && s == nil {
+		return true
+	}
+	if m == nil || s == nil {
+		return false
+	}
+	if m.Op != s.Op || m.All != s.All || m.Limit != s.Limit || m.Offset != s.Offset {
+		return false
+	}
+	if !m.OrderBy.Equal(s.OrderBy) {
+		return false
+	}
+	if !ctesEqual(m.Ctes, s.Ctes) {
+		return false
+	}
+	return statementEqual(m.Left, s.Left) && statementEqual(m.Right, s.Right)
+}
+
+func statementEqual(a, b SqlStatement) bool {
+	switch at := a.(type) {
+	case *SqlSelect:
+		return at.Equal(b)
+	case *SqlUnion:
+		return at.Equal(b)
+	}
+	return false
+}
+
+func (m *SqlUnion) ToPbStatement() *SqlStatementPb {
+	if m.pb == nil {
+		m.pb = &SqlStatementPb{Union: SqlUnionToPb(m)}
+	}
+	return m.pb
+}
+func (m *SqlUnion) FromPB(spb *SqlUnionPb) *SqlUnion {
+	return SqlUnionFromPb(spb)
+}
+
+// SqlUnionToPb convert a set operation to pb.
+func SqlUnionToPb(m *SqlUnion) *SqlUnionPb {
+	s := &SqlUnionPb{
+		Op:     int32(m.Op),
+		All:    m.All,
+		Raw:    m.Raw,
+		Limit:  int32(m.Limit),
+		Offset: int32(m.Offset),
+		Left:   statementToPb(m.Left),
+		Right:  statementToPb(m.Right),
+		Ctes:   ctesToPb(m.Ctes),
+	}
+	if len(m.OrderBy) > 0 {
+		s.OrderBy = ColumnsToPb(m.OrderBy)
+	}
+	return s
+}
+
+// SqlUnionFromPb create a set operation from pb.
+func SqlUnionFromPb(pb *SqlUnionPb) *SqlUnion {
+	m := &SqlUnion{
+		Op:     lex.TokenType(pb.Op),
+		All:    pb.All,
+		Raw:    pb.Raw,
+		Limit:  int(pb.Limit),
+		Offset: int(pb.Offset),
+		Ctes:   ctesFromPb(pb.Ctes),
+	}
+	if pb.Left != nil {
+		m.Left = statementFromPb(pb.Left)
+	}
+	if pb.Right != nil {
+		m.Right = statementFromPb(pb.Right)
+	}
+	if len(pb.OrderBy) > 0 {
+		m.OrderBy = ColumnsFromPb(pb.OrderBy)
+	}
+	return m
+}
+
+func statementToPb(stmt SqlStatement) *SqlStatementPb {
+	switch st := stmt.(type) {
+	case *SqlSelect:
+		return st.ToPbStatement()
+	case *SqlUnion:
+		return st.ToPbStatement()
+	}
+	return nil
+}
@@ -37,3 +37,30 @@ func TestApplySchema(t *testing.T) {
 	err = a.Drop(s, "fake")
 	assert.NotEqual(t, nil, err)
 }
+
+func TestSchemaVersion(t *testing.T) {
+	a := schema.NewApplyer(func(s *schema.Schema) schema.Source {
+		sdb := datasource.NewSchemaDb(s)
+		s.InfoSchema.DS = sdb
+		return sdb
+	})
+	reg := schema.NewRegistry(a)
+	a.Init(reg)
+
+	db, err := memdb.NewMemDbData("users", [][]driver.Value{{122, "bob"}}, []string{"user_id", "name"})
+	assert.Equal(t, nil, err)
+
+	s := schema.NewSchema("versioned")
+	s.DS = db
+	v := s.Version()
+	err = a.AddOrUpdateOnSchema(s, s)
+	assert.Equal(t, nil, err)
+	assert.True(t, s.Version() > v, "refresh must change version")
+
+	v = s.Version()
+	tbl, err := s.Table("users")
+	assert.Equal(t, nil, err)
+	err = a.Drop(s, tbl)
+	assert.Equal(t, nil, err)
+	assert.True(t, s.Version() > v, "drop must change version")
+}
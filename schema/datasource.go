@@ -15,6 +15,8 @@ var (
 	ErrNotFound = fmt.Errorf("Not Found")
 	// ErrNotImplemented this feature is not implemented for this source.
 	ErrNotImplemented = fmt.Errorf("Not Implemented")
+	// ErrTxDone the transaction has already been committed or rolled back.
+	ErrTxDone = fmt.Errorf("Transaction has already been committed or rolled back")
 )
 
 type (
@@ -129,6 +131,12 @@ type (
 	ConnSeeker interface {
 		Get(key driver.Value) (Message, error)
 	}
+	// ConnMultiSeeker is a ConnSeeker that can fetch many keys in a single
+	// call.  Rows are returned only for keys that were found.
+	ConnMultiSeeker interface {
+		ConnSeeker
+		MultiGet(keys []driver.Value) ([]Message, error)
+	}
 	// ConnMutation creates a Mutator connection similar to Open() connection for select
 	// - accepts the plan context used in this upsert/insert/update
 	// - returns a connection which must be closed
@@ -146,8 +154,27 @@ type (
 		Put(ctx context.Context, key Key, value interface{}) (Key, error)
 		PutMulti(ctx context.Context, keys []Key, src interface{}) ([]Key, error)
 	}
+	// ConnTransaction is an optional interface for a Conn whose source supports
+	// transactions.  After Begin, the mutations of the conn are not visible to
+	// other conns, or durable, until Commit.  Rollback undoes them.  Conns to
+	// the same source Join the transaction begun by another so they read and
+	// write through it, see Transaction.  The transaction stays open after the
+	// conns in it are closed.
+	ConnTransaction interface {
+		Begin() error
+		Join(tx ConnTransaction) error
+		Commit() error
+		Rollback() error
+	}
+	// ConnStats is an optional interface for a Conn that can supply the
+	// statistics of its table natively, ie from its own metadata or an
+	// aggregate query, instead of ANALYZE TABLE scanning every row.
+	ConnStats interface {
+		TableStats() (*TableStats, error)
+	}
 	// ConnPatchWhere pass through where expression to underlying datasource
-	// Used for update statements WHERE x = y
+	// Used for update statements WHERE x = y whose values don't reference
+	// the rows they update, other updates read the rows and Put them back.
 	ConnPatchWhere interface {
 		PatchWhere(ctx context.Context, where expr.Node, patch interface{}) (int64, error)
 	}
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	u "github.com/araddon/gou"
@@ -78,6 +79,13 @@ type (
 		DropTable(table string) error
 	}
 
+	// Creator interface for sources that can create tables, ie for
+	// CREATE TABLE ... AS SELECT.
+	Creator interface {
+		// CreateTable create given table, its fields are its columns
+		CreateTable(tbl *Table) error
+	}
+
 	// Schema is a "Virtual" Schema and may have multiple different backing sources.
 	// - Multiple DataSource(s) (each may be discrete source type such as mysql, elasticsearch, etc)
 	// - each schema supplies tables to the virtual table pool
@@ -94,6 +102,7 @@ type (
 		tableMap      map[string]*Table  // Tables and their field info, flattened from all child schemas
 		tableNames    []string           // List Table names, flattened all schemas into one list
 		lastRefreshed time.Time          // Last time we refreshed this schema
+		version       uint64             // incremented on each change of tables
 		mu            sync.RWMutex       // lock for schema mods
 	}
 
@@ -111,6 +120,32 @@ type (
 		cols           []string               // array of column names
 		lastRefreshed  time.Time              // Last time we refreshed this schema
 		rows           [][]driver.Value
+		stats          atomic.Value // *TableStats, estimated statistics
+	}
+
+	// TableStats are estimates about the data in a table used by the
+	// planner, ie to choose the smaller side of a join to build on.
+	TableStats struct {
+		RowCt   int64                   // Estimated number of rows
+		Columns map[string]*ColumnStats // Per column stats by column name, nil if not analyzed
+		Updated time.Time               // When these stats were collected
+	}
+
+	// ColumnStats are estimates about the values of a column, used to
+	// estimate the selectivity of predicates on it.
+	ColumnStats struct {
+		NDV       int64             // Estimated number of distinct non-null values
+		NullFrac  float64           // Fraction of rows that are NULL
+		Min       driver.Value      // Smallest non-null value
+		Max       driver.Value      // Largest non-null value
+		Histogram []HistogramBucket // Equi-depth histogram of non-null values, optional
+	}
+
+	// HistogramBucket of an equi-depth histogram, the rows with values
+	// above the previous buckets Upper up to and including this Upper.
+	HistogramBucket struct {
+		Upper driver.Value // Inclusive upper bound
+		Ct    int64        // Estimated number of rows
 	}
 
 	// Field Describes the column info, name, data type, defaults, index, null
@@ -203,6 +238,10 @@ func (m *Schema) Since(dur time.Duration) bool {
 // Current Is this schema up to date?
 func (m *Schema) Current() bool { return m.Since(SchemaRefreshInterval) }
 
+// Version of this schema, changes each time its tables are added, dropped
+// or refreshed so anything derived from it may be invalidated.
+func (m *Schema) Version() uint64 { return atomic.LoadUint64(&m.version) }
+
 // Tables gets list of all tables for this schema.
 func (m *Schema) Tables() []string { return m.tableNames }
 
@@ -239,14 +278,11 @@ func (m *Schema) Table(tableIn string) (*Table, error) {
 // OpenConn get a connection from this schema by table name.
 func (m *Schema) OpenConn(tableName string) (Conn, error) {
 	tableName = strings.ToLower(tableName)
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	sch, ok := m.tableSchemas[tableName]
-	if !ok || sch == nil || sch.DS == nil {
-		return nil, fmt.Errorf("Could not find a DataSource for that table %q", tableName)
+	ds, err := m.tableSource(tableName)
+	if err != nil {
+		return nil, err
 	}
-
-	conn, err := sch.DS.Open(tableName)
+	conn, err := ds.Open(tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -256,6 +292,17 @@ func (m *Schema) OpenConn(tableName string) (Conn, error) {
 	return conn, nil
 }
 
+// tableSource the source providing this (lower-cased) table name.
+func (m *Schema) tableSource(tableName string) (Source, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sch, ok := m.tableSchemas[tableName]
+	if !ok || sch == nil || sch.DS == nil {
+		return nil, fmt.Errorf("Could not find a DataSource for that table %q", tableName)
+	}
+	return sch.DS, nil
+}
+
 // Schema Find a child Schema for given schema name,
 func (m *Schema) Schema(schemaName string) (*Schema, error) {
 	// We always lower-case schema names
@@ -318,6 +365,7 @@ func (m *Schema) addSchemaForTable(tableName string, ss *Schema) {
 func (m *Schema) refreshSchemaUnlocked() {
 
 	m.lastRefreshed = time.Now()
+	atomic.AddUint64(&m.version, 1)
 
 	if m.DS != nil {
 		for _, tableName := range m.DS.Tables() {
@@ -361,7 +409,13 @@ func (m *Schema) dropTable(tbl *Table) error {
 
 	delete(m.tableMap, tbl.Name)
 	delete(m.tableSchemas, tbl.Name)
+	if ts != nil && ts != m && len(ts.Tables()) == 1 {
+		// a child schema of just this table, ie an in-memory table of a
+		// CREATE TABLE ... AS SELECT, else a refresh would add it back
+		delete(m.schemas, ts.Name)
+	}
 	m.tableNames = tl
+	atomic.AddUint64(&m.version, 1)
 
 	if salter, ok := m.InfoSchema.DS.(Alter); ok {
 		err := salter.DropTable(tbl.Name)
@@ -398,6 +452,7 @@ func (m *Schema) addTable(tbl *Table) error {
 	tbl.init(m)
 
 	m.tableMap[tbl.Name] = tbl
+	atomic.AddUint64(&m.version, 1)
 
 	m.addschemaForTableUnlocked(tbl.Name, tbl.Schema)
 	return nil
@@ -457,6 +512,9 @@ func (m *Schema) loadTable(tableName string) error {
 
 	// Add partitions
 	if m.Conf != nil {
+		if m.Conf.PartitionCt > 0 {
+			tbl.PartitionCt = uint32(m.Conf.PartitionCt)
+		}
 		for _, tp := range m.Conf.Partitions {
 			if tp.Table == tableName {
 				tbl.Partition = tp
@@ -572,6 +630,16 @@ func (m *Table) SetColumnsFromFields() {
 // Columns list of all column names.
 func (m *Table) Columns() []string { return m.cols }
 
+// Stats the estimated statistics of this table, nil if unknown.
+func (m *Table) Stats() *TableStats {
+	stats, _ := m.stats.Load().(*TableStats)
+	return stats
+}
+
+// SetStats replace the statistics of this table, safe while it is read
+// by queries being planned.
+func (m *Table) SetStats(stats *TableStats) { m.stats.Store(stats) }
+
 // AsRows return all fields suiteable as list of values for Describe/Show statements.
 func (m *Table) AsRows() [][]driver.Value {
 	if len(m.rows) > 0 {
@@ -592,6 +660,16 @@ func (m *Table) SetRows(rows [][]driver.Value) {
 // FieldNamesPositions List of Field Names and ordinal position in Column list
 func (m *Table) FieldNamesPositions() map[string]int { return m.FieldPositions }
 
+// PrimaryKey the field names of the primary key index, nil if none.
+func (m *Table) PrimaryKey() []string {
+	for _, idx := range m.Indexes {
+		if idx.PrimaryKey {
+			return idx.Fields
+		}
+	}
+	return nil
+}
+
 // Current Is this schema object current?  ie, have we refreshed it from
 // source since refresh interval.
 func (m *Table) Current() bool { return m.Since(SchemaRefreshInterval) }
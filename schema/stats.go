@@ -0,0 +1,319 @@
+package schema
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dchest/siphash"
+)
+
+const (
+	// statsSampleSize max number of values per column sampled to build
+	// the histogram of a column.
+	statsSampleSize = 10000
+	// statsHistogramBuckets max number of buckets of a column histogram.
+	statsHistogramBuckets = 64
+	// hllPrecision number of bits of the hash used to pick the register
+	// of the distinct count sketch, ~0.8% standard error.
+	hllPrecision = 14
+)
+
+// StatsCollector builds the TableStats of a table from its rows, for
+// sources that can't supply them natively (ConnStats).
+//
+//    sc := NewStatsCollector(tbl.Columns())
+//    for each row { sc.Add(row) }
+//    tbl.SetStats(sc.Stats())
+type StatsCollector struct {
+	cols  []string
+	stats []*columnCollector
+	rowCt int64
+	rnd   *rand.Rand
+}
+
+// columnCollector accumulates the stats of a single column.
+type columnCollector struct {
+	nullCt int64
+	ct     int64 // non-null values seen
+	min    driver.Value
+	max    driver.Value
+	ndv    *hyperLogLog
+	sample []driver.Value // reservoir sample of non-null values
+}
+
+// NewStatsCollector create a collector for rows of the given columns.
+func NewStatsCollector(cols []string) *StatsCollector {
+	m := &StatsCollector{
+		cols:  cols,
+		stats: make([]*columnCollector, len(cols)),
+		// deterministic so the stats of a table are repeatable
+		rnd: rand.New(rand.NewSource(int64(len(cols)))),
+	}
+	for i := range cols {
+		m.stats[i] = &columnCollector{ndv: newHyperLogLog()}
+	}
+	return m
+}
+
+// Add a row, its values in the same order as the collectors columns.
+func (m *StatsCollector) Add(row []driver.Value) {
+	m.rowCt++
+	for i, cs := range m.stats {
+		var v driver.Value
+		if i < len(row) {
+			v = row[i]
+		}
+		if v == nil {
+			cs.nullCt++
+			continue
+		}
+		cs.ct++
+		cs.ndv.add(v)
+		if cs.min == nil || CompareValues(v, cs.min) < 0 {
+			cs.min = v
+		}
+		if cs.max == nil || CompareValues(v, cs.max) > 0 {
+			cs.max = v
+		}
+		if len(cs.sample) < statsSampleSize {
+			cs.sample = append(cs.sample, v)
+		} else if j := m.rnd.Int63n(cs.ct); j < statsSampleSize {
+			cs.sample[j] = v
+		}
+	}
+}
+
+// Stats the table stats of the rows added.
+func (m *StatsCollector) Stats() *TableStats {
+	ts := &TableStats{
+		RowCt:   m.rowCt,
+		Columns: make(map[string]*ColumnStats, len(m.cols)),
+		Updated: time.Now(),
+	}
+	for i, col := range m.cols {
+		cs := m.stats[i]
+		colStats := &ColumnStats{
+			NDV: cs.ndv.estimate(),
+			Min: cs.min,
+			Max: cs.max,
+		}
+		if colStats.NDV > cs.ct {
+			colStats.NDV = cs.ct
+		}
+		if m.rowCt > 0 {
+			colStats.NullFrac = float64(cs.nullCt) / float64(m.rowCt)
+		}
+		colStats.Histogram = equiDepthHistogram(cs.sample, cs.ct)
+		ts.Columns[col] = colStats
+	}
+	return ts
+}
+
+// equiDepthHistogram of the sampled values, each bucket an equal share
+// of the ct non-null rows.
+func equiDepthHistogram(sample []driver.Value, ct int64) []HistogramBucket {
+	n := len(sample)
+	if n == 0 {
+		return nil
+	}
+	sort.Slice(sample, func(i, j int) bool { return CompareValues(sample[i], sample[j]) < 0 })
+	buckets := statsHistogramBuckets
+	if n < buckets {
+		buckets = n
+	}
+	hist := make([]HistogramBucket, 0, buckets)
+	lower := 0
+	for i := 0; i < buckets; i++ {
+		upper := (i+1)*n/buckets - 1
+		if upper < lower {
+			continue
+		}
+		bct := int64(math.Round(float64(ct) * float64(upper-lower+1) / float64(n)))
+		lower = upper + 1
+		// heavy hitters span buckets, merge those with the same bound
+		if last := len(hist) - 1; last >= 0 && CompareValues(hist[last].Upper, sample[upper]) == 0 {
+			hist[last].Ct += bct
+			continue
+		}
+		hist = append(hist, HistogramBucket{Upper: sample[upper], Ct: bct})
+	}
+	return hist
+}
+
+// FracEqual estimate the fraction of rows whose value equals v.
+func (m *ColumnStats) FracEqual(v driver.Value) float64 {
+	if v == nil {
+		return m.NullFrac
+	}
+	if m.Min != nil && CompareValues(v, m.Min) < 0 {
+		return 0
+	}
+	if m.Max != nil && CompareValues(v, m.Max) > 0 {
+		return 0
+	}
+	if m.NDV <= 0 {
+		return 0
+	}
+	return (1 - m.NullFrac) / float64(m.NDV)
+}
+
+// FracLess estimate the fraction of rows whose value is less than
+// (or equal to if inclusive) v.
+func (m *ColumnStats) FracLess(v driver.Value, inclusive bool) float64 {
+	nonNull := 1 - m.NullFrac
+	switch {
+	case v == nil:
+		return 0
+	case m.Min != nil && CompareValues(v, m.Min) < 0:
+		return 0
+	case m.Max != nil && CompareValues(v, m.Max) > 0:
+		return nonNull
+	}
+	if len(m.Histogram) == 0 {
+		if frac, ok := interpolate(m.Min, m.Max, v); ok {
+			return nonNull * frac
+		}
+		return nonNull / 3
+	}
+	var total, below float64
+	for _, b := range m.Histogram {
+		total += float64(b.Ct)
+	}
+	if total == 0 {
+		return 0
+	}
+	lower := m.Min
+	for _, b := range m.Histogram {
+		c := CompareValues(b.Upper, v)
+		if c < 0 || (c == 0 && inclusive) {
+			below += float64(b.Ct)
+			lower = b.Upper
+			continue
+		}
+		// v falls in this bucket
+		frac, ok := interpolate(lower, b.Upper, v)
+		if !ok {
+			frac = 0.5
+		}
+		below += frac * float64(b.Ct)
+		break
+	}
+	return nonNull * below / total
+}
+
+// interpolate the position of v between lower and upper, false if not numeric.
+func interpolate(lower, upper, v driver.Value) (float64, bool) {
+	lf, ok1 := valueFloat(lower)
+	uf, ok2 := valueFloat(upper)
+	vf, ok3 := valueFloat(v)
+	if !ok1 || !ok2 || !ok3 {
+		return 0, false
+	}
+	if uf <= lf {
+		return 1, true
+	}
+	frac := (vf - lf) / (uf - lf)
+	return math.Max(0, math.Min(1, frac)), true
+}
+
+// CompareValues orders 2 column values, numerically if both are numbers
+// (or numeric strings), by time if times, otherwise as strings.
+func CompareValues(a, b driver.Value) int {
+	if af, ok := valueFloat(a); ok {
+		if bf, ok := valueFloat(b); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			switch {
+			case at.Before(bt):
+				return -1
+			case at.After(bt):
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(valueString(a), valueString(b))
+}
+
+func valueFloat(v driver.Value) (float64, bool) {
+	switch vt := v.(type) {
+	case int64:
+		return float64(vt), true
+	case int:
+		return float64(vt), true
+	case int32:
+		return float64(vt), true
+	case uint64:
+		return float64(vt), true
+	case float64:
+		return vt, true
+	case float32:
+		return float64(vt), true
+	case string:
+		f, err := strconv.ParseFloat(vt, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func valueString(v driver.Value) string {
+	switch vt := v.(type) {
+	case string:
+		return vt
+	case []byte:
+		return string(vt)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// hyperLogLog sketch estimating the number of distinct values added.
+type hyperLogLog struct {
+	registers []uint8
+}
+
+func newHyperLogLog() *hyperLogLog {
+	return &hyperLogLog{registers: make([]uint8, 1<<hllPrecision)}
+}
+
+func (m *hyperLogLog) add(v driver.Value) {
+	h := siphash.Hash(5431, 97531, []byte(valueString(v)))
+	idx := h >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(h<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	if rank > m.registers[idx] {
+		m.registers[idx] = rank
+	}
+}
+
+func (m *hyperLogLog) estimate() int64 {
+	n := float64(len(m.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range m.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	est := 0.7213 / (1 + 1.079/n) * n * n / sum
+	if est <= 2.5*n && zeros > 0 {
+		// small cardinalities, linear counting
+		est = n * math.Log(n/float64(zeros))
+	}
+	return int64(math.Round(est))
+}
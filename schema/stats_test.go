@@ -0,0 +1,74 @@
+package schema_test
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/schema"
+)
+
+func TestStatsCollector(t *testing.T) {
+	sc := schema.NewStatsCollector([]string{"id", "name", "score"})
+	for i := 0; i < 50000; i++ {
+		var score driver.Value
+		if i%10 != 0 {
+			score = int64(i % 1000)
+		}
+		sc.Add([]driver.Value{int64(i), fmt.Sprintf("name-%d", i%100), score})
+	}
+	stats := sc.Stats()
+	assert.Equal(t, int64(50000), stats.RowCt)
+
+	id := stats.Columns["id"]
+	// the distinct count sketch is within a few percent
+	assert.True(t, math.Abs(float64(id.NDV)-50000) < 2500, "ndv %d", id.NDV)
+	assert.Equal(t, int64(0), id.Min)
+	assert.Equal(t, int64(49999), id.Max)
+	assert.Equal(t, 0.0, id.NullFrac)
+	assert.True(t, len(id.Histogram) > 1)
+	assert.True(t, math.Abs(id.FracLess(int64(25000), false)-0.5) < 0.05)
+	assert.Equal(t, 0.0, id.FracLess(int64(-1), false))
+	assert.Equal(t, 1.0, id.FracLess(int64(60000), false))
+	assert.Equal(t, 0.0, id.FracEqual(int64(60000)))
+
+	name := stats.Columns["name"]
+	assert.True(t, math.Abs(float64(name.NDV)-100) <= 3, "ndv %d", name.NDV)
+	assert.True(t, math.Abs(name.FracEqual("name-5")-0.01) < 0.001, "%v", name.FracEqual("name-5"))
+
+	score := stats.Columns["score"]
+	assert.Equal(t, 0.1, score.NullFrac)
+	// the null rows are never less than a value
+	assert.True(t, math.Abs(score.FracLess(int64(500), false)-0.45) < 0.05, "%v", score.FracLess(int64(500), false))
+
+	assert.Equal(t, -1, schema.CompareValues(int64(2), "10"))
+	assert.Equal(t, 1, schema.CompareValues("b", "a"))
+}
+
+func TestTableStats(t *testing.T) {
+	tbl := schema.NewTable("stats")
+	assert.True(t, tbl.Stats() == nil)
+
+	// stats are replaced while being read, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if stats := tbl.Stats(); stats != nil && stats.RowCt < 0 {
+					t.Errorf("bad stats %+v", stats)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		tbl.SetStats(&schema.TableStats{RowCt: int64(i)})
+	}
+	wg.Wait()
+	assert.Equal(t, int64(99), tbl.Stats().RowCt)
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"strings"
+	"sync"
+)
+
+// Transaction is a multi-statement transaction across the sources of
+// a schema.  Each source that supports transactions (its conns implement
+// ConnTransaction) is begun on the first conn opened through the
+// transaction, later conns to it Join that conn's transaction, and they
+// are committed or rolled back together.  Conns not opened through the
+// transaction do not see its writes.  Sources that do not support
+// transactions are opened as-is.
+type Transaction struct {
+	mu      sync.Mutex
+	sources map[Source]ConnTransaction
+	conns   []ConnTransaction // in order begun
+	done    bool
+}
+
+// NewTransaction create a new, open transaction.
+func NewTransaction() *Transaction {
+	return &Transaction{sources: make(map[Source]ConnTransaction)}
+}
+
+// OpenConn open a connection to this schema's table as part of this transaction.
+func (m *Transaction) OpenConn(s *Schema, tableName string) (Conn, error) {
+	tableName = strings.ToLower(tableName)
+	ds, err := s.tableSource(tableName)
+	if err != nil {
+		return nil, err
+	}
+	return m.Open(ds, tableName)
+}
+
+// Open a connection to the source's table as part of this transaction.
+func (m *Transaction) Open(ds Source, tableName string) (Conn, error) {
+	conn, err := ds.Open(tableName)
+	if err != nil {
+		return nil, err
+	}
+	tx, ok := conn.(ConnTransaction)
+	if !ok {
+		return conn, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done {
+		conn.Close()
+		return nil, ErrTxDone
+	}
+	if begun, ok := m.sources[ds]; ok {
+		if err = tx.Join(begun); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+	if err = tx.Begin(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	m.sources[ds] = tx
+	m.conns = append(m.conns, tx)
+	return conn, nil
+}
+
+// Done is true once this transaction is committed or rolled back.
+func (m *Transaction) Done() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.done
+}
+
+// Commit the transaction of each source begun.  All sources are
+// committed, the first error is returned.
+func (m *Transaction) Commit() error {
+	return m.finish(ConnTransaction.Commit)
+}
+
+// Rollback the transaction of each source begun.
+func (m *Transaction) Rollback() error {
+	return m.finish(ConnTransaction.Rollback)
+}
+
+func (m *Transaction) finish(end func(ConnTransaction) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done {
+		return ErrTxDone
+	}
+	m.done = true
+	var firstErr error
+	for _, tx := range m.conns {
+		if err := end(tx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -123,7 +123,25 @@ func RunTestSuite(t TestingT) {
 
 	// Distinct keyword
 	TestSelect(t, "SELECT COUNT(DISTINCT(`users.email`)) AS cd FROM users",
-		[][]driver.Value{{int64(0)}},
+		[][]driver.Value{{int64(3)}},
+	)
+	TestSelect(t, "SELECT COUNT(DISTINCT(`users.user_id`)) AS cd FROM users",
+		[][]driver.Value{{int64(3)}},
+	)
+	TestSelect(t, "SELECT COUNT(DISTINCT user_id) AS users, COUNT(user_id) AS ct FROM orders",
+		[][]driver.Value{{int64(2), int64(3)}},
+	)
+	TestSelect(t, "SELECT SUM(DISTINCT price) AS total FROM orders",
+		[][]driver.Value{{float64(60)}},
+	)
+	TestSelect(t, "SELECT DISTINCT user_id FROM orders",
+		[][]driver.Value{{"9Ip1aKbeZe2njCDM"}, {"abcabcabc"}},
+	)
+	TestSelect(t, "SELECT DISTINCT user_id FROM orders ORDER BY user_id DESC LIMIT 1",
+		[][]driver.Value{{"abcabcabc"}},
+	)
+	TestSelect(t, "SELECT DISTINCT item_id, price FROM orders",
+		[][]driver.Value{{"1", "22.50"}, {"2", "37.50"}},
 	)
 
 	TestSelect(t, "SELECT email FROM users ORDER BY email DESC",
@@ -138,11 +156,6 @@ func RunTestSuite(t TestingT) {
 	TestSelectErr(t, "SELECT email, non_existent_field FROM users ORDER BY email ASC", nil)
 
 	/*
-		// TODO: #56 DISTINCT inside count()
-		testutil.TestSelect(t, "SELECT COUNT(DISTINCT(`users.user_id`)) AS cd FROM users",
-			[][]driver.Value{{int64(3)}},
-		)
-
 		// TODO: #56 this doesn't work because ordering is non-deterministic coming out of group by currently
 		//  which technically don't think there is any sql expectation of ordering, but there is for this test harness
 		testutil.TestSelect(t, "select `users`.`user_id` AS userids FROM users GROUP BY `users`.`user_id`;",
@@ -183,6 +196,17 @@ func RunSimpleSuite(t TestingT) {
 		[][]driver.Value{{"aaron@email.com"}},
 	)
 
+	// LIMIT, OFFSET, pushed down to sources that support it
+	TestSelect(t, "SELECT email FROM users ORDER BY email ASC LIMIT 1 OFFSET 1",
+		[][]driver.Value{{"bob@email.com"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE email = \"aaron@email.com\" LIMIT 1",
+		[][]driver.Value{{"aaron@email.com"}},
+	)
+	TestSelect(t, "SELECT email FROM users WHERE email = \"aaron@email.com\" LIMIT 1 OFFSET 1",
+		[][]driver.Value{},
+	)
+
 	// - user_id != NULL (on string column)
 	// - as well as count(*)
 	TestSelect(t, "SELECT COUNT(*) AS count FROM users WHERE (`users.user_id` != NULL)",
@@ -200,9 +224,24 @@ func RunSimpleSuite(t TestingT) {
 		[][]driver.Value{{float64(14.0)}}, // aaron@email.combob@email.comnot_an_email_2 = 42 characters / 3 = 14
 	)
 
-	// Distinct keyword
+	// Distinct keyword, DISTINCT pushed down to sources that support it
 	TestSelect(t, "SELECT COUNT(DISTINCT(`users`.`email`)) AS cd FROM users",
-		[][]driver.Value{{int64(0)}},
+		[][]driver.Value{{int64(3)}},
+	)
+	TestSelect(t, "SELECT COUNT(DISTINCT user_id) AS users, COUNT(user_id) AS ct FROM orders",
+		[][]driver.Value{{int64(2), int64(3)}},
+	)
+	TestSelect(t, "SELECT DISTINCT user_id FROM orders ORDER BY user_id ASC LIMIT 1 OFFSET 1",
+		[][]driver.Value{{"abcabcabc"}},
+	)
+
+	// CASE expressions and conditional functions, evaluated by sources
+	// that support them natively or late evaluated
+	TestSelect(t, "SELECT email, CASE WHEN interests != NULL THEN \"yes\" ELSE \"no\" END AS has FROM users WHERE email = \"aaron@email.com\"",
+		[][]driver.Value{{"aaron@email.com", "yes"}},
+	)
+	TestSelect(t, "SELECT if(email = \"bob@email.com\", \"b\", \"a\") AS isbob, coalesce(email, \"none\") AS e FROM users WHERE email = \"bob@email.com\"",
+		[][]driver.Value{{"b", "bob@email.com"}},
 	)
 
 	// Function in select projected columns that needs to be late evaluated.
@@ -224,11 +263,6 @@ func RunSimpleSuite(t TestingT) {
 	TestSelectErr(t, "SELECT email, non_existent_field FROM users ORDER BY email ASC", nil)
 
 	/*
-		// TODO: #56 DISTINCT inside count()
-		testutil.TestSelect(t, "SELECT COUNT(DISTINCT(`users.user_id`)) AS cd FROM users",
-			[][]driver.Value{{int64(3)}},
-		)
-
 		// TODO: #56 this doesn't work because ordering is non-deterministic coming out of group by currently
 		//  which technically don't think there is any sql expectation of ordering, but there is for this test harness
 		testutil.TestSelect(t, "select `users`.`user_id` AS userids FROM users GROUP BY `users`.`user_id`;",
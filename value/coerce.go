@@ -91,6 +91,18 @@ func Cast(valType ValueType, val Value) (Value, error) {
 			return NewIntValue(iv), nil
 		}
 		return nil, ErrConversion
+	case NumberType:
+		fv, ok := ValueToFloat64(val)
+		if ok {
+			return NewNumberValue(fv), nil
+		}
+		return nil, ErrConversion
+	case BoolType:
+		bv, ok := ValueToBool(val)
+		if ok {
+			return NewBoolValue(bv), nil
+		}
+		return nil, ErrConversion
 	}
 	return nil, ErrConversionNotSupported
 }
@@ -168,7 +168,14 @@ func TestCast(t *testing.T) {
 	iv, _ := ValueToInt(NewIntValue(100))
 	assert.Equal(t, int(100), iv)
 
+	// to NUMBER, BOOL
+	good(float64(22.5), NumberType, NewStringValue("22.5"))
+	good(float64(100), NumberType, NewIntValue(100))
+	good(true, BoolType, NewStringValue("true"))
+	good(false, BoolType, NewIntValue(0))
+
 	castBad(BoolType, NewIntValue(500))
+	castBad(NumberType, NewStringValue("hello"))
 	castBad(TimeType, NewStringValue("hello"))
 	castBad(IntType, NewStringValue("hello"))
 	castBad(IntType, NewStringValue(""))
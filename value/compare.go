@@ -0,0 +1,206 @@
+package value
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+var (
+	// BinaryCollation compares strings byte-wise, the default.
+	BinaryCollation Collation = binaryCollation{}
+)
+
+type (
+	// Collation compares two strings for ordering, returning -1, 0, 1
+	// for a < b, a == b, a > b.
+	Collation interface {
+		CompareString(a, b string) int
+	}
+	binaryCollation struct{}
+	// textCollation is a language aware collation, the underlying
+	// collator is not safe for concurrent use so guard it.
+	textCollation struct {
+		mu sync.Mutex
+		c  *collate.Collator
+	}
+)
+
+func (binaryCollation) CompareString(a, b string) int { return strings.Compare(a, b) }
+
+func (m *textCollation) CompareString(a, b string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.c.CompareString(a, b)
+}
+
+// CollationFromName find a collation by name as used in
+//
+//    ORDER BY name COLLATE <name>
+//
+// - "" or "binary" or any name ending "_bin" is byte-wise comparison
+// - "nocase" or any name ending in "_ci" is case-insensitive
+// - otherwise name is a language tag "en", "de_DE", "sv-SE" and optionally
+//   with "_ci" suffix for case-insensitive in that language.
+func CollationFromName(name string) (Collation, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case name == "", name == "binary", strings.HasSuffix(name, "_bin"):
+		return BinaryCollation, nil
+	case name == "nocase":
+		return &textCollation{c: collate.New(language.Und, collate.IgnoreCase)}, nil
+	}
+	var opts []collate.Option
+	if strings.HasSuffix(name, "_ci") {
+		name = strings.TrimSuffix(name, "_ci")
+		opts = append(opts, collate.IgnoreCase)
+	} else {
+		name = strings.TrimSuffix(name, "_cs")
+	}
+	tag, err := language.Parse(strings.Replace(name, "_", "-", -1))
+	if err != nil {
+		if len(opts) == 0 {
+			return nil, fmt.Errorf("unrecognized collation %q", name)
+		}
+		// mysql style utf8mb4_general_ci, not a language
+		tag = language.Und
+	}
+	return &textCollation{c: collate.New(tag, opts...)}, nil
+}
+
+// IsNilish is this value a NULL for comparison purposes, ie nil, NilValue
+// or a NaN number.
+func IsNilish(v Value) bool {
+	switch vt := v.(type) {
+	case nil, NilValue:
+		return true
+	case NumberValue:
+		return math.IsNaN(vt.Val())
+	}
+	return false
+}
+
+// Compare compares two values after detecting type, returning -1, 0, 1
+// for l < r, l == r, l > r.  Nil values are smaller than any other value.
+// Numbers compare numerically (coercing numeric strings), times
+// chronologically, bools false < true, strings byte-wise.
+// Error if the values are of types that can't be compared.
+func Compare(l, r Value) (int, error) {
+	return CompareCollate(l, r, nil)
+}
+
+// CompareCollate compares as Compare does, but uses given Collation for
+// string comparison.  Nil collation is binary.
+func CompareCollate(l, r Value, c Collation) (int, error) {
+
+	ln, rn := IsNilish(l), IsNilish(r)
+	switch {
+	case ln && rn:
+		return 0, nil
+	case ln:
+		return -1, nil
+	case rn:
+		return 1, nil
+	}
+
+	switch lt := l.(type) {
+	case IntValue:
+		if rt, ok := r.(IntValue); ok {
+			return compareInt64(lt.Val(), rt.Val()), nil
+		}
+		if rf, ok := ValueToFloat64(r); ok {
+			return compareFloat64(lt.Float(), rf), nil
+		}
+	case NumberValue:
+		if rf, ok := ValueToFloat64(r); ok {
+			return compareFloat64(lt.Val(), rf), nil
+		}
+	case BoolValue:
+		if rb, ok := ValueToBool(r); ok {
+			return compareBool(lt.Val(), rb), nil
+		}
+	case TimeValue:
+		if rt, ok := ValueToTime(r); ok {
+			switch {
+			case lt.Val().Before(rt):
+				return -1, nil
+			case lt.Val().After(rt):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case StringValue:
+		switch r.(type) {
+		case StringValue:
+			return compareString(lt.Val(), r.ToString(), c), nil
+		case IntValue, NumberValue, BoolValue, TimeValue:
+			// let the typed side decide, then invert
+			if cmp, err := CompareCollate(r, l, c); err == nil {
+				return -cmp, nil
+			}
+		}
+	case Map:
+		// maps are also slices (of keys) but have no ordering
+		return 0, fmt.Errorf("Could not compare %T to %T", l, r)
+	case Slice:
+		rs, ok := r.(Slice)
+		if !ok {
+			break
+		}
+		rvals := rs.SliceValue()
+		for i, lv := range lt.SliceValue() {
+			if i >= len(rvals) {
+				return 1, nil
+			}
+			if cmp, err := CompareCollate(lv, rvals[i], c); err != nil || cmp != 0 {
+				return cmp, err
+			}
+		}
+		return compareInt64(int64(lt.Len()), int64(rs.Len())), nil
+	}
+
+	// mixed types that could not be coerced compare on string form
+	switch r.(type) {
+	case Map, Slice:
+		return 0, fmt.Errorf("Could not compare %T to %T", l, r)
+	}
+	return compareString(l.ToString(), r.ToString(), c), nil
+}
+
+func compareString(l, r string, c Collation) int {
+	if c == nil {
+		return strings.Compare(l, r)
+	}
+	return c.CompareString(l, r)
+}
+func compareInt64(l, r int64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+func compareFloat64(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+func compareBool(l, r bool) int {
+	switch {
+	case l == r:
+		return 0
+	case !l:
+		return -1
+	}
+	return 1
+}
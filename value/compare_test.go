@@ -0,0 +1,75 @@
+package value
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compareTest struct {
+	l, r Value
+	cmp  int
+}
+
+var cmpTime = time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var compareTests = []compareTest{
+	{NewIntValue(9), NewIntValue(10), -1},
+	{NewIntValue(10), NewIntValue(10), 0},
+	{NewNumberValue(10.5), NewIntValue(10), 1},
+	{NewStringValue("10"), NewStringValue("9"), -1},
+	{NewStringValue("10"), NewIntValue(9), 1},
+	{NewIntValue(9), NewStringValue("10"), -1},
+	{NewStringValue("abc"), NewStringValue("abd"), -1},
+	{NewStringValue("B"), NewStringValue("a"), -1},
+	{NewBoolValue(false), NewBoolValue(true), -1},
+	{NewTimeValue(cmpTime), NewTimeValue(cmpTime.Add(time.Hour)), -1},
+	{NewTimeValue(cmpTime), NewStringValue("2015-12-31"), 1},
+	{NewStringValue("2015-12-31"), NewTimeValue(cmpTime), -1},
+	{nil, NewIntValue(1), -1},
+	{NewNilValue(), NewStringValue(""), -1},
+	{NewIntValue(1), NewNilValue(), 1},
+	{NewNilValue(), nil, 0},
+	{NewNumberNil(), NewIntValue(-5), -1},
+	{NewStringsValue([]string{"a", "b"}), NewStringsValue([]string{"a", "c"}), -1},
+	{NewStringsValue([]string{"a", "b"}), NewStringsValue([]string{"a"}), 1},
+}
+
+func TestCompare(t *testing.T) {
+	for _, tc := range compareTests {
+		cmp, err := Compare(tc.l, tc.r)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, tc.cmp, cmp, "%v <=> %v", tc.l, tc.r)
+	}
+	_, err := Compare(NewMapIntValue(map[string]int64{"a": 1}), NewIntValue(1))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCollation(t *testing.T) {
+	c, err := CollationFromName("nocase")
+	assert.Equal(t, nil, err)
+	cmp, _ := CompareCollate(NewStringValue("B"), NewStringValue("a"), c)
+	assert.Equal(t, 1, cmp)
+	cmp, _ = CompareCollate(NewStringValue("ABC"), NewStringValue("abc"), c)
+	assert.Equal(t, 0, cmp)
+
+	c, err = CollationFromName("utf8mb4_general_ci")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, c.CompareString("Hello", "hello"))
+
+	c, err = CollationFromName("utf8_bin")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, BinaryCollation, c)
+
+	// swedish sorts ä after z, german sorts it with a
+	c, err = CollationFromName("sv_SE")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, c.CompareString("ä", "z"))
+	c, err = CollationFromName("de")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, -1, c.CompareString("ä", "z"))
+
+	_, err = CollationFromName("not a collation")
+	assert.NotEqual(t, nil, err)
+}
@@ -360,3 +360,31 @@ func TestFilterContexts(t *testing.T) {
 	_, ok = vm.MatchesInc(ctx, readCtx, q)
 	assert.True(t, !ok, "Should be ok")
 }
+
+func TestFilterQLOrderBy(t *testing.T) {
+	t.Parallel()
+
+	fs, err := rel.ParseFilterQL(`FILTER x > 1 ORDER BY x DESC NULLS LAST, name COLLATE nocase`)
+	assert.Equal(t, nil, err)
+	ob, err := vm.NewOrderBy(fs.OrderBy)
+	assert.Equal(t, nil, err)
+
+	rows := []expr.EvalContext{
+		datasource.NewContextSimpleNative(map[string]interface{}{"x": 9, "name": "B"}),
+		datasource.NewContextSimpleNative(map[string]interface{}{"name": "c"}),
+		datasource.NewContextSimpleNative(map[string]interface{}{"x": 10, "name": "z"}),
+		datasource.NewContextSimpleNative(map[string]interface{}{"x": 9, "name": "a"}),
+	}
+	ob.Sort(rows)
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		v, _ := row.Get("name")
+		names[i] = v.ToString()
+	}
+	assert.Equal(t, []string{"z", "a", "B", "c"}, names)
+
+	fs, err = rel.ParseFilterQL(`FILTER x > 1 ORDER BY x COLLATE not_a_collation`)
+	assert.Equal(t, nil, err)
+	_, err = vm.NewOrderBy(fs.OrderBy)
+	assert.NotEqual(t, nil, err)
+}
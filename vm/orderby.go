@@ -0,0 +1,105 @@
+package vm
+
+import (
+	"sort"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+// OrderBy compares rows by the ORDER BY columns of a SqlSelect or
+// FilterStatement.  Keys are typed value.Value's compared with
+// value.CompareCollate honoring each columns ASC/DESC, NULLS FIRST/LAST
+// and COLLATE.
+type OrderBy struct {
+	cols     rel.Columns
+	collates []value.Collation
+}
+
+// NewOrderBy create an OrderBy comparator for these order by columns,
+// error if a column has an unrecognized collation.
+func NewOrderBy(cols rel.Columns) (*OrderBy, error) {
+	collates := make([]value.Collation, len(cols))
+	for i, col := range cols {
+		if col.Collate == "" {
+			continue
+		}
+		c, err := value.CollationFromName(col.Collate)
+		if err != nil {
+			return nil, err
+		}
+		collates[i] = c
+	}
+	return &OrderBy{cols: cols, collates: collates}, nil
+}
+
+// Keys evaluate the order by expressions against this row, a nil key
+// means the value was NULL or could not be evaluated.
+func (m *OrderBy) Keys(ctx expr.EvalContext) []value.Value {
+	keys := make([]value.Value, len(m.cols))
+	for i, col := range m.cols {
+		if col.Expr == nil {
+			continue
+		}
+		if v, ok := Eval(ctx, col.Expr); ok {
+			keys[i] = v
+		}
+	}
+	return keys
+}
+
+// Compare two sets of keys from Keys(), returning -1, 0, 1 if a sorts
+// before, same, after b.
+func (m *OrderBy) Compare(a, b []value.Value) int {
+	for i, col := range m.cols {
+		an, bn := value.IsNilish(a[i]), value.IsNilish(b[i])
+		switch {
+		case an && bn:
+			continue
+		case an || bn:
+			// nulls position is independent of asc/desc
+			if an == col.NullsFirst() {
+				return -1
+			}
+			return 1
+		}
+		cmp, err := value.CompareCollate(a[i], b[i], m.collates[i])
+		if err != nil || cmp == 0 {
+			continue
+		}
+		if !col.Asc() {
+			return -cmp
+		}
+		return cmp
+	}
+	return 0
+}
+
+// Less is row a before row b.
+func (m *OrderBy) Less(a, b expr.EvalContext) bool {
+	return m.Compare(m.Keys(a), m.Keys(b)) < 0
+}
+
+// Sort the rows in place, stable so rows with equal keys keep their
+// original order.
+func (m *OrderBy) Sort(rows []expr.EvalContext) {
+	keys := make([][]value.Value, len(rows))
+	for i, row := range rows {
+		keys[i] = m.Keys(row)
+	}
+	sort.Stable(&orderByRows{m, rows, keys})
+}
+
+type orderByRows struct {
+	ob   *OrderBy
+	rows []expr.EvalContext
+	keys [][]value.Value
+}
+
+func (m *orderByRows) Len() int           { return len(m.rows) }
+func (m *orderByRows) Less(i, j int) bool { return m.ob.Compare(m.keys[i], m.keys[j]) < 0 }
+func (m *orderByRows) Swap(i, j int) {
+	m.rows[i], m.rows[j] = m.rows[j], m.rows[i]
+	m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+}
@@ -0,0 +1,89 @@
+package vm
+
+import (
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/rel"
+	"github.com/araddon/qlbridge/value"
+)
+
+// subQueryValues the materialized sub-query values for this row, keyed
+// by the rows values of the outer expressions if correlated.  NULL outer
+// keys match no rows.  False if the sub-query has not been resolved.
+func subQueryValues(ctx expr.EvalContext, node *rel.SubQueryNode, depth int) ([]value.Value, bool) {
+	if !node.Resolved() {
+		u.Warnf("sub-query was not resolved before evaluation: %s", node)
+		return nil, false
+	}
+	keys := make([]value.Value, len(node.Outer))
+	for i, n := range node.Outer {
+		v, ok := evalDepth(ctx, n, depth+1)
+		if !ok || value.IsNilish(v) {
+			return nil, true
+		}
+		keys[i] = v
+	}
+	return node.Values(keys), true
+}
+
+// walkSubQuery scalar sub-query value:   x > (SELECT count(*) FROM t)
+// NULL (or the sub-queries Empty value) for no rows, more than one row
+// can not be evaluated.
+func walkSubQuery(ctx expr.EvalContext, node *rel.SubQueryNode, depth int) (value.Value, bool) {
+	vals, ok := subQueryValues(ctx, node, depth)
+	if !ok {
+		return nil, false
+	}
+	switch len(vals) {
+	case 0:
+		if node.Empty != nil {
+			return node.Empty, true
+		}
+		return value.NewNilValue(), true
+	case 1:
+		return vals[0], true
+	}
+	u.Debugf("scalar sub-query returned %d rows: %s", len(vals), node)
+	return nil, false
+}
+
+// walkExistsSubQuery    EXISTS (SELECT ...)
+func walkExistsSubQuery(ctx expr.EvalContext, node *rel.SubQueryNode, depth int) (value.Value, bool) {
+	vals, ok := subQueryValues(ctx, node, depth)
+	if !ok {
+		return nil, false
+	}
+	return value.NewBoolValue(len(vals) > 0), true
+}
+
+// walkInSubQuery    x IN (SELECT y FROM ...)
+// As sql, if x is not found but x or any of the sub-query values is NULL
+// the result is NULL (can't evaluate) so NOT IN does not match either.
+func walkInSubQuery(ctx expr.EvalContext, arg expr.Node, node *rel.SubQueryNode, depth int) (value.Value, bool) {
+	vals, ok := subQueryValues(ctx, node, depth)
+	if !ok {
+		return nil, false
+	}
+	if len(vals) == 0 {
+		return value.NewBoolValue(false), true
+	}
+	a, ok := evalDepth(ctx, arg, depth+1)
+	if !ok || value.IsNilish(a) {
+		return nil, false
+	}
+	hasNull := false
+	for _, v := range vals {
+		if value.IsNilish(v) {
+			hasNull = true
+			continue
+		}
+		if c, err := value.Compare(a, v); err == nil && c == 0 {
+			return value.BoolValueTrue, true
+		}
+	}
+	if hasNull {
+		return nil, false
+	}
+	return value.NewBoolValue(false), true
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/araddon/qlbridge/expr"
 	"github.com/araddon/qlbridge/lex"
+	"github.com/araddon/qlbridge/rel"
 	"github.com/araddon/qlbridge/value"
 )
 
@@ -122,8 +123,14 @@ func resolveIncludesDepth(ctx expr.Includer, arg expr.Node, depth int) error {
 				return err
 			}
 		}
+	case *expr.CaseNode:
+		for _, narg := range n.ChildrenArgs() {
+			if err := resolveIncludesDepth(ctx, narg, depth+1); err != nil {
+				return err
+			}
+		}
 	case *expr.NumberNode, *expr.IdentityNode, *expr.StringNode, nil,
-		*expr.ValueNode, *expr.NullNode:
+		*expr.ValueNode, *expr.NullNode, *expr.ParamNode:
 		return nil
 	case *expr.IncludeNode:
 		return resolveInclude(ctx, n, depth+1)
@@ -162,10 +169,15 @@ func evalDepth(ctx expr.EvalContext, arg expr.Node, depth int) (value.Value, boo
 		return walkArray(ctx, argVal, depth)
 	case *expr.FuncNode:
 		return walkFunc(ctx, argVal, depth)
+	case *expr.CaseNode:
+		return walkCase(ctx, argVal, depth)
 	case *expr.IdentityNode:
 		return walkIdentity(ctx, argVal)
 	case *expr.StringNode:
 		return value.NewStringValue(argVal.Text), true
+	case *expr.ParamNode:
+		// a placeholder with no value bound to it
+		return nil, false
 	case nil:
 		return nil, false
 	case *expr.NullNode:
@@ -173,6 +185,8 @@ func evalDepth(ctx expr.EvalContext, arg expr.Node, depth int) (value.Value, boo
 		return value.NewNilValue(), true
 	case *expr.IncludeNode:
 		return walkInclude(ctx, argVal, depth+1)
+	case *rel.SubQueryNode:
+		return walkSubQuery(ctx, argVal, depth)
 	case *expr.ValueNode:
 		if argVal.Value == nil {
 			return nil, false
@@ -312,7 +326,28 @@ func walkBinary(ctx expr.EvalContext, node *expr.BinaryNode, depth int) (value.V
 	}
 	return val, ok
 }
+
+// walkIsNull evaluate `x IS NULL`, or `x IS NOT NULL` which is parsed as
+// `x != NULL`.  Missing values, ie the NULL side of an outer join, are null.
+func walkIsNull(ctx expr.EvalContext, node *expr.BinaryNode, depth int) (value.Value, bool) {
+	v, ok := evalDepth(ctx, node.Args[0], depth+1)
+	isNull := !ok || v == nil || v.Nil()
+	if node.Operator.T == lex.TokenNE {
+		return value.NewBoolValue(!isNull), true
+	}
+	return value.NewBoolValue(isNull), true
+}
+
 func evalBinary(ctx expr.EvalContext, node *expr.BinaryNode, depth int) (value.Value, bool) {
+	if sq, isSubQuery := node.Args[1].(*rel.SubQueryNode); isSubQuery && node.Operator.T == lex.TokenIN {
+		return walkInSubQuery(ctx, node.Args[0], sq, depth)
+	}
+	if _, isNull := node.Args[1].(*expr.NullNode); isNull {
+		switch node.Operator.T {
+		case lex.TokenIs, lex.TokenNE:
+			return walkIsNull(ctx, node, depth)
+		}
+	}
 	ar, aok := evalDepth(ctx, node.Args[0], depth+1)
 	br, bok := evalDepth(ctx, node.Args[1], depth+1)
 
@@ -799,13 +834,19 @@ func walkIdentity(ctx expr.EvalContext, node *expr.IdentityNode) (value.Value, b
 		return nil, false
 	}
 	if node.HasLeftRight() {
-		return ctx.Get(node.OriginalText())
+		if v, ok := ctx.Get(node.OriginalText()); ok {
+			return v, ok
+		}
 	}
 	return ctx.Get(node.Text)
 }
 
 func walkUnary(ctx expr.EvalContext, node *expr.UnaryNode, depth int) (value.Value, bool) {
 
+	if sq, isSubQuery := node.Arg.(*rel.SubQueryNode); isSubQuery && node.Operator.T == lex.TokenExists {
+		return walkExistsSubQuery(ctx, sq, depth)
+	}
+
 	a, ok := Eval(ctx, node.Arg)
 	if !ok {
 		switch node.Operator.T {
@@ -953,6 +994,12 @@ func walkFunc(ctx expr.EvalContext, node *expr.FuncNode, depth int) (value.Value
 	if node.F.CustomFunc == nil {
 		return nil, false
 	}
+	if node.Lazy != nil {
+		// the func evaluates only the args it needs
+		return node.Lazy(ctx, node.Args, func(arg expr.Node) (value.Value, bool) {
+			return evalDepth(ctx, arg, depth+1)
+		})
+	}
 	if node.Eval == nil {
 		u.LogThrottle(u.WARN, 10, "No Eval() for %s", node.Name)
 		return nil, false
@@ -970,6 +1017,41 @@ func walkFunc(ctx expr.EvalContext, node *expr.FuncNode, depth int) (value.Value
 	return node.Eval(ctx, args)
 }
 
+// walkCase evaluate a CASE expression, lazily: only the WHENs up to the
+// first one that matches and its THEN (or the ELSE) are evaluated.  No
+// match and no ELSE is NULL, as is a NULL operand of a simple case.
+func walkCase(ctx expr.EvalContext, node *expr.CaseNode, depth int) (value.Value, bool) {
+	var operand value.Value
+	if node.Operand != nil {
+		if v, ok := evalDepth(ctx, node.Operand, depth+1); ok && v != nil && !v.Nil() {
+			operand = v
+		}
+	}
+	for i, when := range node.Whens {
+		if node.Operand == nil {
+			if matched, ok := evalBool(ctx, when, depth+1); !ok || !matched {
+				continue
+			}
+		} else {
+			if operand == nil {
+				break
+			}
+			wv, ok := evalDepth(ctx, when, depth+1)
+			if !ok || wv == nil || wv.Nil() {
+				continue
+			}
+			if eq, err := value.Equal(operand, wv); err != nil || !eq {
+				continue
+			}
+		}
+		return evalDepth(ctx, node.Thens[i], depth+1)
+	}
+	if node.Else != nil {
+		return evalDepth(ctx, node.Else, depth+1)
+	}
+	return value.NewNilValue(), true
+}
+
 func operateNumbers(op lex.Token, av, bv value.NumberValue) value.Value {
 	switch op.T {
 	case lex.TokenPlus, lex.TokenStar, lex.TokenMultiply, lex.TokenDivide, lex.TokenMinus,
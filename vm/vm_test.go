@@ -175,6 +175,10 @@ var (
 		vmt(`user_id LIKE "*bc"`, true, noError),
 		vmt(`user_id LIKE "\*bc"`, false, noError),
 		vmt(`user_id != NULL`, true, noError),
+		vmt(`user_id IS NOT NULL`, true, noError),
+		vmt(`user_id IS NULL`, false, noError),
+		vmt(`not_a_field IS NULL`, true, noError),
+		vmt(`not_a_field IS NOT NULL`, false, noError),
 
 		// Binary Bool
 		vmt(`bvalt == true`, true, noError),
@@ -247,6 +251,14 @@ var (
 		// context lookups? simple
 		vmt(`user_id`, "abc", noError),
 
+		// Case
+		vmt(`CASE WHEN int5 > 3 THEN "big" ELSE "small" END`, "big", noError),
+		vmt(`CASE WHEN int5 > 10 THEN "big" WHEN int5 > 1 THEN "medium" END`, "medium", noError),
+		vmt(`CASE user_id WHEN "xyz" THEN 1 WHEN "abc" THEN 2 ELSE 3 END`, int64(2), noError),
+		vmt(`CASE user_id WHEN "xyz" THEN 1 ELSE int5 * 2 END`, int64(10), noError),
+		vmt(`CASE not_a_field WHEN "abc" THEN 1 ELSE 0 END`, int64(0), noError),
+		vmt(`CASE WHEN int5 > 3 THEN "big" END == "big"`, true, noError),
+
 		// functional syntax
 		vmt(`eq(toint(int5),5)`, true, noError),
 		vmt(`eq(toint(int5),6)`, false, noError),
@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/plan"
+)
+
+var (
+	// Ensure that we implement the Executor, Task Runner interfaces
+	_ exec.Executor   = (*Executor)(nil)
+	_ exec.TaskRunner = (*RemoteSource)(nil)
+)
+
+// Coordinator builds jobs whose partition scans run on workers.
+type Coordinator struct {
+	// Workers addresses, the partitions of a scan are assigned to
+	// them round-robin.
+	Workers []string
+	// Dial opens the connection to a worker, defaults to tcp.
+	Dial DialFunc
+}
+
+// NewCoordinator create a coordinator of the workers at the given
+// (tcp host:port) addresses.
+func NewCoordinator(workers ...string) *Coordinator {
+	return &Coordinator{Workers: workers, Dial: dialTCP}
+}
+
+func dialTCP(ctx context.Context, addr string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+// BuildSqlJob given a plan context (query statement, +context) create
+// a job, the same as exec.BuildSqlJob except partition scans are run
+// on the workers.
+func (m *Coordinator) BuildSqlJob(ctx *plan.Context) (*exec.JobExecutor, error) {
+	if len(m.Workers) == 0 {
+		return nil, fmt.Errorf("no workers")
+	}
+	job := exec.NewExecutor(ctx, plan.NewPlanner(ctx))
+	job.Executor = &Executor{JobExecutor: job, c: m}
+	task, err := exec.BuildSqlJobPlanned(job.Planner, job.Executor, ctx)
+	if err != nil {
+		return nil, err
+	}
+	taskRunner, ok := task.(exec.TaskRunner)
+	if !ok {
+		return nil, fmt.Errorf("Expected TaskRunner but was %T", task)
+	}
+	job.RootTask = taskRunner
+	return job, nil
+}
+
+// Executor the exec.Executor of a coordinator, each partition of a
+// PartitionScan is a fragment sent to a worker.
+type Executor struct {
+	*exec.JobExecutor
+	c *Coordinator
+}
+
+// WalkPartitionScan create a RemoteSource per partition, merging their rows.
+func (m *Executor) WalkPartitionScan(p *plan.PartitionScan) (exec.Task, error) {
+	execTask := exec.NewTaskParallel(m.Ctx)
+	inputs := make([]exec.TaskRunner, len(p.Parts))
+	for i, part := range p.Parts {
+		f, err := NewFragment(m.Ctx, part)
+		if err != nil {
+			return nil, err
+		}
+		addr := m.c.Workers[i%len(m.c.Workers)]
+		// sequential wrapper so each partition keeps its own output
+		seq := exec.NewTaskSequential(m.Ctx)
+		if err = seq.Add(NewRemoteSource(m.Ctx, m.c.dial(), addr, f)); err != nil {
+			return nil, err
+		}
+		if err = execTask.Add(seq); err != nil {
+			return nil, err
+		}
+		inputs[i] = seq
+	}
+	err := execTask.Add(exec.NewPartitionMerge(m.Ctx, inputs))
+	if err != nil {
+		return nil, err
+	}
+	return execTask, nil
+}
+
+func (m *Coordinator) dial() DialFunc {
+	if m.Dial == nil {
+		return dialTCP
+	}
+	return m.Dial
+}
+
+// RemoteSource a task whose rows are those of a fragment run on a worker.
+type RemoteSource struct {
+	*exec.TaskBase
+	dial DialFunc
+	addr string
+	f    *Fragment
+}
+
+// NewRemoteSource create a task running fragment f on the worker at addr.
+func NewRemoteSource(ctx *plan.Context, dial DialFunc, addr string, f *Fragment) *RemoteSource {
+	return &RemoteSource{
+		TaskBase: exec.NewTaskBase(ctx),
+		dial:     dial,
+		addr:     addr,
+		f:        f,
+	}
+}
+
+// Run send the fragment to the worker and emit the rows it streams back.
+func (m *RemoteSource) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.MessageOut())
+
+	conn, err := m.dial(m.Ctx.Context, m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// closing the conn cancels the fragment on the worker
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-m.SigChan():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err = gob.NewEncoder(conn).Encode(m.f); err != nil {
+		return err
+	}
+	dec := NewRowDecoder(conn)
+	out := m.MessageOut()
+	for {
+		msg, err := dec.Decode()
+		if err != nil {
+			select {
+			case <-m.SigChan():
+				// closed by us
+				return nil
+			default:
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("%s: %v", m.addr, err)
+		}
+		select {
+		case <-m.SigChan():
+			return nil
+		case out <- msg:
+		}
+	}
+}
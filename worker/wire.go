@@ -0,0 +1,159 @@
+package worker
+
+import (
+	"database/sql/driver"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/schema"
+
+	// registers the row value types (time, AggPartial) with gob
+	_ "github.com/araddon/qlbridge/exec"
+)
+
+const (
+	// RowBatchSize max number of rows sent in a single frame.
+	RowBatchSize = 100
+)
+
+// frame is a single gob encoded message of a worker's response stream,
+// a batch of rows, or the end of the stream with its error if any.
+type frame struct {
+	Cols map[string]int // col index of the rows of this frame
+	Rows []wireRow
+	Done bool
+	Err  string
+}
+
+// wireRow the wire representation of a message row.
+type wireRow struct {
+	Id   uint64
+	Key  string
+	Vals []driver.Value
+}
+
+// RowEncoder writes message rows to a stream, buffering them into
+// frames of up to RowBatchSize rows.
+//
+//    enc := NewRowEncoder(conn)
+//    for each msg { enc.Encode(msg) }
+//    enc.Close(err)
+type RowEncoder struct {
+	enc   *gob.Encoder
+	batch frame
+}
+
+// NewRowEncoder create an encoder writing to w.
+func NewRowEncoder(w io.Writer) *RowEncoder {
+	return &RowEncoder{enc: gob.NewEncoder(w)}
+}
+
+// Encode a message, buffered until the batch is full or Flush.
+func (m *RowEncoder) Encode(msg schema.Message) error {
+	var row wireRow
+	var cols map[string]int
+	switch mt := msg.(type) {
+	case *datasource.SqlDriverMessageMap:
+		key, _ := mt.Key().(string)
+		row = wireRow{Id: mt.IdVal, Key: key, Vals: mt.Vals}
+		cols = mt.ColIndex
+	case *datasource.SqlDriverMessage:
+		row = wireRow{Id: mt.IdVal, Vals: mt.Vals}
+	default:
+		return fmt.Errorf("unsupported message type for wire %T", msg)
+	}
+	// a frame's rows share a col index
+	if len(m.batch.Rows) > 0 && !sameCols(m.batch.Cols, cols) {
+		if err := m.Flush(); err != nil {
+			return err
+		}
+	}
+	m.batch.Cols = cols
+	m.batch.Rows = append(m.batch.Rows, row)
+	if len(m.batch.Rows) >= RowBatchSize {
+		return m.Flush()
+	}
+	return nil
+}
+
+// Flush the buffered rows.
+func (m *RowEncoder) Flush() error {
+	if len(m.batch.Rows) == 0 {
+		return nil
+	}
+	err := m.enc.Encode(&m.batch)
+	m.batch = frame{}
+	return err
+}
+
+// Close flush the buffered rows and end the stream, err is sent to
+// the decoder.
+func (m *RowEncoder) Close(err error) error {
+	if ferr := m.Flush(); ferr != nil {
+		return ferr
+	}
+	end := frame{Done: true}
+	if err != nil {
+		end.Err = err.Error()
+	}
+	return m.enc.Encode(&end)
+}
+
+// RowDecoder reads the message rows written by a RowEncoder.
+type RowDecoder struct {
+	dec  *gob.Decoder
+	cur  frame
+	pos  int
+	done bool
+}
+
+// NewRowDecoder create a decoder reading from r.
+func NewRowDecoder(r io.Reader) *RowDecoder {
+	return &RowDecoder{dec: gob.NewDecoder(r)}
+}
+
+// Decode the next message, returns io.EOF at the end of the stream
+// or the error the encoder was closed with.
+func (m *RowDecoder) Decode() (*datasource.SqlDriverMessageMap, error) {
+	for m.pos >= len(m.cur.Rows) {
+		if m.done {
+			return nil, io.EOF
+		}
+		m.cur = frame{}
+		m.pos = 0
+		if err := m.dec.Decode(&m.cur); err != nil {
+			if err == io.EOF {
+				// the stream ended without its end frame
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if m.cur.Done {
+			m.done = true
+			if m.cur.Err != "" {
+				return nil, &RemoteError{Msg: m.cur.Err}
+			}
+		}
+	}
+	row := m.cur.Rows[m.pos]
+	m.pos++
+	msg := datasource.NewSqlDriverMessageMap(row.Id, row.Vals, m.cur.Cols)
+	if row.Key != "" {
+		msg.SetKey(row.Key)
+	}
+	return msg, nil
+}
+
+// RemoteError an error of a fragment run by a worker.
+type RemoteError struct {
+	Msg string
+}
+
+func (m *RemoteError) Error() string { return "worker: " + m.Msg }
+
+func sameCols(a, b map[string]int) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
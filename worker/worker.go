@@ -0,0 +1,252 @@
+// Package worker runs plan fragments of a query on other processes.  A
+// Coordinator builds the job of a statement the same as exec does, except
+// that the partitions of a PartitionScan are serialized (protobuf) into
+// Fragments and each is sent to one of N workers.  A worker (Server) runs
+// its fragment (source, where, partial group-by) and streams the rows back
+// over the same connection, where they are merged (and group-by finalized)
+// by the coordinator's job.
+//
+//    coordinator                        worker
+//       fragment (gob, PlanPb)   ->     Server.ServeConn
+//                                <-     frames of rows (gob) ... end frame
+package worker
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	u "github.com/araddon/gou"
+
+	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+)
+
+// DialFunc opens a connection to the worker at addr.
+type DialFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+// Fragment a plan fragment to run on a worker, the source of a single
+// partition of a PartitionScan along with its tasks.
+type Fragment struct {
+	Context []byte // protobuf plan.ContextPb
+	Plan    []byte // protobuf plan.PlanPb of the source
+}
+
+// NewFragment serialize the source p of the plan context.
+func NewFragment(ctx *plan.Context, p *plan.Source) (*Fragment, error) {
+	ctxPb, err := ctx.ToPB().Marshal()
+	if err != nil {
+		return nil, err
+	}
+	pb, err := p.ToPb()
+	if err != nil {
+		return nil, err
+	}
+	planPb, err := pb.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{Context: ctxPb, Plan: planPb}, nil
+}
+
+// Source rebuild the plan context, source of the fragment, its schema
+// from the loader.
+func (m *Fragment) Source(loader plan.SchemaLoader) (*plan.Source, error) {
+	ctxPb := &plan.ContextPb{}
+	if err := ctxPb.Unmarshal(m.Context); err != nil {
+		return nil, err
+	}
+	ctx := plan.NewContextFromPb(ctxPb)
+	sch, err := loader(ctx.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Schema = sch
+	pb := &plan.PlanPb{}
+	if err := pb.Unmarshal(m.Plan); err != nil {
+		return nil, err
+	}
+	if pb.Source == nil {
+		return nil, fmt.Errorf("fragment plan is not a source")
+	}
+	return plan.SourceFromPB(pb, ctx)
+}
+
+// Server a worker, running the fragments sent to it on each connection.
+type Server struct {
+	// Loader of the schema of a fragment, defaults to the schemas of
+	// the default registry.
+	Loader plan.SchemaLoader
+
+	mu     sync.Mutex
+	ls     []net.Listener
+	closed bool
+}
+
+// NewServer create a worker server.
+func NewServer() *Server {
+	return &Server{Loader: registrySchema}
+}
+
+func registrySchema(name string) (*schema.Schema, error) {
+	s, ok := schema.DefaultRegistry().Schema(name)
+	if !ok {
+		return nil, schema.ErrNotFound
+	}
+	return s, nil
+}
+
+// Serve the connections of l until it is closed.
+func (m *Server) Serve(l net.Listener) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return fmt.Errorf("worker server closed")
+	}
+	m.ls = append(m.ls, l)
+	m.mu.Unlock()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			m.mu.Lock()
+			closed := m.closed
+			m.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go m.ServeConn(conn)
+	}
+}
+
+// Close the listeners of the server.
+func (m *Server) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for _, l := range m.ls {
+		l.Close()
+	}
+	return nil
+}
+
+// DialPipe a DialFunc for an in-process worker, the connection is served
+// by this server whatever the addr.
+func (m *Server) DialPipe(ctx context.Context, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go m.ServeConn(server)
+	return client, nil
+}
+
+// ServeConn read a fragment from conn and stream its rows back, the
+// fragment is cancelled if the coordinator closes the connection.
+func (m *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+
+	f := &Fragment{}
+	if err := gob.NewDecoder(conn).Decode(f); err != nil {
+		u.Warnf("could not read fragment: %v", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// nothing more is sent, this returns when the conn is closed
+		io.Copy(ioutil.Discard, conn)
+		cancel()
+	}()
+
+	enc := NewRowEncoder(conn)
+	err := m.run(ctx, f, enc)
+	if err != nil {
+		u.Warnf("fragment error: %v", err)
+	}
+	if err := enc.Close(err); err != nil {
+		u.Debugf("could not end stream: %v", err)
+	}
+}
+
+// run the fragment, encoding its rows to enc.
+func (m *Server) run(ctx context.Context, f *Fragment, enc *RowEncoder) error {
+	loader := m.Loader
+	if loader == nil {
+		loader = registrySchema
+	}
+	p, err := f.Source(loader)
+	if err != nil {
+		return err
+	}
+	pctx := p.Context()
+	pctx.Context = ctx
+
+	job := exec.NewExecutor(pctx, nil)
+	task, err := job.WalkPlanAll(p)
+	if err != nil {
+		return err
+	}
+	root := exec.NewTaskSequential(pctx)
+	if err = root.Add(task); err != nil {
+		return err
+	}
+	if err = root.Add(newStreamWriter(pctx, enc)); err != nil {
+		return err
+	}
+	job.RootTask = root
+	if err = job.Setup(); err != nil {
+		return err
+	}
+	return job.Run()
+}
+
+var (
+	// Ensure that we implement the Task Runner interface
+	_ exec.TaskRunner = (*streamWriter)(nil)
+)
+
+// streamWriter the last task of a worker's fragment job, encoding the
+// rows to the coordinator.
+type streamWriter struct {
+	*exec.TaskBase
+	enc *RowEncoder
+}
+
+func newStreamWriter(ctx *plan.Context, enc *RowEncoder) *streamWriter {
+	return &streamWriter{TaskBase: exec.NewTaskBase(ctx), enc: enc}
+}
+
+// Run encode rows until the input is closed, flushing whenever no more
+// rows are waiting.
+func (m *streamWriter) Run() error {
+	defer m.Ctx.Recover()
+	defer close(m.MessageOut())
+
+	in := m.MessageIn()
+	for {
+		select {
+		case <-m.SigChan():
+			return nil
+		case msg, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if msg == nil {
+				// end of rows signal
+				continue
+			}
+			if err := m.enc.Encode(msg); err != nil {
+				return err
+			}
+			if len(in) == 0 {
+				if err := m.enc.Flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
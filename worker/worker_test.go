@@ -0,0 +1,194 @@
+package worker_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/araddon/qlbridge/datasource"
+	"github.com/araddon/qlbridge/datasource/memdb"
+	"github.com/araddon/qlbridge/exec"
+	"github.com/araddon/qlbridge/expr"
+	"github.com/araddon/qlbridge/expr/builtins"
+	"github.com/araddon/qlbridge/plan"
+	"github.com/araddon/qlbridge/schema"
+	"github.com/araddon/qlbridge/worker"
+)
+
+func TestMain(m *testing.M) {
+	builtins.LoadAllBuiltins()
+	os.Exit(m.Run())
+}
+
+// partSource a memdb table split into partitions, each scanned by
+// its own memdb.
+type partSource struct {
+	*memdb.MemDb
+	parts []*memdb.MemDb
+}
+
+func (m *partSource) Partitions() []*schema.Partition {
+	parts := make([]*schema.Partition, len(m.parts))
+	for i := range m.parts {
+		parts[i] = &schema.Partition{Id: fmt.Sprintf("p%d", i)}
+	}
+	return parts
+}
+func (m *partSource) PartitionSource(p *schema.Partition) (schema.Conn, error) {
+	var i int
+	if _, err := fmt.Sscanf(p.Id, "p%d", &i); err != nil {
+		return nil, err
+	}
+	return m.parts[i].Open(p.Id)
+}
+
+func loadPartSource(t *testing.T, schemaName string) {
+	cols := []string{"id", "region", "amount"}
+	parts := [][][]driver.Value{
+		{{int64(1), "east", int64(10)}, {int64(2), "west", int64(20)}},
+		{{int64(3), "east", int64(30)}, {int64(4), "east", int64(5)}},
+		{{int64(5), "west", int64(40)}},
+	}
+	src := &partSource{}
+	var all [][]driver.Value
+	for _, rows := range parts {
+		part, err := memdb.NewMemDbData("sales", rows, cols)
+		assert.Equal(t, nil, err)
+		src.parts = append(src.parts, part)
+		all = append(all, rows...)
+	}
+	mdb, err := memdb.NewMemDbData("sales", all, cols)
+	assert.Equal(t, nil, err)
+	src.MemDb = mdb
+	assert.Equal(t, nil, schema.RegisterSourceAsSchema(schemaName, src))
+}
+
+// runJob run the sql on the coordinator's workers, rows sorted by
+// their first column.
+func runJob(t *testing.T, c *worker.Coordinator, schemaName, sql string) ([][]driver.Value, error) {
+	sch, ok := schema.DefaultRegistry().Schema(schemaName)
+	assert.True(t, ok)
+	ctx := plan.NewContext(sql)
+	ctx.Schema = sch
+	job, err := c.BuildSqlJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]schema.Message, 0)
+	if err = job.RootTask.Add(exec.NewResultBuffer(ctx, &msgs)); err != nil {
+		return nil, err
+	}
+	if err = job.Setup(); err != nil {
+		return nil, err
+	}
+	err = job.Run()
+	rows := make([][]driver.Value, 0, len(msgs))
+	for _, msg := range msgs {
+		rows = append(rows, msg.(*datasource.SqlDriverMessageMap).Vals)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return schema.CompareValues(rows[i][0], rows[j][0]) < 0
+	})
+	return rows, err
+}
+
+func TestWorkerInProcess(t *testing.T) {
+	loadPartSource(t, "worker_inproc")
+	srv := worker.NewServer()
+	c := worker.NewCoordinator("w1", "w2")
+	c.Dial = srv.DialPipe
+
+	rows, err := runJob(t, c, "worker_inproc", `SELECT region, count(*), sum(amount) FROM sales
+		WHERE amount > 5 GROUP BY region`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]driver.Value{{"east", int64(2), float64(40)}, {"west", int64(2), float64(60)}}, rows)
+
+	rows, err = runJob(t, c, "worker_inproc", `SELECT id, amount FROM sales WHERE region = "east"`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]driver.Value{{int64(1), int64(10)}, {int64(3), int64(30)}, {int64(4), int64(5)}}, rows)
+
+	// errors of a worker fail the job
+	c.Dial = func(ctx context.Context, addr string) (net.Conn, error) {
+		if addr == "w2" {
+			return nil, fmt.Errorf("no route to %s", addr)
+		}
+		return srv.DialPipe(ctx, addr)
+	}
+	_, err = runJob(t, c, "worker_inproc", `SELECT count(*) FROM sales`)
+	assert.NotEqual(t, nil, err)
+
+	// as do the errors of running the fragment
+	c.Dial = srv.DialPipe
+	srv.Loader = func(name string) (*schema.Schema, error) { return nil, schema.ErrNotFound }
+	_, err = runJob(t, c, "worker_inproc", `SELECT count(*) FROM sales`)
+	assert.NotEqual(t, nil, err)
+	assert.True(t, strings.Contains(err.Error(), "worker: Not Found"), err.Error())
+}
+
+func TestWorkerTCP(t *testing.T) {
+	loadPartSource(t, "worker_tcp")
+	var addrs []string
+	for i := 0; i < 2; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Equal(t, nil, err)
+		srv := worker.NewServer()
+		go srv.Serve(l)
+		defer srv.Close()
+		addrs = append(addrs, l.Addr().String())
+	}
+	c := worker.NewCoordinator(addrs...)
+
+	rows, err := runJob(t, c, "worker_tcp", `SELECT region, avg(amount), min(id) FROM sales GROUP BY region`)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]driver.Value{{"east", float64(15), int64(1)}, {"west", float64(30), int64(2)}}, rows)
+}
+
+func TestWorkerWire(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	cols := map[string]int{"a": 0, "b": 1, "c": 2}
+
+	var buf bytes.Buffer
+	enc := worker.NewRowEncoder(&buf)
+	for i := 0; i < worker.RowBatchSize+5; i++ {
+		msg := datasource.NewSqlDriverMessageMap(uint64(i), []driver.Value{int64(i), "x", ts}, cols)
+		assert.Equal(t, nil, enc.Encode(msg))
+	}
+	partial := datasource.NewSqlDriverMessageMap(1, []driver.Value{&expr.AggPartial{Ct: 2, N: 3.5}, nil}, nil)
+	partial.SetKey("k1")
+	assert.Equal(t, nil, enc.Encode(partial))
+	assert.NotEqual(t, nil, enc.Encode(datasource.NewContextSimple()))
+	assert.Equal(t, nil, enc.Close(nil))
+
+	dec := worker.NewRowDecoder(&buf)
+	for i := 0; i < worker.RowBatchSize+5; i++ {
+		msg, err := dec.Decode()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, uint64(i), msg.Id())
+		assert.Equal(t, []driver.Value{int64(i), "x", ts}, msg.Vals)
+		assert.Equal(t, cols, msg.ColIndex)
+	}
+	msg, err := dec.Decode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "k1", msg.Key())
+	assert.Equal(t, expr.AggPartial{Ct: 2, N: 3.5}, msg.Vals[0])
+	assert.Equal(t, nil, msg.Vals[1])
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+
+	// the error the stream ended with
+	buf.Reset()
+	enc = worker.NewRowEncoder(&buf)
+	assert.Equal(t, nil, enc.Close(fmt.Errorf("bad fragment")))
+	_, err = worker.NewRowDecoder(&buf).Decode()
+	assert.Equal(t, "worker: bad fragment", err.Error())
+}